@@ -0,0 +1,264 @@
+// Package app 单进程模式
+// 职责：在一个进程内组装行情源、价格缓存、套利引擎、风控和并发执行器，按依赖顺序启动、逆序停止
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"arbitragex/cmd/arbitragex/internal/config"
+	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
+	"arbitragex/common/tracing"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/exchange"
+	"arbitragex/pkg/execution"
+	"arbitragex/pkg/feed"
+	"arbitragex/pkg/risk"
+	"arbitragex/pkg/settings"
+	"arbitragex/pkg/store"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// App 单进程套利系统
+// 行情写入价格缓存，扫描循环识别套利机会；开启自动交易时新出现的机会经风控检查后提交给并发执行器，
+// 套利机会和执行结果保存到仓储（配置了 MySQL 时为 MySQL，否则为内存）
+type App struct {
+	Config     config.Config
+	PriceCache cache.PriceCache
+	Feed       *feed.Feed
+	Engine     *engine.ArbitrageEngine
+	Scanner    *engine.Scanner
+	Risk       *risk.Checker
+	Executor   *execution.DefaultConcurrentExecutor
+	Store      *store.Store
+
+	recorder *store.OpportunityRecorder
+	reloader *settings.Reloader // 参数热更新（未配置时为 nil）
+	journal  *execution.FileJournal
+	amount   decimal.Decimal
+
+	// accepting 是否接受新的执行（Stop 后不再提交），inflight 为已提交未结束的执行
+	accepting bool
+	inflight  sync.WaitGroup
+	mu        sync.Mutex
+
+	logger logx.Logger
+}
+
+// New 按配置创建单进程套利系统（为每个启用的交易所创建行情适配器和订单执行器）
+func New(c config.Config) (*App, error) {
+	adapters, err := c.Exchanges.NewAdapters(c.Symbols)
+	if err != nil {
+		return nil, err
+	}
+	executors, err := c.Exchanges.NewExecutors(c.Executor, c.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	if len(adapters) == 0 {
+		return nil, fmt.Errorf("没有启用的交易所")
+	}
+
+	return NewWithComponents(c, adapters, executors)
+}
+
+// NewWithComponents 使用指定的行情适配器和订单执行器创建单进程套利系统（exchange -> adapter / executor）
+func NewWithComponents(c config.Config, adapters map[string]exchange.ExchangeAdapter, executors map[string]execution.OrderExecutor) (*App, error) {
+	engineConfig, err := c.Engine.EngineConfig(c.Exchanges.TradingFees())
+	if err != nil {
+		return nil, err
+	}
+	limits, err := c.Risk.Limits()
+	if err != nil {
+		return nil, err
+	}
+	amount, err := decimal.NewFromString(c.Trading.Amount)
+	if err != nil || !amount.IsPositive() {
+		return nil, fmt.Errorf("无效的 Trading.Amount: %q", c.Trading.Amount)
+	}
+	executor, journal, err := c.Executor.NewConcurrentExecutor(executors)
+	if err != nil {
+		return nil, err
+	}
+
+	exchanges := make([]string, 0, len(adapters))
+	priceCache := cache.NewMemoryPriceCache(c.PriceTTL)
+	priceFeed := feed.New(priceCache, c.Symbols)
+	for name, adapter := range adapters {
+		priceFeed.AddAdapter(name, adapter)
+		exchanges = append(exchanges, name)
+	}
+	arbitrageEngine := engine.NewArbitrageEngine(engineConfig, priceCache)
+
+	a := &App{
+		Config:     c,
+		PriceCache: priceCache,
+		Feed:       priceFeed,
+		Engine:     arbitrageEngine,
+		Scanner:    engine.NewScanner(arbitrageEngine, c.Symbols, exchanges, c.Scan.Interval),
+		Risk:       risk.NewChecker(limits),
+		Executor:   executor,
+		Store:      c.MySQL.NewStore(),
+		journal:    journal,
+		amount:     amount,
+		logger:     logx.WithContext(context.Background()),
+	}
+
+	if source := c.Reload.NewSource(a.Store.Configs); source != nil {
+		a.reloader = settings.NewReloader(source, settings.Params{Engine: engineConfig, Risk: limits}, arbitrageEngine, a.Risk)
+	}
+
+	a.recorder = store.NewOpportunityRecorder(a.Store.Opportunities)
+	a.Engine.OnEvent(a.recorder.Record)
+	a.Engine.OnEvent(a.onEvent)
+	a.Executor.OnResult(a.Risk.Record)
+	a.Executor.OnResult(a.recordResult)
+	return a, nil
+}
+
+// Start 按依赖顺序启动：参数热更新 → 执行器（恢复上次未完成的执行）→ 行情源 → 扫描循环
+func (a *App) Start(ctx context.Context) error {
+	if a.reloader != nil {
+		// 首次读取失败或参数无效时已记录日志，使用启动配置继续运行
+		a.reloader.Start(a.Config.Reload.Interval)
+	}
+	if err := a.Executor.Start(ctx); err != nil {
+		a.stopReloader()
+		return fmt.Errorf("启动执行器失败: %w", err)
+	}
+	if err := a.Feed.Start(ctx); err != nil {
+		a.Executor.Stop()
+		a.stopReloader()
+		return fmt.Errorf("启动行情源失败: %w", err)
+	}
+
+	a.mu.Lock()
+	a.accepting = a.Config.Trading.Enabled
+	a.mu.Unlock()
+
+	if err := a.Scanner.Start(); err != nil {
+		a.Stop(ctx)
+		return fmt.Errorf("启动扫描失败: %w", err)
+	}
+
+	mode := "只监控"
+	if a.Config.Trading.Enabled {
+		mode = fmt.Sprintf("自动交易（模拟: %v，每次 %s USDT）", a.Config.Executor.Paper, a.amount)
+	}
+	a.logger.Infof("套利系统已启动: %d 个交易所，%d 个交易对，%s", len(a.Feed.Exchanges()), len(a.Config.Symbols), mode)
+	return nil
+}
+
+// Stop 逆序停止：暂停扫描并停止提交新的执行 → 等待执行中的套利结束（最多 DrainTimeout 或 ctx 取消）→
+// 停止执行器 → 断开交易所 → 保存进行中的套利机会记录并关闭执行日志 → 停止参数热更新
+func (a *App) Stop(ctx context.Context) {
+	if a.Scanner.Running() {
+		a.Scanner.Pause()
+	}
+
+	a.mu.Lock()
+	a.accepting = false
+	a.mu.Unlock()
+	a.drain(ctx)
+
+	a.Executor.Stop()
+	a.Feed.Stop()
+
+	if err := a.recorder.Flush(ctx); err != nil {
+		a.logger.Errorf("保存套利机会记录失败: %v", err)
+	}
+	if a.journal != nil {
+		a.journal.Close()
+	}
+	a.stopReloader()
+	a.logger.Info("套利系统已停止")
+}
+
+// stopReloader 停止参数热更新
+func (a *App) stopReloader() {
+	if a.reloader != nil {
+		a.reloader.Stop()
+	}
+}
+
+// drain 等待已提交的执行结束
+func (a *App) drain(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		a.inflight.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(a.Config.Trading.DrainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		a.logger.Errorf("等待执行结束超时（%s），未结束的执行由执行日志在下次启动时恢复", a.Config.Trading.DrainTimeout)
+	case <-ctx.Done():
+		a.logger.Errorf("等待执行结束被中断: %v", ctx.Err())
+	}
+}
+
+// onEvent 新出现的套利机会经风控检查后提交执行（OpportunityHook，执行在独立协程中进行，不阻塞扫描）
+func (a *App) onEvent(ctx context.Context, event *engine.OpportunityEvent) {
+	if event.Type != engine.OpportunityOpened {
+		return
+	}
+	opp := execution.FromEngineOpportunity(event.Opportunity)
+
+	a.mu.Lock()
+	if !a.accepting {
+		a.mu.Unlock()
+		return
+	}
+	_, span := tracing.Start(ctx, "risk.Check")
+	err := a.Risk.Check(opp, a.amount)
+	tracing.End(span, err)
+	if err != nil {
+		a.mu.Unlock()
+		a.logger.Infof("套利机会 %s 未执行: %v", opp.ID, err)
+		return
+	}
+	a.inflight.Add(1)
+	a.mu.Unlock()
+
+	// 执行不随扫描的 ctx 取消，但沿用其中发现机会的 span
+	execCtx := context.WithoutCancel(ctx)
+	a.logger.Infof("执行套利机会 %s: %s %s -> %s, 金额 %s USDT", opp.ID, opp.Symbol, opp.BuyExchange, opp.SellExchange, a.amount)
+	results, err := a.Executor.Submit(execCtx, opp, a.amount)
+	if err != nil {
+		a.inflight.Done()
+		a.Risk.Release(opp, a.amount)
+		a.logger.Errorf("提交套利机会 %s 失败: %v", opp.ID, err)
+		return
+	}
+
+	// 执行器受理后才标记机会已执行
+	if err := a.recorder.MarkExecuted(ctx, opp.ID); err != nil {
+		a.logger.Errorf("标记套利机会 %s 已执行失败: %v", opp.ID, err)
+	}
+
+	go func() {
+		defer a.inflight.Done()
+
+		result, err := a.Executor.Await(execCtx, results)
+		if err != nil {
+			a.logger.Errorf("执行套利机会 %s 失败: %v", opp.ID, err)
+			return
+		}
+		a.logger.Infof("套利机会 %s 执行结束: %s, 实际收益 %s USDT", opp.ID, result.Status, result.ActualProfit)
+	}()
+}
+
+// recordResult 保存执行结果（ResultHook）
+func (a *App) recordResult(result *execution.ExecutionResult) {
+	if err := a.Store.Executions.Save(context.Background(), result); err != nil {
+		a.logger.Errorf("保存执行结果 %s 失败: %v", result.ID, err)
+	}
+}
@@ -0,0 +1,197 @@
+// Package app 单进程模式单元测试
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arbitragex/cmd/arbitragex/internal/config"
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/exchange"
+	"arbitragex/pkg/execution"
+	"arbitragex/pkg/settings"
+
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+// tickerAdapter 模拟交易所适配器（订阅时推送一条固定行情）
+type tickerAdapter struct {
+	name     string
+	bid, ask string
+}
+
+func (m *tickerAdapter) GetName() string                  { return m.name }
+func (m *tickerAdapter) GetSupportedSymbols() []string    { return []string{"BTC/USDT"} }
+func (m *tickerAdapter) IsConnected() bool                { return true }
+func (m *tickerAdapter) Connect(context.Context) error    { return nil }
+func (m *tickerAdapter) Disconnect() error                { return nil }
+func (m *tickerAdapter) UnsubscribeTicker([]string) error { return nil }
+func (m *tickerAdapter) Ping(context.Context) error       { return nil }
+
+func (m *tickerAdapter) SubscribeTicker(ctx context.Context, symbols []string, handler exchange.TickerHandler) error {
+	handler(&exchange.Ticker{
+		Exchange:  m.name,
+		Symbol:    "BTC/USDT",
+		BidPrice:  decimal.RequireFromString(m.bid),
+		AskPrice:  decimal.RequireFromString(m.ask),
+		LastPrice: decimal.RequireFromString(m.bid),
+		Volume24h: decimal.NewFromInt(1000),
+		Timestamp: time.Now(),
+	})
+	return nil
+}
+
+func (m *tickerAdapter) GetTicker(ctx context.Context, symbol string) (*exchange.Ticker, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *tickerAdapter) GetTickers(ctx context.Context, symbols []string) ([]*exchange.Ticker, error) {
+	return nil, errors.New("not implemented")
+}
+
+// testConfig 测试配置：binance 买入、okx 卖出，价差 2%
+func testConfig(t *testing.T) config.Config {
+	t.Helper()
+
+	c := config.Config{
+		Name: "arbitragex-test",
+		Exchanges: settings.Exchanges{
+			{Name: "binance", Type: "cex", Enabled: true, MakerFee: 0.001, TakerFee: 0.001},
+			{Name: "okx", Type: "cex", Enabled: true, MakerFee: 0.001, TakerFee: 0.001},
+		},
+		Symbols:  []string{"BTC/USDT"},
+		PriceTTL: time.Minute,
+		Scan:     config.ScanConf{Interval: 20 * time.Millisecond},
+		Trading: config.TradingConf{
+			Enabled:      true,
+			Amount:       "100",
+			DrainTimeout: 5 * time.Second,
+		},
+		Risk: settings.RiskConf{
+			MaxTradeAmount:    "1000",
+			MaxRiskScore:      100,
+			MaxOpportunityAge: time.Minute,
+		},
+	}
+	for _, v := range []any{&c.Engine, &c.Executor} {
+		if err := conf.FillDefault(v); err != nil {
+			t.Fatalf("FillDefault() error = %v", err)
+		}
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	return c
+}
+
+// newTestApp 创建使用模拟行情和模拟盘执行器的单进程系统
+func newTestApp(t *testing.T, c config.Config) *App {
+	t.Helper()
+
+	level := func(price, amount string) []execution.OrderBookLevel {
+		return []execution.OrderBookLevel{{Price: decimal.RequireFromString(price), Amount: decimal.RequireFromString(amount)}}
+	}
+	binance := execution.NewPaperExecutor("binance", 0.001, 0.001)
+	binance.UpdateOrderBook(&execution.OrderBook{Exchange: "binance", Symbol: "BTC/USDT", Bids: level("49990", "1"), Asks: level("50000", "1")})
+	okx := execution.NewPaperExecutor("okx", 0.001, 0.001)
+	okx.UpdateOrderBook(&execution.OrderBook{Exchange: "okx", Symbol: "BTC/USDT", Bids: level("51000", "1"), Asks: level("51010", "1")})
+
+	a, err := NewWithComponents(c,
+		map[string]exchange.ExchangeAdapter{
+			"binance": &tickerAdapter{name: "binance", bid: "49990", ask: "50000"},
+			"okx":     &tickerAdapter{name: "okx", bid: "51000", ask: "51010"},
+		},
+		map[string]execution.OrderExecutor{"binance": binance, "okx": okx},
+	)
+	if err != nil {
+		t.Fatalf("NewWithComponents() error = %v", err)
+	}
+	return a
+}
+
+// TestApp_AutoExecute 测试新出现的套利机会经风控批准后执行，停止时等待执行结束并保存结果
+func TestApp_AutoExecute(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(t, testConfig(t))
+	if err := a.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for a.Risk.Stats().Approved == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	a.Stop(ctx)
+
+	// 同一套利机会持续存在期间只执行一次
+	stats := a.Risk.Stats()
+	if stats.Approved != 1 || !stats.Volume.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("Risk.Stats() = %+v, want 1 approved", stats)
+	}
+	results, err := a.Store.Executions.ListByStatus(ctx, "", 10)
+	if err != nil {
+		t.Fatalf("ListByStatus() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("保存了 %d 条执行结果, want 1", len(results))
+	}
+	result := results[0]
+	if result.Status != execution.ExecutionStatusCompleted || !result.ActualProfit.IsPositive() {
+		t.Errorf("result = %+v, want completed with profit", result)
+	}
+	if !stats.PnL.Equal(result.ActualProfit) {
+		t.Errorf("Risk.Stats().PnL = %s, want %s", stats.PnL, result.ActualProfit)
+	}
+
+	record, err := a.Store.Opportunities.Get(ctx, result.OpportunityID)
+	if err != nil {
+		t.Fatalf("Opportunities.Get() error = %v", err)
+	}
+	if !record.Executed {
+		t.Errorf("record.Executed = false, want true")
+	}
+}
+
+// TestApp_RiskRejected 测试风控拒绝的套利机会不提交执行
+func TestApp_RiskRejected(t *testing.T) {
+	ctx := context.Background()
+	c := testConfig(t)
+	c.Risk.MaxTradeAmount = "50"
+	a := newTestApp(t, c)
+	if err := a.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for a.Risk.Stats().Rejected == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	a.Stop(ctx)
+
+	if stats := a.Risk.Stats(); stats.Rejected != 1 || stats.Approved != 0 {
+		t.Errorf("Risk.Stats() = %+v, want 1 rejected", stats)
+	}
+	if results, _ := a.Store.Executions.ListByStatus(ctx, "", 10); len(results) != 0 {
+		t.Errorf("保存了 %d 条执行结果, want 0", len(results))
+	}
+}
+
+// TestNewWithComponents_InvalidConfig 测试无效的交易金额和风控限额
+func TestNewWithComponents_InvalidConfig(t *testing.T) {
+	for name, mutate := range map[string]func(*config.Config){
+		"交易金额为 0":  func(c *config.Config) { c.Trading.Amount = "0" },
+		"交易金额无效":   func(c *config.Config) { c.Trading.Amount = "abc" },
+		"单笔上限无效":   func(c *config.Config) { c.Risk.MaxTradeAmount = "abc" },
+		"当日亏损为负数":  func(c *config.Config) { c.Risk.MaxDailyLoss = "-1" },
+		"风险评分超出范围": func(c *config.Config) { c.Risk.MaxRiskScore = 120 },
+		"引擎阈值无效":   func(c *config.Config) { c.Engine.MinVolume = "0" },
+	} {
+		c := testConfig(t)
+		mutate(&c)
+		if _, err := NewWithComponents(c, nil, nil); err == nil {
+			t.Errorf("%s: NewWithComponents() error = nil", name)
+		}
+	}
+}
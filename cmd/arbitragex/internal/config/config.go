@@ -0,0 +1,106 @@
+// Package config 单进程模式配置（config/config.yaml）
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/settings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/prometheus"
+	"github.com/zeromicro/go-zero/core/trace"
+)
+
+type Config struct {
+	Name string `json:",default=arbitragex"`
+
+	// Log 日志配置
+	Log logx.LogConf
+
+	// Prometheus 指标（Host 为空时不启动，如 Host: 0.0.0.0、Port: 9101 暴露 /metrics）
+	Prometheus prometheus.Config
+
+	// Telemetry 链路追踪（OpenTelemetry，配置 Endpoint 后导出，Name 为空时使用 Name）
+	Telemetry trace.Config `json:",optional"`
+
+	// Exchanges 交易所（只连接 Enabled 的 CEX）
+	Exchanges settings.Exchanges
+
+	// Symbols 监控的交易对（标准格式，如 BTC/USDT）
+	Symbols []string `json:",default=[BTC/USDT,ETH/USDT]"`
+
+	// PriceTTL 价格缓存有效期（超过后不参与扫描）
+	PriceTTL time.Duration `json:",default=5s"`
+
+	// Scan 扫描循环配置
+	Scan ScanConf
+
+	// Engine 套利引擎阈值
+	Engine settings.EngineConf
+
+	// Executor 套利执行器
+	Executor settings.ExecutorConf
+
+	// Credentials API 密钥来源（环境变量或加密密钥库，真实交易需要）
+	Credentials settings.CredentialConf
+
+	// Trading 自动交易配置
+	Trading TradingConf
+
+	// Risk 交易前风控
+	Risk settings.RiskConf
+
+	// Reload 运行中热更新引擎阈值、手续费率和风控限额（参数文件或 system_config 表）
+	Reload settings.ReloadConf
+
+	// MySQL 持久化（为空时套利机会和执行记录只保存在内存中）
+	MySQL settings.MySQLConf `json:",optional"`
+}
+
+// ScanConf 扫描循环配置
+type ScanConf struct {
+	Interval time.Duration `json:",default=1s"` // 扫描间隔
+}
+
+// TradingConf 自动交易配置
+type TradingConf struct {
+	Enabled      bool          `json:",default=false"` // 是否自动执行新出现的套利机会（关闭时只监控）
+	Amount       string        `json:",default=100"`   // 每次执行的金额（USDT）
+	DrainTimeout time.Duration `json:",default=30s"`   // 停止时等待执行中的套利结束的最长时间
+}
+
+// Validate 校验配置（settings.Load 加载后调用）
+func (c Config) Validate() error {
+	if err := c.Exchanges.Validate(); err != nil {
+		return err
+	}
+	if len(c.Symbols) == 0 {
+		return fmt.Errorf("Symbols: 至少配置一个交易对")
+	}
+	if c.Scan.Interval <= 0 {
+		return fmt.Errorf("Scan.Interval 必须大于 0: %s", c.Scan.Interval)
+	}
+	if _, err := c.Engine.EngineConfig(c.Exchanges.TradingFees()); err != nil {
+		return err
+	}
+	if err := c.Executor.Validate(); err != nil {
+		return err
+	}
+	if err := c.Credentials.Validate(); err != nil {
+		return err
+	}
+	if !c.Executor.Paper {
+		if err := c.Exchanges.ValidateCredentials(c.Credentials); err != nil {
+			return err
+		}
+	}
+	if amount, err := decimal.NewFromString(c.Trading.Amount); err != nil || !amount.IsPositive() {
+		return fmt.Errorf("无效的 Trading.Amount: %q", c.Trading.Amount)
+	}
+	if _, err := c.Risk.Limits(); err != nil {
+		return err
+	}
+	return c.Reload.Validate(c.MySQL)
+}
@@ -0,0 +1,51 @@
+// Package config 单进程模式配置单元测试
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"arbitragex/pkg/settings"
+)
+
+// TestLoad 测试加载仓库中的配置文件
+func TestLoad(t *testing.T) {
+	var c Config
+	if err := settings.Load("../../../../config/config.yaml", &c); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := c.Exchanges.Enabled().Names(); len(got) != 2 || got[0] != "binance" || got[1] != "okx" {
+		t.Errorf("Exchanges.Enabled() = %v, want [binance okx]", got)
+	}
+	if c.Trading.Amount != "100" || c.Risk.MaxTradeAmount != "1000" || c.Executor.QueueSize != 1000 || c.Engine.MinVolume != "1000" {
+		t.Errorf("Trading = %+v, Risk = %+v, Executor = %+v, Engine = %+v", c.Trading, c.Risk, c.Executor, c.Engine)
+	}
+}
+
+// TestLoad_Invalid 测试加载时校验配置
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"没有启用的交易所", "Exchanges:\n  - Name: binance\n    Enabled: false\n"},
+		{"交易所重复", "Exchanges:\n  - Name: binance\n  - Name: Binance\n"},
+		{"手续费率无效", "Exchanges:\n  - Name: binance\n    TakerFee: 1.5\n"},
+		{"引擎阈值无效", "Exchanges:\n  - Name: binance\nEngine:\n  MinVolume: abc\n"},
+		{"队列长度为 0", "Exchanges:\n  - Name: binance\nExecutor:\n  QueueSize: 0\n"},
+		{"真实交易缺少密钥", "Exchanges:\n  - Name: binance\nExecutor:\n  Paper: false\n"},
+		{"交易金额无效", "Exchanges:\n  - Name: binance\nTrading:\n  Amount: \"-1\"\n"},
+		{"风控限额无效", "Exchanges:\n  - Name: binance\nRisk:\n  MaxDailyLoss: \"-1\"\n"},
+	}
+	for _, tt := range tests {
+		file := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(file, []byte(tt.yaml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		var c Config
+		if err := settings.Load(file, &c); err == nil {
+			t.Errorf("%s: Load() error = nil", tt.name)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Package main 单进程模式
+// 在一个进程内运行行情源、价格缓存、套利引擎、风控和并发执行器，适用于本地开发和小规模部署：
+//
+//	arbitragex -f config/config.yaml
+//
+// 收到 SIGINT / SIGTERM 后停止扫描，等待执行中的套利结束再退出；再次收到信号时立即退出
+package main
+
+import (
+	"context"
+	"flag"
+	"os/signal"
+	"syscall"
+
+	"arbitragex/cmd/arbitragex/internal/app"
+	"arbitragex/cmd/arbitragex/internal/config"
+	"arbitragex/pkg/settings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/prometheus"
+	"github.com/zeromicro/go-zero/core/trace"
+)
+
+var configFile = flag.String("f", "config/config.yaml", "the config file")
+
+func main() {
+	flag.Parse()
+
+	// 加载并校验配置文件（${VAR} 按环境变量展开，交易所密钥可由 <NAME>_API_KEY 等环境变量覆盖）
+	var c config.Config
+	settings.MustLoad(*configFile, &c)
+	logx.MustSetup(c.Log)
+	defer logx.Close()
+	prometheus.StartAgent(c.Prometheus)
+	if c.Telemetry.Name == "" {
+		c.Telemetry.Name = c.Name
+	}
+	trace.StartAgent(c.Telemetry)
+	defer trace.StopAgent()
+
+	// 组装各组件
+	a, err := app.New(c)
+	logx.Must(err)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// 按依赖顺序启动
+	logx.Must(a.Start(ctx))
+
+	// 等待退出信号，之后恢复默认的信号处理（再次收到信号时立即退出）
+	<-ctx.Done()
+	stop()
+	logx.Info("收到退出信号，正在停止...")
+
+	// 逆序停止，等待执行中的套利结束
+	a.Stop(context.Background())
+}
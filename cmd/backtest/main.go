@@ -0,0 +1,198 @@
+// Package main 回测工具
+// 把录制的行情文件（JSON Lines，见 pkg/marketdata）在模拟时钟上回放给套利引擎，按模拟成交统计收益。
+// 参数支持逗号分隔的多个取值，会对所有组合分别回测：
+//
+//	backtest -data binance.jsonl,okx.jsonl -min-profit-rate 0.003,0.005 -slippage 0.0005,0.001 -min-volume 500,1000
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/backtest"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/marketdata"
+)
+
+func main() {
+	data := flag.String("data", "", "行情文件（逗号分隔，支持通配符和 cmd/recorder 录制的 .jsonl.gz 文件）")
+	symbols := flag.String("symbols", "", "扫描的交易对（逗号分隔，默认为行情中出现的全部交易对）")
+	minProfitRates := flag.String("min-profit-rate", "0.005", "最小收益率（逗号分隔多个取值）")
+	slippageRates := flag.String("slippage", "0.001", "滑点率（逗号分隔多个取值）")
+	minVolumes := flag.String("min-volume", "1000", "每笔交易金额 USDT（逗号分隔多个取值）")
+	minConfirmations := flag.Int("confirmations", 1, "连续扫描到多少次才交易")
+	scanInterval := flag.Duration("scan-interval", time.Second, "扫描间隔（模拟时间）")
+	latency := flag.Duration("latency", 100*time.Millisecond, "发现机会到成交的延迟（模拟时间）")
+	showTrades := flag.Bool("trades", false, "输出每笔交易")
+	jsonOutput := flag.Bool("json", false, "以 JSON 输出完整报告")
+	flag.Parse()
+
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	if *data == "" {
+		log.Fatal("必须指定 -data")
+	}
+	paths, err := expandPaths(splitList(*data))
+	if err != nil {
+		log.Fatalf("解析 -data 失败: %v", err)
+	}
+
+	profitRates, err := parseFloats(*minProfitRates)
+	if err != nil {
+		log.Fatalf("解析 -min-profit-rate 失败: %v", err)
+	}
+	slippages, err := parseFloats(*slippageRates)
+	if err != nil {
+		log.Fatalf("解析 -slippage 失败: %v", err)
+	}
+	volumes, err := parseDecimals(*minVolumes)
+	if err != nil {
+		log.Fatalf("解析 -min-volume 失败: %v", err)
+	}
+
+	var reports []*backtest.Report
+	for _, profitRate := range profitRates {
+		for _, slippage := range slippages {
+			for _, volume := range volumes {
+				engineConfig := engine.DefaultEngineConfig()
+				engineConfig.MinProfitRate = profitRate
+				engineConfig.SlippageRate = slippage
+				engineConfig.MinVolume = volume
+				engineConfig.MinConfirmations = *minConfirmations
+
+				config := backtest.DefaultConfig()
+				config.Engine = engineConfig
+				config.Symbols = splitList(*symbols)
+				config.ScanInterval = *scanInterval
+				config.ExecutionLatency = *latency
+
+				report, err := run(paths, config)
+				if err != nil {
+					log.Fatalf("回测失败: %v", err)
+				}
+				reports = append(reports, report)
+			}
+		}
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			log.Fatalf("输出报告失败: %v", err)
+		}
+		return
+	}
+
+	printSummary(reports)
+	if *showTrades {
+		for _, report := range reports {
+			printTrades(report)
+		}
+	}
+}
+
+// run 打开行情文件执行一次回测（每组参数重新读取文件）
+func run(paths []string, config *backtest.Config) (*backtest.Report, error) {
+	reader, closeFiles, err := marketdata.OpenFiles(paths...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFiles()
+
+	return backtest.Run(context.Background(), reader, config)
+}
+
+// printSummary 每组参数输出一行汇总
+func printSummary(reports []*backtest.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "min_profit_rate\tslippage\tmin_volume\topportunities\ttrades\thit_rate\tgross\tfees\tnet_pnl\tfee_drag\tavg_latency\t")
+	for _, r := range reports {
+		fmt.Fprintf(w, "%.4f\t%.4f\t%.0f\t%d\t%d\t%.1f%%\t%.4f\t%.4f\t%.4f\t%.1f%%\t%s\t\n",
+			r.MinProfitRate, r.SlippageRate, r.MinVolume, r.Opportunities, len(r.Trades),
+			r.HitRate*100, r.GrossProfit, r.Fees, r.NetProfit, r.FeeDrag*100, r.AvgLatency)
+	}
+	w.Flush()
+}
+
+// printTrades 输出每笔交易
+func printTrades(report *backtest.Report) {
+	fmt.Printf("\nmin_profit_rate=%.4f slippage=%.4f min_volume=%.0f\n", report.MinProfitRate, report.SlippageRate, report.MinVolume)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "executed_at\tsymbol\tbuy\tsell\texpected\tnet_pnl\tfees\tlatency\torders")
+	for _, t := range report.Trades {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.4f\t%.4f\t%.4f\t%s\t%d\n",
+			t.ExecutedAt.Format("2006-01-02 15:04:05.000"), t.Symbol, t.BuyExchange, t.SellExchange,
+			t.ExpectedProfit, t.NetProfit, t.Fees, t.Latency(), len(t.Orders))
+	}
+	w.Flush()
+}
+
+// expandPaths 展开通配符（如 data/binance-*.jsonl.gz）
+func expandPaths(patterns []string) ([]string, error) {
+	var paths []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("没有匹配的文件: %s", pattern)
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// splitList 解析逗号分隔的列表（忽略空项）
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseFloats 解析逗号分隔的数值列表
+func parseFloats(s string) ([]float64, error) {
+	var values []float64
+	for _, v := range splitList(s) {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, f)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("至少需要一个取值")
+	}
+	return values, nil
+}
+
+// parseDecimals 解析逗号分隔的金额列表
+func parseDecimals(s string) ([]decimal.Decimal, error) {
+	var values []decimal.Decimal
+	for _, v := range splitList(s) {
+		d, err := decimal.NewFromString(v)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, d)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("至少需要一个取值")
+	}
+	return values, nil
+}
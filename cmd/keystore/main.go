@@ -0,0 +1,212 @@
+// Package main 加密密钥库管理工具
+// 创建和修改交易所 API 密钥库（scrypt + AES-256-GCM），配置 Credentials.Keystore 后服务从密钥库读取密钥
+//
+//	keystore -file config/keystore.json list
+//	keystore -file config/keystore.json set -exchange binance -account sub1
+//	keystore -file config/keystore.json delete -exchange binance -account sub1
+//
+// 口令从环境变量 ARBITRAGEX_KEYSTORE_PASSPHRASE 读取，未设置时在终端输入；
+// set 的 API Key、Secret 和 Passphrase 在终端输入（不回显），非终端时按行从标准输入读取
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"arbitragex/pkg/credential"
+
+	"golang.org/x/term"
+)
+
+const passphraseEnv = "ARBITRAGEX_KEYSTORE_PASSPHRASE"
+
+func main() {
+	file := flag.String("file", "config/keystore.json", "密钥库文件")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "用法: %s [-file keystore.json] list|set|delete [-exchange name] [-account name]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	log.SetFlags(0)
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	command := flag.Arg(0)
+	args := flag.NewFlagSet(command, flag.ExitOnError)
+	exchange := args.String("exchange", "", "交易所名称（binance、okx）")
+	account := args.String("account", credential.DefaultAccount, "子账户")
+	args.Parse(flag.Args()[1:])
+
+	// 出错时先返回，等 run 中的 defer 清零口令和密钥后再退出
+	if err := run(command, *file, *exchange, *account, bufio.NewReader(os.Stdin)); err != nil {
+		if errors.Is(err, errUsage) {
+			flag.Usage()
+			os.Exit(2)
+		}
+		log.Fatal(err)
+	}
+}
+
+// errUsage 命令无效（输出用法后退出）
+var errUsage = errors.New("无效的命令")
+
+// run 执行密钥库命令
+// 参数:
+//   - command: list、set 或 delete
+//   - file: 密钥库文件
+//   - exchange: 交易所名称（set 和 delete 需要）
+//   - account: 子账户
+//   - input: 非终端时读取口令和密钥的输入
+// 返回:
+//   - error: 命令失败的原因（口令和密钥已清零）
+func run(command, file, exchange, account string, input *bufio.Reader) error {
+	switch command {
+	case "list":
+		keystore, passphrase, err := open(file, input, false)
+		if err != nil {
+			return err
+		}
+		defer credential.Wipe(passphrase)
+		defer keystore.Close()
+
+		for _, name := range keystore.Accounts() {
+			fmt.Println(name)
+		}
+
+	case "set":
+		if exchange == "" {
+			return errors.New("set 需要 -exchange")
+		}
+		keystore, passphrase, err := open(file, input, true)
+		if err != nil {
+			return err
+		}
+		defer credential.Wipe(passphrase)
+		defer keystore.Close()
+
+		secret := &credential.Credential{}
+		defer secret.Zero()
+		if secret.APIKey, err = readSecret(input, "API Key: "); err != nil {
+			return err
+		}
+		if secret.APISecret, err = readSecret(input, "API Secret: "); err != nil {
+			return err
+		}
+		if secret.Passphrase, err = readSecret(input, "Passphrase（OKX 需要，其他交易所直接回车）: "); err != nil {
+			return err
+		}
+		if len(secret.APIKey) == 0 || len(secret.APISecret) == 0 {
+			return errors.New("API Key 和 Secret 不能为空")
+		}
+
+		keystore.Set(exchange, account, secret)
+		if err := keystore.Save(file, passphrase); err != nil {
+			return fmt.Errorf("保存密钥库失败: %w", err)
+		}
+		fmt.Printf("已保存 %s/%s 的密钥（%s）\n", strings.ToLower(exchange), account, secret)
+
+	case "delete":
+		if exchange == "" {
+			return errors.New("delete 需要 -exchange")
+		}
+		keystore, passphrase, err := open(file, input, false)
+		if err != nil {
+			return err
+		}
+		defer credential.Wipe(passphrase)
+		defer keystore.Close()
+
+		if !keystore.Delete(exchange, account) {
+			return fmt.Errorf("密钥库中没有 %s/%s", strings.ToLower(exchange), account)
+		}
+		if err := keystore.Save(file, passphrase); err != nil {
+			return fmt.Errorf("保存密钥库失败: %w", err)
+		}
+		fmt.Printf("已删除 %s/%s 的密钥\n", strings.ToLower(exchange), account)
+
+	default:
+		return errUsage
+	}
+	return nil
+}
+
+// open 读取口令并打开密钥库
+// 参数:
+//   - create: 文件不存在时创建空的密钥库（新口令需要输入两次确认）
+// 返回:
+//   - *credential.Keystore: 已解密的密钥库
+//   - []byte: 口令（保存时使用，调用方使用完后清零）
+//   - error: 失败时返回（口令已清零）
+func open(file string, input *bufio.Reader, create bool) (*credential.Keystore, []byte, error) {
+	_, err := os.Stat(file)
+	exists := err == nil
+	if !exists && !(create && errors.Is(err, os.ErrNotExist)) {
+		return nil, nil, fmt.Errorf("打开密钥库失败: %w", err)
+	}
+
+	passphrase := []byte(os.Getenv(passphraseEnv))
+	if len(passphrase) == 0 {
+		if passphrase, err = readSecret(input, "密钥库口令: "); err != nil {
+			return nil, nil, err
+		}
+		if !exists {
+			confirm, err := readSecret(input, "再次输入口令: ")
+			equal := err == nil && bytes.Equal(passphrase, confirm)
+			credential.Wipe(confirm)
+			if err != nil {
+				credential.Wipe(passphrase)
+				return nil, nil, err
+			}
+			if !equal {
+				credential.Wipe(passphrase)
+				return nil, nil, errors.New("两次输入的口令不一致")
+			}
+		}
+	}
+	if len(passphrase) == 0 {
+		return nil, nil, errors.New("密钥库口令不能为空")
+	}
+
+	if !exists {
+		return credential.NewKeystore(), passphrase, nil
+	}
+	keystore, err := credential.OpenKeystore(file, passphrase)
+	if err != nil {
+		credential.Wipe(passphrase)
+		return nil, nil, fmt.Errorf("打开密钥库失败: %w", err)
+	}
+	return keystore, passphrase, nil
+}
+
+// readSecret 读取一行敏感输入（终端不回显）
+func readSecret(input *bufio.Reader, prompt string) ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, prompt)
+		secret, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			credential.Wipe(secret)
+			return nil, fmt.Errorf("读取输入失败: %w", err)
+		}
+		return secret, nil
+	}
+
+	line, err := input.ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return nil, nil
+	}
+	secret := append([]byte(nil), bytes.TrimRight(line, "\r\n")...)
+	credential.Wipe(line)
+	return secret, nil
+}
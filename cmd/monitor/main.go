@@ -1,5 +1,10 @@
 // Package main 实时监控程序
 // 监控多个交易所的小币种价格，识别套利机会
+//
+// 设置环境变量 MYSQL_DSN 时，扫描到的套利机会会记录到 arbitrage_opportunities 表
+//
+// 设置环境变量 REPLAY_DATA（录制文件，逗号分隔，支持通配符）时不连接交易所，改为回放录制的行情；
+// REPLAY_SPEED 为回放倍速（默认 1，0 表示尽快回放）
 package main
 
 import (
@@ -8,13 +13,17 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"arbitragex/common/cache"
 	"arbitragex/pkg/engine"
-	"arbitragex/pkg/exchange"
+	"arbitragex/pkg/feed"
+	"arbitragex/pkg/store"
 )
 
 var (
@@ -41,12 +50,16 @@ var (
 	// 套利引擎
 	arbitrageEngine *engine.ArbitrageEngine
 
-	// 交易所适配器
-	adapters map[string]exchange.ExchangeAdapter
+	// 行情源
+	priceFeed *feed.Feed
 
 	// 运行状态
 	running = true
 
+	// 回放的录制文件和倍速（未设置 REPLAY_DATA 时连接交易所）
+	replayFiles []string
+	replaySpeed float64
+
 	// 统计数据
 	stats struct {
 		sync.RWMutex
@@ -76,8 +89,22 @@ func main() {
 	config := engine.DefaultEngineConfig()
 	arbitrageEngine = engine.NewArbitrageEngine(config, priceCache)
 
-	// 初始化交易所适配器
-	adapters = make(map[string]exchange.ExchangeAdapter)
+	// 记录套利机会（可选）
+	var recorder *store.OpportunityRecorder
+	if dsn := os.Getenv("MYSQL_DSN"); dsn != "" {
+		recorder = store.NewOpportunityRecorder(store.NewMySQLStoreFromDSN(dsn).Opportunities)
+		arbitrageEngine.OnEvent(recorder.Record)
+		log.Println("📝 套利机会将记录到 MySQL")
+	}
+
+	// 回放录制的行情（可选）
+	if data := os.Getenv("REPLAY_DATA"); data != "" {
+		var err error
+		if replayFiles, replaySpeed, err = replayConfig(data, os.Getenv("REPLAY_SPEED")); err != nil {
+			log.Fatalf("回放配置错误: %v", err)
+		}
+		log.Printf("⏪ 回放 %d 个录制文件（%.1f 倍速）", len(replayFiles), replaySpeed)
+	}
 
 	// 启动交易所连接
 	if err := startExchanges(ctx); err != nil {
@@ -107,181 +134,82 @@ func main() {
 	log.Println("正在停止监控系统...")
 	running = false
 	cancel()
+	priceFeed.Stop()
 
 	// 等待所有协程退出
 	time.Sleep(1 * time.Second)
 
+	if recorder != nil {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := recorder.Flush(flushCtx); err != nil {
+			log.Printf("⚠️  保存套利机会记录失败: %v", err)
+		}
+		flushCancel()
+	}
+
 	log.Println("✅ 监控系统已停止")
 }
 
 // startExchanges 启动交易所连接
+// 行情源连接各交易所（或回放录制文件），行情写入价格缓存
 func startExchanges(ctx context.Context) error {
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(exchanges))
-
-	for _, ex := range exchanges {
-		wg.Add(1)
-		go func(exchangeName string) {
-			defer wg.Done()
-
-			var adapter exchange.ExchangeAdapter
-			var err error
-
-			switch exchangeName {
-			case "binance":
-				adapter, err = createBinanceAdapter()
-			case "okx":
-				adapter, err = createOKXAdapter()
-			default:
-				log.Printf("⚠️  不支持的交易所: %s", exchangeName)
-				return
-			}
+	priceFeed = feed.New(priceCache, symbols)
+	priceFeed.OnPrice(onPriceUpdate)
 
+	if replayFiles != nil {
+		for name, adapter := range feed.NewReplayAdapters(exchanges, symbols, replayFiles, replaySpeed) {
+			priceFeed.AddAdapter(name, adapter)
+		}
+	} else {
+		for _, name := range exchanges {
+			adapter, err := feed.NewAdapter(name, symbols)
 			if err != nil {
-				log.Printf("❌ 创建 %s 适配器失败: %v", exchangeName, err)
-				errChan <- err
-				return
+				log.Printf("⚠️  %v", err)
+				continue
 			}
-
-			// 启动连接
-			if err := adapter.Connect(ctx); err != nil {
-				log.Printf("❌ %s 连接失败: %v", exchangeName, err)
-				errChan <- err
-				return
-			}
-
-			// 订阅所有交易对的价格
-			// 将符号格式转换为交易所格式（例如：BTC/USDT -> BTCUSDT）
-			formattedSymbols := formatSymbolsForExchange(symbols, exchangeName)
-
-			if err := adapter.SubscribeTicker(ctx, formattedSymbols, func(ticker *exchange.Ticker) {
-				onPriceUpdate(exchangeName, ticker)
-			}); err != nil {
-				log.Printf("❌ %s 订阅失败: %v", exchangeName, err)
-				errChan <- err
-				return
-			}
-
-			log.Printf("✅ %s WebSocket 已连接", exchangeName)
-			adapters[exchangeName] = adapter
-		}(ex)
+			priceFeed.AddAdapter(name, adapter)
+		}
 	}
 
-	// 等待所有交易所启动
-	wg.Wait()
-	close(errChan)
-
-	// 检查错误
-	for err := range errChan {
-		if err != nil {
-			return fmt.Errorf("交易所启动失败: %w", err)
+	if err := priceFeed.Start(ctx); err != nil {
+		return fmt.Errorf("交易所启动失败: %w", err)
+	}
+	for _, status := range priceFeed.Status() {
+		if status.Error != "" {
+			log.Printf("❌ %s", status.Error)
+		} else {
+			log.Printf("✅ %s WebSocket 已连接", status.Name)
 		}
 	}
-
 	return nil
 }
 
-// createBinanceAdapter 创建 Binance 适配器
-func createBinanceAdapter() (exchange.ExchangeAdapter, error) {
-	config := &exchange.ExchangeConfig{
-		Name: "binance",
-		WebSocket: exchange.WebSocketConfig{
-			ExchangeName: "binance",
-			BaseURL:      "wss://stream.binance.com:9443/ws",
-			PingInterval: 30 * time.Second,
-			Reconnect:    false,
-		},
-		REST: exchange.RESTConfig{
-			BaseURL:    "https://api.binance.com",
-			Timeout:    10 * time.Second,
-			MaxRetries: 3,
-		},
-		Symbols: formatSymbolsForExchange(symbols, "binance"),
-		Enabled: true,
-	}
-
-	return exchange.NewBinanceAdapter(config), nil
-}
-
-// createOKXAdapter 创建 OKX 适配器
-func createOKXAdapter() (exchange.ExchangeAdapter, error) {
-	config := &exchange.ExchangeConfig{
-		Name: "okx",
-		WebSocket: exchange.WebSocketConfig{
-			ExchangeName: "okx",
-			BaseURL:      "wss://ws.okx.com:8443/ws/v5/public",
-			PingInterval: 30 * time.Second,
-			Reconnect:    false,
-		},
-		REST: exchange.RESTConfig{
-			BaseURL:    "https://www.okx.com",
-			Timeout:    10 * time.Second,
-			MaxRetries: 3,
-		},
-		Symbols: formatSymbolsForExchange(symbols, "okx"),
-		Enabled: true,
+// replayConfig 解析回放的录制文件（逗号分隔，支持通配符）和倍速
+func replayConfig(data, speed string) ([]string, float64, error) {
+	var files []string
+	for _, pattern := range strings.Split(data, ",") {
+		matches, err := filepath.Glob(strings.TrimSpace(pattern))
+		if err != nil {
+			return nil, 0, err
+		}
+		files = append(files, matches...)
 	}
-
-	return exchange.NewOKXAdapter(config), nil
-}
-
-// formatSymbolsForExchange 将符号格式转换为交易所格式
-func formatSymbolsForExchange(symbols []string, exchangeName string) []string {
-	formatted := make([]string, len(symbols))
-	for i, symbol := range symbols {
-		// BTC/USDT -> BTCUSDT
-		formatted[i] = formatSymbol(symbol, exchangeName)
+	if len(files) == 0 {
+		return nil, 0, fmt.Errorf("没有匹配的录制文件: %s", data)
 	}
-	return formatted
-}
 
-// formatSymbol 格式化单个符号
-func formatSymbol(symbol string, exchangeName string) string {
-	switch exchangeName {
-	case "binance":
-		// Binance 格式: BTC/USDT -> BTCUSDT（移除斜杠）
-		return replaceAll(symbol, "/", "")
-	case "okx":
-		// OKX 格式: BTC/USDT -> BTC-USDT（使用连字符）
-		return replaceAll(symbol, "/", "-")
-	default:
-		// 默认移除斜杠
-		return replaceAll(symbol, "/", "")
+	if speed == "" {
+		return files, 1, nil
 	}
-}
-
-// replaceAll 替换字符串
-func replaceAll(s, old, new string) string {
-	result := ""
-	for i := 0; i < len(s); i++ {
-		if i+len(old) <= len(s) && s[i:i+len(old)] == old {
-			result += new
-			i += len(old) - 1
-		} else {
-			result += string(s[i])
-		}
+	multiplier, err := strconv.ParseFloat(speed, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("无效的 REPLAY_SPEED: %w", err)
 	}
-	return result
+	return files, multiplier, nil
 }
 
-// onPriceUpdate 价格更新回调
-func onPriceUpdate(exchange string, ticker *exchange.Ticker) {
-	// 存储到价格缓存
-	priceData := &cache.PriceData{
-		Exchange:  exchange,
-		Symbol:    ticker.Symbol,
-		BidPrice:  ticker.BidPrice,
-		AskPrice:  ticker.AskPrice,
-		LastPrice: ticker.LastPrice,
-		Volume24h: ticker.Volume24h,
-		Timestamp: ticker.Timestamp,
-	}
-
-	if err := priceCache.SetPrice(context.Background(), exchange, ticker.Symbol, priceData); err != nil {
-		log.Printf("⚠️  存储价格失败: %v", err)
-		return
-	}
-
+// onPriceUpdate 价格更新回调（行情已写入价格缓存）
+func onPriceUpdate(price *cache.PriceData) {
 	// 更新统计
 	stats.Lock()
 	stats.priceUpdates++
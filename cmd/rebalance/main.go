@@ -0,0 +1,107 @@
+// Package main 持仓再平衡工具
+// 默认以演练模式输出再平衡计划（JSON），加 -execute 才会真正提币
+//
+//	rebalance -f config/config.yaml [-execute] [-threshold 0.2]
+//
+// 交易所和 API 密钥来源读取自配置文件的 Exchanges 和 Credentials（与其他服务相同，
+// 密钥从环境变量 <NAME>_API_KEY 等或加密密钥库读取）
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"arbitragex/pkg/execution"
+	"arbitragex/pkg/rebalance"
+	"arbitragex/pkg/settings"
+)
+
+// Config 再平衡工具的配置（与单进程模式共用配置文件，只读取交易所和密钥来源）
+type Config struct {
+	// Exchanges 交易所（在已启用的交易所之间再平衡）
+	Exchanges settings.Exchanges
+
+	// Credentials API 密钥来源（查询余额和提币需要）
+	Credentials settings.CredentialConf
+}
+
+// Validate 校验配置（settings.Load 加载后调用）
+func (c Config) Validate() error {
+	if err := c.Exchanges.Validate(); err != nil {
+		return err
+	}
+	if err := c.Credentials.Validate(); err != nil {
+		return err
+	}
+	return c.Exchanges.ValidateCredentials(c.Credentials)
+}
+
+var configFile = flag.String("f", "config/config.yaml", "the config file")
+
+func main() {
+	execute := flag.Bool("execute", false, "执行再平衡计划（默认仅输出计划）")
+	threshold := flag.Float64("threshold", 0.2, "触发再平衡的偏离阈值")
+	flag.Parse()
+
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	var c Config
+	settings.MustLoad(*configFile, &c)
+
+	executors, err := newTransferExecutors(c)
+	if err != nil {
+		log.Fatalf("创建交易所执行器失败: %v", err)
+	}
+
+	config := rebalance.DefaultConfig()
+	config.Exchanges = c.Exchanges.Enabled().Names()
+	config.DryRun = !*execute
+	for _, asset := range config.Assets {
+		asset.Threshold = *threshold
+	}
+
+	rebalancer := rebalance.NewRebalancer(config, executors)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	plan, err := rebalancer.Plan(ctx)
+	if err != nil {
+		log.Fatalf("生成再平衡计划失败: %v", err)
+	}
+
+	if !plan.DryRun && len(plan.Transfers) > 0 {
+		if err := rebalancer.Execute(ctx, plan); err != nil {
+			log.Printf("执行再平衡计划失败: %v", err)
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(plan); err != nil {
+		log.Fatalf("输出计划失败: %v", err)
+	}
+}
+
+// newTransferExecutors 为已启用的交易所创建支持查询余额和提币的执行器（exchange -> executor）
+func newTransferExecutors(c Config) (map[string]execution.TransferExecutor, error) {
+	executors, err := c.Exchanges.NewExecutors(settings.ExecutorConf{Paper: false}, c.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := make(map[string]execution.TransferExecutor, len(executors))
+	for name, executor := range executors {
+		transfer, ok := executor.(execution.TransferExecutor)
+		if !ok {
+			return nil, fmt.Errorf("%s 不支持资金划转", name)
+		}
+		transfers[name] = transfer
+	}
+	return transfers, nil
+}
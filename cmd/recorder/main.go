@@ -0,0 +1,229 @@
+// Package main 行情录制工具
+// 通过交易所适配器订阅行情，把每条价格行情（连同原始 WebSocket 消息、交易所时间和本地接收时间）
+// 写入按时间和大小切分的 gzip 压缩 JSON Lines 文件，供回测（cmd/backtest）和复现解析问题使用。
+// 可选按固定间隔通过 REST 拉取订单簿快照一并录制：
+//
+//	recorder -out data/ -symbols BTC/USDT,ETH/USDT -rotate 1h -book-interval 1s
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/pkg/exchange"
+	"arbitragex/pkg/execution"
+	"arbitragex/pkg/marketdata"
+)
+
+// 录制计数
+var (
+	tickerCount    int64
+	orderBookCount int64
+	writeErrors    int64
+)
+
+func main() {
+	out := flag.String("out", "marketdata", "输出目录")
+	prefix := flag.String("prefix", "marketdata", "文件名前缀")
+	symbolList := flag.String("symbols", "BTC/USDT,ETH/USDT", "录制的交易对（逗号分隔）")
+	exchangeList := flag.String("exchanges", "binance,okx", "录制的交易所（逗号分隔）")
+	rotate := flag.Duration("rotate", time.Hour, "按时间切分文件（0 表示不切分）")
+	maxSize := flag.Int64("max-size", 256, "单个文件压缩后的大小上限 MB（0 表示不限制）")
+	flushInterval := flag.Duration("flush", time.Second, "写盘间隔")
+	bookInterval := flag.Duration("book-interval", 0, "通过 REST 拉取订单簿快照的间隔（0 表示不录制订单簿）")
+	flag.Parse()
+
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	symbols := splitList(*symbolList)
+	exchanges := splitList(*exchangeList)
+	if len(symbols) == 0 || len(exchanges) == 0 {
+		log.Fatal("必须指定 -symbols 和 -exchanges")
+	}
+
+	writer, err := marketdata.NewFileWriter(marketdata.FileWriterConfig{
+		Dir:            *out,
+		Prefix:         *prefix,
+		RotateInterval: *rotate,
+		MaxBytes:       *maxSize * 1024 * 1024,
+	})
+	if err != nil {
+		log.Fatalf("创建录制文件失败: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var adapters []exchange.ExchangeAdapter
+	for _, name := range exchanges {
+		adapter, err := subscribe(ctx, name, symbols, writer)
+		if err != nil {
+			log.Fatalf("订阅 %s 行情失败: %v", name, err)
+		}
+		adapters = append(adapters, adapter)
+		log.Printf("✅ %s 已订阅 %d 个交易对", name, len(symbols))
+	}
+
+	var wg sync.WaitGroup
+	if *bookInterval > 0 {
+		for _, name := range exchanges {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				pollOrderBooks(ctx, name, symbols, *bookInterval, writer)
+			}(name)
+		}
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		flushLoop(ctx, writer, *flushInterval)
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	log.Printf("开始录制到 %s，按 Ctrl+C 停止...", *out)
+	<-sigChan
+
+	log.Println("正在停止录制...")
+	cancel()
+	for _, adapter := range adapters {
+		adapter.Disconnect()
+	}
+	wg.Wait()
+
+	if err := writer.Close(); err != nil {
+		log.Printf("⚠️  关闭录制文件失败: %v", err)
+	}
+	log.Printf("✅ 录制结束: 行情 %d 条，订单簿 %d 条，写入失败 %d 次",
+		atomic.LoadInt64(&tickerCount), atomic.LoadInt64(&orderBookCount), atomic.LoadInt64(&writeErrors))
+}
+
+// subscribe 连接交易所并订阅价格行情
+func subscribe(ctx context.Context, name string, symbols []string, writer *marketdata.FileWriter) (exchange.ExchangeAdapter, error) {
+	config := &exchange.ExchangeConfig{Name: name, Symbols: symbols, Enabled: true}
+
+	var adapter exchange.ExchangeAdapter
+	switch name {
+	case "binance":
+		config.REST.BaseURL = "https://api.binance.com"
+		adapter = exchange.NewBinanceAdapter(config)
+	case "okx":
+		config.REST.BaseURL = "https://www.okx.com"
+		adapter = exchange.NewOKXAdapter(config)
+	default:
+		return nil, fmt.Errorf("不支持的交易所: %s", name)
+	}
+
+	if err := adapter.Connect(ctx); err != nil {
+		return nil, err
+	}
+	err := adapter.SubscribeTicker(ctx, symbols, func(ticker *exchange.Ticker) {
+		event := marketdata.NewTickerEvent(&cache.PriceData{
+			Exchange:  name,
+			Symbol:    ticker.Symbol,
+			BidPrice:  ticker.BidPrice,
+			AskPrice:  ticker.AskPrice,
+			LastPrice: ticker.LastPrice,
+			Volume24h: ticker.Volume24h,
+			Timestamp: ticker.Timestamp,
+		})
+		event.ExchangeTime = ticker.ExchangeTime
+		event.Raw = ticker.Raw
+		record(writer, event, &tickerCount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return adapter, nil
+}
+
+// pollOrderBooks 定时拉取订单簿快照（公开接口，不需要 API Key）
+func pollOrderBooks(ctx context.Context, name string, symbols []string, interval time.Duration, writer *marketdata.FileWriter) {
+	var executor execution.OrderExecutor
+	switch name {
+	case "binance":
+		executor = execution.NewBinanceExecutor("", nil, "")
+	case "okx":
+		executor = execution.NewOKXExecutor("", nil, "", "")
+	default:
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, symbol := range symbols {
+				book, err := executor.GetOrderBook(ctx, name, symbol)
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Printf("⚠️  获取 %s %s 订单簿失败: %v", name, symbol, err)
+					}
+					continue
+				}
+				record(writer, marketdata.NewOrderBookEvent(book), &orderBookCount)
+			}
+		}
+	}
+}
+
+// record 写入一条事件并计数
+func record(writer *marketdata.FileWriter, event *marketdata.Event, counter *int64) {
+	if err := writer.Write(event); err != nil {
+		if atomic.AddInt64(&writeErrors, 1) == 1 {
+			log.Printf("⚠️  写入录制文件失败: %v", err)
+		}
+		return
+	}
+	atomic.AddInt64(counter, 1)
+}
+
+// flushLoop 定时写盘并输出录制统计
+func flushLoop(ctx context.Context, writer *marketdata.FileWriter, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastReport := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := writer.Flush(); err != nil {
+				log.Printf("⚠️  写盘失败: %v", err)
+			}
+			if time.Since(lastReport) >= time.Minute {
+				lastReport = time.Now()
+				log.Printf("📊 已录制: 行情 %d 条，订单簿 %d 条",
+					atomic.LoadInt64(&tickerCount), atomic.LoadInt64(&orderBookCount))
+			}
+		}
+	}
+}
+
+// splitList 解析逗号分隔的列表（忽略空项）
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,28 @@
+package cache
+
+import "github.com/zeromicro/go-zero/core/metric"
+
+// 查询结果（metricLookups 的 result 标签）
+const (
+	lookupHit     = "hit"
+	lookupMiss    = "miss"
+	lookupExpired = "expired"
+)
+
+// 价格缓存指标（开启 Prometheus 后记录）
+var (
+	metricLookups = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "cache",
+		Name:      "lookups_total",
+		Help:      "Price cache lookups by result (hit, miss, expired).",
+		Labels:    []string{"exchange", "result"},
+	})
+	metricEvictions = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "cache",
+		Name:      "evictions_total",
+		Help:      "Expired prices removed by the cleanup routine.",
+		Labels:    []string{},
+	})
+)
@@ -8,6 +8,12 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"arbitragex/common/clock"
+	"arbitragex/common/decimal"
+	"arbitragex/common/tracing"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // PriceCache 价格缓存接口
@@ -36,13 +42,16 @@ type PriceCache interface {
 
 // PriceData 价格数据结构
 type PriceData struct {
-	Exchange  string    `json:"exchange"`
-	Symbol    string    `json:"symbol"`
-	BidPrice  float64   `json:"bid_price"`
-	AskPrice  float64   `json:"ask_price"`
-	LastPrice float64   `json:"last_price"`
-	Volume24h float64   `json:"volume_24h"`
-	Timestamp time.Time `json:"timestamp"`
+	Exchange  string          `json:"exchange"`
+	Symbol    string          `json:"symbol"`
+	BidPrice  decimal.Decimal `json:"bid_price"`
+	AskPrice  decimal.Decimal `json:"ask_price"`
+	LastPrice decimal.Decimal `json:"last_price"`
+	Volume24h decimal.Decimal `json:"volume_24h"`
+	Timestamp time.Time       `json:"timestamp"`
+
+	// SpanContext 写入缓存的 span（套利引擎发现机会的 span 以此为父 span，不序列化）
+	SpanContext trace.SpanContext `json:"-"`
 }
 
 // cachedItem 缓存项
@@ -56,6 +65,7 @@ type MemoryPriceCache struct {
 	mu         sync.RWMutex
 	data       map[string]*cachedItem
 	defaultTTL time.Duration
+	clock      clock.Clock
 }
 
 // NewMemoryPriceCache 创建内存价格缓存
@@ -68,9 +78,18 @@ func NewMemoryPriceCache(defaultTTL time.Duration) *MemoryPriceCache {
 	return &MemoryPriceCache{
 		data:       make(map[string]*cachedItem),
 		defaultTTL: defaultTTL,
+		clock:      clock.Real,
 	}
 }
 
+// SetClock 设置时钟（测试和回测时使用手动时钟，过期判断按注入的时间计算）
+func (c *MemoryPriceCache) SetClock(clk clock.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clock = clock.OrReal(clk)
+}
+
 // priceKey 生成价格缓存的键
 func (c *MemoryPriceCache) priceKey(exchange, symbol string) string {
 	return fmt.Sprintf("price:%s:%s", exchange, symbol)
@@ -78,7 +97,7 @@ func (c *MemoryPriceCache) priceKey(exchange, symbol string) string {
 
 // isExpired 检查缓存是否过期
 func (c *MemoryPriceCache) isExpired(item *cachedItem) bool {
-	return time.Now().After(item.expiresAt)
+	return c.clock.Now().After(item.expiresAt)
 }
 
 // cleanupExpired 清理过期缓存
@@ -89,12 +108,21 @@ func (c *MemoryPriceCache) cleanupExpired() {
 	for key, item := range c.data {
 		if c.isExpired(item) {
 			delete(c.data, key)
+			metricEvictions.Inc()
 		}
 	}
 }
 
 // SetPrice 设置价格数据
+// ctx 中有行情的 span 时记录写入缓存的 span，并保存在 ticker.SpanContext 中
 func (c *MemoryPriceCache) SetPrice(ctx context.Context, exchange, symbol string, ticker *PriceData) error {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		_, span := tracing.Start(ctx, "cache.SetPrice",
+			trace.WithAttributes(tracing.Exchange.String(exchange), tracing.Symbol.String(symbol)))
+		defer span.End()
+		ticker.SpanContext = span.SpanContext()
+	}
+
 	key := c.priceKey(exchange, symbol)
 
 	c.mu.Lock()
@@ -102,7 +130,7 @@ func (c *MemoryPriceCache) SetPrice(ctx context.Context, exchange, symbol string
 
 	c.data[key] = &cachedItem{
 		data:      ticker,
-		expiresAt: time.Now().Add(c.defaultTTL),
+		expiresAt: c.clock.Now().Add(c.defaultTTL),
 	}
 
 	return nil
@@ -117,14 +145,17 @@ func (c *MemoryPriceCache) GetPrice(ctx context.Context, exchange, symbol string
 
 	item, ok := c.data[key]
 	if !ok {
+		metricLookups.Inc(exchange, lookupMiss)
 		return nil, ErrCacheNotFound
 	}
 
 	// 检查是否过期
 	if c.isExpired(item) {
+		metricLookups.Inc(exchange, lookupExpired)
 		return nil, ErrCacheNotFound
 	}
 
+	metricLookups.Inc(exchange, lookupHit)
 	return item.data, nil
 }
 
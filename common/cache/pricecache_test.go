@@ -5,6 +5,9 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"arbitragex/common/clock"
+	"arbitragex/common/decimal"
 )
 
 // TestNewMemoryPriceCache 测试创建内存价格缓存
@@ -68,9 +71,9 @@ func TestMemoryPriceCache_SetPrice_GetPrice(t *testing.T) {
 	ticker := &PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43000.50,
-		AskPrice:  43100.00,
-		LastPrice: 43050.00,
+		BidPrice:  decimal.NewFromFloat(43000.50),
+		AskPrice:  decimal.NewFromFloat(43100.00),
+		LastPrice: decimal.NewFromFloat(43050.00),
 		Timestamp: time.Now(),
 	}
 
@@ -95,8 +98,8 @@ func TestMemoryPriceCache_SetPrice_GetPrice(t *testing.T) {
 		t.Errorf("Symbol = %s, want %s", retrieved.Symbol, ticker.Symbol)
 	}
 
-	if retrieved.BidPrice != ticker.BidPrice {
-		t.Errorf("BidPrice = %f, want %f", retrieved.BidPrice, ticker.BidPrice)
+	if !retrieved.BidPrice.Equal(ticker.BidPrice) {
+		t.Errorf("BidPrice = %v, want %v", retrieved.BidPrice, ticker.BidPrice)
 	}
 }
 
@@ -120,15 +123,15 @@ func TestMemoryPriceCache_SetPriceBatch(t *testing.T) {
 		"BTC/USDT": {
 			Exchange:  "binance",
 			Symbol:    "BTC/USDT",
-			BidPrice:  43000.50,
-			AskPrice:  43100.00,
+			BidPrice:  decimal.NewFromFloat(43000.50),
+			AskPrice:  decimal.NewFromFloat(43100.00),
 			Timestamp: time.Now(),
 		},
 		"ETH/USDT": {
 			Exchange:  "binance",
 			Symbol:    "ETH/USDT",
-			BidPrice:  2200.50,
-			AskPrice:  2201.00,
+			BidPrice:  decimal.NewFromFloat(2200.50),
+			AskPrice:  decimal.NewFromFloat(2201.00),
 			Timestamp: time.Now(),
 		},
 	}
@@ -141,13 +144,13 @@ func TestMemoryPriceCache_SetPriceBatch(t *testing.T) {
 
 	// 验证数据
 	btc, _ := cache.GetPrice(ctx, "binance", "BTC/USDT")
-	if btc.BidPrice != 43000.50 {
-		t.Errorf("BTC BidPrice = %f, want 43000.50", btc.BidPrice)
+	if !btc.BidPrice.Equal(decimal.NewFromFloat(43000.50)) {
+		t.Errorf("BTC BidPrice = %v, want 43000.50", btc.BidPrice)
 	}
 
 	eth, _ := cache.GetPrice(ctx, "binance", "ETH/USDT")
-	if eth.BidPrice != 2200.50 {
-		t.Errorf("ETH BidPrice = %f, want 2200.50", eth.BidPrice)
+	if !eth.BidPrice.Equal(decimal.NewFromFloat(2200.50)) {
+		t.Errorf("ETH BidPrice = %v, want 2200.50", eth.BidPrice)
 	}
 }
 
@@ -161,13 +164,13 @@ func TestMemoryPriceCache_GetPriceBatch(t *testing.T) {
 		"BTC/USDT": {
 			Exchange:  "binance",
 			Symbol:    "BTC/USDT",
-			BidPrice:  43000.50,
+			BidPrice:  decimal.NewFromFloat(43000.50),
 			Timestamp: time.Now(),
 		},
 		"ETH/USDT": {
 			Exchange:  "binance",
 			Symbol:    "ETH/USDT",
-			BidPrice:  2200.50,
+			BidPrice:  decimal.NewFromFloat(2200.50),
 			Timestamp: time.Now(),
 		},
 	}
@@ -203,7 +206,7 @@ func TestMemoryPriceCache_DeletePrice(t *testing.T) {
 	ticker := &PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43000.50,
+		BidPrice:  decimal.NewFromFloat(43000.50),
 		Timestamp: time.Now(),
 	}
 
@@ -233,13 +236,13 @@ func TestMemoryPriceCache_GetAllPrices(t *testing.T) {
 		"BTC/USDT": {
 			Exchange:  "binance",
 			Symbol:    "BTC/USDT",
-			BidPrice:  43000.50,
+			BidPrice:  decimal.NewFromFloat(43000.50),
 			Timestamp: time.Now(),
 		},
 		"ETH/USDT": {
 			Exchange:  "binance",
 			Symbol:    "ETH/USDT",
-			BidPrice:  2200.50,
+			BidPrice:  decimal.NewFromFloat(2200.50),
 			Timestamp: time.Now(),
 		},
 	}
@@ -250,7 +253,7 @@ func TestMemoryPriceCache_GetAllPrices(t *testing.T) {
 	cache.SetPrice(ctx, "okx", "BTC/USDT", &PriceData{
 		Exchange:  "okx",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43010.00,
+		BidPrice:  decimal.NewFromFloat(43010.00),
 		Timestamp: time.Now(),
 	})
 
@@ -276,13 +279,13 @@ func TestMemoryPriceCache_ClearExchange(t *testing.T) {
 		"BTC/USDT": {
 			Exchange:  "binance",
 			Symbol:    "BTC/USDT",
-			BidPrice:  43000.50,
+			BidPrice:  decimal.NewFromFloat(43000.50),
 			Timestamp: time.Now(),
 		},
 		"ETH/USDT": {
 			Exchange:  "binance",
 			Symbol:    "ETH/USDT",
-			BidPrice:  2200.50,
+			BidPrice:  decimal.NewFromFloat(2200.50),
 			Timestamp: time.Now(),
 		},
 	}
@@ -291,7 +294,7 @@ func TestMemoryPriceCache_ClearExchange(t *testing.T) {
 	cache.SetPrice(ctx, "okx", "BTC/USDT", &PriceData{
 		Exchange:  "okx",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43010.00,
+		BidPrice:  decimal.NewFromFloat(43010.00),
 		Timestamp: time.Now(),
 	})
 
@@ -318,11 +321,13 @@ func TestMemoryPriceCache_ClearExchange(t *testing.T) {
 func TestMemoryPriceCache_Expiration(t *testing.T) {
 	ctx := context.Background()
 	cache := NewMemoryPriceCache(100 * time.Millisecond) // 100ms TTL
+	clk := clock.NewManual(time.Now())
+	cache.SetClock(clk)
 
 	ticker := &PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43000.50,
+		BidPrice:  decimal.NewFromFloat(43000.50),
 		Timestamp: time.Now(),
 	}
 
@@ -335,8 +340,14 @@ func TestMemoryPriceCache_Expiration(t *testing.T) {
 		t.Errorf("Expected to find price immediately, got error: %v", err)
 	}
 
-	// 等待过期
-	time.Sleep(150 * time.Millisecond)
+	// 到期前仍然有效
+	clk.Advance(100 * time.Millisecond)
+	if _, err := cache.GetPrice(ctx, "binance", "BTC/USDT"); err != nil {
+		t.Errorf("Expected to find price at TTL boundary, got error: %v", err)
+	}
+
+	// 推进时钟直到过期
+	clk.Advance(50 * time.Millisecond)
 
 	// 再次获取，应该已过期
 	_, err = cache.GetPrice(ctx, "binance", "BTC/USDT")
@@ -357,7 +368,7 @@ func TestMemoryPriceCache_ConcurrentAccess(t *testing.T) {
 			ticker := &PriceData{
 				Exchange:  "binance",
 				Symbol:    "BTC/USDT",
-				BidPrice:  float64(43000 + idx),
+				BidPrice:  decimal.NewFromInt(int64(43000 + idx)),
 				Timestamp: time.Now(),
 			}
 			cache.SetPrice(ctx, "binance", "BTC/USDT", ticker)
@@ -376,8 +387,8 @@ func TestMemoryPriceCache_ConcurrentAccess(t *testing.T) {
 		t.Errorf("Failed to get price after concurrent writes: %v", err)
 	}
 
-	if ticker.BidPrice < 43000 || ticker.BidPrice > 43010 {
-		t.Errorf("BidPrice out of expected range: %f", ticker.BidPrice)
+	if ticker.BidPrice.LessThan(decimal.NewFromInt(43000)) || ticker.BidPrice.GreaterThan(decimal.NewFromInt(43010)) {
+		t.Errorf("BidPrice out of expected range: %v", ticker.BidPrice)
 	}
 }
 
@@ -386,7 +397,7 @@ func TestPriceDataToJSON(t *testing.T) {
 	ticker := &PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43000.50,
+		BidPrice:  decimal.NewFromFloat(43000.50),
 		Timestamp: time.Now(),
 	}
 
@@ -407,8 +418,8 @@ func TestPriceDataToJSON(t *testing.T) {
 		t.Errorf("Exchange = %s, want %s", decoded.Exchange, ticker.Exchange)
 	}
 
-	if decoded.BidPrice != ticker.BidPrice {
-		t.Errorf("BidPrice = %f, want %f", decoded.BidPrice, ticker.BidPrice)
+	if !decoded.BidPrice.Equal(ticker.BidPrice) {
+		t.Errorf("BidPrice = %v, want %v", decoded.BidPrice, ticker.BidPrice)
 	}
 }
 
@@ -429,7 +440,7 @@ func BenchmarkSetPrice(b *testing.B) {
 	ticker := &PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43000.50,
+		BidPrice:  decimal.NewFromFloat(43000.50),
 		Timestamp: time.Now(),
 	}
 
@@ -446,7 +457,7 @@ func BenchmarkGetPrice(b *testing.B) {
 	ticker := &PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43000.50,
+		BidPrice:  decimal.NewFromFloat(43000.50),
 		Timestamp: time.Now(),
 	}
 
@@ -0,0 +1,219 @@
+// Package clock 提供可注入的时钟
+// 职责：统一引擎、缓存和执行模块的时间来源。生产环境使用系统时钟，测试和回测使用手动推进的时钟，
+// 过期、超时逻辑不需要真实等待，回测也可以快于真实时间运行
+package clock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock 时钟接口
+type Clock interface {
+	// Now 当前时间
+	Now() time.Time
+
+	// Since 距 t 经过的时间
+	Since(t time.Time) time.Duration
+
+	// After 经过 d 后向通道发送当时的时间
+	After(d time.Duration) <-chan time.Time
+
+	// AfterFunc 经过 d 后在新协程中调用 f
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer 定时器
+type Timer interface {
+	// Stop 取消定时器，定时器已触发或已取消时返回 false
+	Stop() bool
+}
+
+// Real 系统时钟
+var Real Clock = realClock{}
+
+// realClock 系统时钟实现
+type realClock struct{}
+
+// Now 当前时间
+func (realClock) Now() time.Time { return time.Now() }
+
+// Since 距 t 经过的时间
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+// After 经过 d 后向通道发送当时的时间
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// AfterFunc 经过 d 后在新协程中调用 f
+func (realClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+// OrReal c 为空时返回系统时钟
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real
+	}
+	return c
+}
+
+// WithTimeout 按时钟计算超时的 context.WithTimeout
+// 系统时钟直接使用 context.WithTimeout；其他时钟在时钟推进到截止时间时取消
+// 参数:
+//   - ctx: 父上下文
+//   - c: 时钟
+//   - timeout: 超时时间
+// 返回:
+//   - context.Context: 超时后取消的上下文（Err 为 context.DeadlineExceeded）
+//   - context.CancelFunc: 释放定时器
+func WithTimeout(ctx context.Context, c Clock, timeout time.Duration) (context.Context, context.CancelFunc) {
+	c = OrReal(c)
+	if _, ok := c.(realClock); ok {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	timer := c.AfterFunc(timeout, func() {
+		cancel(context.DeadlineExceeded)
+	})
+	tctx := &timeoutContext{Context: ctx, deadline: c.Now().Add(timeout)}
+	return tctx, func() {
+		timer.Stop()
+		cancel(context.Canceled)
+	}
+}
+
+// timeoutContext 手动时钟的超时上下文（Err 与 context.WithTimeout 一致）
+type timeoutContext struct {
+	context.Context
+	deadline time.Time
+}
+
+// Deadline 截止时间（按注入的时钟计算）
+func (c *timeoutContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+// Err 超时时返回 context.DeadlineExceeded
+func (c *timeoutContext) Err() error {
+	if c.Context.Err() == nil {
+		return nil
+	}
+	if cause := context.Cause(c.Context); cause == context.DeadlineExceeded {
+		return cause
+	}
+	return c.Context.Err()
+}
+
+// Manual 手动推进的时钟（测试和回测使用）
+// 时间只在 Set / Advance 时变化，到期的定时器在推进时间时触发
+type Manual struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+// manualTimer 手动时钟的定时器
+type manualTimer struct {
+	clock    *Manual
+	deadline time.Time
+	fire     func(now time.Time)
+}
+
+// NewManual 创建手动时钟
+// 参数:
+//   - start: 初始时间
+// 返回:
+//   - *Manual: 手动时钟
+func NewManual(start time.Time) *Manual {
+	return &Manual{now: start}
+}
+
+// Now 当前时间
+func (m *Manual) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Since 距 t 经过的时间
+func (m *Manual) Since(t time.Time) time.Duration {
+	return m.Now().Sub(t)
+}
+
+// After 时钟推进 d 后向通道发送当时的时间
+func (m *Manual) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	m.schedule(d, func(now time.Time) { ch <- now })
+	return ch
+}
+
+// AfterFunc 时钟推进 d 后在新协程中调用 f
+func (m *Manual) AfterFunc(d time.Duration, f func()) Timer {
+	return m.schedule(d, func(time.Time) { go f() })
+}
+
+// Advance 时钟前进 d，并触发到期的定时器
+func (m *Manual) Advance(d time.Duration) {
+	m.mu.Lock()
+	now := m.now.Add(d)
+	m.mu.Unlock()
+	m.Set(now)
+}
+
+// Set 把时钟设置到 t（早于当前时间时忽略，时间不会倒退），并触发到期的定时器
+func (m *Manual) Set(t time.Time) {
+	m.mu.Lock()
+	if t.After(m.now) {
+		m.now = t
+	}
+	now := m.now
+
+	var due []*manualTimer
+	pending := m.timers[:0]
+	for _, timer := range m.timers {
+		if timer.deadline.After(now) {
+			pending = append(pending, timer)
+		} else {
+			due = append(due, timer)
+		}
+	}
+	m.timers = pending
+	m.mu.Unlock()
+
+	// 按到期时间顺序触发
+	sort.SliceStable(due, func(i, j int) bool { return due[i].deadline.Before(due[j].deadline) })
+	for _, timer := range due {
+		timer.fire(now)
+	}
+}
+
+// schedule 注册定时器（d ≤ 0 时立即触发）
+func (m *Manual) schedule(d time.Duration, fire func(now time.Time)) *manualTimer {
+	m.mu.Lock()
+	timer := &manualTimer{clock: m, deadline: m.now.Add(d), fire: fire}
+	if d <= 0 {
+		now := m.now
+		m.mu.Unlock()
+		fire(now)
+		return timer
+	}
+	m.timers = append(m.timers, timer)
+	m.mu.Unlock()
+	return timer
+}
+
+// Stop 取消定时器
+func (t *manualTimer) Stop() bool {
+	m := t.clock
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, timer := range m.timers {
+		if timer == t {
+			m.timers = append(m.timers[:i], m.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
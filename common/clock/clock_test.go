@@ -0,0 +1,97 @@
+// Package clock 时钟单元测试
+package clock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestManual_After 测试推进时间时按到期顺序触发定时器
+func TestManual_After(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManual(t0)
+
+	first := c.After(time.Second)
+	second := c.After(2 * time.Second)
+
+	c.Advance(500 * time.Millisecond)
+	select {
+	case <-first:
+		t.Fatal("timer fired before deadline")
+	default:
+	}
+
+	c.Advance(2 * time.Second)
+	if got := <-first; !got.Equal(t0.Add(2500 * time.Millisecond)) {
+		t.Errorf("first fired at %v, want clock time after advance", got)
+	}
+	<-second
+
+	if c.Since(t0) != 2500*time.Millisecond {
+		t.Errorf("Since() = %v, want 2.5s", c.Since(t0))
+	}
+
+	// 时间不会倒退
+	c.Set(t0)
+	if !c.Now().Equal(t0.Add(2500 * time.Millisecond)) {
+		t.Errorf("Now() after Set(earlier) = %v, want unchanged", c.Now())
+	}
+}
+
+// TestManual_AfterFuncStop 测试取消的定时器不会触发
+func TestManual_AfterFuncStop(t *testing.T) {
+	c := NewManual(time.Now())
+
+	fired := make(chan struct{}, 1)
+	timer := c.AfterFunc(time.Second, func() { fired <- struct{}{} })
+	if !timer.Stop() {
+		t.Error("Stop() = false, want true for pending timer")
+	}
+	if timer.Stop() {
+		t.Error("second Stop() = true, want false")
+	}
+
+	c.Advance(time.Minute)
+	select {
+	case <-fired:
+		t.Error("stopped timer fired")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+// TestWithTimeout 测试手动时钟推进到截止时间时上下文超时
+func TestWithTimeout(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManual(t0)
+
+	ctx, cancel := WithTimeout(context.Background(), c, 30*time.Second)
+	defer cancel()
+
+	if deadline, ok := ctx.Deadline(); !ok || !deadline.Equal(t0.Add(30*time.Second)) {
+		t.Errorf("Deadline() = %v, %v, want clock time + 30s", deadline, ok)
+	}
+
+	c.Advance(29 * time.Second)
+	if ctx.Err() != nil {
+		t.Fatalf("Err() = %v before deadline", ctx.Err())
+	}
+
+	c.Advance(time.Second)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context not canceled at deadline")
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err() = %v, want DeadlineExceeded", ctx.Err())
+	}
+
+	// 主动取消返回 Canceled
+	ctx, cancel = WithTimeout(context.Background(), c, time.Second)
+	cancel()
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("Err() after cancel = %v, want Canceled", ctx.Err())
+	}
+}
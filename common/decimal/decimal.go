@@ -0,0 +1,373 @@
+// Package decimal 提供定点小数
+// 职责：价格、数量、手续费和收益统一使用十进制定点数表示，从解析交易所返回的字符串、计算手续费和收益，
+// 到提交订单和写入 DECIMAL(20, 8) 字段都不经过 float64，避免二进制浮点误差累积和向交易所发送非法的价格字符串
+package decimal
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Precision 内部保留的小数位数（乘除结果按此精度四舍五入）
+const Precision = 18
+
+// scale 10^Precision
+var scale = pow10(Precision)
+
+// plainNumber 十进制数字格式（big.Rat 还接受分数、十六进制和下划线分隔，这里只允许普通小数和科学计数法）
+var plainNumber = regexp.MustCompile(`^[+-]?\d+(\.\d+)?([eE][+-]?\d+)?$`)
+
+// Zero 零值（Decimal 的零值即为 0）
+var Zero = Decimal{}
+
+// Decimal 定点小数
+// 内部以 值 × 10^18 的整数保存；值不可变，所有运算返回新的 Decimal，可以安全地在协程间共享和按值复制
+type Decimal struct {
+	v *big.Int // nil 表示 0
+}
+
+// NewFromInt 由整数创建
+func NewFromInt(i int64) Decimal {
+	return Decimal{v: new(big.Int).Mul(big.NewInt(i), scale)}
+}
+
+// NewFromFloat 由 float64 创建
+// 按能还原该 float64 的最短十进制表示转换（0.1 转换为 0.1，而不是 0.1000000000000000055…），
+// 用于配置项和测试数据；NaN 和无穷大会 panic
+func NewFromFloat(f float64) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic(fmt.Sprintf("decimal: cannot convert %v to Decimal", f))
+	}
+	return RequireFromString(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// NewFromString 解析十进制字符串（如 "43000.12"、"-0.5"、"1e-8"）
+// 参数:
+//   - s: 十进制字符串（超过 18 位的小数四舍五入）
+// 返回:
+//   - Decimal: 解析结果
+//   - error: 格式错误
+func NewFromString(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if !plainNumber.MatchString(s) {
+		return Zero, fmt.Errorf("decimal: invalid number %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Zero, fmt.Errorf("decimal: invalid number %q", s)
+	}
+	num := new(big.Int).Mul(r.Num(), scale)
+	return Decimal{v: divRound(num, r.Denom())}, nil
+}
+
+// RequireFromString 解析十进制字符串，格式错误时 panic（用于常量和测试数据）
+func RequireFromString(s string) Decimal {
+	d, err := NewFromString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Add 加法
+func (d Decimal) Add(o Decimal) Decimal {
+	return Decimal{v: new(big.Int).Add(d.int(), o.int())}
+}
+
+// Sub 减法
+func (d Decimal) Sub(o Decimal) Decimal {
+	return Decimal{v: new(big.Int).Sub(d.int(), o.int())}
+}
+
+// Mul 乘法（结果按 18 位小数四舍五入）
+func (d Decimal) Mul(o Decimal) Decimal {
+	num := new(big.Int).Mul(d.int(), o.int())
+	return Decimal{v: divRound(num, scale)}
+}
+
+// Div 除法（结果按 18 位小数四舍五入），除数为 0 时 panic
+func (d Decimal) Div(o Decimal) Decimal {
+	if o.IsZero() {
+		panic("decimal: division by zero")
+	}
+	num := new(big.Int).Mul(d.int(), scale)
+	return Decimal{v: divRound(num, o.int())}
+}
+
+// Neg 取反
+func (d Decimal) Neg() Decimal {
+	return Decimal{v: new(big.Int).Neg(d.int())}
+}
+
+// Abs 绝对值
+func (d Decimal) Abs() Decimal {
+	return Decimal{v: new(big.Int).Abs(d.int())}
+}
+
+// Cmp 比较大小（d < o 返回 -1，相等返回 0，d > o 返回 1）
+func (d Decimal) Cmp(o Decimal) int {
+	return d.int().Cmp(o.int())
+}
+
+// Equal 是否相等
+func (d Decimal) Equal(o Decimal) bool { return d.Cmp(o) == 0 }
+
+// GreaterThan 是否大于 o
+func (d Decimal) GreaterThan(o Decimal) bool { return d.Cmp(o) > 0 }
+
+// GreaterThanOrEqual 是否大于等于 o
+func (d Decimal) GreaterThanOrEqual(o Decimal) bool { return d.Cmp(o) >= 0 }
+
+// LessThan 是否小于 o
+func (d Decimal) LessThan(o Decimal) bool { return d.Cmp(o) < 0 }
+
+// LessThanOrEqual 是否小于等于 o
+func (d Decimal) LessThanOrEqual(o Decimal) bool { return d.Cmp(o) <= 0 }
+
+// Sign 符号（负数 -1，0 为 0，正数 1）
+func (d Decimal) Sign() int { return d.int().Sign() }
+
+// IsZero 是否为 0
+func (d Decimal) IsZero() bool { return d.Sign() == 0 }
+
+// IsPositive 是否大于 0
+func (d Decimal) IsPositive() bool { return d.Sign() > 0 }
+
+// IsNegative 是否小于 0
+func (d Decimal) IsNegative() bool { return d.Sign() < 0 }
+
+// Round 四舍五入到 places 位小数（远离 0 方向进位）
+func (d Decimal) Round(places int32) Decimal {
+	if places >= Precision {
+		return d
+	}
+	unit := pow10(Precision - places)
+	return Decimal{v: new(big.Int).Mul(divRound(d.int(), unit), unit)}
+}
+
+// Truncate 截断到 places 位小数（向 0 方向舍去）
+func (d Decimal) Truncate(places int32) Decimal {
+	if places >= Precision {
+		return d
+	}
+	unit := pow10(Precision - places)
+	return Decimal{v: new(big.Int).Mul(new(big.Int).Quo(d.int(), unit), unit)}
+}
+
+// TruncateStep 向 0 方向舍去到 step 的整数倍（按交易所的价格精度、数量步长下单），step ≤ 0 时原样返回
+func (d Decimal) TruncateStep(step Decimal) Decimal {
+	if !step.IsPositive() {
+		return d
+	}
+	n := new(big.Int).Quo(d.int(), step.int())
+	return Decimal{v: n.Mul(n, step.int())}
+}
+
+// Float64 转换为 float64（用于收益率、评分等比例计算和展示，可能损失精度）
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String 十进制字符串（不使用科学计数法，去掉末尾的 0，如 "43000.1"、"0.00000001"）
+func (d Decimal) String() string {
+	s := d.StringFixed(Precision)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// StringFixed 四舍五入到 places 位小数后的字符串（保留末尾的 0，如 StringFixed(8) = "0.10000000"）
+func (d Decimal) StringFixed(places int32) string {
+	if places < 0 {
+		places = 0
+	}
+	if places > Precision {
+		places = Precision
+	}
+
+	v := d.Round(places).int()
+	digits := new(big.Int).Abs(v).String()
+	if len(digits) <= Precision {
+		digits = strings.Repeat("0", Precision-len(digits)+1) + digits
+	}
+	intPart, fracPart := digits[:len(digits)-Precision], digits[len(digits)-Precision:]
+
+	s := intPart
+	if places > 0 {
+		s += "." + fracPart[:places]
+	}
+	if v.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// Format 实现 fmt.Formatter，日志中可以直接使用 %.2f、%v、%s
+func (d Decimal) Format(f fmt.State, verb rune) {
+	var s string
+	switch verb {
+	case 'f', 'F':
+		places, ok := f.Precision()
+		if !ok {
+			places = 6
+		}
+		s = d.StringFixed(int32(places))
+	case 'v', 's', 'g', 'G':
+		s = d.String()
+	case 'q':
+		s = strconv.Quote(d.String())
+	default:
+		fmt.Fprintf(f, "%%!%c(decimal.Decimal=%s)", verb, d.String())
+		return
+	}
+
+	if f.Flag('+') && d.Sign() >= 0 {
+		s = "+" + s
+	}
+	if width, ok := f.Width(); ok && len(s) < width {
+		pad := strings.Repeat(" ", width-len(s))
+		if f.Flag('-') {
+			s += pad
+		} else {
+			s = pad + s
+		}
+	}
+	fmt.Fprint(f, s)
+}
+
+// MarshalJSON 序列化为 JSON 数字（精确的十进制表示）
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON 解析 JSON 数字或字符串（交易所接口通常以字符串返回价格）
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*d = Zero
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+		if s == "" {
+			*d = Zero
+			return nil
+		}
+	}
+
+	parsed, err := NewFromString(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value 实现 driver.Valuer，以字符串写入数据库 DECIMAL 字段
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan 实现 sql.Scanner，读取数据库 DECIMAL 字段
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Zero
+		return nil
+	case []byte:
+		return d.scanString(string(v))
+	case string:
+		return d.scanString(v)
+	case int64:
+		*d = NewFromInt(v)
+		return nil
+	case float64:
+		*d = NewFromFloat(v)
+		return nil
+	default:
+		return fmt.Errorf("decimal: cannot scan %T", src)
+	}
+}
+
+// scanString 解析数据库返回的字符串
+func (d *Decimal) scanString(s string) error {
+	parsed, err := NewFromString(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Min 最小值
+func Min(first Decimal, rest ...Decimal) Decimal {
+	min := first
+	for _, d := range rest {
+		if d.LessThan(min) {
+			min = d
+		}
+	}
+	return min
+}
+
+// Max 最大值
+func Max(first Decimal, rest ...Decimal) Decimal {
+	max := first
+	for _, d := range rest {
+		if d.GreaterThan(max) {
+			max = d
+		}
+	}
+	return max
+}
+
+// Sum 求和
+func Sum(values ...Decimal) Decimal {
+	sum := new(big.Int)
+	for _, d := range values {
+		sum.Add(sum, d.int())
+	}
+	return Decimal{v: sum}
+}
+
+// int 内部整数（零值返回 0）
+func (d Decimal) int() *big.Int {
+	if d.v == nil {
+		return new(big.Int)
+	}
+	return d.v
+}
+
+// divRound 整数除法，按远离 0 方向四舍五入
+func divRound(num, den *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	// |2r| ≥ |den| 时进位
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	if twice.Cmp(new(big.Int).Abs(den)) >= 0 {
+		if (num.Sign() < 0) != (den.Sign() < 0) {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// pow10 10^n
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
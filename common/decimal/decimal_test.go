@@ -0,0 +1,155 @@
+// Package decimal 定点小数单元测试
+package decimal
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// TestNewFromString 测试解析十进制字符串
+func TestNewFromString(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "43000.12", want: "43000.12"},
+		{in: "-0.5", want: "-0.5"},
+		{in: "0.00000001", want: "0.00000001"},
+		{in: "1e-8", want: "0.00000001"},
+		{in: "100.000", want: "100"},
+		{in: "0.0000000000000000005", want: "0.000000000000000001"},
+		{in: "", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1/3", wantErr: true},
+		{in: "NaN", wantErr: true},
+		{in: "0x10", wantErr: true},
+		{in: "0b101", wantErr: true},
+		{in: "1_000", wantErr: true},
+		{in: "1.", wantErr: true},
+		{in: "+Inf", wantErr: true},
+		{in: "+2.5E2", want: "250"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := NewFromString(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewFromString(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("NewFromString(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestArithmetic 测试运算没有二进制浮点误差
+func TestArithmetic(t *testing.T) {
+	// float64 中 0.1 + 0.2 = 0.30000000000000004
+	if got := NewFromFloat(0.1).Add(NewFromFloat(0.2)); got.String() != "0.3" {
+		t.Errorf("0.1 + 0.2 = %s, want 0.3", got)
+	}
+
+	// 累加一万次 0.0001 USDT 手续费
+	sum := Zero
+	fee := RequireFromString("0.0001")
+	for i := 0; i < 10000; i++ {
+		sum = sum.Add(fee)
+	}
+	if !sum.Equal(NewFromInt(1)) {
+		t.Errorf("sum = %s, want 1", sum)
+	}
+
+	price := RequireFromString("43000.1")
+	amount := RequireFromString("0.003")
+	if got := price.Mul(amount); got.String() != "129.0003" {
+		t.Errorf("Mul() = %s, want 129.0003", got)
+	}
+	if got := NewFromInt(1).Div(NewFromInt(3)); got.String() != "0.333333333333333333" {
+		t.Errorf("Div() = %s, want 18 places", got)
+	}
+	if got := NewFromInt(-2).Div(NewFromInt(3)); got.String() != "-0.666666666666666667" {
+		t.Errorf("Div() = %s, want rounded away from zero", got)
+	}
+	if got := price.Sub(price); !got.IsZero() || got.String() != "0" {
+		t.Errorf("Sub() = %s, want 0", got)
+	}
+}
+
+// TestRounding 测试舍入和按步长截断
+func TestRounding(t *testing.T) {
+	d := RequireFromString("1.23456789")
+
+	if got := d.Round(4).String(); got != "1.2346" {
+		t.Errorf("Round(4) = %s, want 1.2346", got)
+	}
+	if got := d.Neg().Round(4).String(); got != "-1.2346" {
+		t.Errorf("Neg().Round(4) = %s, want -1.2346", got)
+	}
+	if got := d.Truncate(4).String(); got != "1.2345" {
+		t.Errorf("Truncate(4) = %s, want 1.2345", got)
+	}
+	if got := d.StringFixed(2); got != "1.23" {
+		t.Errorf("StringFixed(2) = %s, want 1.23", got)
+	}
+	if got := RequireFromString("0.1").StringFixed(8); got != "0.10000000" {
+		t.Errorf("StringFixed(8) = %s, want 0.10000000", got)
+	}
+	if got := RequireFromString("-0.004").StringFixed(2); got != "0.00" {
+		t.Errorf("StringFixed(2) = %s, want 0.00", got)
+	}
+	if got := RequireFromString("43000.127").TruncateStep(RequireFromString("0.01")).String(); got != "43000.12" {
+		t.Errorf("TruncateStep(0.01) = %s, want 43000.12", got)
+	}
+	if got := RequireFromString("0.0157").TruncateStep(RequireFromString("0.005")).String(); got != "0.015" {
+		t.Errorf("TruncateStep(0.005) = %s, want 0.015", got)
+	}
+}
+
+// TestJSON 测试 JSON 序列化为数字，解析兼容数字和字符串
+func TestJSON(t *testing.T) {
+	type payload struct {
+		Price  Decimal `json:"price"`
+		Amount Decimal `json:"amount"`
+	}
+
+	data, err := json.Marshal(payload{Price: RequireFromString("43000.1"), Amount: Zero})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(data) != `{"price":43000.1,"amount":0}` {
+		t.Errorf("Marshal() = %s", data)
+	}
+
+	var got payload
+	if err := json.Unmarshal([]byte(`{"price":"43000.10","amount":0.5}`), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got.Price.String() != "43000.1" || got.Amount.String() != "0.5" {
+		t.Errorf("Unmarshal() = %+v", got)
+	}
+	if err := json.Unmarshal([]byte(`{"price":"abc"}`), &got); err == nil {
+		t.Error("Unmarshal() invalid price error = nil")
+	}
+}
+
+// TestFormatAndScan 测试格式化输出和数据库读写
+func TestFormatAndScan(t *testing.T) {
+	d := RequireFromString("43000.125")
+	if got := fmt.Sprintf("%.2f|%v|%10.1f|%s", d, d, d, d); got != "43000.13|43000.125|   43000.1|43000.125" {
+		t.Errorf("Sprintf() = %q", got)
+	}
+
+	var scanned Decimal
+	if err := scanned.Scan([]byte("0.10000000")); err != nil || scanned.String() != "0.1" {
+		t.Errorf("Scan([]byte) = %s, %v", scanned, err)
+	}
+	if err := scanned.Scan(nil); err != nil || !scanned.IsZero() {
+		t.Errorf("Scan(nil) = %s, %v", scanned, err)
+	}
+	if v, _ := d.Value(); v != "43000.125" {
+		t.Errorf("Value() = %v", v)
+	}
+}
@@ -0,0 +1,75 @@
+// Package tracing 提供链路追踪工具
+// 职责：统一行情接收、价格缓存、套利引擎、风控和执行模块的 OpenTelemetry span，
+// 同一套利机会从行情到成交的 span 在同一条链路中，并带有套利机会 ID 属性（arbitragex.opportunity.id）；
+// 未启动追踪（go-zero trace.StartAgent）时 span 不记录也不导出
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName 链路追踪的 Tracer 名称
+const TracerName = "arbitragex"
+
+// span 属性
+const (
+	OpportunityID = attribute.Key("arbitragex.opportunity.id") // 套利机会 ID
+	ExecutionID   = attribute.Key("arbitragex.execution.id")   // 执行 ID
+	Exchange      = attribute.Key("arbitragex.exchange")       // 交易所
+	Symbol        = attribute.Key("arbitragex.symbol")         // 交易对
+)
+
+// opportunityKey 上下文中保存套利机会 ID 的键
+type opportunityKey struct{}
+
+// WithOpportunityID 在上下文中保存套利机会 ID，之后用 Start 创建的 span 都带有该 ID
+func WithOpportunityID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, opportunityKey{}, id)
+}
+
+// OpportunityIDFromContext 上下文中的套利机会 ID（没有时为空）
+func OpportunityIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(opportunityKey{}).(string)
+	return id
+}
+
+// Start 创建 span（上下文中有套利机会 ID 时添加 arbitragex.opportunity.id 属性）
+// 参数:
+//   - ctx: 父 span 所在的上下文
+//   - name: span 名称
+//   - opts: span 选项（trace.WithAttributes、trace.WithSpanKind、trace.WithLinks 等）
+// 返回:
+//   - context.Context: 包含新 span 的上下文
+//   - trace.Span: 新 span（调用方负责 End）
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if id := OpportunityIDFromContext(ctx); id != "" {
+		opts = append(opts, trace.WithAttributes(OpportunityID.String(id)))
+	}
+	return otel.Tracer(TracerName).Start(ctx, name, opts...)
+}
+
+// End 结束 span，err 不为空时记录错误
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ContextWithParent 以 parent 作为父 span 的上下文（parent 无效时返回 ctx）
+// 用于跨协程或跨消息传递时恢复链路（如行情中保存的 span、消息总线传递的 span）
+func ContextWithParent(ctx context.Context, parent trace.SpanContext) context.Context {
+	if !parent.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithSpanContext(ctx, parent)
+}
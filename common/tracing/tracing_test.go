@@ -0,0 +1,67 @@
+// Package tracing 链路追踪工具单元测试
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestStart 测试 span 带有上下文中的套利机会 ID，End 记录错误
+func TestStart(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx := WithOpportunityID(context.Background(), "opp-1")
+	ctx, parent := Start(ctx, "parent")
+	_, child := Start(ctx, "child", trace.WithAttributes(Exchange.String("binance")))
+	End(child, errors.New("下单失败"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	got := spans[0]
+	if got.Name() != "child" || got.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("child = %s, parent %v", got.Name(), got.Parent().SpanID())
+	}
+	attrs := make(map[string]string)
+	for _, kv := range got.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	if attrs[string(OpportunityID)] != "opp-1" || attrs[string(Exchange)] != "binance" {
+		t.Errorf("child attributes = %v", attrs)
+	}
+	if got.Status().Code != codes.Error || len(got.Events()) != 1 {
+		t.Errorf("child status = %v, events %d, want error recorded", got.Status(), len(got.Events()))
+	}
+	if spans[1].Status().Code == codes.Error {
+		t.Errorf("parent status = %v, want unset", spans[1].Status())
+	}
+}
+
+// TestContextWithParent 测试恢复保存的 span，无效的 span 不改变上下文
+func TestContextWithParent(t *testing.T) {
+	ctx := context.Background()
+	if got := ContextWithParent(ctx, trace.SpanContext{}); got != ctx {
+		t.Error("ContextWithParent(invalid) changed the context")
+	}
+	if OpportunityIDFromContext(WithOpportunityID(ctx, "")) != "" {
+		t.Error("WithOpportunityID(\"\") stored an empty ID")
+	}
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+	})
+	if got := trace.SpanContextFromContext(ContextWithParent(ctx, parent)); !got.Equal(parent) {
+		t.Errorf("ContextWithParent() span = %v, want %v", got, parent)
+	}
+}
@@ -0,0 +1,422 @@
+// Package backtest 回测
+// 职责：在模拟时钟上把录制的行情回放给套利引擎，用模拟成交执行器下单，统计收益、命中率、延迟和手续费
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/clock"
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/execution"
+	"arbitragex/pkg/marketdata"
+)
+
+// positionTolerance 两边成交数量差异的容差
+var positionTolerance = decimal.RequireFromString("0.00000001")
+
+// Config 回测配置
+type Config struct {
+	// Engine 套利引擎配置（为空时使用默认配置）
+	Engine *engine.EngineConfig `json:"engine"`
+
+	// Symbols 扫描的交易对（为空时扫描行情中出现过的所有交易对）
+	Symbols []string `json:"symbols,omitempty"`
+
+	// ScanInterval 扫描间隔（模拟时间）
+	ScanInterval time.Duration `json:"scan_interval"`
+
+	// ExecutionLatency 发现机会到下单成交的延迟（模拟时间，期间到达的行情会影响成交价格）
+	ExecutionLatency time.Duration `json:"execution_latency"`
+
+	// PriceTTL 行情有效期（超过后不参与扫描）
+	PriceTTL time.Duration `json:"price_ttl"`
+}
+
+// DefaultConfig 默认回测配置
+func DefaultConfig() *Config {
+	return &Config{
+		Engine:           engine.DefaultEngineConfig(),
+		ScanInterval:     time.Second,
+		ExecutionLatency: 100 * time.Millisecond,
+		PriceTTL:         5 * time.Second,
+	}
+}
+
+// Trade 一次模拟交易
+type Trade struct {
+	OpportunityID string    `json:"opportunity_id"`
+	Symbol        string    `json:"symbol"`
+	BuyExchange   string    `json:"buy_exchange"`
+	SellExchange  string    `json:"sell_exchange"`
+	DiscoveredAt  time.Time `json:"discovered_at"` // 机会首次发现时间
+	ExecutedAt    time.Time `json:"executed_at"`   // 下单成交时间
+
+	// Orders 买入、卖出以及对冲订单
+	Orders []*execution.Order `json:"orders"`
+
+	// ExpectedProfit 引擎预期的净收益（USDT）
+	ExpectedProfit decimal.Decimal `json:"expected_profit"`
+
+	// GrossProfit 扣除手续费前的收益（USDT）
+	GrossProfit decimal.Decimal `json:"gross_profit"`
+
+	// Fees 手续费（USDT）
+	Fees decimal.Decimal `json:"fees"`
+
+	// NetProfit 净收益（USDT）
+	NetProfit decimal.Decimal `json:"net_profit"`
+}
+
+// Latency 机会首次发现到成交的时间
+func (t *Trade) Latency() time.Duration {
+	return t.ExecutedAt.Sub(t.DiscoveredAt)
+}
+
+// Report 回测报告
+type Report struct {
+	// MinProfitRate / SlippageRate / MinVolume 本次回测的引擎参数
+	MinProfitRate float64         `json:"min_profit_rate"`
+	SlippageRate  float64         `json:"slippage_rate"`
+	MinVolume     decimal.Decimal `json:"min_volume"`
+
+	// Start / End 行情时间范围
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Events 回放的行情事件数
+	Events int `json:"events"`
+
+	// Scans 扫描次数
+	Scans int `json:"scans"`
+
+	// Opportunities 发现的机会数
+	Opportunities int `json:"opportunities"`
+
+	// Trades 模拟交易
+	Trades []*Trade `json:"trades"`
+
+	// Wins 净收益为正的交易数
+	Wins int `json:"wins"`
+
+	// HitRate 命中率（Wins / 交易数）
+	HitRate float64 `json:"hit_rate"`
+
+	// GrossProfit / Fees / NetProfit 汇总收益（USDT）
+	GrossProfit decimal.Decimal `json:"gross_profit"`
+	Fees        decimal.Decimal `json:"fees"`
+	NetProfit   decimal.Decimal `json:"net_profit"`
+
+	// FeeDrag 手续费占扣费前收益的比例
+	FeeDrag float64 `json:"fee_drag"`
+
+	// AvgLatency 平均持有延迟（机会首次发现到成交）
+	AvgLatency time.Duration `json:"avg_latency"`
+}
+
+// pendingTrade 等待执行延迟到期的机会
+type pendingTrade struct {
+	opp       *engine.ArbitrageOpportunity
+	executeAt time.Time
+}
+
+// runner 一次回测的状态
+type runner struct {
+	config     *Config
+	clock      *clock.Manual // 模拟时钟（按行情时间推进，不会后退）
+	priceCache *cache.MemoryPriceCache
+	engine     *engine.ArbitrageEngine
+	executors  map[string]*execution.PaperExecutor
+	symbols    []string
+	exchanges  []string
+	pending    []*pendingTrade
+	nextScan   time.Time
+	report     *Report
+}
+
+// Run 回放行情并返回回测报告
+// 参数:
+//   - ctx: 上下文对象
+//   - reader: 按时间排序的行情事件
+//   - config: 回测配置
+// 返回:
+//   - *Report: 回测报告
+//   - error: 错误信息
+func Run(ctx context.Context, reader marketdata.EventReader, config *Config) (*Report, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	engineConfig := engine.DefaultEngineConfig()
+	if config.Engine != nil {
+		copied := *config.Engine
+		engineConfig = &copied
+	}
+	if config.ScanInterval <= 0 {
+		return nil, fmt.Errorf("扫描间隔必须大于 0")
+	}
+
+	r := &runner{
+		config:     config,
+		clock:      clock.NewManual(time.Time{}),
+		priceCache: cache.NewMemoryPriceCache(config.PriceTTL),
+		executors:  make(map[string]*execution.PaperExecutor),
+		symbols:    append([]string(nil), config.Symbols...),
+		report: &Report{
+			MinProfitRate: engineConfig.MinProfitRate,
+			SlippageRate:  engineConfig.SlippageRate,
+			MinVolume:     engineConfig.MinVolume,
+			Trades:        make([]*Trade, 0),
+		},
+	}
+	r.priceCache.SetClock(r.clock)
+	r.engine = engine.NewArbitrageEngine(engineConfig, r.priceCache)
+	r.engine.SetClock(r.clock)
+	r.engine.OnEvent(func(ctx context.Context, event *engine.OpportunityEvent) {
+		if event.Type != engine.OpportunityOpened {
+			return
+		}
+		r.report.Opportunities++
+		r.pending = append(r.pending, &pendingTrade{opp: event.Opportunity, executeAt: event.Time.Add(config.ExecutionLatency)})
+	})
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		event, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if r.report.Events == 0 {
+			r.report.Start = event.Time
+			r.nextScan = event.Time.Add(config.ScanInterval)
+		}
+		r.advance(ctx, event.Time)
+		r.apply(ctx, event)
+		r.report.Events++
+		r.report.End = event.Time
+	}
+
+	// 行情结束后执行剩余的机会（使用最后的订单簿）
+	for _, p := range r.pending {
+		r.clock.Set(p.executeAt)
+		r.execute(ctx, p.opp)
+	}
+	r.pending = nil
+
+	r.summarize()
+	return r.report, nil
+}
+
+// advance 按时间顺序执行 t 之前到期的扫描和下单
+func (r *runner) advance(ctx context.Context, t time.Time) {
+	for {
+		next := r.nextScan
+		isScan := true
+		for _, p := range r.pending {
+			if p.executeAt.Before(next) {
+				next = p.executeAt
+				isScan = false
+			}
+		}
+		if next.After(t) {
+			return
+		}
+
+		r.clock.Set(next)
+		if isScan {
+			r.scan(ctx)
+			r.nextScan = r.nextScan.Add(r.config.ScanInterval)
+			continue
+		}
+
+		due := r.pending[:0]
+		var ready []*pendingTrade
+		for _, p := range r.pending {
+			if p.executeAt.After(next) {
+				due = append(due, p)
+			} else {
+				ready = append(ready, p)
+			}
+		}
+		r.pending = due
+		for _, p := range ready {
+			r.execute(ctx, p.opp)
+		}
+	}
+}
+
+// apply 应用一个行情事件
+func (r *runner) apply(ctx context.Context, event *marketdata.Event) {
+	r.clock.Set(event.Time)
+	r.observe(event.Exchange, event.Symbol)
+
+	switch event.Type {
+	case marketdata.EventTicker:
+		r.priceCache.SetPrice(ctx, event.Exchange, event.Symbol, event.Ticker)
+	case marketdata.EventOrderBook:
+		book := event.OrderBook
+		r.executor(event.Exchange).UpdateOrderBook(book)
+
+		// 只录制了订单簿时用最优买卖价作为行情
+		if len(book.Bids) > 0 && len(book.Asks) > 0 {
+			r.priceCache.SetPrice(ctx, event.Exchange, event.Symbol, &cache.PriceData{
+				Exchange:  event.Exchange,
+				Symbol:    event.Symbol,
+				BidPrice:  book.Bids[0].Price,
+				AskPrice:  book.Asks[0].Price,
+				LastPrice: book.Bids[0].Price.Add(book.Asks[0].Price).Div(decimal.NewFromInt(2)),
+				Timestamp: event.Time,
+			})
+		}
+	}
+}
+
+// observe 记录行情中出现过的交易所和交易对
+func (r *runner) observe(exchange, symbol string) {
+	if !contains(r.exchanges, exchange) {
+		r.exchanges = append(r.exchanges, exchange)
+		sort.Strings(r.exchanges)
+	}
+	if len(r.config.Symbols) == 0 && !contains(r.symbols, symbol) {
+		r.symbols = append(r.symbols, symbol)
+		sort.Strings(r.symbols)
+	}
+}
+
+// scan 扫描一次套利机会
+func (r *runner) scan(ctx context.Context) {
+	r.report.Scans++
+	r.engine.ScanOpportunities(ctx, r.symbols, r.exchanges)
+}
+
+// execute 两边同时以 IOC 限价单下单（价格放宽滑点），成交数量不一致时以市价单对冲
+func (r *runner) execute(ctx context.Context, opp *engine.ArbitrageOpportunity) {
+	engineConfig := r.engine.Config()
+	amount := engineConfig.MinVolume.Div(opp.BuyPrice)
+
+	trade := &Trade{
+		OpportunityID:  opp.ID,
+		Symbol:         opp.Symbol,
+		BuyExchange:    opp.BuyExchange,
+		SellExchange:   opp.SellExchange,
+		DiscoveredAt:   opp.DiscoveredAt,
+		ExecutedAt:     r.clock.Now(),
+		ExpectedProfit: opp.NetProfit,
+	}
+
+	buy := r.place(ctx, trade, &execution.PlaceOrderRequest{
+		Exchange: opp.BuyExchange, Symbol: opp.Symbol, Side: execution.OrderSideBuy,
+		Type: execution.OrderTypeLimit, TimeInForce: execution.TimeInForceIOC,
+		Price: opp.BuyPrice.Mul(decimal.NewFromFloat(1 + engineConfig.SlippageRate)), Amount: amount,
+	})
+	sell := r.place(ctx, trade, &execution.PlaceOrderRequest{
+		Exchange: opp.SellExchange, Symbol: opp.Symbol, Side: execution.OrderSideSell,
+		Type: execution.OrderTypeLimit, TimeInForce: execution.TimeInForceIOC,
+		Price: opp.SellPrice.Mul(decimal.NewFromFloat(1 - engineConfig.SlippageRate)), Amount: amount,
+	})
+
+	// 多买入的部分在买入交易所卖出，多卖出的部分在卖出交易所买回
+	net := buy.Sub(sell)
+	switch {
+	case net.GreaterThan(positionTolerance):
+		r.place(ctx, trade, &execution.PlaceOrderRequest{
+			Exchange: opp.BuyExchange, Symbol: opp.Symbol, Side: execution.OrderSideSell,
+			Type: execution.OrderTypeMarket, Amount: net,
+		})
+	case net.LessThan(positionTolerance.Neg()):
+		r.place(ctx, trade, &execution.PlaceOrderRequest{
+			Exchange: opp.SellExchange, Symbol: opp.Symbol, Side: execution.OrderSideBuy,
+			Type: execution.OrderTypeMarket, Amount: net.Neg(),
+		})
+	}
+
+	if buy.LessThanOrEqual(positionTolerance) && sell.LessThanOrEqual(positionTolerance) {
+		// 两边均未成交，不计入交易
+		return
+	}
+
+	for _, order := range trade.Orders {
+		quote := order.FilledAmount.Mul(order.AveragePrice)
+		if order.Side == execution.OrderSideBuy {
+			trade.GrossProfit = trade.GrossProfit.Sub(quote)
+		} else {
+			trade.GrossProfit = trade.GrossProfit.Add(quote)
+		}
+		trade.Fees = trade.Fees.Add(order.Fee)
+	}
+	trade.NetProfit = trade.GrossProfit.Sub(trade.Fees)
+	r.report.Trades = append(r.report.Trades, trade)
+}
+
+// place 下单并记录订单，返回成交数量（下单失败时为 0）
+func (r *runner) place(ctx context.Context, trade *Trade, req *execution.PlaceOrderRequest) decimal.Decimal {
+	order, err := r.executor(req.Exchange).PlaceOrder(ctx, req)
+	if err != nil {
+		return decimal.Zero
+	}
+	trade.Orders = append(trade.Orders, order)
+	return order.FilledAmount
+}
+
+// executor 获取交易所的模拟成交执行器（手续费取引擎配置）
+func (r *runner) executor(exchange string) *execution.PaperExecutor {
+	if executor, ok := r.executors[exchange]; ok {
+		return executor
+	}
+
+	makerFee, takerFee := 0.001, 0.001
+	for _, fee := range r.engine.Config().TradingFees {
+		if fee.Exchange == exchange {
+			makerFee, takerFee = fee.MakerFee, fee.TakerFee
+		}
+	}
+
+	executor := execution.NewPaperExecutor(exchange, makerFee, takerFee)
+	executor.SetClock(r.clock)
+	r.executors[exchange] = executor
+	return executor
+}
+
+// summarize 汇总交易统计
+func (r *runner) summarize() {
+	report := r.report
+	var latency time.Duration
+	for _, trade := range report.Trades {
+		if trade.NetProfit.IsPositive() {
+			report.Wins++
+		}
+		report.GrossProfit = report.GrossProfit.Add(trade.GrossProfit)
+		report.Fees = report.Fees.Add(trade.Fees)
+		report.NetProfit = report.NetProfit.Add(trade.NetProfit)
+		latency += trade.Latency()
+	}
+
+	if n := len(report.Trades); n > 0 {
+		report.HitRate = float64(report.Wins) / float64(n)
+		report.AvgLatency = latency / time.Duration(n)
+	}
+	if !report.GrossProfit.IsZero() {
+		report.FeeDrag = report.Fees.Div(report.GrossProfit.Abs()).Float64()
+	}
+}
+
+// contains 切片中是否包含字符串
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+// Package backtest 回测单元测试
+package backtest
+
+import (
+	"context"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/execution"
+	"arbitragex/pkg/marketdata"
+)
+
+// sliceReader 按顺序返回内存中的行情事件
+type sliceReader struct {
+	events []*marketdata.Event
+}
+
+// Next 读取下一个事件
+func (r *sliceReader) Next() (*marketdata.Event, error) {
+	if len(r.events) == 0 {
+		return nil, io.EOF
+	}
+	event := r.events[0]
+	r.events = r.events[1:]
+	return event, nil
+}
+
+// book 构造单档订单簿事件
+func book(exchange string, t time.Time, bid, ask float64) *marketdata.Event {
+	return marketdata.NewOrderBookEvent(&execution.OrderBook{
+		Exchange:  exchange,
+		Symbol:    "BTC/USDT",
+		Bids:      []execution.OrderBookLevel{{Price: decimal.NewFromFloat(bid), Amount: decimal.NewFromInt(10)}},
+		Asks:      []execution.OrderBookLevel{{Price: decimal.NewFromFloat(ask), Amount: decimal.NewFromInt(10)}},
+		Timestamp: t,
+	})
+}
+
+// TestRun 测试价差出现到消失期间只交易一次，并统计收益和延迟
+func TestRun(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	reader := &sliceReader{events: []*marketdata.Event{
+		book("binance", t0, 42990, 43000),
+		book("okx", t0, 44000, 44010),
+		book("binance", t0.Add(2500*time.Millisecond), 43990, 44000),
+		book("okx", t0.Add(5*time.Second), 44000, 44010),
+	}}
+
+	config := DefaultConfig()
+	report, err := Run(context.Background(), reader, config)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if report.Events != 4 || report.Scans != 5 {
+		t.Errorf("events = %d scans = %d, want 4 5", report.Events, report.Scans)
+	}
+	if report.Opportunities != 1 || len(report.Trades) != 1 {
+		t.Fatalf("opportunities = %d trades = %d, want 1 1", report.Opportunities, len(report.Trades))
+	}
+
+	trade := report.Trades[0]
+	if trade.BuyExchange != "binance" || trade.SellExchange != "okx" || len(trade.Orders) != 2 {
+		t.Errorf("trade = %s -> %s with %d orders, want binance -> okx with 2", trade.BuyExchange, trade.SellExchange, len(trade.Orders))
+	}
+	if trade.Latency() != config.ExecutionLatency || report.AvgLatency != config.ExecutionLatency {
+		t.Errorf("latency = %v avg %v, want %v", trade.Latency(), report.AvgLatency, config.ExecutionLatency)
+	}
+
+	amount := 1000.0 / 43000
+	wantGross := amount * (44000 - 43000)
+	wantFees := amount * (43000 + 44000) * 0.001
+	if math.Abs(report.GrossProfit.Float64()-wantGross) > 1e-6 || math.Abs(report.Fees.Float64()-wantFees) > 1e-6 {
+		t.Errorf("gross = %v fees = %v, want %v %v", report.GrossProfit, report.Fees, wantGross, wantFees)
+	}
+	if math.Abs(report.NetProfit.Float64()-(wantGross-wantFees)) > 1e-6 || report.HitRate != 1 {
+		t.Errorf("net = %v hit rate = %v, want %v 1", report.NetProfit, report.HitRate, wantGross-wantFees)
+	}
+	if math.Abs(report.FeeDrag-wantFees/wantGross) > 1e-9 {
+		t.Errorf("fee drag = %v, want %v", report.FeeDrag, wantFees/wantGross)
+	}
+}
+
+// TestRun_Sweep 测试提高最小收益率后不再交易
+func TestRun_Sweep(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := func() *sliceReader {
+		return &sliceReader{events: []*marketdata.Event{
+			book("binance", t0, 42990, 43000),
+			book("okx", t0, 44000, 44010),
+			book("okx", t0.Add(3*time.Second), 44000, 44010),
+		}}
+	}
+
+	for _, tt := range []struct {
+		minProfitRate float64
+		wantTrades    int
+	}{
+		{0.005, 1},
+		{0.05, 0},
+	} {
+		config := DefaultConfig()
+		engineConfig := engine.DefaultEngineConfig()
+		engineConfig.MinProfitRate = tt.minProfitRate
+		config.Engine = engineConfig
+
+		report, err := Run(context.Background(), events(), config)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if len(report.Trades) != tt.wantTrades || report.MinProfitRate != tt.minProfitRate {
+			t.Errorf("min profit rate %v: trades = %d, want %d", report.MinProfitRate, len(report.Trades), tt.wantTrades)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Package bus 服务间消息总线
+// 职责：在价格服务、套利引擎和交易服务之间传递价格更新、套利机会和执行结果；
+// 提供 Redis Streams 实现（多进程部署）和内存实现（测试和单进程模式）
+//
+// 投递语义：
+//   - 至少一次：处理函数返回 nil 后才确认消息，返回错误或进程崩溃时消息保留，超过确认超时后重新投递
+//   - 消费组：同一消费组内每条消息只投递给一个消费者；不同消费组各自收到全部消息
+//   - 新建的消费组只接收之后发布的消息
+//
+// 重新投递可能导致重复处理，处理函数需要幂等（或丢弃过期的消息）
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// 消息主题
+const (
+	TopicPrices        = "arbitragex:prices"        // 价格更新（cache.PriceData）
+	TopicOpportunities = "arbitragex:opportunities" // 套利机会生命周期事件（engine.OpportunityEvent）
+	TopicExecutions    = "arbitragex:executions"    // 执行结果（execution.ExecutionResult）
+)
+
+const (
+	defaultMaxLen     = 100000           // 默认每个主题保留的消息数
+	defaultAckTimeout = 30 * time.Second // 默认确认超时
+)
+
+// ErrClosed 消息总线已关闭
+var ErrClosed = errors.New("消息总线已关闭")
+
+// Message 消息
+type Message struct {
+	ID          string            // 消息 ID（同一主题内递增）
+	Topic       string            // 主题
+	Payload     []byte            // 消息内容（JSON）
+	Header      map[string]string // 消息头（发布时 ctx 中的链路追踪上下文，W3C traceparent / tracestate）
+	Redelivered bool              // 是否为重新投递（之前的处理未确认）
+}
+
+// Decode 解析 JSON 消息内容
+func (m *Message) Decode(v any) error {
+	return json.Unmarshal(m.Payload, v)
+}
+
+// propagator 消息头中的链路追踪上下文格式
+var propagator = propagation.TraceContext{}
+
+// traceHeader 将 ctx 中的 span 编码为消息头（ctx 中没有 span 时为空）
+func traceHeader(ctx context.Context) map[string]string {
+	header := make(map[string]string)
+	propagator.Inject(ctx, propagation.MapCarrier(header))
+	if len(header) == 0 {
+		return nil
+	}
+	return header
+}
+
+// handlerContext 处理函数的上下文：包含发布消息时的 span，处理函数中创建的 span 与发布方在同一条链路中
+func (m *Message) handlerContext(ctx context.Context) context.Context {
+	if len(m.Header) == 0 {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier(m.Header))
+}
+
+// Handler 消息处理函数（返回 nil 时确认消息，返回错误时消息在确认超时后重新投递）
+// ctx 中包含发布消息时的 span（如有）
+// 同一消费者的消息按顺序处理，处理函数阻塞期间不会收到新消息
+type Handler func(ctx context.Context, msg *Message) error
+
+// Bus 消息总线
+type Bus interface {
+	// Publish 发布消息（ctx 中的 span 随消息传递给处理函数）
+	// 返回:
+	//   - string: 消息 ID
+	Publish(ctx context.Context, topic string, payload []byte) (string, error)
+
+	// Subscribe 以消费组中的消费者身份订阅主题（消费组不存在时创建），在后台协程中处理消息，直到 ctx 取消或总线关闭
+	// 参数:
+	//   - topic: 主题
+	//   - group: 消费组（同组内的消费者分摊消息）
+	//   - consumer: 消费者名称（同一消费组内唯一；Redis 实现中重启后沿用同一名称会先处理上次未确认的消息）
+	//   - handler: 消息处理函数
+	Subscribe(ctx context.Context, topic, group, consumer string, handler Handler) error
+
+	// Close 停止所有订阅并关闭总线
+	Close() error
+}
+
+// PublishJSON 将 v 编码为 JSON 后发布
+func PublishJSON(ctx context.Context, b Bus, topic string, v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return b.Publish(ctx, topic, payload)
+}
+
+// Conf 消息总线配置
+type Conf struct {
+	Redis      redis.RedisConf // Redis 连接（Streams）
+	Consumer   string          `json:",optional"`       // 消费者名称（为空时使用主机名；同一服务的多个实例需要不同的名称）
+	MaxLen     int64           `json:",default=100000"` // 每个主题保留的消息数（近似裁剪）
+	AckTimeout time.Duration   `json:",default=30s"`    // 确认超时（超过后未确认的消息重新投递给组内的消费者）
+}
+
+// Enabled 是否配置了消息总线
+func (c Conf) Enabled() bool {
+	return c.Redis.Host != ""
+}
+
+// ConsumerName 消费者名称（未配置时使用主机名）
+func (c Conf) ConsumerName() string {
+	if c.Consumer != "" {
+		return c.Consumer
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "default"
+}
@@ -0,0 +1,58 @@
+// Package bus 消息总线链路追踪单元测试
+package bus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestBus_TraceContext 测试发布时 ctx 中的 span 随消息传递给处理函数（内存和 Redis 实现）
+func TestBus_TraceContext(t *testing.T) {
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:     trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	buses := map[string]Bus{
+		"memory": NewMemoryBus(10, time.Minute),
+		"redis":  newTestRedisBus(t, miniredis.RunT(t), time.Minute),
+	}
+	for name, b := range buses {
+		t.Run(name, func(t *testing.T) {
+			defer b.Close()
+			ctx := context.Background()
+
+			spans := make(chan trace.SpanContext, 2)
+			handler := func(ctx context.Context, msg *Message) error {
+				spans <- trace.SpanContextFromContext(ctx)
+				return nil
+			}
+			if err := b.Subscribe(ctx, TopicOpportunities, "trade", "trade-1", handler); err != nil {
+				t.Fatalf("Subscribe() error = %v", err)
+			}
+
+			if _, err := b.Publish(trace.ContextWithSpanContext(ctx, parent), TopicOpportunities, []byte("traced")); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			if _, err := b.Publish(ctx, TopicOpportunities, []byte("untraced")); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+
+			for i, want := range []trace.SpanContext{parent.WithRemote(true), {}} {
+				select {
+				case got := <-spans:
+					if !got.Equal(want) {
+						t.Errorf("message %d span = %v, want %v", i, got, want)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("等待第 %d 条消息超时", i+1)
+				}
+			}
+		})
+	}
+}
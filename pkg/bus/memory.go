@@ -0,0 +1,232 @@
+package bus
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// memoryTopic 内存主题
+type memoryTopic struct {
+	seq      uint64     // 最后一条消息的序号（从 1 开始）
+	base     uint64     // messages[0] 的序号
+	messages []*Message // 保留的消息
+	groups   map[string]*memoryGroup
+}
+
+// memoryGroup 内存消费组
+type memoryGroup struct {
+	next    uint64 // 下一条待投递消息的序号
+	pending map[uint64]*memoryPending
+}
+
+// memoryPending 已投递未确认的消息
+type memoryPending struct {
+	message     *Message
+	consumer    string
+	deliveredAt time.Time
+}
+
+// MemoryBus 内存消息总线（测试和单进程模式）
+// 语义与 Redis 实现一致：消费组、确认和超时重新投递；消息不持久化，进程退出后丢失
+type MemoryBus struct {
+	maxLen     int
+	ackTimeout time.Duration
+	topics     map[string]*memoryTopic
+	notify     chan struct{} // 有新消息时关闭并替换，唤醒等待的消费者
+	done       chan struct{}
+	closed     bool
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+	logger     logx.Logger
+}
+
+// NewMemoryBus 创建内存消息总线
+// 参数:
+//   - maxLen: 每个主题保留的消息数（≤ 0 时使用默认值 100000）
+//   - ackTimeout: 确认超时（≤ 0 时使用默认值 30s）
+// 返回:
+//   - *MemoryBus: 内存消息总线
+func NewMemoryBus(maxLen int, ackTimeout time.Duration) *MemoryBus {
+	if maxLen <= 0 {
+		maxLen = defaultMaxLen
+	}
+	if ackTimeout <= 0 {
+		ackTimeout = defaultAckTimeout
+	}
+
+	return &MemoryBus{
+		maxLen:     maxLen,
+		ackTimeout: ackTimeout,
+		topics:     make(map[string]*memoryTopic),
+		notify:     make(chan struct{}),
+		done:       make(chan struct{}),
+		logger:     logx.WithContext(context.Background()),
+	}
+}
+
+// Publish 发布消息
+func (b *MemoryBus) Publish(ctx context.Context, topic string, payload []byte) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return "", ErrClosed
+	}
+
+	t := b.topic(topic)
+	t.seq++
+	msg := &Message{
+		ID:      strconv.FormatUint(t.seq, 10),
+		Topic:   topic,
+		Payload: append([]byte(nil), payload...),
+		Header:  traceHeader(ctx),
+	}
+	t.messages = append(t.messages, msg)
+	if trim := len(t.messages) - b.maxLen; trim > 0 {
+		t.messages = append([]*Message(nil), t.messages[trim:]...)
+		t.base += uint64(trim)
+	}
+
+	close(b.notify)
+	b.notify = make(chan struct{})
+	return msg.ID, nil
+}
+
+// Subscribe 以消费组中的消费者身份订阅主题
+func (b *MemoryBus) Subscribe(ctx context.Context, topic, group, consumer string, handler Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	t := b.topic(topic)
+	if _, ok := t.groups[group]; !ok {
+		t.groups[group] = &memoryGroup{
+			next:    t.seq + 1,
+			pending: make(map[uint64]*memoryPending),
+		}
+	}
+
+	b.wg.Add(1)
+	go b.consume(ctx, topic, group, consumer, handler)
+	return nil
+}
+
+// Close 停止所有订阅（等待正在处理的消息结束）
+func (b *MemoryBus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.done)
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return nil
+}
+
+// topic 获取主题（不存在时创建，需持有锁）
+func (b *MemoryBus) topic(name string) *memoryTopic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &memoryTopic{
+			base:   1,
+			groups: make(map[string]*memoryGroup),
+		}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// consume 消费循环
+func (b *MemoryBus) consume(ctx context.Context, topic, group, consumer string, handler Handler) {
+	defer b.wg.Done()
+
+	for {
+		msg, seq, notify := b.fetch(topic, group, consumer)
+		if msg == nil {
+			// 没有可投递的消息：等待新消息或未确认消息超时
+			timer := time.NewTimer(b.ackTimeout)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-b.done:
+				timer.Stop()
+				return
+			case <-notify:
+			case <-timer.C:
+			}
+			timer.Stop()
+			continue
+		}
+
+		if err := handler(msg.handlerContext(ctx), msg); err != nil {
+			b.logger.Errorf("处理消息失败 %s/%s (group=%s, consumer=%s): %v", topic, msg.ID, group, consumer, err)
+			continue
+		}
+		b.ack(topic, group, seq)
+	}
+}
+
+// fetch 取下一条待投递的消息：优先重新投递超时未确认的消息，其次投递新消息
+// 返回:
+//   - *Message: 消息（没有可投递的消息时为 nil）
+//   - uint64: 消息序号（用于确认）
+//   - <-chan struct{}: 有新消息时关闭的通知
+func (b *MemoryBus) fetch(topic, group, consumer string) (*Message, uint64, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t := b.topics[topic]
+	g := t.groups[group]
+	now := time.Now()
+
+	var (
+		expired    *memoryPending
+		expiredSeq uint64
+	)
+	for seq, p := range g.pending {
+		if now.Sub(p.deliveredAt) >= b.ackTimeout && (expired == nil || seq < expiredSeq) {
+			expired, expiredSeq = p, seq
+		}
+	}
+	if expired != nil {
+		expired.consumer = consumer
+		expired.deliveredAt = now
+		redelivered := *expired.message
+		redelivered.Redelivered = true
+		return &redelivered, expiredSeq, b.notify
+	}
+
+	if g.next < t.base {
+		// 未投递的消息已被裁剪
+		g.next = t.base
+	}
+	if g.next > t.seq {
+		return nil, 0, b.notify
+	}
+
+	seq := g.next
+	g.next++
+	msg := t.messages[seq-t.base]
+	g.pending[seq] = &memoryPending{message: msg, consumer: consumer, deliveredAt: now}
+	delivered := *msg
+	return &delivered, seq, b.notify
+}
+
+// ack 确认消息
+func (b *MemoryBus) ack(topic, group string, seq uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.topics[topic].groups[group].pending, seq)
+}
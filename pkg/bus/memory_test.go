@@ -0,0 +1,181 @@
+// Package bus 内存消息总线单元测试
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector 收集处理过的消息
+type collector struct {
+	mu       sync.Mutex
+	messages []*Message
+	received chan struct{}
+}
+
+// newCollector 创建消息收集器
+func newCollector() *collector {
+	return &collector{received: make(chan struct{}, 1000)}
+}
+
+// handle 记录消息（Handler）
+func (c *collector) handle(ctx context.Context, msg *Message) error {
+	c.mu.Lock()
+	c.messages = append(c.messages, msg)
+	c.mu.Unlock()
+	c.received <- struct{}{}
+	return nil
+}
+
+// wait 等待收到 n 条消息
+func (c *collector) wait(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-c.received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("等待第 %d 条消息超时", i+1)
+		}
+	}
+}
+
+// payloads 已收到的消息内容（排序后）
+func (c *collector) payloads() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	payloads := make([]string, 0, len(c.messages))
+	for _, msg := range c.messages {
+		payloads = append(payloads, string(msg.Payload))
+	}
+	sort.Strings(payloads)
+	return payloads
+}
+
+// TestMemoryBus_ConsumerGroups 测试同组消费者分摊消息、不同消费组各自收到全部消息
+func TestMemoryBus_ConsumerGroups(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBus(0, time.Minute)
+	defer b.Close()
+
+	// 订阅前发布的消息不会投递给新建的消费组
+	if _, err := b.Publish(ctx, TopicPrices, []byte("old")); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	engine := newCollector()
+	trade := newCollector()
+	for _, consumer := range []string{"trade-1", "trade-2"} {
+		if err := b.Subscribe(ctx, TopicPrices, "trade", consumer, trade.handle); err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+	}
+	if err := b.Subscribe(ctx, TopicPrices, "engine", "engine-1", engine.handle); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	var want []string
+	for i := 0; i < 20; i++ {
+		payload := fmt.Sprintf("p%02d", i)
+		want = append(want, payload)
+		if _, err := PublishJSON(ctx, b, TopicPrices, payload); err != nil {
+			t.Fatalf("PublishJSON() error = %v", err)
+		}
+	}
+	engine.wait(t, 20)
+	trade.wait(t, 20)
+
+	for name, c := range map[string]*collector{"engine": engine, "trade": trade} {
+		got := c.payloads()
+		if len(got) != len(want) {
+			t.Fatalf("%s 收到 %d 条消息, want %d", name, len(got), len(want))
+		}
+		for i, payload := range got {
+			var decoded string
+			if err := (&Message{Payload: []byte(payload)}).Decode(&decoded); err != nil || decoded != want[i] {
+				t.Errorf("%s[%d] = %s, want %q", name, i, payload, want[i])
+			}
+		}
+	}
+}
+
+// TestMemoryBus_Redelivery 测试处理失败的消息在确认超时后重新投递
+func TestMemoryBus_Redelivery(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBus(0, 50*time.Millisecond)
+	defer b.Close()
+
+	var (
+		mu       sync.Mutex
+		attempts []*Message
+	)
+	done := make(chan struct{})
+	err := b.Subscribe(ctx, TopicOpportunities, "trade", "trade-1", func(ctx context.Context, msg *Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts = append(attempts, msg)
+		if len(attempts) == 1 {
+			return errors.New("暂时失败")
+		}
+		close(done)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	id, err := b.Publish(ctx, TopicOpportunities, []byte("opp-1"))
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("消息未重新投递")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(attempts) != 2 || attempts[0].Redelivered || !attempts[1].Redelivered || attempts[1].ID != id {
+		t.Errorf("attempts = %+v, want first delivery then redelivery of %s", attempts, id)
+	}
+}
+
+// TestMemoryBus_Close 测试关闭后停止订阅并拒绝发布
+func TestMemoryBus_Close(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := NewMemoryBus(2, time.Minute)
+
+	c := newCollector()
+	if err := b.Subscribe(ctx, TopicExecutions, "engine", "engine-1", c.handle); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	cancel()
+
+	// 消费者已退出，超出 maxLen 的消息被裁剪
+	for i := 0; i < 5; i++ {
+		if _, err := b.Publish(context.Background(), TopicExecutions, []byte{byte('0' + i)}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if got := len(b.topics[TopicExecutions].messages); got != 2 {
+		t.Errorf("保留 %d 条消息, want 2", got)
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := b.Publish(context.Background(), TopicExecutions, nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish() after Close error = %v, want ErrClosed", err)
+	}
+	if err := b.Subscribe(context.Background(), TopicExecutions, "engine", "engine-1", c.handle); !errors.Is(err, ErrClosed) {
+		t.Errorf("Subscribe() after Close error = %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,269 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+const (
+	payloadField = "payload"   // 消息内容字段
+	headerPrefix = "header:"   // 消息头字段前缀（如 header:traceparent）
+	readBatch    = 100         // 每次读取的消息数
+	readBlock    = time.Second // 等待新消息的最长时间
+	retryDelay   = time.Second // Redis 出错后的重试间隔
+)
+
+// publishScript 追加消息并近似裁剪到 MaxLen 条（ARGV[2:] 为字段名和值）
+const publishScript = `local args = {'XADD', KEYS[1], 'MAXLEN', '~', ARGV[1], '*'}
+for i = 2, #ARGV do args[#args + 1] = ARGV[i] end
+return redis.call(unpack(args))`
+
+// RedisBus Redis Streams 消息总线
+// 每个主题对应一个 Stream，消费组对应 Stream 的消费组；每个订阅使用独立的阻塞连接读取消息，
+// 启动时先处理本消费者上次未确认的消息，运行中定期认领组内超过确认超时未确认的消息（包括已退出的消费者的消息）
+type RedisBus struct {
+	rds        *redis.Redis
+	maxLen     int64
+	ackTimeout time.Duration
+	cancels    []context.CancelFunc
+	closed     bool
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+	logger     logx.Logger
+}
+
+// NewRedisBus 创建 Redis Streams 消息总线
+// 参数:
+//   - rds: Redis 客户端
+//   - maxLen: 每个主题保留的消息数（≤ 0 时使用默认值 100000）
+//   - ackTimeout: 确认超时（≤ 0 时使用默认值 30s）
+// 返回:
+//   - *RedisBus: 消息总线
+func NewRedisBus(rds *redis.Redis, maxLen int64, ackTimeout time.Duration) *RedisBus {
+	if maxLen <= 0 {
+		maxLen = defaultMaxLen
+	}
+	if ackTimeout <= 0 {
+		ackTimeout = defaultAckTimeout
+	}
+
+	return &RedisBus{
+		rds:        rds,
+		maxLen:     maxLen,
+		ackTimeout: ackTimeout,
+		logger:     logx.WithContext(context.Background()),
+	}
+}
+
+// MustNewRedisBus 按配置创建 Redis Streams 消息总线（配置错误时退出）
+func MustNewRedisBus(c Conf) *RedisBus {
+	return NewRedisBus(redis.MustNewRedis(c.Redis), c.MaxLen, c.AckTimeout)
+}
+
+// Publish 发布消息
+func (b *RedisBus) Publish(ctx context.Context, topic string, payload []byte) (string, error) {
+	b.mu.Lock()
+	closed := b.closed
+	b.mu.Unlock()
+	if closed {
+		return "", ErrClosed
+	}
+
+	args := []any{b.maxLen, payloadField, payload}
+	for key, value := range traceHeader(ctx) {
+		args = append(args, headerPrefix+key, value)
+	}
+	reply, err := b.rds.EvalCtx(ctx, publishScript, []string{topic}, args...)
+	if err != nil {
+		return "", err
+	}
+	id, ok := reply.(string)
+	if !ok {
+		return "", fmt.Errorf("XADD 返回了意外的结果: %v", reply)
+	}
+	return id, nil
+}
+
+// Subscribe 以消费组中的消费者身份订阅主题（消费组不存在时从最新位置创建）
+func (b *RedisBus) Subscribe(ctx context.Context, topic, group, consumer string, handler Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	if _, err := b.rds.XGroupCreateMkStreamCtx(ctx, topic, group, "$"); err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("创建消费组 %s/%s 失败: %w", topic, group, err)
+	}
+	node, err := redis.CreateBlockingNode(b.rds)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	b.cancels = append(b.cancels, cancel)
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		defer node.Close()
+		b.consume(ctx, node, topic, group, consumer, handler)
+	}()
+	return nil
+}
+
+// Close 停止所有订阅（等待正在处理的消息结束）
+func (b *RedisBus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	for _, cancel := range b.cancels {
+		cancel()
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return nil
+}
+
+// consume 消费循环
+func (b *RedisBus) consume(ctx context.Context, node redis.RedisNode, topic, group, consumer string, handler Handler) {
+	// 先读取本消费者上次未确认的消息（从 ID 0 之后依次读取），读完后读取新消息（ID 为 >）
+	cursor := "0"
+	lastClaim := time.Now()
+
+	for ctx.Err() == nil {
+		if time.Since(lastClaim) >= b.ackTimeout {
+			lastClaim = time.Now()
+			if err := b.claim(ctx, node, topic, group, consumer, handler); err != nil {
+				b.logger.Errorf("认领未确认消息失败 %s (group=%s): %v", topic, group, err)
+			}
+		}
+
+		history := cursor != ">"
+		block := readBlock
+		if history {
+			block = -1
+		}
+		streams, err := b.rds.XReadGroupCtx(ctx, node, group, consumer, readBatch, block, false, topic, cursor)
+		if errors.Is(err, red.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			b.logger.Errorf("读取消息失败 %s (group=%s): %v", topic, group, err)
+			b.sleep(ctx, retryDelay)
+			continue
+		}
+
+		var entries []red.XMessage
+		for _, stream := range streams {
+			entries = append(entries, stream.Messages...)
+		}
+		if history {
+			if len(entries) == 0 {
+				cursor = ">"
+				continue
+			}
+			cursor = entries[len(entries)-1].ID
+		}
+		b.handle(ctx, topic, group, consumer, entries, history, handler)
+	}
+}
+
+// claim 认领组内超过确认超时未确认的消息并处理
+func (b *RedisBus) claim(ctx context.Context, node redis.RedisNode, topic, group, consumer string, handler Handler) error {
+	cursor := "0-0"
+	for ctx.Err() == nil {
+		entries, next, err := node.XAutoClaim(ctx, &red.XAutoClaimArgs{
+			Stream:   topic,
+			Group:    group,
+			MinIdle:  b.ackTimeout,
+			Start:    cursor,
+			Count:    readBatch,
+			Consumer: consumer,
+		}).Result()
+		if err != nil {
+			return err
+		}
+		b.handle(ctx, topic, group, consumer, entries, true, handler)
+		if next == "0-0" || len(entries) == 0 {
+			return nil
+		}
+		cursor = next
+	}
+	return nil
+}
+
+// handle 依次处理消息，处理成功的消息逐条确认
+func (b *RedisBus) handle(ctx context.Context, topic, group, consumer string, entries []red.XMessage, redelivered bool, handler Handler) {
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+
+		payload, ok := entry.Values[payloadField].(string)
+		if !ok {
+			// 格式错误的消息无法处理，直接确认避免反复投递
+			b.logger.Errorf("丢弃格式错误的消息 %s/%s: %v", topic, entry.ID, entry.Values)
+			b.ack(ctx, topic, group, entry.ID)
+			continue
+		}
+
+		msg := &Message{ID: entry.ID, Topic: topic, Payload: []byte(payload), Header: entryHeader(entry), Redelivered: redelivered}
+		if err := handler(msg.handlerContext(ctx), msg); err != nil {
+			b.logger.Errorf("处理消息失败 %s/%s (group=%s, consumer=%s): %v", topic, entry.ID, group, consumer, err)
+			continue
+		}
+		b.ack(ctx, topic, group, entry.ID)
+	}
+}
+
+// entryHeader 读取消息头字段
+func entryHeader(entry red.XMessage) map[string]string {
+	var header map[string]string
+	for field, value := range entry.Values {
+		key, ok := strings.CutPrefix(field, headerPrefix)
+		if !ok {
+			continue
+		}
+		if value, ok := value.(string); ok {
+			if header == nil {
+				header = make(map[string]string)
+			}
+			header[key] = value
+		}
+	}
+	return header
+}
+
+// ack 确认消息（订阅已停止时仍然确认处理完成的消息）
+func (b *RedisBus) ack(ctx context.Context, topic, group, id string) {
+	if _, err := b.rds.XAckCtx(context.WithoutCancel(ctx), topic, group, id); err != nil {
+		b.logger.Errorf("确认消息失败 %s/%s (group=%s): %v", topic, id, group, err)
+	}
+}
+
+// sleep 等待 d 或 ctx 取消
+func (b *RedisBus) sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
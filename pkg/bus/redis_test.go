@@ -0,0 +1,135 @@
+// Package bus Redis Streams 消息总线单元测试
+package bus
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+// newTestRedisBus 创建连接到 miniredis 的消息总线
+func newTestRedisBus(t *testing.T, mr *miniredis.Miniredis, ackTimeout time.Duration) *RedisBus {
+	t.Helper()
+	return NewRedisBus(redis.New(mr.Addr()), 10, ackTimeout)
+}
+
+// TestRedisBus_PublishSubscribe 测试发布和消费组分发
+func TestRedisBus_PublishSubscribe(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	b := newTestRedisBus(t, mr, time.Minute)
+
+	engine := newCollector()
+	trade := newCollector()
+	if err := b.Subscribe(ctx, TopicPrices, "engine", "engine-1", engine.handle); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := b.Subscribe(ctx, TopicPrices, "trade", "trade-1", trade.handle); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	// 重复订阅已存在的消费组
+	if err := b.Subscribe(ctx, TopicPrices, "trade", "trade-2", trade.handle); err != nil {
+		t.Fatalf("Subscribe() existing group error = %v", err)
+	}
+
+	for _, payload := range []string{"a", "b", "c"} {
+		if _, err := b.Publish(ctx, TopicPrices, []byte(payload)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	engine.wait(t, 3)
+	trade.wait(t, 3)
+
+	for name, c := range map[string]*collector{"engine": engine, "trade": trade} {
+		if got := c.payloads(); len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+			t.Errorf("%s 收到 %v, want [a b c]", name, got)
+		}
+	}
+
+	// 关闭时等待处理结束，处理完成的消息都已确认
+	b.Close()
+	pending, err := b.rds.XInfoGroupsCtx(ctx, TopicPrices)
+	if err != nil {
+		t.Fatalf("XInfoGroups() error = %v", err)
+	}
+	for _, group := range pending {
+		if group.Pending != 0 {
+			t.Errorf("group %s pending = %d, want 0", group.Name, group.Pending)
+		}
+	}
+}
+
+// TestRedisBus_Redelivery 测试重启后先处理上次未确认的消息，以及认领超时未确认的消息
+func TestRedisBus_Redelivery(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	// 第一个进程：处理失败，消息保持未确认
+	first := newTestRedisBus(t, mr, time.Minute)
+	failed := make(chan struct{}, 10)
+	err := first.Subscribe(ctx, TopicOpportunities, "trade", "trade-1", func(ctx context.Context, msg *Message) error {
+		failed <- struct{}{}
+		return errors.New("暂时失败")
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	id, err := first.Publish(ctx, TopicOpportunities, []byte("opp-1"))
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	select {
+	case <-failed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("消息未投递")
+	}
+	first.Close()
+
+	// 重启后沿用同一消费者名称：先读取未确认的消息
+	restarted := newTestRedisBus(t, mr, time.Minute)
+	c := newCollector()
+	if err := restarted.Subscribe(ctx, TopicOpportunities, "trade", "trade-1", c.handle); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	c.wait(t, 1)
+	restarted.Close()
+	if msg := c.messages[0]; msg.ID != id || !msg.Redelivered || string(msg.Payload) != "opp-1" {
+		t.Errorf("message = %+v, want redelivered %s", msg, id)
+	}
+
+	// 已退出的消费者留下的未确认消息由组内其他消费者在确认超时后认领
+	var attempts int32
+	crashed := newTestRedisBus(t, mr, time.Minute)
+	err = crashed.Subscribe(ctx, TopicOpportunities, "trade", "trade-2", func(ctx context.Context, msg *Message) error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("暂时失败")
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	id, err = crashed.Publish(ctx, TopicOpportunities, []byte("opp-2"))
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&attempts) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	crashed.Close()
+
+	survivor := newTestRedisBus(t, mr, 100*time.Millisecond)
+	defer survivor.Close()
+	claimed := newCollector()
+	if err := survivor.Subscribe(ctx, TopicOpportunities, "trade", "trade-3", claimed.handle); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	claimed.wait(t, 1)
+	if msg := claimed.messages[0]; msg.ID != id || !msg.Redelivered {
+		t.Errorf("claimed = %+v, want redelivered %s", msg, id)
+	}
+}
@@ -0,0 +1,165 @@
+// Package credential 交易所 API 密钥来源
+// 密钥以 []byte 形式返回，使用完调用 Zero 清零；打印 Credential 时只输出脱敏后的内容
+package credential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultAccount 未指定子账户时使用的账户名
+const DefaultAccount = "default"
+
+// ErrNotFound 来源中没有该交易所/子账户的密钥
+var ErrNotFound = errors.New("未找到 API 密钥")
+
+// Credential 交易所 API 密钥（使用完调用 Zero 清除内存中的明文）
+type Credential struct {
+	APIKey     []byte `json:"api_key"`
+	APISecret  []byte `json:"api_secret"`
+	Passphrase []byte `json:"passphrase,omitempty"` // OKX 需要
+}
+
+// Clone 复制密钥（副本需要单独清零）
+func (c *Credential) Clone() *Credential {
+	return &Credential{
+		APIKey:     clone(c.APIKey),
+		APISecret:  clone(c.APISecret),
+		Passphrase: clone(c.Passphrase),
+	}
+}
+
+// Zero 清零密钥
+func (c *Credential) Zero() {
+	if c == nil {
+		return
+	}
+	Wipe(c.APIKey)
+	Wipe(c.APISecret)
+	Wipe(c.Passphrase)
+	c.APIKey, c.APISecret, c.Passphrase = nil, nil, nil
+}
+
+// String 脱敏后的密钥（只显示 API Key 首尾各 4 位，Secret 和 Passphrase 只显示是否已设置）
+func (c Credential) String() string {
+	return fmt.Sprintf("Credential{APIKey: %s, APISecret: %s, Passphrase: %s}",
+		MaskAPIKey(c.APIKey), maskSecret(c.APISecret), maskSecret(c.Passphrase))
+}
+
+// GoString 脱敏后的密钥（%#v）
+func (c Credential) GoString() string {
+	return c.String()
+}
+
+// Provider API 密钥来源
+type Provider interface {
+	// Name 来源名称（用于日志）
+	Name() string
+
+	// Credential 获取交易所子账户的密钥
+	// 参数:
+	//   - exchange: 交易所名称（不区分大小写）
+	//   - account: 子账户（为空时使用 DefaultAccount）
+	// 返回:
+	//   - *Credential: 密钥副本（调用方使用完后调用 Zero）
+	//   - error: 没有该密钥时返回 ErrNotFound
+	Credential(ctx context.Context, exchange, account string) (*Credential, error)
+
+	// Close 释放来源持有的密钥（密钥库清零内存中的明文）
+	Close()
+}
+
+// chainProvider 按顺序查找的多个来源
+type chainProvider struct {
+	providers []Provider
+}
+
+// NewChain 创建按顺序查找的密钥来源（返回第一个有该密钥的来源的结果）
+// 参数:
+//   - providers: 密钥来源（优先级从高到低）
+// 返回:
+//   - Provider: Close 时关闭所有来源
+func NewChain(providers ...Provider) Provider {
+	return &chainProvider{providers: providers}
+}
+
+// Name 来源名称
+func (c *chainProvider) Name() string {
+	names := make([]string, 0, len(c.providers))
+	for _, provider := range c.providers {
+		names = append(names, provider.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+// Credential 依次查找各来源
+func (c *chainProvider) Credential(ctx context.Context, exchange, account string) (*Credential, error) {
+	for _, provider := range c.providers {
+		credential, err := provider.Credential(ctx, exchange, account)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", provider.Name(), err)
+		}
+		return credential, nil
+	}
+	return nil, fmt.Errorf("%s/%s: %w", normalizeExchange(exchange), normalizeAccount(account), ErrNotFound)
+}
+
+// Close 关闭所有来源
+func (c *chainProvider) Close() {
+	for _, provider := range c.providers {
+		provider.Close()
+	}
+}
+
+// MaskAPIKey 脱敏 API Key（长度大于 8 时保留首尾各 4 位）
+func MaskAPIKey(key []byte) string {
+	if len(key) == 0 {
+		return `""`
+	}
+	if len(key) <= 8 {
+		return "****"
+	}
+	return string(key[:4]) + "****" + string(key[len(key)-4:])
+}
+
+// Wipe 清零字节切片
+func Wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// maskSecret 脱敏 Secret（只显示是否已设置）
+func maskSecret(secret []byte) string {
+	if len(secret) == 0 {
+		return `""`
+	}
+	return "******"
+}
+
+// clone 复制字节切片（空切片返回 nil）
+func clone(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
+
+// normalizeExchange 交易所名称（小写）
+func normalizeExchange(exchange string) string {
+	return strings.ToLower(strings.TrimSpace(exchange))
+}
+
+// normalizeAccount 子账户名称（为空时为 DefaultAccount）
+func normalizeAccount(account string) string {
+	account = strings.TrimSpace(account)
+	if account == "" {
+		return DefaultAccount
+	}
+	return account
+}
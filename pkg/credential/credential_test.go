@@ -0,0 +1,144 @@
+// Package credential API 密钥来源单元测试
+package credential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestKeystore_SaveOpen 测试密钥库加密保存、解密读取和子账户
+func TestKeystore_SaveOpen(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "keystore.json")
+	passphrase := []byte("correct horse battery staple")
+
+	keystore := NewKeystore()
+	keystore.Set("Binance", "", &Credential{APIKey: []byte("main-key"), APISecret: []byte("main-secret")})
+	keystore.Set("binance", "sub1", &Credential{APIKey: []byte("sub1-key"), APISecret: []byte("sub1-secret")})
+	keystore.Set("okx", "", &Credential{APIKey: []byte("okx-key"), APISecret: []byte("okx-secret"), Passphrase: []byte("okx-pass")})
+	if err := keystore.Save(file, passphrase); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "okx") {
+		t.Errorf("keystore file contains plaintext: %s", data)
+	}
+	if info, err := os.Stat(file); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("keystore file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	if _, err := OpenKeystore(file, []byte("wrong")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("OpenKeystore(wrong passphrase) error = %v, want ErrWrongPassphrase", err)
+	}
+
+	opened, err := OpenKeystore(file, passphrase)
+	if err != nil {
+		t.Fatalf("OpenKeystore() error = %v", err)
+	}
+	if got := strings.Join(opened.Accounts(), ","); got != "binance/default,binance/sub1,okx/default" {
+		t.Errorf("Accounts() = %s", got)
+	}
+
+	sub1, err := opened.Credential(ctx, "BINANCE", "sub1")
+	if err != nil {
+		t.Fatalf("Credential(binance, sub1) error = %v", err)
+	}
+	if string(sub1.APIKey) != "sub1-key" || string(sub1.APISecret) != "sub1-secret" {
+		t.Errorf("Credential(binance, sub1) = %q/%q", sub1.APIKey, sub1.APISecret)
+	}
+
+	// 返回的是副本：调用方清零不影响密钥库
+	secret := sub1.APISecret
+	sub1.Zero()
+	if strings.Trim(string(secret), "\x00") != "" {
+		t.Errorf("Zero() left %q", secret)
+	}
+	again, _ := opened.Credential(ctx, "binance", "sub1")
+	if string(again.APISecret) != "sub1-secret" {
+		t.Errorf("Credential() after caller Zero = %q", again.APISecret)
+	}
+
+	if _, err := opened.Credential(ctx, "binance", "sub2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Credential(binance, sub2) error = %v, want ErrNotFound", err)
+	}
+	if !opened.Delete("binance", "sub1") || opened.Delete("binance", "sub1") {
+		t.Error("Delete(binance, sub1) should succeed exactly once")
+	}
+
+	opened.Close()
+	if _, err := opened.Credential(ctx, "okx", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Credential() after Close error = %v, want ErrNotFound", err)
+	}
+}
+
+// TestEnvProvider 测试环境变量来源的变量名（默认账户和子账户）
+func TestEnvProvider(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("BINANCE_API_KEY", "env-key")
+	t.Setenv("BINANCE_API_SECRET", "env-secret")
+	t.Setenv("BINANCE_SUB_1_API_KEY", "sub-key")
+	t.Setenv("BINANCE_SUB_1_API_SECRET", "sub-secret")
+	t.Setenv("OKX_API_KEY", "")
+	t.Setenv("OKX_API_SECRET", "")
+
+	provider := NewEnvProvider()
+	credential, err := provider.Credential(ctx, "binance", "")
+	if err != nil || string(credential.APIKey) != "env-key" || string(credential.APISecret) != "env-secret" {
+		t.Errorf("Credential(binance) = %v, %v", credential, err)
+	}
+	credential, err = provider.Credential(ctx, "binance", "sub-1")
+	if err != nil || string(credential.APIKey) != "sub-key" {
+		t.Errorf("Credential(binance, sub-1) = %v, %v", credential, err)
+	}
+	if _, err := provider.Credential(ctx, "okx", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Credential(okx) error = %v, want ErrNotFound", err)
+	}
+}
+
+// TestChain 测试按优先级查找多个来源
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("BINANCE_API_KEY", "env-key")
+	t.Setenv("BINANCE_API_SECRET", "env-secret")
+	t.Setenv("OKX_API_KEY", "")
+	t.Setenv("OKX_API_SECRET", "")
+
+	keystore := NewKeystore()
+	keystore.Set("binance", "", &Credential{APIKey: []byte("store-key"), APISecret: []byte("store-secret")})
+	keystore.Set("okx", "", &Credential{APIKey: []byte("okx-key"), APISecret: []byte("okx-secret")})
+	provider := NewChain(NewEnvProvider(), keystore)
+	defer provider.Close()
+
+	if credential, err := provider.Credential(ctx, "binance", ""); err != nil || string(credential.APIKey) != "env-key" {
+		t.Errorf("Credential(binance) = %v, %v, want env", credential, err)
+	}
+	if credential, err := provider.Credential(ctx, "okx", ""); err != nil || string(credential.APIKey) != "okx-key" {
+		t.Errorf("Credential(okx) = %v, %v, want keystore", credential, err)
+	}
+	if _, err := provider.Credential(ctx, "bybit", "sub1"); !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "bybit/sub1") {
+		t.Errorf("Credential(bybit) error = %v", err)
+	}
+}
+
+// TestCredential_String 测试打印密钥时脱敏
+func TestCredential_String(t *testing.T) {
+	credential := Credential{APIKey: []byte("abcd1234efgh5678"), APISecret: []byte("top-secret"), Passphrase: []byte("pass")}
+	for _, format := range []string{"%v", "%+v", "%s", "%#v"} {
+		got := fmt.Sprintf(format, credential)
+		if strings.Contains(got, "top-secret") || strings.Contains(got, "pass}") || strings.Contains(got, "1234efgh") {
+			t.Errorf("Sprintf(%s) = %s, leaks secret", format, got)
+		}
+	}
+	if got := fmt.Sprint(&credential); !strings.Contains(got, "abcd****5678") {
+		t.Errorf("Sprint(&credential) = %s", got)
+	}
+}
@@ -0,0 +1,68 @@
+package credential
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// envProvider 从环境变量读取密钥
+type envProvider struct{}
+
+// NewEnvProvider 创建从环境变量读取密钥的来源
+// 默认账户读取 <EXCHANGE>_API_KEY、<EXCHANGE>_API_SECRET、<EXCHANGE>_PASSPHRASE（如 BINANCE_API_KEY），
+// 子账户读取 <EXCHANGE>_<ACCOUNT>_API_KEY 等（如 BINANCE_SUB1_API_KEY，账户名中的非字母数字字符替换为 _）
+func NewEnvProvider() Provider {
+	return envProvider{}
+}
+
+// Name 来源名称
+func (envProvider) Name() string {
+	return "env"
+}
+
+// Credential 读取环境变量（API_KEY 和 API_SECRET 都未设置时返回 ErrNotFound）
+func (envProvider) Credential(ctx context.Context, exchange, account string) (*Credential, error) {
+	prefix := EnvPrefix(exchange, account)
+	apiKey := os.Getenv(prefix + "API_KEY")
+	apiSecret := os.Getenv(prefix + "API_SECRET")
+	if apiKey == "" && apiSecret == "" {
+		return nil, ErrNotFound
+	}
+	return &Credential{
+		APIKey:     clone([]byte(apiKey)),
+		APISecret:  clone([]byte(apiSecret)),
+		Passphrase: clone([]byte(os.Getenv(prefix + "PASSPHRASE"))),
+	}, nil
+}
+
+// Close 环境变量来源不持有密钥
+func (envProvider) Close() {}
+
+// EnvPrefix 交易所子账户对应的环境变量前缀
+// 参数:
+//   - exchange: 交易所名称
+//   - account: 子账户（为空或为 DefaultAccount 时不包含账户名）
+// 返回:
+//   - string: 如 BINANCE_、BINANCE_SUB1_
+func EnvPrefix(exchange, account string) string {
+	prefix := envName(exchange) + "_"
+	if account = normalizeAccount(account); account != DefaultAccount {
+		prefix += envName(account) + "_"
+	}
+	return prefix
+}
+
+// envName 转换为环境变量名（大写，非字母数字替换为 _）
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.TrimSpace(name))
+}
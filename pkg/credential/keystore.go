@@ -0,0 +1,288 @@
+package credential
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreVersion = 1
+	keystoreKDF     = "scrypt"
+
+	// scrypt 参数（约 100ms / 32MB 内存，只在启动和修改密钥库时计算一次）
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32 // AES-256
+	saltLen      = 16
+)
+
+// ErrWrongPassphrase 口令错误或密钥库文件被篡改
+var ErrWrongPassphrase = errors.New("密钥库口令错误或文件已损坏")
+
+// keystoreFile 密钥库文件格式（JSON，密文为按 交易所 -> 子账户 -> 密钥 组织的 JSON）
+type keystoreFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Keystore 加密密钥库
+// 文件使用 scrypt 从口令派生密钥、AES-256-GCM 加密，每个交易所可以保存多个子账户的密钥；
+// 打开后明文保存在内存中，Close 时清零
+type Keystore struct {
+	accounts map[string]map[string]*Credential // exchange -> account -> credential
+	mu       sync.RWMutex
+}
+
+// NewKeystore 创建空的密钥库（调用 Save 写入文件）
+func NewKeystore() *Keystore {
+	return &Keystore{accounts: make(map[string]map[string]*Credential)}
+}
+
+// OpenKeystore 解密密钥库文件
+// 参数:
+//   - path: 密钥库文件
+//   - passphrase: 口令（调用方使用完后清零）
+// 返回:
+//   - *Keystore: 已解密的密钥库
+//   - error: 读取失败、格式不支持或口令错误（ErrWrongPassphrase）
+func OpenKeystore(path string, passphrase []byte) (*Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥库失败: %w", err)
+	}
+	var file keystoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("解析密钥库 %s 失败: %w", path, err)
+	}
+	if file.Version != keystoreVersion || file.KDF != keystoreKDF {
+		return nil, fmt.Errorf("不支持的密钥库格式: version %d, kdf %q", file.Version, file.KDF)
+	}
+
+	gcm, err := newGCM(passphrase, file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Nonce) != gcm.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, keystoreAAD())
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	defer Wipe(plaintext)
+
+	keystore := NewKeystore()
+	if err := json.Unmarshal(plaintext, &keystore.accounts); err != nil {
+		return nil, fmt.Errorf("解析密钥库内容失败: %w", err)
+	}
+	if keystore.accounts == nil {
+		keystore.accounts = make(map[string]map[string]*Credential)
+	}
+	return keystore, nil
+}
+
+// Save 加密写入密钥库文件（权限 0600，先写临时文件再替换，每次保存使用新的 salt 和 nonce）
+// 参数:
+//   - path: 密钥库文件
+//   - passphrase: 口令（调用方使用完后清零）
+func (k *Keystore) Save(path string, passphrase []byte) error {
+	if len(passphrase) == 0 {
+		return fmt.Errorf("密钥库口令不能为空")
+	}
+
+	k.mu.RLock()
+	plaintext, err := json.Marshal(k.accounts)
+	k.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("序列化密钥库失败: %w", err)
+	}
+	defer Wipe(plaintext)
+
+	file := keystoreFile{
+		Version: keystoreVersion,
+		KDF:     keystoreKDF,
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    make([]byte, saltLen),
+	}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return fmt.Errorf("生成 salt 失败: %w", err)
+	}
+	gcm, err := newGCM(passphrase, file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return fmt.Errorf("生成 nonce 失败: %w", err)
+	}
+	file.Ciphertext = gcm.Seal(nil, file.Nonce, plaintext, keystoreAAD())
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化密钥库失败: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+// Set 保存交易所子账户的密钥（保存副本，调用方仍需清零传入的密钥）
+// 参数:
+//   - exchange: 交易所名称（不区分大小写）
+//   - account: 子账户（为空时为 DefaultAccount）
+//   - credential: API 密钥
+func (k *Keystore) Set(exchange, account string, credential *Credential) {
+	exchange, account = normalizeExchange(exchange), normalizeAccount(account)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.accounts[exchange] == nil {
+		k.accounts[exchange] = make(map[string]*Credential)
+	}
+	k.accounts[exchange][account].Zero()
+	k.accounts[exchange][account] = credential.Clone()
+}
+
+// Delete 删除交易所子账户的密钥
+// 返回:
+//   - bool: 密钥是否存在
+func (k *Keystore) Delete(exchange, account string) bool {
+	exchange, account = normalizeExchange(exchange), normalizeAccount(account)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	credential, ok := k.accounts[exchange][account]
+	if !ok {
+		return false
+	}
+	credential.Zero()
+	delete(k.accounts[exchange], account)
+	if len(k.accounts[exchange]) == 0 {
+		delete(k.accounts, exchange)
+	}
+	return true
+}
+
+// Accounts 密钥库中的账户（exchange/account，按名称排序）
+func (k *Keystore) Accounts() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	var accounts []string
+	for exchange, credentials := range k.accounts {
+		for account := range credentials {
+			accounts = append(accounts, exchange+"/"+account)
+		}
+	}
+	sort.Strings(accounts)
+	return accounts
+}
+
+// Name 来源名称
+func (k *Keystore) Name() string {
+	return "keystore"
+}
+
+// Credential 获取交易所子账户的密钥副本
+func (k *Keystore) Credential(ctx context.Context, exchange, account string) (*Credential, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	credential, ok := k.accounts[normalizeExchange(exchange)][normalizeAccount(account)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return credential.Clone(), nil
+}
+
+// Close 清零内存中的所有密钥
+func (k *Keystore) Close() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, credentials := range k.accounts {
+		for _, credential := range credentials {
+			credential.Zero()
+		}
+	}
+	k.accounts = make(map[string]map[string]*Credential)
+}
+
+// ReadPassphraseFile 从文件读取密钥库口令（去掉末尾换行，如 Docker secret）
+// 返回:
+//   - []byte: 口令（调用方使用完后清零）
+func ReadPassphraseFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥库口令失败: %w", err)
+	}
+	passphrase := clone(bytes.TrimRight(data, "\r\n"))
+	Wipe(data)
+	return passphrase, nil
+}
+
+// newGCM 从口令派生密钥并创建 AES-GCM（派生的密钥在返回前清零）
+func newGCM(passphrase, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, n, r, p, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("派生密钥失败: %w", err)
+	}
+	defer Wipe(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// keystoreAAD 认证的附加数据（防止修改文件中的版本号）
+func keystoreAAD() []byte {
+	return []byte(fmt.Sprintf("arbitragex-keystore/v%d", keystoreVersion))
+}
+
+// writeFileAtomic 写入临时文件后替换目标文件
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("写入密钥库失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入密钥库失败: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入密钥库失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入密钥库失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("写入密钥库失败: %w", err)
+	}
+	return nil
+}
@@ -10,29 +10,57 @@ import (
 	"time"
 
 	"arbitragex/common/cache"
+	"arbitragex/common/clock"
+	"arbitragex/common/decimal"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ArbitrageOpportunity 套利机会
 type ArbitrageOpportunity struct {
-	ID          string    `json:"id"`           // 唯一标识
+	ID          string    `json:"id"`           // 唯一标识（机会持续期间不变）
 	Symbol      string    `json:"symbol"`       // 交易对
 	BuyExchange string    `json:"buy_exchange"` // 买入交易所
 	SellExchange string   `json:"sell_exchange"` // 卖出交易所
-	BuyPrice     float64   `json:"buy_price"`    // 买入价格
-	SellPrice    float64   `json:"sell_price"`   // 卖出价格
-	PriceDiff    float64   `json:"price_diff"`   // 价格差
+	BuyPrice     decimal.Decimal `json:"buy_price"`    // 买入价格
+	SellPrice    decimal.Decimal `json:"sell_price"`   // 卖出价格
+	PriceDiff    decimal.Decimal `json:"price_diff"`   // 价格差
 	PriceDiffRate float64  `json:"price_diff_rate"` // 价差百分比
 	RevenueRate  float64   `json:"revenue_rate"` // 毛收益率
-	EstRevenue   float64   `json:"est_revenue"`  // 预期收益（USDT）
-	EstCost      float64   `json:"est_cost"`     // 预期成本（USDT）
-	NetProfit    float64   `json:"net_profit"`   // 净收益（USDT）
+	EstRevenue   decimal.Decimal `json:"est_revenue"`  // 预期收益（USDT）
+	EstCost      decimal.Decimal `json:"est_cost"`     // 预期成本（USDT）
+	NetProfit    decimal.Decimal `json:"net_profit"`   // 净收益（USDT）
 	ProfitRate   float64   `json:"profit_rate"`  // 净收益率
 	RiskScore    float64   `json:"risk_score"`   // 风险评分 (0-100)
 	Score        float64   `json:"score"`        // 综合评分
-	DiscoveredAt time.Time `json:"discovered_at"` // 发现时间
+	DiscoveredAt time.Time `json:"discovered_at"` // 首次发现时间
+	LastSeenAt   time.Time `json:"last_seen_at"`  // 最近一次扫描到的时间
+	Ticks        int       `json:"ticks"`         // 连续被扫描到的次数
+	Persistence  time.Duration `json:"persistence"` // 持续时间（首次发现到最近一次扫描）
 	ValidUntil   time.Time `json:"valid_until"`   // 有效期至
+
+	priceSpans []trace.SpanContext // 买卖两边价格写入缓存的 span（较新的在前）
+}
+
+// 套利机会生命周期事件类型
+const (
+	OpportunityOpened  = "opened"  // 机会出现（达到确认次数）
+	OpportunityUpdated = "updated" // 机会在新一次扫描中仍然存在
+	OpportunityClosed  = "closed"  // 机会消失
+)
+
+// OpportunityEvent 套利机会生命周期事件
+type OpportunityEvent struct {
+	Type        string                `json:"type"`        // 事件类型（opened, updated, closed）
+	Opportunity *ArbitrageOpportunity `json:"opportunity"` // 套利机会（closed 事件为消失前最后一次扫描到的状态）
+	Time        time.Time             `json:"time"`        // 事件时间
+
+	spanContext trace.SpanContext // 发现机会的 span（回调的 ctx 以此为父 span）
 }
 
+// OpportunityHook 生命周期事件回调（在扫描协程中同步执行，不能修改机会对象）
+type OpportunityHook func(ctx context.Context, event *OpportunityEvent)
+
 // TradingFee 交易手续费配置
 type TradingFee struct {
 	Exchange string  `json:"exchange"` // 交易所名称
@@ -43,13 +71,14 @@ type TradingFee struct {
 // EngineConfig 套利引擎配置
 type EngineConfig struct {
 	MinProfitRate    float64     `json:"min_profit_rate"`    // 最小收益率阈值（如 0.005 = 0.5%）
-	MinProfitAmount  float64     `json:"min_profit_amount"`  // 最小收益金额（USDT）
+	MinProfitAmount  decimal.Decimal `json:"min_profit_amount"` // 最小收益金额（USDT）
 	MaxRiskScore     float64     `json:"max_risk_score"`     // 最大风险评分
 	OpportunityTTL   time.Duration `json:"opportunity_ttl"`  // 机会有效期
 	TradingFees      []TradingFee `json:"trading_fees"`      // 各交易所手续费
 	SlippageRate     float64      `json:"slippage_rate"`     // 滑点率（如 0.001 = 0.1%）
-	GasFee           float64      `json:"gas_fee"`           // Gas 费（USDT，仅 DEX）
-	MinVolume        float64      `json:"min_volume"`        // 最小成交量要求
+	GasFee           decimal.Decimal `json:"gas_fee"`        // Gas 费（USDT，仅 DEX）
+	MinVolume        decimal.Decimal `json:"min_volume"`     // 最小成交量要求（按此金额估算收益，USDT）
+	MinConfirmations int          `json:"min_confirmations"` // 连续扫描到多少次才输出机会（≤ 1 表示立即输出）
 }
 
 // ArbitrageEngine 套利引擎
@@ -58,8 +87,27 @@ type ArbitrageEngine struct {
 	priceCache  cache.PriceCache
 	mu          sync.RWMutex
 	opportunities map[string]*ArbitrageOpportunity
+	tracked     map[string]*trackedOpportunity
+	scanHooks   []ScanHook
+	eventHooks  []OpportunityHook
+	clock       clock.Clock
+}
+
+// trackedOpportunity 跨扫描跟踪的套利机会（同一交易对和买卖交易所组合）
+type trackedOpportunity struct {
+	id          string
+	symbol      string
+	firstSeenAt time.Time
+	ticks       int
+	confirmed   bool
+	last        *ArbitrageOpportunity
+	spanContext trace.SpanContext // 发现机会（达到确认次数）的 span
 }
 
+// ScanHook 扫描完成回调（每次扫描都会调用，opportunities 为本次扫描过滤后的全部机会，可能为空）
+// 回调在扫描协程中同步执行，不能修改机会对象
+type ScanHook func(ctx context.Context, opportunities []*ArbitrageOpportunity)
+
 // NewArbitrageEngine 创建套利引擎
 func NewArbitrageEngine(config *EngineConfig, priceCache cache.PriceCache) *ArbitrageEngine {
 	if config == nil {
@@ -70,14 +118,107 @@ func NewArbitrageEngine(config *EngineConfig, priceCache cache.PriceCache) *Arbi
 		config:      config,
 		priceCache:  priceCache,
 		opportunities: make(map[string]*ArbitrageOpportunity),
+		tracked:     make(map[string]*trackedOpportunity),
+		clock:       clock.Real,
+	}
+}
+
+// Config 获取引擎配置（只读，运行时修改使用 UpdateConfig）
+func (e *ArbitrageEngine) Config() *EngineConfig {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.config
+}
+
+// UpdateConfig 校验并替换引擎配置（从下一次扫描开始生效）
+// 参数:
+//   - config: 新配置（引擎保存副本，调用方之后的修改不影响引擎）
+// 返回:
+//   - error: 配置无效
+func (e *ArbitrageEngine) UpdateConfig(config *EngineConfig) error {
+	if config == nil {
+		return fmt.Errorf("引擎配置不能为空")
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	updated := *config
+	updated.TradingFees = append([]TradingFee(nil), config.TradingFees...)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.config = &updated
+	return nil
+}
+
+// Validate 校验引擎配置
+func (c *EngineConfig) Validate() error {
+	if c.MinProfitRate < 0 || c.MinProfitRate >= 1 {
+		return fmt.Errorf("最小收益率必须在 [0, 1) 之间: %v", c.MinProfitRate)
+	}
+	if c.MinProfitAmount.IsNegative() {
+		return fmt.Errorf("最小收益金额不能为负数: %s", c.MinProfitAmount)
+	}
+	if c.MaxRiskScore < 0 || c.MaxRiskScore > 100 {
+		return fmt.Errorf("最大风险评分必须在 [0, 100] 之间: %v", c.MaxRiskScore)
+	}
+	if c.OpportunityTTL <= 0 {
+		return fmt.Errorf("机会有效期必须大于 0: %s", c.OpportunityTTL)
 	}
+	if c.SlippageRate < 0 || c.SlippageRate >= 1 {
+		return fmt.Errorf("滑点率必须在 [0, 1) 之间: %v", c.SlippageRate)
+	}
+	if c.GasFee.IsNegative() {
+		return fmt.Errorf("Gas 费不能为负数: %s", c.GasFee)
+	}
+	if !c.MinVolume.IsPositive() {
+		return fmt.Errorf("最小成交量必须大于 0: %s", c.MinVolume)
+	}
+	if c.MinConfirmations < 0 {
+		return fmt.Errorf("确认次数不能为负数: %d", c.MinConfirmations)
+	}
+
+	seen := make(map[string]bool, len(c.TradingFees))
+	for _, fee := range c.TradingFees {
+		if fee.Exchange == "" {
+			return fmt.Errorf("手续费配置缺少交易所名称")
+		}
+		if seen[fee.Exchange] {
+			return fmt.Errorf("交易所手续费重复配置: %s", fee.Exchange)
+		}
+		seen[fee.Exchange] = true
+		if fee.MakerFee < 0 || fee.MakerFee >= 1 || fee.TakerFee < 0 || fee.TakerFee >= 1 {
+			return fmt.Errorf("%s 手续费率必须在 [0, 1) 之间: maker %v, taker %v", fee.Exchange, fee.MakerFee, fee.TakerFee)
+		}
+	}
+	return nil
+}
+
+// now 当前时间（未持有锁时使用）
+func (e *ArbitrageEngine) now() time.Time {
+	e.mu.RLock()
+	clk := e.clock
+	e.mu.RUnlock()
+
+	return clk.Now()
+}
+
+// SetClock 设置时钟（测试和回测时使用手动时钟）
+func (e *ArbitrageEngine) SetClock(clk clock.Clock) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.clock = clock.OrReal(clk)
 }
 
 // DefaultEngineConfig 默认引擎配置
 func DefaultEngineConfig() *EngineConfig {
 	return &EngineConfig{
 		MinProfitRate:   0.005, // 0.5%
-		MinProfitAmount: 10.0,  // 10 USDT
+		MinProfitAmount: decimal.NewFromInt(10), // 10 USDT
 		MaxRiskScore:    50.0,  // 风险评分 ≤ 50
 		OpportunityTTL:  5 * time.Second,
 		TradingFees: []TradingFee{
@@ -86,16 +227,20 @@ func DefaultEngineConfig() *EngineConfig {
 			{Exchange: "bybit", MakerFee: 0.001, TakerFee: 0.001},    // 0.1%
 		},
 		SlippageRate: 0.001, // 0.1%
-		GasFee:       0.0,   // CEX 无 gas 费
-		MinVolume:    1000.0, // 最小 1000 USDT
+		GasFee:       decimal.Zero,            // CEX 无 gas 费
+		MinVolume:    decimal.NewFromInt(1000), // 最小 1000 USDT
+		MinConfirmations: 1,  // 扫描到即输出
 	}
 }
 
 // ScanOpportunities 扫描套利机会
+// 同一交易对和买卖交易所组合在连续扫描中保持相同的 ID，直到某次扫描不再出现
 // symbols: 要扫描的交易对列表
 // exchanges: 要扫描的交易所列表
-// 返回: 发现的套利机会列表
+// 返回: 发现的套利机会列表（已达到确认次数）
 func (e *ArbitrageEngine) ScanOpportunities(ctx context.Context, symbols []string, exchanges []string) ([]*ArbitrageOpportunity, error) {
+	defer observeScan(time.Now())
+
 	var opportunities []*ArbitrageOpportunity
 
 	// 遍历每个交易对
@@ -119,11 +264,42 @@ func (e *ArbitrageEngine) ScanOpportunities(ctx context.Context, symbols []strin
 	opportunities = e.filterAndSortOpportunities(opportunities)
 
 	// 更新缓存
-	e.updateOpportunityCache(opportunities)
+	opportunities, events := e.updateOpportunityCache(symbols, opportunities, e.now())
+
+	// 通知回调（机会记录等）
+	e.mu.RLock()
+	eventHooks := e.eventHooks
+	scanHooks := e.scanHooks
+	e.mu.RUnlock()
+	for _, event := range events {
+		eventCtx := event.context(ctx)
+		for _, hook := range eventHooks {
+			hook(eventCtx, event)
+		}
+	}
+	for _, hook := range scanHooks {
+		hook(ctx, opportunities)
+	}
 
 	return opportunities, nil
 }
 
+// OnScan 注册扫描完成回调
+func (e *ArbitrageEngine) OnScan(hook ScanHook) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.scanHooks = append(e.scanHooks, hook)
+}
+
+// OnEvent 注册生命周期事件回调
+func (e *ArbitrageEngine) OnEvent(hook OpportunityHook) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.eventHooks = append(e.eventHooks, hook)
+}
+
 // getPricesFromExchanges 从多个交易所获取价格
 func (e *ArbitrageEngine) getPricesFromExchanges(ctx context.Context, symbol string, exchanges []string) (map[string]*cache.PriceData, error) {
 	prices := make(map[string]*cache.PriceData)
@@ -151,12 +327,15 @@ func (e *ArbitrageEngine) findArbitrageForSymbol(ctx context.Context, symbol str
 			Price:    price.AskPrice, // 使用卖价作为买入价
 			BidPrice: price.BidPrice, // 保存买价
 			AskPrice: price.AskPrice, // 保存卖价
+
+			Timestamp:   price.Timestamp,
+			SpanContext: price.SpanContext,
 		})
 	}
 
 	// 按价格排序（从低到高）
 	sort.Slice(priceList, func(i, j int) bool {
-		return priceList[i].Price < priceList[j].Price
+		return priceList[i].Price.LessThan(priceList[j].Price)
 	})
 
 	// 遍历所有交易所组合
@@ -168,10 +347,17 @@ func (e *ArbitrageEngine) findArbitrageForSymbol(ctx context.Context, symbol str
 			// 计算套利机会
 			opp := e.calculateArbitrage(ctx, symbol, buyExchange, sellExchange)
 
+			if opp == nil {
+				continue
+			}
+			metricFound.Inc(symbol)
+
 			// 检查是否满足最小收益要求
-			if opp != nil && opp.NetProfit > e.config.MinProfitAmount {
-				opportunities = append(opportunities, opp)
+			if !opp.NetProfit.GreaterThan(e.Config().MinProfitAmount) {
+				metricFiltered.Inc(filterMinProfitAmount)
+				continue
 			}
+			opportunities = append(opportunities, opp)
 		}
 	}
 
@@ -181,23 +367,33 @@ func (e *ArbitrageEngine) findArbitrageForSymbol(ctx context.Context, symbol str
 // exchangePrice 交易所价格
 type exchangePrice struct {
 	Exchange string
-	Price    float64
-	BidPrice float64
-	AskPrice float64
+	Price    decimal.Decimal
+	BidPrice decimal.Decimal
+	AskPrice decimal.Decimal
+
+	Timestamp   time.Time         // 价格时间
+	SpanContext trace.SpanContext // 价格写入缓存的 span
 }
 
 // calculateArbitrage 计算套利机会详情
 func (e *ArbitrageEngine) calculateArbitrage(ctx context.Context, symbol string, buyExchange, sellExchange *exchangePrice) *ArbitrageOpportunity {
+	cfg := e.Config()
+
 	// 基础数据
 	buyPrice := buyExchange.AskPrice  // 买入使用卖价
 	sellPrice := sellExchange.BidPrice // 卖出使用买价
 
+	// 缺少报价时跳过
+	if !buyPrice.IsPositive() || !sellPrice.IsPositive() {
+		return nil
+	}
+
 	// 计算价格差
-	priceDiff := sellPrice - buyPrice
-	priceDiffRate := priceDiff / buyPrice
+	priceDiff := sellPrice.Sub(buyPrice)
+	priceDiffRate := priceDiff.Div(buyPrice).Float64()
 
 	// 如果价格差 ≤ 0，没有套利机会
-	if priceDiff <= 0 {
+	if !priceDiff.IsPositive() {
 		return nil
 	}
 
@@ -209,32 +405,32 @@ func (e *ArbitrageEngine) calculateArbitrage(ctx context.Context, symbol string,
 	revenueRate := priceDiffRate
 
 	// 计算预期收益（假设交易 1000 USDT）
-	tradingAmount := e.config.MinVolume
-	estRevenue := priceDiff * (tradingAmount / buyPrice)
+	tradingAmount := cfg.MinVolume
+	estRevenue := priceDiff.Mul(tradingAmount).Div(buyPrice)
 
 	// 计算成本
 	// 1. 交易手续费
-	buyFeeAmount := tradingAmount * buyFee
-	sellFeeAmount := tradingAmount * sellFee
-	totalFees := buyFeeAmount + sellFeeAmount
+	buyFeeAmount := tradingAmount.Mul(decimal.NewFromFloat(buyFee))
+	sellFeeAmount := tradingAmount.Mul(decimal.NewFromFloat(sellFee))
+	totalFees := buyFeeAmount.Add(sellFeeAmount)
 
 	// 2. 滑点成本
-	slippageCost := tradingAmount * e.config.SlippageRate
+	slippageCost := tradingAmount.Mul(decimal.NewFromFloat(cfg.SlippageRate))
 
 	// 3. Gas 费（DEX）
-	gasFee := e.config.GasFee
+	gasFee := cfg.GasFee
 
 	// 总成本
-	estCost := totalFees + slippageCost + gasFee
+	estCost := decimal.Sum(totalFees, slippageCost, gasFee)
 
 	// 计算净收益
-	netProfit := estRevenue - estCost
-	profitRate := netProfit / tradingAmount
+	netProfit := estRevenue.Sub(estCost)
 
 	// 如果净收益 ≤ 0，没有套利机会
-	if netProfit <= 0 {
+	if !netProfit.IsPositive() {
 		return nil
 	}
+	profitRate := netProfit.Div(tradingAmount).Float64()
 
 	// 计算风险评分
 	riskScore := e.calculateRiskScore(buyExchange.Exchange, sellExchange.Exchange, priceDiffRate)
@@ -242,8 +438,9 @@ func (e *ArbitrageEngine) calculateArbitrage(ctx context.Context, symbol string,
 	// 计算综合评分
 	score := e.calculateScore(profitRate, riskScore, revenueRate)
 
-	// 生成 ID
-	id := generateOpportunityID(symbol, buyExchange.Exchange, sellExchange.Exchange)
+	// 生成 ID（机会已在跟踪中时由 updateOpportunityCache 替换为已有的 ID）
+	now := e.now()
+	id := generateOpportunityID(symbol, buyExchange.Exchange, sellExchange.Exchange, now)
 
 	// 创建套利机会对象
 	opportunity := &ArbitrageOpportunity{
@@ -262,8 +459,11 @@ func (e *ArbitrageEngine) calculateArbitrage(ctx context.Context, symbol string,
 		ProfitRate:    profitRate,
 		RiskScore:     riskScore,
 		Score:         score,
-		DiscoveredAt:  time.Now(),
-		ValidUntil:    time.Now().Add(e.config.OpportunityTTL),
+		DiscoveredAt:  now,
+		LastSeenAt:    now,
+		Ticks:         1,
+		ValidUntil:    now.Add(cfg.OpportunityTTL),
+		priceSpans:    priceSpans(buyExchange, sellExchange),
 	}
 
 	return opportunity
@@ -272,7 +472,7 @@ func (e *ArbitrageEngine) calculateArbitrage(ctx context.Context, symbol string,
 // getFeeRate 获取手续费率
 // isTaker: 是否为 taker 手续费（通常吃单是 taker）
 func (e *ArbitrageEngine) getFeeRate(exchange string, isTaker bool) float64 {
-	for _, fee := range e.config.TradingFees {
+	for _, fee := range e.Config().TradingFees {
 		if fee.Exchange == exchange {
 			if isTaker {
 				return fee.TakerFee
@@ -349,26 +549,32 @@ func (e *ArbitrageEngine) calculateScore(profitRate, riskScore, revenueRate floa
 // filterAndSortOpportunities 过滤和排序机会
 func (e *ArbitrageEngine) filterAndSortOpportunities(opportunities []*ArbitrageOpportunity) []*ArbitrageOpportunity {
 	var filtered []*ArbitrageOpportunity
+	now := e.now()
+	cfg := e.Config()
 
 	// 过滤
 	for _, opp := range opportunities {
 		// 检查收益率阈值
-		if opp.ProfitRate < e.config.MinProfitRate {
+		if opp.ProfitRate < cfg.MinProfitRate {
+			metricFiltered.Inc(filterMinProfitRate)
 			continue
 		}
 
 		// 检查收益金额阈值
-		if opp.NetProfit < e.config.MinProfitAmount {
+		if opp.NetProfit.LessThan(cfg.MinProfitAmount) {
+			metricFiltered.Inc(filterMinProfitAmount)
 			continue
 		}
 
 		// 检查风险评分阈值
-		if opp.RiskScore > e.config.MaxRiskScore {
+		if opp.RiskScore > cfg.MaxRiskScore {
+			metricFiltered.Inc(filterMaxRiskScore)
 			continue
 		}
 
 		// 检查是否过期
-		if time.Now().After(opp.ValidUntil) {
+		if now.After(opp.ValidUntil) {
+			metricFiltered.Inc(filterExpired)
 			continue
 		}
 
@@ -384,17 +590,82 @@ func (e *ArbitrageEngine) filterAndSortOpportunities(opportunities []*ArbitrageO
 }
 
 // updateOpportunityCache 更新机会缓存
-func (e *ArbitrageEngine) updateOpportunityCache(opportunities []*ArbitrageOpportunity) {
+// 将本次扫描的机会与上次扫描合并：沿用已有机会的 ID 和首次发现时间，累计扫描次数，
+// 本次扫描的交易对中不再出现的机会视为消失
+// 参数:
+//   - symbols: 本次扫描的交易对（未扫描的交易对的机会保持不变）
+//   - candidates: 本次扫描过滤后的机会（按评分排序）
+//   - now: 扫描时间
+// 返回:
+//   - []*ArbitrageOpportunity: 已达到确认次数的机会
+//   - []*OpportunityEvent: 生命周期事件
+func (e *ArbitrageEngine) updateOpportunityCache(symbols []string, candidates []*ArbitrageOpportunity, now time.Time) ([]*ArbitrageOpportunity, []*OpportunityEvent) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	// 清空旧缓存
-	e.opportunities = make(map[string]*ArbitrageOpportunity)
+	var confirmed []*ArbitrageOpportunity
+	var events []*OpportunityEvent
+	seen := make(map[string]bool, len(candidates))
 
-	// 添加新机会
-	for _, opp := range opportunities {
-		e.opportunities[opp.ID] = opp
+	for _, opp := range candidates {
+		key := opportunityKey(opp.Symbol, opp.BuyExchange, opp.SellExchange)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		tracked, ok := e.tracked[key]
+		if !ok {
+			tracked = &trackedOpportunity{id: opp.ID, symbol: opp.Symbol, firstSeenAt: opp.DiscoveredAt}
+			e.tracked[key] = tracked
+		}
+		tracked.ticks++
+
+		opp.ID = tracked.id
+		opp.DiscoveredAt = tracked.firstSeenAt
+		opp.Ticks = tracked.ticks
+		opp.Persistence = opp.LastSeenAt.Sub(opp.DiscoveredAt)
+
+		if tracked.ticks < e.config.MinConfirmations {
+			metricFiltered.Inc(filterUnconfirmed)
+			continue
+		}
+
+		eventType := OpportunityUpdated
+		if !tracked.confirmed {
+			tracked.confirmed = true
+			tracked.spanContext = traceDetection(opp)
+			eventType = OpportunityOpened
+			metricOpened.Inc(opp.Symbol)
+		}
+		tracked.last = opp
+		confirmed = append(confirmed, opp)
+		events = append(events, &OpportunityEvent{Type: eventType, Opportunity: opp, Time: now, spanContext: tracked.spanContext})
+	}
+
+	scanned := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		scanned[symbol] = true
+	}
+	for key, tracked := range e.tracked {
+		if seen[key] || !scanned[tracked.symbol] {
+			continue
+		}
+		delete(e.tracked, key)
+		if tracked.confirmed {
+			events = append(events, &OpportunityEvent{Type: OpportunityClosed, Opportunity: tracked.last, Time: now, spanContext: tracked.spanContext})
+		}
+	}
+
+	// 重建缓存（只包含已确认的机会）
+	e.opportunities = make(map[string]*ArbitrageOpportunity, len(e.tracked))
+	for _, tracked := range e.tracked {
+		if tracked.confirmed {
+			e.opportunities[tracked.id] = tracked.last
+		}
 	}
+
+	return confirmed, events
 }
 
 // GetOpportunity 根据 ID 获取机会
@@ -408,7 +679,7 @@ func (e *ArbitrageEngine) GetOpportunity(id string) (*ArbitrageOpportunity, erro
 	}
 
 	// 检查是否过期
-	if time.Now().After(opp.ValidUntil) {
+	if e.clock.Now().After(opp.ValidUntil) {
 		return nil, fmt.Errorf("opportunity expired: %s", id)
 	}
 
@@ -421,7 +692,7 @@ func (e *ArbitrageEngine) GetAllOpportunities() []*ArbitrageOpportunity {
 	defer e.mu.RUnlock()
 
 	var valid []*ArbitrageOpportunity
-	now := time.Now()
+	now := e.clock.Now()
 
 	for _, opp := range e.opportunities {
 		if now.Before(opp.ValidUntil) {
@@ -432,31 +703,40 @@ func (e *ArbitrageEngine) GetAllOpportunities() []*ArbitrageOpportunity {
 	return valid
 }
 
-// generateOpportunityID 生成机会 ID
-func generateOpportunityID(symbol, buyExchange, sellExchange string) string {
-	return fmt.Sprintf("%s_%s_%s_%d", symbol, buyExchange, sellExchange, time.Now().UnixNano())
+// generateOpportunityID 生成机会 ID（交易对 + 买卖交易所 + 首次发现时间，机会消失后再出现时 ID 不同）
+func generateOpportunityID(symbol, buyExchange, sellExchange string, discoveredAt time.Time) string {
+	return fmt.Sprintf("%s_%s_%s_%d", symbol, buyExchange, sellExchange, discoveredAt.UnixNano())
+}
+
+// opportunityKey 跟踪机会使用的组合键
+func opportunityKey(symbol, buyExchange, sellExchange string) string {
+	return symbol + "|" + buyExchange + "|" + sellExchange
 }
 
 // CalculateProfitAmount 计算给定交易金额的预期收益
 // tradingAmount: 交易金额（USDT）
 // 返回: 净收益
-func (e *ArbitrageEngine) CalculateProfitAmount(opp *ArbitrageOpportunity, tradingAmount float64) float64 {
+func (e *ArbitrageEngine) CalculateProfitAmount(opp *ArbitrageOpportunity, tradingAmount decimal.Decimal) decimal.Decimal {
 	// 计算收益
-	revenue := opp.PriceDiff * (tradingAmount / opp.BuyPrice)
+	revenue := decimal.Zero
+	if opp.BuyPrice.IsPositive() {
+		revenue = opp.PriceDiff.Mul(tradingAmount).Div(opp.BuyPrice)
+	}
 
 	// 计算成本
 	buyFee := e.getFeeRate(opp.BuyExchange, true)
 	sellFee := e.getFeeRate(opp.SellExchange, true)
-	totalFees := tradingAmount * (buyFee + sellFee)
-	slippageCost := tradingAmount * e.config.SlippageRate
-	totalCost := totalFees + slippageCost + e.config.GasFee
+	totalFees := tradingAmount.Mul(decimal.NewFromFloat(buyFee + sellFee))
+	cfg := e.Config()
+	slippageCost := tradingAmount.Mul(decimal.NewFromFloat(cfg.SlippageRate))
+	totalCost := decimal.Sum(totalFees, slippageCost, cfg.GasFee)
 
 	// 净收益
-	return revenue - totalCost
+	return revenue.Sub(totalCost)
 }
 
 // IsProfitable 判断给定交易金额是否有利可图
-func (e *ArbitrageEngine) IsProfitable(opp *ArbitrageOpportunity, tradingAmount float64) bool {
+func (e *ArbitrageEngine) IsProfitable(opp *ArbitrageOpportunity, tradingAmount decimal.Decimal) bool {
 	profit := e.CalculateProfitAmount(opp, tradingAmount)
-	return profit > 0
+	return profit.IsPositive()
 }
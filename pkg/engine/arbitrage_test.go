@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"arbitragex/common/cache"
+	"arbitragex/common/clock"
+	"arbitragex/common/decimal"
 )
 
 // TestNewArbitrageEngine 测试创建套利引擎
@@ -36,8 +38,8 @@ func TestDefaultEngineConfig(t *testing.T) {
 		t.Errorf("MinProfitRate = %f, want 0.005", config.MinProfitRate)
 	}
 
-	if config.MinProfitAmount != 10.0 {
-		t.Errorf("MinProfitAmount = %f, want 10.0", config.MinProfitAmount)
+	if !config.MinProfitAmount.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("MinProfitAmount = %v, want 10", config.MinProfitAmount)
 	}
 
 	if len(config.TradingFees) == 0 {
@@ -55,16 +57,16 @@ func TestCalculateArbitrage(t *testing.T) {
 	// 创建两个交易所的价格（价格差较大以确保有利可图）
 	binancePrice := &exchangePrice{
 		Exchange: "binance",
-		BidPrice: 43000.0,
-		AskPrice: 43100.0,
-		Price:    43100.0, // 买入价
+		BidPrice: decimal.NewFromFloat(43000.0),
+		AskPrice: decimal.NewFromFloat(43100.0),
+		Price:    decimal.NewFromFloat(43100.0), // 买入价
 	}
 
 	okxPrice := &exchangePrice{
 		Exchange: "okx",
-		BidPrice: 43500.0, // 更高的卖价（价格差 400 USDT）
-		AskPrice: 43550.0,
-		Price:    43550.0,
+		BidPrice: decimal.NewFromFloat(43500.0), // 更高的卖价（价格差 400 USDT）
+		AskPrice: decimal.NewFromFloat(43550.0),
+		Price:    decimal.NewFromFloat(43550.0),
 	}
 
 	// 计算 Binance 买入，OKX 卖出
@@ -84,13 +86,13 @@ func TestCalculateArbitrage(t *testing.T) {
 	}
 
 	// 验证价格差
-	expectedPriceDiff := 43500.0 - 43100.0 // 400
-	if opp.PriceDiff != expectedPriceDiff {
+	expectedPriceDiff := decimal.NewFromInt(43500 - 43100) // 400
+	if !opp.PriceDiff.Equal(expectedPriceDiff) {
 		t.Errorf("PriceDiff = %f, want %f", opp.PriceDiff, expectedPriceDiff)
 	}
 
 	// 验证净收益 > 0
-	if opp.NetProfit <= 0 {
+	if !opp.NetProfit.IsPositive() {
 		t.Errorf("NetProfit = %f, want > 0", opp.NetProfit)
 	}
 }
@@ -105,16 +107,16 @@ func TestCalculateArbitrage_NoProfit(t *testing.T) {
 	// 创建价格差很小的两个交易所
 	price1 := &exchangePrice{
 		Exchange: "binance",
-		BidPrice: 43000.0,
-		AskPrice: 43000.5,
-		Price:    43000.5,
+		BidPrice: decimal.NewFromFloat(43000.0),
+		AskPrice: decimal.NewFromFloat(43000.5),
+		Price:    decimal.NewFromFloat(43000.5),
 	}
 
 	price2 := &exchangePrice{
 		Exchange: "okx",
-		BidPrice: 43001.0,
-		AskPrice: 43001.5,
-		Price:    43001.5,
+		BidPrice: decimal.NewFromFloat(43001.0),
+		AskPrice: decimal.NewFromFloat(43001.5),
+		Price:    decimal.NewFromFloat(43001.5),
 	}
 
 	// 计算套利（价格差太小，应该无利可图）
@@ -257,7 +259,7 @@ func TestCalculateScore(t *testing.T) {
 func TestScanOpportunities(t *testing.T) {
 	ctx := context.Background()
 	config := DefaultEngineConfig()
-	config.MinProfitAmount = 5.0 // 降低最小收益要求到 5 USDT
+	config.MinProfitAmount = decimal.NewFromInt(5) // 降低最小收益要求到 5 USDT
 	priceCache := cache.NewMemoryPriceCache(5 * time.Second)
 	engine := NewArbitrageEngine(config, priceCache)
 
@@ -268,9 +270,9 @@ func TestScanOpportunities(t *testing.T) {
 	binancePrice := &cache.PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43000.0,
-		AskPrice:  43100.0,
-		LastPrice: 43050.0,
+		BidPrice:  decimal.NewFromFloat(43000.0),
+		AskPrice:  decimal.NewFromFloat(43100.0),
+		LastPrice: decimal.NewFromFloat(43050.0),
 		Timestamp: now,
 	}
 	priceCache.SetPrice(ctx, "binance", "BTC/USDT", binancePrice)
@@ -279,9 +281,9 @@ func TestScanOpportunities(t *testing.T) {
 	okxPrice := &cache.PriceData{
 		Exchange:  "okx",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43500.0,
-		AskPrice:  43550.0,
-		LastPrice: 43525.0,
+		BidPrice:  decimal.NewFromFloat(43500.0),
+		AskPrice:  decimal.NewFromFloat(43550.0),
+		LastPrice: decimal.NewFromFloat(43525.0),
 		Timestamp: now,
 	}
 	priceCache.SetPrice(ctx, "okx", "BTC/USDT", okxPrice)
@@ -290,9 +292,9 @@ func TestScanOpportunities(t *testing.T) {
 	ethPriceBinance := &cache.PriceData{
 		Exchange:  "binance",
 		Symbol:    "ETH/USDT",
-		BidPrice:  2200.0,
-		AskPrice:  2200.5,
-		LastPrice:  2200.25,
+		BidPrice:  decimal.NewFromFloat(2200.0),
+		AskPrice:  decimal.NewFromFloat(2200.5),
+		LastPrice:  decimal.NewFromFloat(2200.25),
 		Timestamp: now,
 	}
 	priceCache.SetPrice(ctx, "binance", "ETH/USDT", ethPriceBinance)
@@ -300,9 +302,9 @@ func TestScanOpportunities(t *testing.T) {
 	ethPriceOKX := &cache.PriceData{
 		Exchange:  "okx",
 		Symbol:    "ETH/USDT",
-		BidPrice:  2200.6,
-		AskPrice:  2201.0,
-		LastPrice:  2200.8,
+		BidPrice:  decimal.NewFromFloat(2200.6),
+		AskPrice:  decimal.NewFromFloat(2201.0),
+		LastPrice:  decimal.NewFromFloat(2200.8),
 		Timestamp: now,
 	}
 	priceCache.SetPrice(ctx, "okx", "ETH/USDT", ethPriceOKX)
@@ -342,15 +344,15 @@ func TestScanOpportunities(t *testing.T) {
 		// 测试 calculateArbitrage 直接调用
 		binancePrice := &exchangePrice{
 			Exchange: "binance",
-			BidPrice:  43000.0,
-			AskPrice:  43100.0,
-			Price:     43100.0,
+			BidPrice:  decimal.NewFromFloat(43000.0),
+			AskPrice:  decimal.NewFromFloat(43100.0),
+			Price:     decimal.NewFromFloat(43100.0),
 		}
 		okxPrice := &exchangePrice{
 			Exchange: "okx",
-			BidPrice:  43500.0,
-			AskPrice:  43550.0,
-			Price:     43550.0,
+			BidPrice:  decimal.NewFromFloat(43500.0),
+			AskPrice:  decimal.NewFromFloat(43550.0),
+			Price:     decimal.NewFromFloat(43550.0),
 		}
 
 		testOpp := engine.calculateArbitrage(ctx, "BTC/USDT", binancePrice, okxPrice)
@@ -370,7 +372,7 @@ func TestScanOpportunities(t *testing.T) {
 			t.Errorf("First opportunity symbol = %s, want BTC/USDT", opp.Symbol)
 		}
 
-		if opp.NetProfit <= 0 {
+		if !opp.NetProfit.IsPositive() {
 			t.Errorf("First opportunity NetProfit = %f, want > 0", opp.NetProfit)
 		}
 	}
@@ -388,7 +390,7 @@ func TestGetOpportunity(t *testing.T) {
 		Symbol:       "BTC/USDT",
 		BuyExchange:  "binance",
 		SellExchange: "okx",
-		NetProfit:    100.0,
+		NetProfit:    decimal.NewFromFloat(100.0),
 		DiscoveredAt: time.Now(),
 		ValidUntil:   time.Now().Add(5 * time.Second),
 	}
@@ -458,21 +460,21 @@ func TestGetAllOpportunities(t *testing.T) {
 		{
 			ID:          "opp_1",
 			Symbol:      "BTC/USDT",
-			NetProfit:   100.0,
+			NetProfit:   decimal.NewFromFloat(100.0),
 			DiscoveredAt: now,
 			ValidUntil:  now.Add(5 * time.Second),
 		},
 		{
 			ID:          "opp_2",
 			Symbol:      "ETH/USDT",
-			NetProfit:   50.0,
+			NetProfit:   decimal.NewFromFloat(50.0),
 			DiscoveredAt: now,
 			ValidUntil:  now.Add(5 * time.Second),
 		},
 		{
 			ID:          "opp_3",
 			Symbol:      "DOGE/USDT",
-			NetProfit:   200.0,
+			NetProfit:   decimal.NewFromFloat(200.0),
 			DiscoveredAt: now,
 			ValidUntil:  now.Add(-5 * time.Second), // 已过期
 		},
@@ -502,9 +504,9 @@ func TestCalculateProfitAmount(t *testing.T) {
 	opp := &ArbitrageOpportunity{
 		BuyExchange:  "binance",
 		SellExchange: "okx",
-		BuyPrice:     43000.0,
-		SellPrice:    43200.0,
-		PriceDiff:    200.0,
+		BuyPrice:     decimal.NewFromFloat(43000.0),
+		SellPrice:    decimal.NewFromFloat(43200.0),
+		PriceDiff:    decimal.NewFromFloat(200.0),
 	}
 
 	// 测试不同交易金额的收益
@@ -532,8 +534,8 @@ func TestCalculateProfitAmount(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			profit := engine.CalculateProfitAmount(opp, tt.tradingAmount)
-			if profit < tt.minProfit {
+			profit := engine.CalculateProfitAmount(opp, decimal.NewFromFloat(tt.tradingAmount))
+			if profit.LessThan(decimal.NewFromFloat(tt.minProfit)) {
 				t.Errorf("CalculateProfitAmount() = %f, want >= %f", profit, tt.minProfit)
 			}
 		})
@@ -550,12 +552,12 @@ func TestIsProfitable(t *testing.T) {
 	profitableOpp := &ArbitrageOpportunity{
 		BuyExchange:  "binance",
 		SellExchange: "okx",
-		BuyPrice:     43000.0,
-		SellPrice:    43200.0,
-		PriceDiff:    200.0,
+		BuyPrice:     decimal.NewFromFloat(43000.0),
+		SellPrice:    decimal.NewFromFloat(43200.0),
+		PriceDiff:    decimal.NewFromFloat(200.0),
 	}
 
-	if !engine.IsProfitable(profitableOpp, 1000.0) {
+	if !engine.IsProfitable(profitableOpp, decimal.NewFromInt(1000)) {
 		t.Error("Expected opportunity to be profitable, but IsProfitable returned false")
 	}
 
@@ -563,12 +565,12 @@ func TestIsProfitable(t *testing.T) {
 	unprofitableOpp := &ArbitrageOpportunity{
 		BuyExchange:  "binance",
 		SellExchange: "okx",
-		BuyPrice:     43000.0,
-		SellPrice:    43000.5,
-		PriceDiff:    0.5,
+		BuyPrice:     decimal.NewFromFloat(43000.0),
+		SellPrice:    decimal.NewFromFloat(43000.5),
+		PriceDiff:    decimal.NewFromFloat(0.5),
 	}
 
-	if engine.IsProfitable(unprofitableOpp, 1000.0) {
+	if engine.IsProfitable(unprofitableOpp, decimal.NewFromInt(1000)) {
 		t.Error("Expected opportunity to be unprofitable, but IsProfitable returned true")
 	}
 }
@@ -582,16 +584,16 @@ func BenchmarkCalculateArbitrage(b *testing.B) {
 
 	price1 := &exchangePrice{
 		Exchange: "binance",
-		BidPrice: 43000.0,
-		AskPrice: 43100.0,
-		Price:    43100.0,
+		BidPrice: decimal.NewFromFloat(43000.0),
+		AskPrice: decimal.NewFromFloat(43100.0),
+		Price:    decimal.NewFromFloat(43100.0),
 	}
 
 	price2 := &exchangePrice{
 		Exchange: "okx",
-		BidPrice: 43200.0,
-		AskPrice: 43250.0,
-		Price:    43250.0,
+		BidPrice: decimal.NewFromFloat(43200.0),
+		AskPrice: decimal.NewFromFloat(43250.0),
+		Price:    decimal.NewFromFloat(43250.0),
 	}
 
 	b.ResetTimer()
@@ -599,3 +601,200 @@ func BenchmarkCalculateArbitrage(b *testing.B) {
 		engine.calculateArbitrage(ctx, "BTC/USDT", price1, price2)
 	}
 }
+
+// TestOnScan 测试扫描完成回调（没有发现机会时也会调用）
+func TestOnScan(t *testing.T) {
+	ctx := context.Background()
+	priceCache := cache.NewMemoryPriceCache(5 * time.Second)
+	engine := NewArbitrageEngine(DefaultEngineConfig(), priceCache)
+
+	calls := 0
+	var scanned []*ArbitrageOpportunity
+	engine.OnScan(func(ctx context.Context, opportunities []*ArbitrageOpportunity) {
+		calls++
+		scanned = opportunities
+	})
+
+	if _, err := engine.ScanOpportunities(ctx, []string{"BTC/USDT"}, []string{"binance", "okx"}); err != nil {
+		t.Fatalf("ScanOpportunities failed: %v", err)
+	}
+	if calls != 1 || len(scanned) != 0 {
+		t.Errorf("hook calls = %d, opportunities = %d, want 1 call with no opportunities", calls, len(scanned))
+	}
+}
+
+// setSpread 设置 binance 买入、okx 卖出的 BTC/USDT 价格
+func setSpread(ctx context.Context, priceCache cache.PriceCache, okxBid float64) {
+	now := time.Now()
+	priceCache.SetPrice(ctx, "binance", "BTC/USDT", &cache.PriceData{Exchange: "binance", Symbol: "BTC/USDT", BidPrice: decimal.NewFromFloat(43000), AskPrice: decimal.NewFromFloat(43010), Timestamp: now})
+	priceCache.SetPrice(ctx, "okx", "BTC/USDT", &cache.PriceData{Exchange: "okx", Symbol: "BTC/USDT", BidPrice: decimal.NewFromFloat(okxBid), AskPrice: decimal.NewFromFloat(okxBid + 10), Timestamp: now})
+}
+
+// TestScanOpportunities_Lifecycle 测试机会 ID 跨扫描不变，以及 opened / updated / closed 事件
+func TestScanOpportunities_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	priceCache := cache.NewMemoryPriceCache(time.Minute)
+	engine := NewArbitrageEngine(DefaultEngineConfig(), priceCache)
+
+	var events []*OpportunityEvent
+	engine.OnEvent(func(ctx context.Context, event *OpportunityEvent) {
+		events = append(events, event)
+	})
+
+	symbols := []string{"BTC/USDT"}
+	exchanges := []string{"binance", "okx"}
+
+	setSpread(ctx, priceCache, 43800)
+	first, _ := engine.ScanOpportunities(ctx, symbols, exchanges)
+	setSpread(ctx, priceCache, 43900)
+	second, _ := engine.ScanOpportunities(ctx, symbols, exchanges)
+	if len(first) != 1 || len(second) != 1 {
+		t.Fatalf("scans returned %d and %d opportunities, want 1 and 1", len(first), len(second))
+	}
+
+	opp := second[0]
+	if opp.ID != first[0].ID {
+		t.Errorf("ID changed between scans: %s -> %s", first[0].ID, opp.ID)
+	}
+	if opp.Ticks != 2 || !opp.DiscoveredAt.Equal(first[0].DiscoveredAt) || opp.Persistence != opp.LastSeenAt.Sub(opp.DiscoveredAt) {
+		t.Errorf("second scan = ticks %d discovered %v persistence %v, want 2 ticks from first scan", opp.Ticks, opp.DiscoveredAt, opp.Persistence)
+	}
+	if _, err := engine.GetOpportunity(first[0].ID); err != nil {
+		t.Errorf("GetOpportunity(first scan ID) error = %v", err)
+	}
+
+	// 价差消失
+	setSpread(ctx, priceCache, 43000)
+	if third, _ := engine.ScanOpportunities(ctx, symbols, exchanges); len(third) != 0 {
+		t.Errorf("third scan returned %d opportunities, want 0", len(third))
+	}
+	if _, err := engine.GetOpportunity(first[0].ID); err == nil {
+		t.Error("GetOpportunity() after close returned no error")
+	}
+
+	wantTypes := []string{OpportunityOpened, OpportunityUpdated, OpportunityClosed}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("got %d events, want %d", len(events), len(wantTypes))
+	}
+	for i, want := range wantTypes {
+		if events[i].Type != want || events[i].Opportunity.ID != first[0].ID {
+			t.Errorf("event %d = %s %s, want %s %s", i, events[i].Type, events[i].Opportunity.ID, want, first[0].ID)
+		}
+	}
+	if events[2].Opportunity.Ticks != 2 {
+		t.Errorf("closed event ticks = %d, want 2", events[2].Opportunity.Ticks)
+	}
+
+	// 再次出现时是新的机会
+	setSpread(ctx, priceCache, 43800)
+	if again, _ := engine.ScanOpportunities(ctx, symbols, exchanges); len(again) != 1 || again[0].ID == first[0].ID || again[0].Ticks != 1 {
+		t.Errorf("reopened opportunity = %+v, want new ID with 1 tick", again)
+	}
+}
+
+// TestScanOpportunities_MinConfirmations 测试连续扫描到足够次数才输出机会
+func TestScanOpportunities_MinConfirmations(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultEngineConfig()
+	config.MinConfirmations = 3
+	priceCache := cache.NewMemoryPriceCache(time.Minute)
+	engine := NewArbitrageEngine(config, priceCache)
+
+	var events []*OpportunityEvent
+	engine.OnEvent(func(ctx context.Context, event *OpportunityEvent) {
+		events = append(events, event)
+	})
+
+	symbols := []string{"BTC/USDT"}
+	exchanges := []string{"binance", "okx"}
+	setSpread(ctx, priceCache, 43800)
+
+	for i := 1; i <= 2; i++ {
+		if opportunities, _ := engine.ScanOpportunities(ctx, symbols, exchanges); len(opportunities) != 0 {
+			t.Fatalf("scan %d returned %d opportunities before confirmation", i, len(opportunities))
+		}
+	}
+	if len(engine.GetAllOpportunities()) != 0 || len(events) != 0 {
+		t.Fatalf("unconfirmed opportunity visible: cache %d, events %d", len(engine.GetAllOpportunities()), len(events))
+	}
+
+	opportunities, _ := engine.ScanOpportunities(ctx, symbols, exchanges)
+	if len(opportunities) != 1 || opportunities[0].Ticks != 3 {
+		t.Fatalf("third scan = %v, want 1 opportunity with 3 ticks", opportunities)
+	}
+	if len(events) != 1 || events[0].Type != OpportunityOpened {
+		t.Errorf("events = %v, want one opened event", events)
+	}
+
+	// 确认前消失的机会不产生 closed 事件，重新计数
+	setSpread(ctx, priceCache, 43000)
+	engine.ScanOpportunities(ctx, symbols, exchanges)
+	setSpread(ctx, priceCache, 43800)
+	engine.ScanOpportunities(ctx, symbols, exchanges)
+	setSpread(ctx, priceCache, 43000)
+	engine.ScanOpportunities(ctx, symbols, exchanges)
+	if len(events) != 2 || events[1].Type != OpportunityClosed {
+		t.Errorf("events = %d, want opened and closed only", len(events))
+	}
+}
+
+// TestScanOpportunities_Clock 测试机会时间和过期按注入的时钟计算
+func TestScanOpportunities_Clock(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(t0)
+
+	priceCache := cache.NewMemoryPriceCache(time.Minute)
+	priceCache.SetClock(clk)
+	engine := NewArbitrageEngine(DefaultEngineConfig(), priceCache)
+	engine.SetClock(clk)
+
+	setSpread(ctx, priceCache, 43800)
+	opportunities, _ := engine.ScanOpportunities(ctx, []string{"BTC/USDT"}, []string{"binance", "okx"})
+	if len(opportunities) != 1 {
+		t.Fatalf("ScanOpportunities() returned %d opportunities, want 1", len(opportunities))
+	}
+
+	opp := opportunities[0]
+	if !opp.DiscoveredAt.Equal(t0) || !opp.ValidUntil.Equal(t0.Add(DefaultEngineConfig().OpportunityTTL)) {
+		t.Errorf("opportunity = discovered %v valid until %v, want clock time", opp.DiscoveredAt, opp.ValidUntil)
+	}
+
+	clk.Advance(DefaultEngineConfig().OpportunityTTL + time.Millisecond)
+	if _, err := engine.GetOpportunity(opp.ID); err == nil {
+		t.Error("GetOpportunity() after TTL returned no error")
+	}
+	if len(engine.GetAllOpportunities()) != 0 {
+		t.Error("GetAllOpportunities() after TTL returned expired opportunity")
+	}
+}
+
+// TestUpdateConfig 测试运行时更新配置：校验失败时保持原配置，更新后引擎持有副本
+func TestUpdateConfig(t *testing.T) {
+	engine := NewArbitrageEngine(DefaultEngineConfig(), cache.NewMemoryPriceCache(time.Minute))
+
+	invalid := DefaultEngineConfig()
+	invalid.SlippageRate = -0.1
+	if err := engine.UpdateConfig(invalid); err == nil {
+		t.Error("UpdateConfig(negative slippage) error = nil, want error")
+	}
+	invalid = DefaultEngineConfig()
+	invalid.TradingFees = append(invalid.TradingFees, TradingFee{Exchange: "okx"})
+	if err := engine.UpdateConfig(invalid); err == nil {
+		t.Error("UpdateConfig(duplicate fee) error = nil, want error")
+	}
+	if engine.Config().SlippageRate != 0.001 {
+		t.Errorf("SlippageRate = %v after invalid update, want 0.001", engine.Config().SlippageRate)
+	}
+
+	updated := DefaultEngineConfig()
+	updated.MinProfitRate = 0.01
+	if err := engine.UpdateConfig(updated); err != nil {
+		t.Fatalf("UpdateConfig() error = %v", err)
+	}
+	updated.MinProfitRate = 0.5
+	updated.TradingFees[0].TakerFee = 0.5
+	if engine.Config().MinProfitRate != 0.01 || engine.getFeeRate("binance", true) != 0.001 {
+		t.Errorf("Config() = %+v, want copy unaffected by caller changes", engine.Config())
+	}
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/zeromicro/go-zero/core/metric"
+)
+
+// 机会被过滤的原因（metricFiltered 的 reason 标签）
+const (
+	filterMinProfitAmount = "min_profit_amount"
+	filterMinProfitRate   = "min_profit_rate"
+	filterMaxRiskScore    = "max_risk_score"
+	filterExpired         = "expired"
+	filterUnconfirmed     = "unconfirmed"
+)
+
+// 套利引擎指标（开启 Prometheus 后记录）
+var (
+	metricFound = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "engine",
+		Name:      "opportunities_found_total",
+		Help:      "Price gaps with a positive estimated net profit, counted on every scan before filtering.",
+		Labels:    []string{"symbol"},
+	})
+	metricFiltered = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "engine",
+		Name:      "opportunities_filtered_total",
+		Help:      "Opportunities dropped by the engine thresholds, by reason.",
+		Labels:    []string{"reason"},
+	})
+	metricOpened = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "engine",
+		Name:      "opportunities_opened_total",
+		Help:      "Opportunities that reached the required confirmations and were published.",
+		Labels:    []string{"symbol"},
+	})
+	metricScanDuration = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "engine",
+		Name:      "scan_duration_seconds",
+		Help:      "Time spent in one opportunity scan.",
+		Labels:    []string{},
+		Buckets:   []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1},
+	})
+)
+
+// observeScan 记录一次扫描的耗时
+func observeScan(start time.Time) {
+	metricScanDuration.ObserveFloat(time.Since(start).Seconds())
+}
@@ -0,0 +1,84 @@
+// Package engine 套利引擎指标测试
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"arbitragex/common/cache"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/zeromicro/go-zero/core/prometheus"
+)
+
+// TestScanOpportunities_Metrics 测试扫描记录发现、过滤、发布的机会数和扫描耗时
+func TestScanOpportunities_Metrics(t *testing.T) {
+	prometheus.Enable()
+
+	ctx := context.Background()
+	config := DefaultEngineConfig()
+	config.MinConfirmations = 2
+	priceCache := cache.NewMemoryPriceCache(time.Minute)
+	engine := NewArbitrageEngine(config, priceCache)
+	setSpread(ctx, priceCache, 43800)
+
+	found := metricValue(t, "arbitragex_engine_opportunities_found_total", "symbol", "BTC/USDT")
+	unconfirmed := metricValue(t, "arbitragex_engine_opportunities_filtered_total", "reason", filterUnconfirmed)
+	opened := metricValue(t, "arbitragex_engine_opportunities_opened_total", "symbol", "BTC/USDT")
+	scans := metricValue(t, "arbitragex_engine_scan_duration_seconds", "", "")
+
+	for i := 0; i < 2; i++ {
+		engine.ScanOpportunities(ctx, []string{"BTC/USDT"}, []string{"binance", "okx"})
+	}
+
+	if got := metricValue(t, "arbitragex_engine_opportunities_found_total", "symbol", "BTC/USDT") - found; got != 2 {
+		t.Errorf("opportunities_found_total delta = %v, want 2", got)
+	}
+	if got := metricValue(t, "arbitragex_engine_opportunities_filtered_total", "reason", filterUnconfirmed) - unconfirmed; got != 1 {
+		t.Errorf("opportunities_filtered_total{reason=unconfirmed} delta = %v, want 1", got)
+	}
+	if got := metricValue(t, "arbitragex_engine_opportunities_opened_total", "symbol", "BTC/USDT") - opened; got != 1 {
+		t.Errorf("opportunities_opened_total delta = %v, want 1", got)
+	}
+	if got := metricValue(t, "arbitragex_engine_scan_duration_seconds", "", "") - scans; got != 2 {
+		t.Errorf("scan_duration_seconds count delta = %v, want 2", got)
+	}
+}
+
+// metricValue 从默认 Registry 读取指标（Counter 返回值，Histogram 返回样本数）
+// 参数:
+//   - label, value: 只统计该标签等于 value 的序列（label 为空时统计全部）
+func metricValue(t *testing.T, name, label, value string) float64 {
+	t.Helper()
+
+	families, err := prom.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	var total float64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			matched := label == ""
+			for _, pair := range m.GetLabel() {
+				if pair.GetName() == label && pair.GetValue() == value {
+					matched = true
+				}
+			}
+			if !matched {
+				continue
+			}
+			switch {
+			case m.Counter != nil:
+				total += m.GetCounter().GetValue()
+			case m.Histogram != nil:
+				total += float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return total
+}
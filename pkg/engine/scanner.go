@@ -0,0 +1,154 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// ScannerStatus 扫描循环状态
+type ScannerStatus struct {
+	Running       bool          `json:"running"`         // 是否正在扫描
+	Interval      time.Duration `json:"interval"`        // 扫描间隔
+	Scans         int64         `json:"scans"`           // 扫描次数
+	LastScanAt    time.Time     `json:"last_scan_at"`    // 最近一次扫描时间
+	Opportunities int           `json:"opportunities"`   // 最近一次扫描发现的机会数
+	Error         string        `json:"error,omitempty"` // 最近一次扫描的错误
+}
+
+// Scanner 定时扫描套利机会，支持运行时暂停和恢复
+type Scanner struct {
+	engine    *ArbitrageEngine
+	symbols   []string
+	exchanges []string
+	interval  time.Duration
+
+	mu            sync.Mutex
+	cancel        context.CancelFunc
+	done          chan struct{}
+	scans         int64
+	lastScanAt    time.Time
+	opportunities int
+	err           error
+	logger        logx.Logger
+}
+
+// NewScanner 创建扫描循环
+// 参数:
+//   - engine: 套利引擎
+//   - symbols: 扫描的交易对
+//   - exchanges: 扫描的交易所
+//   - interval: 扫描间隔
+// 返回:
+//   - *Scanner: 扫描循环（调用 Start 后开始扫描）
+func NewScanner(engine *ArbitrageEngine, symbols, exchanges []string, interval time.Duration) *Scanner {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	return &Scanner{
+		engine:    engine,
+		symbols:   append([]string(nil), symbols...),
+		exchanges: append([]string(nil), exchanges...),
+		interval:  interval,
+		logger:    logx.WithContext(context.Background()),
+	}
+}
+
+// Start 开始扫描（已在扫描时返回错误）
+func (s *Scanner) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return fmt.Errorf("扫描已在运行")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.loop(ctx, s.done)
+
+	s.logger.Infof("开始扫描套利机会: 交易对 %v, 交易所 %v, 间隔 %s", s.symbols, s.exchanges, s.interval)
+	return nil
+}
+
+// Pause 暂停扫描，等待正在进行的扫描结束（未在扫描时返回错误）
+func (s *Scanner) Pause() error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return fmt.Errorf("扫描未运行")
+	}
+	cancel()
+	<-done
+
+	s.logger.Info("已暂停扫描套利机会")
+	return nil
+}
+
+// Running 是否正在扫描
+func (s *Scanner) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cancel != nil
+}
+
+// Status 扫描循环状态
+func (s *Scanner) Status() ScannerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := ScannerStatus{
+		Running:       s.cancel != nil,
+		Interval:      s.interval,
+		Scans:         s.scans,
+		LastScanAt:    s.lastScanAt,
+		Opportunities: s.opportunities,
+	}
+	if s.err != nil {
+		status.Error = s.err.Error()
+	}
+	return status
+}
+
+// loop 扫描循环（启动后立即扫描一次）
+func (s *Scanner) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.scan(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan 扫描一次并记录结果
+func (s *Scanner) scan(ctx context.Context) {
+	opportunities, err := s.engine.ScanOpportunities(ctx, s.symbols, s.exchanges)
+	if err != nil {
+		s.logger.Errorf("扫描套利机会失败: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scans++
+	s.lastScanAt = s.engine.now()
+	s.opportunities = len(opportunities)
+	s.err = err
+}
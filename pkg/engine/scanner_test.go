@@ -0,0 +1,59 @@
+// Package engine 扫描循环单元测试
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
+)
+
+// TestScanner_StartPause 测试扫描循环的启动、暂停和重复操作
+func TestScanner_StartPause(t *testing.T) {
+	priceCache := cache.NewMemoryPriceCache(time.Minute)
+	ctx := context.Background()
+	priceCache.SetPrice(ctx, "binance", "BTC/USDT", &cache.PriceData{
+		Exchange: "binance", Symbol: "BTC/USDT",
+		BidPrice: decimal.NewFromFloat(43000), AskPrice: decimal.NewFromFloat(43010), Timestamp: time.Now(),
+	})
+	priceCache.SetPrice(ctx, "okx", "BTC/USDT", &cache.PriceData{
+		Exchange: "okx", Symbol: "BTC/USDT",
+		BidPrice: decimal.NewFromFloat(44000), AskPrice: decimal.NewFromFloat(44010), Timestamp: time.Now(),
+	})
+
+	engine := NewArbitrageEngine(DefaultEngineConfig(), priceCache)
+	scanner := NewScanner(engine, []string{"BTC/USDT"}, []string{"binance", "okx"}, 10*time.Millisecond)
+
+	if err := scanner.Pause(); err == nil {
+		t.Error("Pause() before Start() error = nil, want error")
+	}
+	if err := scanner.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := scanner.Start(); err == nil {
+		t.Error("second Start() error = nil, want error")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for scanner.Status().Scans < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := scanner.Pause(); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+
+	status := scanner.Status()
+	if status.Running || status.Scans < 2 || status.Opportunities != 1 {
+		t.Errorf("Status() = %+v, want paused after 2+ scans with 1 opportunity", status)
+	}
+	if len(engine.GetAllOpportunities()) != 1 {
+		t.Errorf("GetAllOpportunities() = %d, want 1", len(engine.GetAllOpportunities()))
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if scans := scanner.Status().Scans; scans != status.Scans {
+		t.Errorf("scans after Pause() = %d, want %d", scans, status.Scans)
+	}
+}
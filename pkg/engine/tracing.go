@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"context"
+
+	"arbitragex/common/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// priceSpans 买卖两边价格的 span（较新的价格在前，作为发现机会的父 span）
+func priceSpans(buy, sell *exchangePrice) []trace.SpanContext {
+	if sell.Timestamp.After(buy.Timestamp) {
+		buy, sell = sell, buy
+	}
+	return []trace.SpanContext{buy.SpanContext, sell.SpanContext}
+}
+
+// traceDetection 记录发现套利机会的 span
+// 以较新一边价格的缓存写入 span 为父 span（从行情接收到发现机会在同一条链路中），另一边价格作为关联 span；
+// 价格的 span 未被采样时只作为关联 span，机会作为新链路的起点单独采样（行情按比例采样时不丢失机会和执行链路）；
+// 价格没有 span 时（如 REST 行情）跳过
+// 返回:
+//   - trace.SpanContext: 发现机会的 span，执行链路以此为父 span
+func traceDetection(opp *ArbitrageOpportunity) trace.SpanContext {
+	ctx := tracing.WithOpportunityID(context.Background(), opp.ID)
+
+	var links []trace.Link
+	for _, span := range opp.priceSpans {
+		switch {
+		case !span.IsValid():
+		case span.IsSampled() && !trace.SpanContextFromContext(ctx).IsValid():
+			ctx = tracing.ContextWithParent(ctx, span)
+		default:
+			links = append(links, trace.Link{SpanContext: span})
+		}
+	}
+
+	_, span := tracing.Start(ctx, "engine.detect", trace.WithLinks(links...), trace.WithAttributes(
+		tracing.Symbol.String(opp.Symbol),
+		attribute.String("arbitragex.buy_exchange", opp.BuyExchange),
+		attribute.String("arbitragex.sell_exchange", opp.SellExchange),
+		attribute.Float64("arbitragex.net_profit", opp.NetProfit.Float64()),
+		attribute.Float64("arbitragex.profit_rate", opp.ProfitRate),
+		attribute.Int("arbitragex.ticks", opp.Ticks),
+	))
+	span.End()
+	return span.SpanContext()
+}
+
+// context 事件回调的上下文（包含发现机会的 span 和套利机会 ID）
+func (e *OpportunityEvent) context(ctx context.Context) context.Context {
+	if e.Opportunity != nil {
+		ctx = tracing.WithOpportunityID(ctx, e.Opportunity.ID)
+	}
+	return tracing.ContextWithParent(ctx, e.spanContext)
+}
@@ -0,0 +1,97 @@
+// Package engine 套利引擎链路追踪测试
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
+	"arbitragex/common/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestScanOpportunities_Tracing 测试发现机会的 span 以较新一边行情的缓存写入 span 为父 span，
+// 另一边行情作为关联 span，事件回调的 ctx 包含发现机会的 span 和套利机会 ID
+func TestScanOpportunities_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx := context.Background()
+	priceCache := cache.NewMemoryPriceCache(time.Minute)
+	engine := NewArbitrageEngine(DefaultEngineConfig(), priceCache)
+
+	var eventCtx context.Context
+	engine.OnEvent(func(ctx context.Context, event *OpportunityEvent) {
+		eventCtx = ctx
+	})
+
+	// 两边行情各自的接收 span，OKX 的较新
+	now := time.Now()
+	binanceCtx, binanceTick := otel.Tracer("test").Start(ctx, "exchange.ticker")
+	priceCache.SetPrice(binanceCtx, "binance", "BTC/USDT", &cache.PriceData{Exchange: "binance", Symbol: "BTC/USDT", BidPrice: decimal.NewFromFloat(43000), AskPrice: decimal.NewFromFloat(43010), Timestamp: now.Add(-time.Second)})
+	binanceTick.End()
+	okxCtx, okxTick := otel.Tracer("test").Start(ctx, "exchange.ticker")
+	priceCache.SetPrice(okxCtx, "okx", "BTC/USDT", &cache.PriceData{Exchange: "okx", Symbol: "BTC/USDT", BidPrice: decimal.NewFromFloat(43800), AskPrice: decimal.NewFromFloat(43810), Timestamp: now})
+	okxTick.End()
+
+	opportunities, _ := engine.ScanOpportunities(ctx, []string{"BTC/USDT"}, []string{"binance", "okx"})
+	if len(opportunities) != 1 || eventCtx == nil {
+		t.Fatalf("ScanOpportunities() = %d opportunities, event %v", len(opportunities), eventCtx != nil)
+	}
+
+	spans := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = append(spans[span.Name()], span)
+	}
+	if len(spans["cache.SetPrice"]) != 2 || len(spans["engine.detect"]) != 1 {
+		t.Fatalf("spans = %v, want 2 cache.SetPrice and 1 engine.detect", spans)
+	}
+
+	var okxWrite, binanceWrite sdktrace.ReadOnlySpan
+	for _, span := range spans["cache.SetPrice"] {
+		switch span.Parent().SpanID() {
+		case okxTick.SpanContext().SpanID():
+			okxWrite = span
+		case binanceTick.SpanContext().SpanID():
+			binanceWrite = span
+		}
+	}
+	if okxWrite == nil || binanceWrite == nil {
+		t.Fatal("cache.SetPrice spans are not children of the ticker spans")
+	}
+
+	detect := spans["engine.detect"][0]
+	if detect.Parent().SpanID() != okxWrite.SpanContext().SpanID() {
+		t.Errorf("engine.detect parent = %v, want OKX cache write", detect.Parent().SpanID())
+	}
+	if links := detect.Links(); len(links) != 1 || links[0].SpanContext.SpanID() != binanceWrite.SpanContext().SpanID() {
+		t.Errorf("engine.detect links = %v, want Binance cache write", links)
+	}
+	if got := spanAttribute(detect, tracing.OpportunityID); got != opportunities[0].ID {
+		t.Errorf("engine.detect %s = %q, want %q", tracing.OpportunityID, got, opportunities[0].ID)
+	}
+
+	if got := trace.SpanContextFromContext(eventCtx); got.SpanID() != detect.SpanContext().SpanID() {
+		t.Errorf("event ctx span = %v, want engine.detect", got.SpanID())
+	}
+	if got := tracing.OpportunityIDFromContext(eventCtx); got != opportunities[0].ID {
+		t.Errorf("event ctx opportunity ID = %q, want %q", got, opportunities[0].ID)
+	}
+}
+
+// spanAttribute span 的字符串属性（不存在时为空）
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.AsString()
+		}
+	}
+	return ""
+}
@@ -13,6 +13,10 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/trace"
+
+	"arbitragex/common/decimal"
+	"arbitragex/common/tracing"
 )
 
 // BinanceAdapter Binance 交易所适配器
@@ -32,6 +36,9 @@ type BinanceAdapter struct {
 // NewBinanceAdapter 创建 Binance 适配器
 func NewBinanceAdapter(config *ExchangeConfig) *BinanceAdapter {
 	wsURL := "wss://stream.binance.com:9443/ws" // Binance 生产环境 WebSocket
+	if config.WebSocket.BaseURL != "" {
+		wsURL = config.WebSocket.BaseURL
+	}
 
 	return &BinanceAdapter{
 		config:         config,
@@ -213,7 +220,7 @@ func (b *BinanceAdapter) receiveMessages(ctx context.Context) {
 			}
 
 			// 处理不同类型的消息
-			if err := b.handleMessage(data); err != nil {
+			if err := b.handleMessage(data, message); err != nil {
 				fmt.Printf("处理消息失败: %v\n", err)
 			}
 		}
@@ -232,8 +239,8 @@ func (b *BinanceAdapter) subscribeTickers(symbols []string) error {
 	// 构建订阅消息 - Binance 组合流格式
 	streams := make([]string, len(symbols))
 	for i, symbol := range symbols {
-		// Binance 流格式: btcusdt@ticker (小写)
-		streams[i] = strings.ToLower(symbol) + "@ticker"
+		// Binance 流格式: btcusdt@ticker (小写，BTC/USDT 需要去掉斜杠)
+		streams[i] = strings.ToLower(strings.ReplaceAll(symbol, "/", "")) + "@ticker"
 	}
 
 	// 构建组合流 URL
@@ -241,7 +248,7 @@ func (b *BinanceAdapter) subscribeTickers(symbols []string) error {
 	streamPath := "/ws/" + strings.Join(streams, "/")
 
 	// 需要重新连接到组合流
-	newURL := "wss://stream.binance.com:9443" + streamPath
+	newURL := strings.TrimSuffix(b.wsURL, "/ws") + streamPath
 
 	dialer := websocket.Dialer{
 		HandshakeTimeout: 10 * time.Second,
@@ -263,13 +270,13 @@ func (b *BinanceAdapter) subscribeTickers(symbols []string) error {
 }
 
 // handleMessage 处理不同类型的 WebSocket 消息
-func (b *BinanceAdapter) handleMessage(data map[string]interface{}) error {
+func (b *BinanceAdapter) handleMessage(data map[string]interface{}, raw []byte) error {
 	// Binance ticker 消息包含 "e" 字段表示事件类型
 	eventType, ok := data["e"].(string)
 	if !ok {
 		// 可能是组合流中的 ticker 消息，直接处理
 		if _, hasSymbol := data["s"]; hasSymbol {
-			return b.handleTickerMessage(data)
+			return b.handleTickerMessage(data, raw)
 		}
 		return nil
 	}
@@ -277,7 +284,7 @@ func (b *BinanceAdapter) handleMessage(data map[string]interface{}) error {
 	switch eventType {
 	case "24hrTicker":
 		// 24小时价格行情
-		return b.handleTickerMessage(data)
+		return b.handleTickerMessage(data, raw)
 	case "error":
 		// 错误消息
 		if msg, ok := data["msg"].(string); ok {
@@ -345,7 +352,8 @@ func (b *BinanceAdapter) heartbeat(ctx context.Context) {
 }
 
 // handleTickerMessage 处理价格消息
-func (b *BinanceAdapter) handleTickerMessage(data map[string]interface{}) error {
+// raw 为原始 WebSocket 消息，随行情一起交给处理器
+func (b *BinanceAdapter) handleTickerMessage(data map[string]interface{}, raw []byte) error {
 	// 解析 Binance ticker 消息格式
 	symbol, ok := data["s"].(string)
 	if !ok {
@@ -356,20 +364,31 @@ func (b *BinanceAdapter) handleTickerMessage(data map[string]interface{}) error
 	formattedSymbol := formatBinanceSymbol(symbol)
 
 	// 解析价格数据
+	_, span := tracing.Start(context.Background(), "exchange.ticker", trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(tracing.Exchange.String("binance"), tracing.Symbol.String(formattedSymbol)))
+	defer span.End()
+
 	ticker := &Ticker{
-		Exchange:  "Binance",
-		Symbol:    formattedSymbol,
-		Timestamp: time.Now(),
+		Exchange:    "Binance",
+		Symbol:      formattedSymbol,
+		Timestamp:   time.Now(),
+		Raw:         raw,
+		SpanContext: span.SpanContext(),
+	}
+
+	// 解析事件时间 (E，毫秒)
+	if eventTime, ok := data["E"].(float64); ok {
+		ticker.ExchangeTime = time.UnixMilli(int64(eventTime))
 	}
 
 	// 解析 bidPrice (b)
 	if bidPrice, ok := data["b"].(string); ok {
-		ticker.BidPrice = parseFloat(bidPrice)
+		ticker.BidPrice = parseDecimal(bidPrice)
 	}
 
 	// 解析 askPrice (a)
 	if askPrice, ok := data["a"].(string); ok {
-		ticker.AskPrice = parseFloat(askPrice)
+		ticker.AskPrice = parseDecimal(askPrice)
 	}
 
 	// 调用处理器
@@ -426,13 +445,13 @@ func formatBinanceSymbol(symbol string) string {
 	return symbol
 }
 
-// parseFloat 安全地将字符串转换为 float64
-func parseFloat(s string) float64 {
-	f, err := json.Number(s).Float64()
+// parseDecimal 安全地将交易所返回的价格字符串转换为 Decimal（格式错误时返回 0）
+func parseDecimal(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
 	if err != nil {
-		return 0
+		return decimal.Zero
 	}
-	return f
+	return d
 }
 
 // BinanceRESTClient Binance REST API 客户端
@@ -480,11 +499,11 @@ func (c *BinanceRESTClient) GetTicker(ctx context.Context, symbol string) (*Tick
 		return nil, err
 	}
 
-	bid := parseFloat(result.BidPrice)
-	ask := parseFloat(result.AskPrice)
+	bid := parseDecimal(result.BidPrice)
+	ask := parseDecimal(result.AskPrice)
 
 	// 计算中间价作为最新价格
-	price := (bid + ask) / 2
+	price := bid.Add(ask).Div(decimal.NewFromInt(2))
 
 	return &Ticker{
 		Exchange:  "Binance",
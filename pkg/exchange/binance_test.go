@@ -3,8 +3,11 @@ package exchange
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
+
+	"arbitragex/common/decimal"
 )
 
 // TestBinanceAdapter_NewBinanceAdapter 测试创建 Binance 适配器
@@ -89,45 +92,50 @@ func TestFormatBinanceSymbol(t *testing.T) {
 	}
 }
 
-// TestParseFloat 测试字符串转浮点数
-func TestParseFloat(t *testing.T) {
+// TestParseDecimal 测试字符串转定点小数
+func TestParseDecimal(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    string
-		expected float64
+		expected string
 	}{
 		{
 			name:     "正常数字",
 			input:    "123.45",
-			expected: 123.45,
+			expected: "123.45",
 		},
 		{
 			name:     "整数",
 			input:    "100",
-			expected: 100.0,
+			expected: "100",
+		},
+		{
+			name:     "末尾的零",
+			input:    "43000.10000000",
+			expected: "43000.1",
 		},
 		{
 			name:     "零",
 			input:    "0",
-			expected: 0.0,
+			expected: "0",
 		},
 		{
 			name:     "无效字符串",
 			input:    "invalid",
-			expected: 0.0,
+			expected: "0",
 		},
 		{
 			name:     "空字符串",
 			input:    "",
-			expected: 0.0,
+			expected: "0",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := parseFloat(tt.input)
-			if result != tt.expected {
-				t.Errorf("parseFloat(%s) = %f, want %f", tt.input, result, tt.expected)
+			result := parseDecimal(tt.input)
+			if result.String() != tt.expected {
+				t.Errorf("parseDecimal(%s) = %s, want %s", tt.input, result, tt.expected)
 			}
 		})
 	}
@@ -266,7 +274,7 @@ func TestHandleTickerMessage(t *testing.T) {
 	}
 
 	// 处理消息
-	err := adapter.handleTickerMessage(message)
+	err := adapter.handleTickerMessage(message, nil)
 	if err != nil {
 		t.Errorf("handleTickerMessage() returned error: %v", err)
 	}
@@ -286,12 +294,12 @@ func TestHandleTickerMessage(t *testing.T) {
 		t.Errorf("Symbol = %s, want BTC/USDT", receivedTicker.Symbol)
 	}
 
-	if receivedTicker.BidPrice != 43000.50 {
-		t.Errorf("BidPrice = %f, want 43000.50", receivedTicker.BidPrice)
+	if !receivedTicker.BidPrice.Equal(decimal.RequireFromString("43000.50")) {
+		t.Errorf("BidPrice = %.2f, want 43000.50", receivedTicker.BidPrice)
 	}
 
-	if receivedTicker.AskPrice != 43100.00 {
-		t.Errorf("AskPrice = %f, want 43100.00", receivedTicker.AskPrice)
+	if !receivedTicker.AskPrice.Equal(decimal.RequireFromString("43100.00")) {
+		t.Errorf("AskPrice = %.2f, want 43100.00", receivedTicker.AskPrice)
 	}
 }
 
@@ -313,16 +321,47 @@ func TestHandleTickerMessage_MissingSymbol(t *testing.T) {
 		"a": "43100.00",
 	}
 
-	err := adapter.handleTickerMessage(message)
+	err := adapter.handleTickerMessage(message, nil)
 	if err == nil {
 		t.Error("handleTickerMessage() with missing symbol should return error")
 	}
 }
 
-// BenchmarkParseFloat 性能测试
-func BenchmarkParseFloat(b *testing.B) {
+// TestHandleMessage_RawFrame 测试行情携带原始消息和交易所事件时间
+func TestHandleMessage_RawFrame(t *testing.T) {
+	adapter := NewBinanceAdapter(&ExchangeConfig{Name: "Binance"})
+
+	received := make(chan *Ticker, 1)
+	adapter.handlerMu.Lock()
+	adapter.tickerHandlers["BTC/USDT"] = []TickerHandler{func(ticker *Ticker) { received <- ticker }}
+	adapter.handlerMu.Unlock()
+
+	frame := []byte(`{"e":"24hrTicker","E":1704067200123,"s":"BTCUSDT","b":"43000.50","a":"43100.00"}`)
+	var data map[string]interface{}
+	if err := json.Unmarshal(frame, &data); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if err := adapter.handleMessage(data, frame); err != nil {
+		t.Fatalf("handleMessage() error = %v", err)
+	}
+
+	select {
+	case ticker := <-received:
+		if !ticker.ExchangeTime.Equal(time.UnixMilli(1704067200123)) {
+			t.Errorf("ExchangeTime = %v, want 1704067200123ms", ticker.ExchangeTime)
+		}
+		if string(ticker.Raw) != string(frame) {
+			t.Errorf("Raw = %s, want original frame", ticker.Raw)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Handler was not called")
+	}
+}
+
+// BenchmarkParseDecimal 性能测试
+func BenchmarkParseDecimal(b *testing.B) {
 	input := "12345.67890"
 	for i := 0; i < b.N; i++ {
-		parseFloat(input)
+		parseDecimal(input)
 	}
 }
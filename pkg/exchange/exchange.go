@@ -4,18 +4,26 @@ package exchange
 
 import (
 	"context"
+	"encoding/json"
 	"time"
+
+	"arbitragex/common/decimal"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Ticker 价格行情数据
 type Ticker struct {
 	Exchange   string    `json:"exchange"`     // 交易所名称
 	Symbol     string    `json:"symbol"`       // 交易对
-	BidPrice    float64   `json:"bid_price"`    // 买一价
-	AskPrice    float64   `json:"ask_price"`    // 卖一价
-	LastPrice   float64   `json:"last_price"`   // 最新成交价
-	Volume24h  float64   `json:"volume_24h"`   // 24小时成交量
-	Timestamp  time.Time `json:"timestamp"`    // 时间戳
+	BidPrice    decimal.Decimal `json:"bid_price"`    // 买一价
+	AskPrice    decimal.Decimal `json:"ask_price"`    // 卖一价
+	LastPrice   decimal.Decimal `json:"last_price"`   // 最新成交价
+	Volume24h  decimal.Decimal `json:"volume_24h"`   // 24小时成交量
+	Timestamp  time.Time `json:"timestamp"`    // 时间戳（本地接收时间）
+	ExchangeTime time.Time `json:"exchange_time,omitempty"` // 交易所推送的事件时间（REST 行情为空）
+	Raw        json.RawMessage `json:"-"`        // 解析出该行情的原始 WebSocket 消息（用于录制和复现解析问题）
+	SpanContext trace.SpanContext `json:"-"`     // 接收该行情的 span（价格缓存和套利引擎的 span 以此为父 span）
 }
 
 // OrderBook 订单簿数据
@@ -29,8 +37,8 @@ type OrderBook struct {
 
 // OrderBookItem 订单簿项
 type OrderBookItem struct {
-	Price  float64 `json:"price"`
-	Amount float64 `json:"amount"`
+	Price  decimal.Decimal `json:"price"`
+	Amount decimal.Decimal `json:"amount"`
 }
 
 // TickerHandler 价格行情处理器
@@ -81,8 +89,6 @@ type RESTConfig struct {
 // ExchangeConfig 交易所配置
 type ExchangeConfig struct {
 	Name         string           // 交易所名称
-	APIKey       string           // API Key
-	APISecret    string           // API Secret
 	WebSocket   WebSocketConfig  // WebSocket 配置
 	REST         RESTConfig       // REST API 配置
 	Symbols      []string         // 支持的交易对
@@ -93,7 +99,7 @@ type ExchangeConfig struct {
 type PriceEvent struct {
 	Exchange string
 	Symbol   string
-	Price    float64
+	Price    decimal.Decimal
 	Timestamp time.Time
 }
 
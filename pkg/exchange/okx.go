@@ -8,11 +8,15 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/trace"
+
+	"arbitragex/common/tracing"
 )
 
 // OKXAdapter OKX 交易所适配器
@@ -32,6 +36,9 @@ type OKXAdapter struct {
 // NewOKXAdapter 创建 OKX 适配器
 func NewOKXAdapter(config *ExchangeConfig) *OKXAdapter {
 	wsURL := "wss://ws.okx.com:8443/ws/v5/public" // OKX 生产环境 WebSocket
+	if config.WebSocket.BaseURL != "" {
+		wsURL = config.WebSocket.BaseURL
+	}
 
 	return &OKXAdapter{
 		config:         config,
@@ -213,7 +220,7 @@ func (o *OKXAdapter) receiveMessages(ctx context.Context) {
 			}
 
 			// 处理不同类型的消息
-			if err := o.handleMessage(data); err != nil {
+			if err := o.handleMessage(data, message); err != nil {
 				fmt.Printf("处理消息失败: %v\n", err)
 			}
 		}
@@ -221,12 +228,12 @@ func (o *OKXAdapter) receiveMessages(ctx context.Context) {
 }
 
 // handleMessage 处理不同类型的 WebSocket 消息
-func (o *OKXAdapter) handleMessage(data map[string]interface{}) error {
+func (o *OKXAdapter) handleMessage(data map[string]interface{}, raw []byte) error {
 	// OKX 消息格式: {"arg": {"channel": "tickers", "instId": "BTC-USDT"}, "data": [...]}
 	if arg, ok := data["arg"].(map[string]interface{}); ok {
 		channel, _ := arg["channel"].(string)
 		if channel == "tickers" {
-			return o.handleTickerMessage(data)
+			return o.handleTickerMessage(data, raw)
 		}
 	}
 
@@ -235,7 +242,8 @@ func (o *OKXAdapter) handleMessage(data map[string]interface{}) error {
 }
 
 // handleTickerMessage 处理价格消息
-func (o *OKXAdapter) handleTickerMessage(data map[string]interface{}) error {
+// raw 为原始 WebSocket 消息，随行情一起交给处理器
+func (o *OKXAdapter) handleTickerMessage(data map[string]interface{}, raw []byte) error {
 	// 解析 OKX ticker 消息格式
 	arg, _ := data["arg"].(map[string]interface{})
 	instID, _ := arg["instId"].(string)
@@ -257,25 +265,38 @@ func (o *OKXAdapter) handleTickerMessage(data map[string]interface{}) error {
 	tickerData := dataArray[0].(map[string]interface{})
 
 	// 解析价格数据
+	_, span := tracing.Start(context.Background(), "exchange.ticker", trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(tracing.Exchange.String("okx"), tracing.Symbol.String(symbol)))
+	defer span.End()
+
 	ticker := &Ticker{
-		Exchange:  "OKX",
-		Symbol:    symbol,
-		Timestamp: time.Now(),
+		Exchange:    "OKX",
+		Symbol:      symbol,
+		Timestamp:   time.Now(),
+		Raw:         raw,
+		SpanContext: span.SpanContext(),
+	}
+
+	// 解析推送时间 (ts，毫秒字符串)
+	if ts, ok := tickerData["ts"].(string); ok {
+		if ms, err := strconv.ParseInt(ts, 10, 64); err == nil && ms > 0 {
+			ticker.ExchangeTime = time.UnixMilli(ms)
+		}
 	}
 
 	// 解析 bidPrice (bidPx)
 	if bidPx, ok := tickerData["bidPx"].(string); ok {
-		ticker.BidPrice = parseFloat(bidPx)
+		ticker.BidPrice = parseDecimal(bidPx)
 	}
 
 	// 解析 askPrice (askPx)
 	if askPx, ok := tickerData["askPx"].(string); ok {
-		ticker.AskPrice = parseFloat(askPx)
+		ticker.AskPrice = parseDecimal(askPx)
 	}
 
 	// 解析 lastPrice (last)
 	if last, ok := tickerData["last"].(string); ok {
-		ticker.LastPrice = parseFloat(last)
+		ticker.LastPrice = parseDecimal(last)
 	}
 
 	// 调用处理器
@@ -455,9 +476,9 @@ func (c *OKXRESTClient) GetTicker(ctx context.Context, symbol string) (*Ticker,
 	}
 
 	data := result.Data[0]
-	bid := parseFloat(data.BidPx)
-	ask := parseFloat(data.AskPx)
-	last := parseFloat(data.Last)
+	bid := parseDecimal(data.BidPx)
+	ask := parseDecimal(data.AskPx)
+	last := parseDecimal(data.Last)
 
 	return &Ticker{
 		Exchange:  "OKX",
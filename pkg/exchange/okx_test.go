@@ -5,6 +5,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"arbitragex/common/decimal"
 )
 
 // TestNewOKXAdapter 测试创建 OKX 适配器
@@ -268,7 +270,7 @@ func TestOKXAdapter_HandleTickerMessage(t *testing.T) {
 	}
 
 	// 处理消息
-	err := adapter.handleTickerMessage(message)
+	err := adapter.handleTickerMessage(message, nil)
 	if err != nil {
 		t.Errorf("handleTickerMessage() returned error: %v", err)
 	}
@@ -288,8 +290,8 @@ func TestOKXAdapter_HandleTickerMessage(t *testing.T) {
 		t.Errorf("Symbol = %s, want BTC/USDT", receivedTicker.Symbol)
 	}
 
-	if receivedTicker.BidPrice != 43000.50 {
-		t.Errorf("BidPrice = %f, want 43000.50", receivedTicker.BidPrice)
+	if !receivedTicker.BidPrice.Equal(decimal.RequireFromString("43000.50")) {
+		t.Errorf("BidPrice = %.2f, want 43000.50", receivedTicker.BidPrice)
 	}
 }
 
@@ -317,7 +319,7 @@ func TestOKXAdapter_HandleTickerMessage_MissingSymbol(t *testing.T) {
 		},
 	}
 
-	err := adapter.handleTickerMessage(message)
+	err := adapter.handleTickerMessage(message, nil)
 	if err == nil {
 		t.Error("Expected error for missing instId, got nil")
 	}
@@ -0,0 +1,462 @@
+// Package exchange 提供行情回放适配器实现
+// 职责：把录制的行情文件（见 pkg/marketdata 和 cmd/recorder）作为实时行情推送，离线运行监控、引擎和服务
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/marketdata"
+)
+
+// Replay 行情回放（多个交易所共用）
+// 一个协程按录制顺序读取文件，把行情分发给对应交易所的回放适配器，所有交易所使用同一个时间起点；
+// 所有适配器都订阅后才开始回放，处理器按录制顺序同步调用，因此同样的文件每次回放得到同样的跨交易所行情序列
+type Replay struct {
+	files []string
+	speed float64
+
+	adapters map[string]*ReplayAdapter // 小写交易所名称 → 回放适配器
+	waiting  int                       // 尚未订阅（也未断开）的适配器数，为 0 时开始回放
+
+	reader     marketdata.EventReader
+	closeFiles func() error
+	opened     bool
+	started    bool
+	finished   bool
+	stop       chan struct{} // 所有适配器断开时关闭
+	done       chan struct{} // 回放结束时关闭
+	err        error
+	mu         sync.Mutex
+}
+
+// NewReplay 创建行情回放
+// 参数:
+//   - files: 录制文件（支持 .jsonl.gz，多个文件按时间合并）
+//   - speed: 回放倍速（1 为原始节奏，10 为十倍速，≤ 0 表示不等待、尽快回放）
+// 返回:
+//   - *Replay: 行情回放（通过 Adapter 为每个交易所创建适配器）
+func NewReplay(files []string, speed float64) *Replay {
+	return &Replay{
+		files:    files,
+		speed:    speed,
+		adapters: make(map[string]*ReplayAdapter),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Adapter 创建交易所的回放适配器（需在任何适配器连接之前创建）
+// 参数:
+//   - config: 交易所配置（Name 为回放的交易所，如 binance；Symbols 为支持的交易对）
+func (p *Replay) Adapter(config *ExchangeConfig) *ReplayAdapter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	adapter := &ReplayAdapter{
+		config:         config,
+		replay:         p,
+		tickerHandlers: make(map[string][]TickerHandler),
+		latest:         make(map[string]*Ticker),
+	}
+	p.adapters[strings.ToLower(config.Name)] = adapter
+	p.waiting++
+	return adapter
+}
+
+// Done 回放结束（文件读完、出错或所有适配器断开连接）时关闭
+func (p *Replay) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err 回放结束的原因（文件读完或主动断开时为 nil）
+func (p *Replay) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// open 首个适配器连接时打开录制文件
+func (p *Replay) open() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.finished {
+		return fmt.Errorf("replay already finished")
+	}
+	if p.opened {
+		return nil
+	}
+
+	reader, closeFiles, err := marketdata.OpenFiles(p.files...)
+	if err != nil {
+		return fmt.Errorf("failed to open replay files: %w", err)
+	}
+	p.reader = reader
+	p.closeFiles = closeFiles
+	p.opened = true
+	return nil
+}
+
+// ready 适配器已订阅或已断开，所有适配器都就绪时开始回放
+func (p *Replay) ready() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.waiting--
+	if p.waiting > 0 || p.started || p.finished {
+		return
+	}
+	p.started = true
+	go p.replay()
+}
+
+// disconnected 适配器断开连接，所有适配器都断开时停止回放
+func (p *Replay) disconnected() {
+	p.mu.Lock()
+	for _, adapter := range p.adapters {
+		if adapter.IsConnected() {
+			p.mu.Unlock()
+			return
+		}
+	}
+
+	if p.finished {
+		p.mu.Unlock()
+		return
+	}
+	close(p.stop)
+	if p.started {
+		// 回放协程负责关闭文件
+		p.mu.Unlock()
+		return
+	}
+	p.finished = true
+	if p.opened {
+		p.err = p.closeFiles()
+	}
+	p.mu.Unlock()
+	close(p.done)
+}
+
+// replay 回放协程
+func (p *Replay) replay() {
+	err := p.run()
+
+	for _, adapter := range p.adapters {
+		adapter.finish()
+	}
+
+	p.mu.Lock()
+	p.finished = true
+	p.err = err
+	if closeErr := p.closeFiles(); p.err == nil {
+		p.err = closeErr
+	}
+	p.mu.Unlock()
+	close(p.done)
+}
+
+// run 按录制时间间隔分发行情（所有交易所共用同一个时间起点），所有适配器断开时返回 nil
+func (p *Replay) run() error {
+	var firstEvent time.Time
+	var startedAt time.Time
+
+	for {
+		select {
+		case <-p.stop:
+			return nil
+		default:
+		}
+
+		event, err := p.reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		adapter, ok := p.adapters[strings.ToLower(event.Exchange)]
+		if !ok {
+			continue
+		}
+		ticker := replayTicker(event)
+		if ticker == nil {
+			continue
+		}
+
+		if p.speed > 0 {
+			if firstEvent.IsZero() {
+				firstEvent, startedAt = event.Time, time.Now()
+			}
+			offset := time.Duration(float64(event.Time.Sub(firstEvent)) / p.speed)
+			if wait := time.Until(startedAt.Add(offset)); wait > 0 && !sleep(wait, p.stop) {
+				return nil
+			}
+		}
+
+		adapter.dispatch(ticker)
+	}
+}
+
+// ReplayAdapter 行情回放适配器
+// 接收 Replay 分发的属于该交易所的行情，调用已注册的 TickerHandler
+type ReplayAdapter struct {
+	config *ExchangeConfig
+	replay *Replay
+
+	tickerHandlers map[string][]TickerHandler
+	handlerMu      sync.RWMutex
+
+	latest   map[string]*Ticker // 交易对 → 最近回放的行情（GetTicker 使用）
+	latestMu sync.RWMutex
+
+	connected  bool
+	subscribed bool // 已订阅或已断开（已通知 Replay 就绪）
+	mu         sync.RWMutex
+}
+
+// NewReplayAdapter 创建单个交易所的行情回放适配器（多个交易所同时回放时使用 NewReplay 共用时间线）
+// 参数:
+//   - config: 交易所配置（Name 为回放的交易所，如 binance；Symbols 为支持的交易对）
+//   - files: 录制文件（支持 .jsonl.gz，多个文件按时间合并）
+//   - speed: 回放倍速（1 为原始节奏，10 为十倍速，≤ 0 表示不等待、尽快回放）
+// 返回:
+//   - *ReplayAdapter: 回放适配器
+func NewReplayAdapter(config *ExchangeConfig, files []string, speed float64) *ReplayAdapter {
+	return NewReplay(files, speed).Adapter(config)
+}
+
+// GetName 获取交易所名称
+func (r *ReplayAdapter) GetName() string {
+	return r.config.Name
+}
+
+// GetSupportedSymbols 获取支持的交易对
+func (r *ReplayAdapter) GetSupportedSymbols() []string {
+	return r.config.Symbols
+}
+
+// Connect 打开录制文件（多个适配器共用）
+func (r *ReplayAdapter) Connect(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.connected {
+		return fmt.Errorf("already connected")
+	}
+	if r.subscribed {
+		return fmt.Errorf("replay already finished")
+	}
+	if err := r.replay.open(); err != nil {
+		return err
+	}
+	r.connected = true
+
+	go func(done <-chan struct{}) {
+		// 连接的上下文结束时断开
+		select {
+		case <-ctx.Done():
+			r.Disconnect()
+		case <-done:
+		}
+	}(r.replay.done)
+
+	return nil
+}
+
+// Disconnect 断开连接（不再接收行情；所有适配器都断开时停止回放并关闭录制文件）
+func (r *ReplayAdapter) Disconnect() error {
+	r.mu.Lock()
+	if !r.connected {
+		r.mu.Unlock()
+		return fmt.Errorf("not connected")
+	}
+	r.connected = false
+	notify := !r.subscribed
+	r.subscribed = true
+	r.mu.Unlock()
+
+	if notify {
+		// 未订阅就断开的适配器不再阻塞其他适配器开始回放
+		r.replay.ready()
+	}
+	r.replay.disconnected()
+	return nil
+}
+
+// IsConnected 检查连接状态（回放结束后为 false）
+func (r *ReplayAdapter) IsConnected() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.connected
+}
+
+// Done 回放结束（文件读完、出错或所有适配器断开连接）时关闭
+func (r *ReplayAdapter) Done() <-chan struct{} {
+	return r.replay.Done()
+}
+
+// Err 回放结束的原因（文件读完或主动断开时为 nil）
+func (r *ReplayAdapter) Err() error {
+	return r.replay.Err()
+}
+
+// SubscribeTicker 订阅价格行情（所有适配器都订阅后开始回放）
+// 交易对支持 BTC/USDT、BTCUSDT 和 BTC-USDT 格式
+func (r *ReplayAdapter) SubscribeTicker(ctx context.Context, symbols []string, handler TickerHandler) error {
+	r.handlerMu.Lock()
+	for _, symbol := range symbols {
+		symbol = normalizeSymbol(symbol)
+		r.tickerHandlers[symbol] = append(r.tickerHandlers[symbol], handler)
+	}
+	r.handlerMu.Unlock()
+
+	r.mu.Lock()
+	if !r.connected {
+		r.mu.Unlock()
+		return fmt.Errorf("failed to subscribe tickers: not connected")
+	}
+	notify := !r.subscribed
+	r.subscribed = true
+	r.mu.Unlock()
+
+	if notify {
+		r.replay.ready()
+	}
+	return nil
+}
+
+// UnsubscribeTicker 取消订阅价格行情
+func (r *ReplayAdapter) UnsubscribeTicker(symbols []string) error {
+	r.handlerMu.Lock()
+	defer r.handlerMu.Unlock()
+
+	for _, symbol := range symbols {
+		delete(r.tickerHandlers, normalizeSymbol(symbol))
+	}
+	return nil
+}
+
+// GetTicker 获取最近回放的价格
+func (r *ReplayAdapter) GetTicker(ctx context.Context, symbol string) (*Ticker, error) {
+	r.latestMu.RLock()
+	defer r.latestMu.RUnlock()
+
+	ticker, ok := r.latest[normalizeSymbol(symbol)]
+	if !ok {
+		return nil, &ExchangeError{Exchange: r.config.Name, Op: "GetTicker", Err: fmt.Errorf("no replayed ticker for %s", symbol)}
+	}
+	copied := *ticker
+	return &copied, nil
+}
+
+// GetTickers 批量获取最近回放的价格
+func (r *ReplayAdapter) GetTickers(ctx context.Context, symbols []string) ([]*Ticker, error) {
+	tickers := make([]*Ticker, 0, len(symbols))
+	for _, symbol := range symbols {
+		ticker, err := r.GetTicker(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		tickers = append(tickers, ticker)
+	}
+	return tickers, nil
+}
+
+// Ping 回放进行中时返回 nil
+func (r *ReplayAdapter) Ping(ctx context.Context) error {
+	if !r.IsConnected() {
+		return &ExchangeError{Exchange: r.config.Name, Op: "Ping", Err: fmt.Errorf("replay not running")}
+	}
+	return nil
+}
+
+// dispatch 推送一条回放的行情（已断开时忽略）
+func (r *ReplayAdapter) dispatch(ticker *Ticker) {
+	if !r.IsConnected() {
+		return
+	}
+
+	r.latestMu.Lock()
+	r.latest[ticker.Symbol] = ticker
+	r.latestMu.Unlock()
+
+	r.handlerMu.RLock()
+	handlers := r.tickerHandlers[ticker.Symbol]
+	r.handlerMu.RUnlock()
+
+	for _, handler := range handlers {
+		copied := *ticker
+		handler(&copied)
+	}
+}
+
+// finish 回放结束后断开连接
+func (r *ReplayAdapter) finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.connected = false
+	r.subscribed = true
+}
+
+// sleep 等待到下一条行情的时间，断开连接时返回 false
+func sleep(d time.Duration, stop <-chan struct{}) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// replayTicker 把录制的行情事件转换为价格行情（订单簿取最优买卖价）
+func replayTicker(event *marketdata.Event) *Ticker {
+	ticker := &Ticker{
+		Exchange:     event.Exchange,
+		Symbol:       event.Symbol,
+		Timestamp:    event.Time,
+		ExchangeTime: event.ExchangeTime,
+		Raw:          event.Raw,
+	}
+
+	switch event.Type {
+	case marketdata.EventTicker:
+		ticker.BidPrice = event.Ticker.BidPrice
+		ticker.AskPrice = event.Ticker.AskPrice
+		ticker.LastPrice = event.Ticker.LastPrice
+		ticker.Volume24h = event.Ticker.Volume24h
+	case marketdata.EventOrderBook:
+		book := event.OrderBook
+		if len(book.Bids) == 0 || len(book.Asks) == 0 {
+			return nil
+		}
+		ticker.BidPrice = book.Bids[0].Price
+		ticker.AskPrice = book.Asks[0].Price
+		ticker.LastPrice = ticker.BidPrice.Add(ticker.AskPrice).Div(decimal.NewFromInt(2))
+	default:
+		return nil
+	}
+	return ticker
+}
+
+// normalizeSymbol 统一交易对格式（BTCUSDT、BTC-USDT -> BTC/USDT）
+func normalizeSymbol(symbol string) string {
+	switch {
+	case strings.Contains(symbol, "/"):
+		return strings.ToUpper(symbol)
+	case strings.Contains(symbol, "-"):
+		return formatOKXSymbol(strings.ToUpper(symbol))
+	default:
+		return formatBinanceSymbol(symbol)
+	}
+}
@@ -0,0 +1,279 @@
+// Package exchange 行情回放适配器测试
+package exchange
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/execution"
+	"arbitragex/pkg/marketdata"
+)
+
+// writeReplayFile 写入测试录制文件
+func writeReplayFile(t *testing.T, events ...*marketdata.Event) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "replay.jsonl")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	defer f.Close()
+
+	w := marketdata.NewWriter(f)
+	for _, event := range events {
+		if err := w.Write(event); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	return path
+}
+
+// replayTickerEvent 构造价格行情事件
+func replayTickerEvent(exchange string, t time.Time, bid, ask float64) *marketdata.Event {
+	return marketdata.NewTickerEvent(&cache.PriceData{Exchange: exchange, Symbol: "BTC/USDT", BidPrice: decimal.NewFromFloat(bid), AskPrice: decimal.NewFromFloat(ask), Timestamp: t})
+}
+
+// TestReplayAdapter_AsFastAsPossible 测试按录制顺序推送该交易所的行情
+func TestReplayAdapter_AsFastAsPossible(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := writeReplayFile(t,
+		replayTickerEvent("binance", t0, 43000, 43001),
+		replayTickerEvent("okx", t0.Add(time.Second), 43100, 43101),
+		marketdata.NewOrderBookEvent(&execution.OrderBook{
+			Exchange:  "binance",
+			Symbol:    "BTC/USDT",
+			Bids:      []execution.OrderBookLevel{{Price: decimal.NewFromFloat(43010), Amount: decimal.NewFromFloat(1)}},
+			Asks:      []execution.OrderBookLevel{{Price: decimal.NewFromFloat(43012), Amount: decimal.NewFromFloat(1)}},
+			Timestamp: t0.Add(time.Hour),
+		}),
+	)
+
+	var adapter ExchangeAdapter = NewReplayAdapter(&ExchangeConfig{Name: "binance"}, []string{path}, 0)
+	replay := adapter.(*ReplayAdapter)
+	if err := adapter.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	var received []*Ticker
+	err := adapter.SubscribeTicker(context.Background(), []string{"BTCUSDT"}, func(ticker *Ticker) {
+		mu.Lock()
+		received = append(received, ticker)
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("SubscribeTicker() error = %v", err)
+	}
+
+	select {
+	case <-replay.Done():
+	case <-time.After(time.Second):
+		t.Fatal("replay did not finish")
+	}
+	if err := replay.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+	if adapter.IsConnected() {
+		t.Error("IsConnected() = true after replay finished")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("received %d tickers, want 2", len(received))
+	}
+	if !received[0].AskPrice.Equal(decimal.NewFromFloat(43001)) || !received[0].Timestamp.Equal(t0) {
+		t.Errorf("first = %+v, want recorded ticker", received[0])
+	}
+	if !received[1].BidPrice.Equal(decimal.NewFromFloat(43010)) || !received[1].AskPrice.Equal(decimal.NewFromFloat(43012)) {
+		t.Errorf("second = %+v, want top of order book", received[1])
+	}
+
+	latest, err := adapter.GetTicker(context.Background(), "BTC/USDT")
+	if err != nil || !latest.AskPrice.Equal(decimal.NewFromFloat(43012)) {
+		t.Errorf("GetTicker() = %+v, %v, want latest replayed ticker", latest, err)
+	}
+}
+
+// TestReplayAdapter_Speed 测试按倍速保持录制时的时间间隔
+func TestReplayAdapter_Speed(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := writeReplayFile(t,
+		replayTickerEvent("okx", t0, 43000, 43001),
+		replayTickerEvent("okx", t0.Add(time.Second), 43000, 43001),
+	)
+
+	adapter := NewReplayAdapter(&ExchangeConfig{Name: "okx"}, []string{path}, 10)
+	if err := adapter.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	start := time.Now()
+	if err := adapter.SubscribeTicker(context.Background(), []string{"BTC-USDT"}, func(*Ticker) {}); err != nil {
+		t.Fatalf("SubscribeTicker() error = %v", err)
+	}
+	<-adapter.Done()
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("replay took %v, want about 100ms at 10x", elapsed)
+	}
+}
+
+// TestReplayAdapter_Disconnect 测试断开连接时停止等待下一条行情
+func TestReplayAdapter_Disconnect(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := writeReplayFile(t,
+		replayTickerEvent("binance", t0, 43000, 43001),
+		replayTickerEvent("binance", t0.Add(time.Hour), 43000, 43001),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	adapter := NewReplayAdapter(&ExchangeConfig{Name: "binance"}, []string{path}, 1)
+	if err := adapter.Connect(ctx); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	first := make(chan struct{}, 2)
+	if err := adapter.SubscribeTicker(ctx, []string{"BTC/USDT"}, func(*Ticker) { first <- struct{}{} }); err != nil {
+		t.Fatalf("SubscribeTicker() error = %v", err)
+	}
+	<-first
+
+	cancel()
+	select {
+	case <-adapter.Done():
+	case <-time.After(time.Second):
+		t.Fatal("replay did not stop after context canceled")
+	}
+	if len(first) != 0 {
+		t.Error("received ticker after disconnect")
+	}
+}
+
+// TestReplay_SharedTimeline 测试多个交易所按录制顺序和同一时间线回放
+func TestReplay_SharedTimeline(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := writeReplayFile(t,
+		replayTickerEvent("okx", t0, 43100, 43101),
+		replayTickerEvent("binance", t0.Add(100*time.Millisecond), 43000, 43001),
+		replayTickerEvent("okx", t0.Add(200*time.Millisecond), 43102, 43103),
+		replayTickerEvent("binance", t0.Add(300*time.Millisecond), 43002, 43003),
+	)
+
+	replay := NewReplay([]string{path}, 1)
+	adapters := []*ReplayAdapter{
+		replay.Adapter(&ExchangeConfig{Name: "binance"}),
+		replay.Adapter(&ExchangeConfig{Name: "okx"}),
+	}
+
+	var mu sync.Mutex
+	var received []string
+	var offsets []time.Duration
+	var startedAt time.Time
+	for _, adapter := range adapters {
+		if err := adapter.Connect(context.Background()); err != nil {
+			t.Fatalf("Connect() error = %v", err)
+		}
+	}
+	for _, adapter := range adapters {
+		if adapter == adapters[1] {
+			startedAt = time.Now()
+		}
+		err := adapter.SubscribeTicker(context.Background(), []string{"BTC/USDT"}, func(ticker *Ticker) {
+			mu.Lock()
+			received = append(received, ticker.Exchange)
+			offsets = append(offsets, time.Since(startedAt))
+			mu.Unlock()
+		})
+		if err != nil {
+			t.Fatalf("SubscribeTicker() error = %v", err)
+		}
+	}
+
+	select {
+	case <-replay.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("replay did not finish")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"okx", "binance", "okx", "binance"}
+	if len(received) != len(want) {
+		t.Fatalf("received %v, want %v", received, want)
+	}
+	for i := range want {
+		if received[i] != want[i] {
+			t.Fatalf("received %v, want %v", received, want)
+		}
+	}
+	// binance 的第一条行情按共同的时间起点延后 100ms 推送
+	if offsets[1] < 80*time.Millisecond || offsets[3] < 280*time.Millisecond {
+		t.Errorf("offsets = %v, want recorded intervals from a shared start", offsets)
+	}
+	for _, adapter := range adapters {
+		if adapter.IsConnected() {
+			t.Errorf("%s IsConnected() = true after replay finished", adapter.GetName())
+		}
+	}
+}
+
+// TestReplay_WaitsForAllAdapters 测试所有交易所都订阅后才开始回放
+func TestReplay_WaitsForAllAdapters(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := writeReplayFile(t,
+		replayTickerEvent("okx", t0, 43100, 43101),
+		replayTickerEvent("binance", t0, 43000, 43001),
+	)
+
+	replay := NewReplay([]string{path}, 0)
+	binance := replay.Adapter(&ExchangeConfig{Name: "binance"})
+	okx := replay.Adapter(&ExchangeConfig{Name: "okx"})
+	for _, adapter := range []*ReplayAdapter{binance, okx} {
+		if err := adapter.Connect(context.Background()); err != nil {
+			t.Fatalf("Connect() error = %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	var received []string
+	handler := func(ticker *Ticker) {
+		mu.Lock()
+		received = append(received, ticker.Exchange)
+		mu.Unlock()
+	}
+	if err := binance.SubscribeTicker(context.Background(), []string{"BTC/USDT"}, handler); err != nil {
+		t.Fatalf("SubscribeTicker() error = %v", err)
+	}
+
+	select {
+	case <-replay.Done():
+		t.Fatal("replay started before all adapters subscribed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := okx.SubscribeTicker(context.Background(), []string{"BTC/USDT"}, handler); err != nil {
+		t.Fatalf("SubscribeTicker() error = %v", err)
+	}
+	select {
+	case <-replay.Done():
+	case <-time.After(time.Second):
+		t.Fatal("replay did not finish")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || received[0] != "okx" || received[1] != "binance" {
+		t.Errorf("received %v, want [okx binance]", received)
+	}
+}
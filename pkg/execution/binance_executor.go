@@ -3,8 +3,6 @@ package execution
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,6 +13,8 @@ import (
 	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
+
+	"arbitragex/common/decimal"
 )
 
 // BinanceExecutor Binance 订单执行器
@@ -22,8 +22,8 @@ type BinanceExecutor struct {
 	// API Key
 	apiKey string
 
-	// API Secret
-	apiSecret string
+	// API Secret（签名密钥，Close 时清零）
+	secret *signingKey
 
 	// REST API 基础 URL
 	baseURL string
@@ -33,16 +33,19 @@ type BinanceExecutor struct {
 
 	// 日志记录器
 	logger logx.Logger
+
+	// 私有数据流（订单推送）
+	orderTracking
 }
 
 // NewBinanceExecutor 创建 Binance 订单执行器
 // 参数:
 //   - apiKey: API 密钥
-//   - apiSecret: API 密钥对应的 Secret
+//   - apiSecret: API 密钥对应的 Secret（执行器保存副本，调用方可以随后清零）
 //   - baseURL: REST API 基础 URL（测试环境可使用测试网 URL）
 // 返回:
 //   - *BinanceExecutor: Binance 订单执行器实例
-func NewBinanceExecutor(apiKey, apiSecret, baseURL string) *BinanceExecutor {
+func NewBinanceExecutor(apiKey string, apiSecret []byte, baseURL string) *BinanceExecutor {
 	// 设置默认基础 URL
 	if baseURL == "" {
 		baseURL = "https://api.binance.com"
@@ -50,7 +53,7 @@ func NewBinanceExecutor(apiKey, apiSecret, baseURL string) *BinanceExecutor {
 
 	return &BinanceExecutor{
 		apiKey:     apiKey,
-		apiSecret:  apiSecret,
+		secret:     newSigningKey(apiSecret),
 		baseURL:    baseURL,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
@@ -68,26 +71,7 @@ func (b *BinanceExecutor) PlaceOrder(ctx context.Context, req *PlaceOrderRequest
 	}
 
 	// 构建请求参数
-	params := url.Values{}
-	params.Set("symbol", b.toBinanceSymbol(req.Symbol))
-	params.Set("side", strings.ToUpper(req.Side))
-	params.Set("type", strings.ToUpper(req.Type))
-
-	// 设置订单类型相关参数
-	switch strings.ToUpper(req.Type) {
-	case "LIMIT":
-		params.Set("timeInForce", "GTC") // Good Till Cancel
-		params.Set("price", strconv.FormatFloat(req.Price, 'f', -1, 64))
-	case "MARKET":
-		// 市价单不需要价格
-	}
-
-	params.Set("quantity", strconv.FormatFloat(req.Amount, 'f', -1, 64))
-
-	// 客户端订单 ID（可选）
-	if req.ClientOrderID != "" {
-		params.Set("newClientOrderId", req.ClientOrderID)
-	}
+	params := b.buildOrderParams(req)
 
 	// 发送请求
 	response, err := b.signAndRequest(ctx, "POST", "/api/v3/order", params)
@@ -96,7 +80,12 @@ func (b *BinanceExecutor) PlaceOrder(ctx context.Context, req *PlaceOrderRequest
 	}
 
 	// 解析响应
-	return b.parseOrderResponse(response, req)
+	order, err := b.parseOrderResponse(response, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.trackOrder(order), nil
 }
 
 // CancelOrder 撤单
@@ -150,6 +139,19 @@ func (b *BinanceExecutor) QueryOrder(ctx context.Context, exchange, orderID stri
 		return nil, fmt.Errorf("无效的订单ID格式: %s", orderID)
 	}
 
+	// 私有数据流已连接时直接使用推送的订单状态
+	if order, ok := b.trackedOrder(orderID); ok {
+		return order, nil
+	}
+	return b.fetchOrder(ctx, orderID)
+}
+
+// fetchOrder 通过 REST 查询订单（不使用推送的状态，私有数据流重连后补查和等待推送超时时使用）
+func (b *BinanceExecutor) fetchOrder(ctx context.Context, orderID string) (*Order, error) {
+	parts := strings.Split(orderID, ":")
+	if len(parts) != 3 || parts[0] != "binance" {
+		return nil, fmt.Errorf("无效的订单ID格式: %s", orderID)
+	}
 	symbol := parts[1]
 	exchangeOrderID := parts[2]
 
@@ -165,7 +167,43 @@ func (b *BinanceExecutor) QueryOrder(ctx context.Context, exchange, orderID stri
 	}
 
 	// 解析响应
-	return b.parseOrderQueryResponse(response)
+	order, err := b.parseOrderQueryResponse(response)
+	if err != nil {
+		return nil, err
+	}
+
+	// 经过状态机，过期的查询结果不会让状态倒退
+	return b.trackOrder(order), nil
+}
+
+// QueryOrderByClientID 按客户端订单ID查询订单
+func (b *BinanceExecutor) QueryOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*Order, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("交易对不能为空")
+	}
+	if clientOrderID == "" {
+		return nil, fmt.Errorf("客户端订单ID不能为空")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", b.toBinanceSymbol(symbol))
+	params.Set("origClientOrderId", clientOrderID)
+
+	response, err := b.signAndRequest(ctx, "GET", "/api/v3/order", params)
+	if err != nil {
+		// -2013: Order does not exist.
+		if strings.Contains(err.Error(), `"code":-2013`) {
+			return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, clientOrderID)
+		}
+		return nil, fmt.Errorf("查询订单失败: %w", err)
+	}
+
+	order, err := b.parseOrderQueryResponse(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.trackOrder(order), nil
 }
 
 // GetOrderBook 获取订单簿深度
@@ -193,6 +231,160 @@ func (b *BinanceExecutor) GetOrderBook(ctx context.Context, exchange, symbol str
 	return b.parseOrderBookResponse(response, symbol)
 }
 
+// GetBalances 查询账户余额
+func (b *BinanceExecutor) GetBalances(ctx context.Context) ([]*Balance, error) {
+	// 发送请求
+	response, err := b.signAndRequest(ctx, "GET", "/api/v3/account", url.Values{})
+	if err != nil {
+		return nil, fmt.Errorf("查询余额失败: %w", err)
+	}
+
+	// 解析响应
+	return b.parseBalancesResponse(response)
+}
+
+// GetDepositAddress 查询充值地址
+func (b *BinanceExecutor) GetDepositAddress(ctx context.Context, asset, network string) (*DepositAddress, error) {
+	// 参数校验
+	if asset == "" {
+		return nil, fmt.Errorf("币种不能为空")
+	}
+
+	// 构建请求参数
+	params := url.Values{}
+	params.Set("coin", strings.ToUpper(asset))
+	if network != "" {
+		params.Set("network", network)
+	}
+
+	// 发送请求
+	response, err := b.signAndRequest(ctx, "GET", "/sapi/v1/capital/deposit/address", params)
+	if err != nil {
+		return nil, fmt.Errorf("查询充值地址失败: %w", err)
+	}
+
+	// 解析响应
+	address, _ := response["address"].(string)
+	if address == "" {
+		return nil, fmt.Errorf("充值地址为空: %s %s", asset, network)
+	}
+	tag, _ := response["tag"].(string)
+
+	return &DepositAddress{
+		Exchange: "binance",
+		Asset:    strings.ToUpper(asset),
+		Network:  network,
+		Address:  address,
+		Tag:      tag,
+	}, nil
+}
+
+// Withdraw 提币
+// Binance 的提币手续费从提币数量中扣除，到账数量 = Amount - 手续费
+func (b *BinanceExecutor) Withdraw(ctx context.Context, req *WithdrawRequest) (*Withdrawal, error) {
+	// 参数校验
+	if err := validateWithdrawRequest(req, "binance"); err != nil {
+		return nil, fmt.Errorf("参数校验失败: %w", err)
+	}
+
+	// 构建请求参数
+	params := url.Values{}
+	params.Set("coin", strings.ToUpper(req.Asset))
+	params.Set("network", req.Network)
+	params.Set("address", req.Address)
+	params.Set("amount", req.Amount.String())
+	if req.Tag != "" {
+		params.Set("addressTag", req.Tag)
+	}
+	if req.ClientID != "" {
+		params.Set("withdrawOrderId", req.ClientID)
+	}
+
+	// 发送请求
+	response, err := b.signAndRequest(ctx, "POST", "/sapi/v1/capital/withdraw/apply", params)
+	if err != nil {
+		return nil, fmt.Errorf("提币失败: %w", err)
+	}
+
+	// 解析响应
+	id, _ := response["id"].(string)
+	if id == "" {
+		return nil, fmt.Errorf("提币失败: 响应中缺少提币ID")
+	}
+
+	withdrawal := &Withdrawal{
+		ID:        id,
+		Exchange:  "binance",
+		Asset:     strings.ToUpper(req.Asset),
+		Network:   req.Network,
+		Address:   req.Address,
+		Amount:    req.Amount,
+		Fee:       req.Fee,
+		ClientID:  req.ClientID,
+		CreatedAt: time.Now(),
+	}
+
+	b.logger.Infof("提币已提交: %s %.8f %s -> %s (%s)", withdrawal.ID, req.Amount, withdrawal.Asset, req.Address, req.Network)
+	return withdrawal, nil
+}
+
+// buildOrderParams 构建下单参数（REST 和 WebSocket 下单共用）
+func (b *BinanceExecutor) buildOrderParams(req *PlaceOrderRequest) url.Values {
+	params := url.Values{}
+	params.Set("symbol", b.toBinanceSymbol(req.Symbol))
+	params.Set("side", strings.ToUpper(req.Side))
+
+	// 设置订单类型相关参数
+	switch req.Type {
+	case OrderTypeLimit:
+		if req.PostOnly {
+			// 只做 Maker 使用 LIMIT_MAKER 类型，不接受 timeInForce
+			params.Set("type", "LIMIT_MAKER")
+		} else {
+			params.Set("type", "LIMIT")
+			params.Set("timeInForce", b.toBinanceTimeInForce(req.TimeInForce))
+		}
+		params.Set("price", req.Price.String())
+		params.Set("quantity", req.Amount.String())
+	case OrderTypeMarket:
+		params.Set("type", "MARKET")
+		if req.QuoteAmount.IsPositive() {
+			// 按计价货币金额下单（如花费 100 USDT 买入）
+			params.Set("quoteOrderQty", req.QuoteAmount.String())
+		} else {
+			params.Set("quantity", req.Amount.String())
+		}
+	}
+
+	// 客户端订单 ID（可选）
+	if req.ClientOrderID != "" {
+		params.Set("newClientOrderId", req.ClientOrderID)
+	}
+
+	return params
+}
+
+// toBinanceTimeInForce 转换为 Binance 有效方式（默认 GTC）
+func (b *BinanceExecutor) toBinanceTimeInForce(timeInForce string) string {
+	if timeInForce == "" {
+		return "GTC"
+	}
+	return strings.ToUpper(timeInForce)
+}
+
+// parseOrderType 解析 Binance 订单类型
+// LIMIT_MAKER 对应只做 Maker 的限价单
+func (b *BinanceExecutor) parseOrderType(orderType, timeInForce string) (string, string, bool) {
+	switch orderType {
+	case "LIMIT_MAKER":
+		return OrderTypeLimit, TimeInForceGTC, true
+	case "MARKET":
+		return OrderTypeMarket, "", false
+	default:
+		return strings.ToLower(orderType), strings.ToLower(timeInForce), false
+	}
+}
+
 // signAndRequest 发送需要签名的请求
 func (b *BinanceExecutor) signAndRequest(ctx context.Context, method, endpoint string, params url.Values) (map[string]interface{}, error) {
 	// 添加时间戳
@@ -217,7 +409,7 @@ func (b *BinanceExecutor) request(ctx context.Context, method, endpoint string,
 
 	// 创建请求
 	var reqBody io.Reader
-	if method == "POST" || method == "DELETE" {
+	if method == "POST" || method == "PUT" || method == "DELETE" {
 		reqBody = strings.NewReader(params.Encode())
 	}
 
@@ -261,9 +453,7 @@ func (b *BinanceExecutor) request(ctx context.Context, method, endpoint string,
 
 // generateSignature 生成签名
 func (b *BinanceExecutor) generateSignature(queryString string) string {
-	h := hmac.New(sha256.New, []byte(b.apiSecret))
-	h.Write([]byte(queryString))
-	return fmt.Sprintf("%x", h.Sum(nil))
+	return fmt.Sprintf("%x", b.secret.sum(queryString))
 }
 
 // toBinanceSymbol 转换为 Binance 交易对格式
@@ -303,13 +493,10 @@ func (b *BinanceExecutor) validatePlaceOrderRequest(req *PlaceOrderRequest) erro
 	if req.Type != OrderTypeLimit && req.Type != OrderTypeMarket {
 		return fmt.Errorf("无效的订单类型: %s", req.Type)
 	}
-	if req.Type == OrderTypeLimit && req.Price <= 0 {
+	if req.Type == OrderTypeLimit && !req.Price.IsPositive() {
 		return fmt.Errorf("限价单价格必须大于 0")
 	}
-	if req.Amount <= 0 {
-		return fmt.Errorf("数量必须大于 0")
-	}
-	return nil
+	return validateSpotOrder("binance", req)
 }
 
 // parseOrderResponse 解析下单响应
@@ -321,11 +508,12 @@ func (b *BinanceExecutor) parseOrderResponse(response map[string]interface{}, re
 
 	// 解析订单信息
 	order := &Order{
-		ID:            fmt.Sprintf("binance:%s:%v", b.toBinanceSymbol(req.Symbol), response["orderId"]),
+		ID:            fmt.Sprintf("binance:%s:%d", b.toBinanceSymbol(req.Symbol), int64(parseFloat(response["orderId"]))),
 		Exchange:      "binance",
 		Symbol:        req.Symbol,
 		Side:          req.Side,
 		Type:          req.Type,
+		PostOnly:      req.PostOnly,
 		Price:         req.Price,
 		Amount:        req.Amount,
 		ClientOrderID: req.ClientOrderID,
@@ -341,21 +529,21 @@ func (b *BinanceExecutor) parseOrderResponse(response map[string]interface{}, re
 
 	// 解析已成交数量
 	if filledQty, ok := response["executedQty"].(string); ok {
-		if qty, err := strconv.ParseFloat(filledQty, 64); err == nil {
+		if qty, err := decimal.NewFromString(filledQty); err == nil {
 			order.FilledAmount = qty
 		}
 	}
 
 	// 解析平均价格
 	if avgPrice, ok := response["avgPrice"].(string); ok {
-		if price, err := strconv.ParseFloat(avgPrice, 64); err == nil {
+		if price, err := decimal.NewFromString(avgPrice); err == nil {
 			order.AveragePrice = price
 		}
 	}
 
 	// 解析手续费
 	if fee, ok := response["commission"].(string); ok {
-		if f, err := strconv.ParseFloat(fee, 64); err == nil {
+		if f, err := decimal.NewFromString(fee); err == nil {
 			order.Fee = f
 		}
 	}
@@ -365,8 +553,18 @@ func (b *BinanceExecutor) parseOrderResponse(response map[string]interface{}, re
 		order.FeeCurrency = feeCurrency
 	}
 
+	// 限价单的有效方式
+	if req.Type == OrderTypeLimit && !req.PostOnly {
+		order.TimeInForce = strings.ToLower(b.toBinanceTimeInForce(req.TimeInForce))
+	}
+
+	// 按金额下单时，数量以交易所计算的 origQty 为准
+	if !order.Amount.IsPositive() {
+		order.Amount = parseDecimal(response["origQty"])
+	}
+
 	// 如果完全成交，更新状态
-	if order.FilledAmount >= order.Amount {
+	if order.Amount.IsPositive() && order.FilledAmount.GreaterThanOrEqual(order.Amount) {
 		order.Status = OrderStatusFilled
 	}
 
@@ -385,47 +583,51 @@ func (b *BinanceExecutor) parseOrderQueryResponse(response map[string]interface{
 	symbol, _ := response["symbol"].(string)
 	side, _ := response["side"].(string)
 	orderType, _ := response["type"].(string)
+	timeInForce, _ := response["timeInForce"].(string)
+	parsedType, parsedTimeInForce, postOnly := b.parseOrderType(orderType, timeInForce)
 
 	order := &Order{
-		ID:       fmt.Sprintf("binance:%s:%v", symbol, response["orderId"]),
-		Exchange: "binance",
-		Symbol:   b.toStandardSymbol(symbol),
-		Side:     strings.ToLower(side),
-		Type:     strings.ToLower(orderType),
-		Status:   b.parseOrderStatus(response),
+		ID:          fmt.Sprintf("binance:%s:%d", symbol, int64(parseFloat(response["orderId"]))),
+		Exchange:    "binance",
+		Symbol:      b.toStandardSymbol(symbol),
+		Side:        strings.ToLower(side),
+		Type:        parsedType,
+		TimeInForce: parsedTimeInForce,
+		PostOnly:    postOnly,
+		Status:      b.parseOrderStatus(response),
 	}
 
 	// 解析价格
 	if price, ok := response["price"].(string); ok {
-		if p, err := strconv.ParseFloat(price, 64); err == nil {
+		if p, err := decimal.NewFromString(price); err == nil {
 			order.Price = p
 		}
 	}
 
 	// 解析数量
 	if qty, ok := response["origQty"].(string); ok {
-		if q, err := strconv.ParseFloat(qty, 64); err == nil {
+		if q, err := decimal.NewFromString(qty); err == nil {
 			order.Amount = q
 		}
 	}
 
 	// 解析已成交数量
 	if filledQty, ok := response["executedQty"].(string); ok {
-		if q, err := strconv.ParseFloat(filledQty, 64); err == nil {
+		if q, err := decimal.NewFromString(filledQty); err == nil {
 			order.FilledAmount = q
 		}
 	}
 
 	// 解析平均价格
 	if avgPrice, ok := response["avgPrice"].(string); ok {
-		if p, err := strconv.ParseFloat(avgPrice, 64); err == nil {
+		if p, err := decimal.NewFromString(avgPrice); err == nil {
 			order.AveragePrice = p
 		}
 	}
 
 	// 解析手续费
 	if fee, ok := response["commission"].(string); ok {
-		if f, err := strconv.ParseFloat(fee, 64); err == nil {
+		if f, err := decimal.NewFromString(fee); err == nil {
 			order.Fee = f
 		}
 	}
@@ -465,8 +667,8 @@ func (b *BinanceExecutor) parseOrderBookResponse(response map[string]interface{}
 	if bids, ok := response["bids"].([]interface{}); ok {
 		for _, bid := range bids {
 			if bidArray, ok := bid.([]interface{}); ok && len(bidArray) >= 2 {
-				price := parseFloat(bidArray[0])
-				amount := parseFloat(bidArray[1])
+				price := parseDecimal(bidArray[0])
+				amount := parseDecimal(bidArray[1])
 				orderBook.Bids = append(orderBook.Bids, OrderBookLevel{
 					Price:  price,
 					Amount: amount,
@@ -479,8 +681,8 @@ func (b *BinanceExecutor) parseOrderBookResponse(response map[string]interface{}
 	if asks, ok := response["asks"].([]interface{}); ok {
 		for _, ask := range asks {
 			if askArray, ok := ask.([]interface{}); ok && len(askArray) >= 2 {
-				price := parseFloat(askArray[0])
-				amount := parseFloat(askArray[1])
+				price := parseDecimal(askArray[0])
+				amount := parseDecimal(askArray[1])
 				orderBook.Asks = append(orderBook.Asks, OrderBookLevel{
 					Price:  price,
 					Amount: amount,
@@ -492,27 +694,47 @@ func (b *BinanceExecutor) parseOrderBookResponse(response map[string]interface{}
 	return orderBook, nil
 }
 
-// parseOrderStatus 解析订单状态
-func (b *BinanceExecutor) parseOrderStatus(response map[string]interface{}) string {
-	status, ok := response["status"].(string)
+// parseBalancesResponse 解析账户余额响应
+func (b *BinanceExecutor) parseBalancesResponse(response map[string]interface{}) ([]*Balance, error) {
+	// 检查是否有错误
+	if errMsg, ok := response["msg"].(string); ok {
+		return nil, fmt.Errorf("查询余额失败: %s", errMsg)
+	}
+
+	items, ok := response["balances"].([]interface{})
 	if !ok {
-		return OrderStatusPending
-	}
-
-	switch status {
-	case "NEW":
-		return OrderStatusOpen
-	case "PARTIALLY_FILLED":
-		return OrderStatusPartiallyFilled
-	case "FILLED":
-		return OrderStatusFilled
-	case "CANCELED":
-		return OrderStatusCanceled
-	case "REJECTED", "EXPIRED":
-		return OrderStatusFailed
-	default:
-		return OrderStatusPending
+		return nil, fmt.Errorf("响应数据格式错误")
 	}
+
+	balances := make([]*Balance, 0, len(items))
+	for _, item := range items {
+		data, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		asset, _ := data["asset"].(string)
+		balance := &Balance{
+			Exchange: "binance",
+			Asset:    asset,
+			Free:     parseDecimal(data["free"]),
+			Locked:   parseDecimal(data["locked"]),
+		}
+
+		// 跳过零余额
+		if !balance.Total().IsPositive() {
+			continue
+		}
+		balances = append(balances, balance)
+	}
+
+	return balances, nil
+}
+
+// parseOrderStatus 解析订单状态
+func (b *BinanceExecutor) parseOrderStatus(response map[string]interface{}) string {
+	status, _ := response["status"].(string)
+	return mapExchangeStatus(binanceOrderStatuses, status)
 }
 
 // parseFloat 安全地解析 float64
@@ -527,3 +749,16 @@ func parseFloat(v interface{}) float64 {
 	}
 	return 0
 }
+
+// parseDecimal 安全地解析价格、数量（交易所以字符串返回，格式错误时返回 0）
+func parseDecimal(v interface{}) decimal.Decimal {
+	switch val := v.(type) {
+	case string:
+		if d, err := decimal.NewFromString(val); err == nil {
+			return d
+		}
+	case float64:
+		return decimal.NewFromFloat(val)
+	}
+	return decimal.Zero
+}
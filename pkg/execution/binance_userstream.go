@@ -0,0 +1,353 @@
+// Package execution 提供订单执行功能
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"arbitragex/common/decimal"
+)
+
+const (
+	// binanceUserStreamURL Binance 私有数据流 WebSocket 地址
+	binanceUserStreamURL = "wss://stream.binance.com:9443/ws"
+
+	// binanceListenKeyKeepalive listenKey 续期间隔（有效期 60 分钟）
+	binanceListenKeyKeepalive = 30 * time.Minute
+)
+
+// BinanceUserStream Binance 私有数据流
+// 通过 listenKey 订阅 executionReport（订单）和 outboundAccountPosition（余额）事件
+type BinanceUserStream struct {
+	streamHandlers
+
+	// 执行器（复用其 REST 客户端申请 listenKey）
+	executor *BinanceExecutor
+
+	// WebSocket 基础地址
+	wsBaseURL string
+
+	// 当前 listenKey
+	listenKey string
+	keyMu     sync.Mutex
+
+	// WebSocket 连接
+	stream *wsStream
+
+	// 累计手续费（executionReport 只带单笔成交的手续费）
+	fees  map[string]decimal.Decimal
+	feeMu sync.Mutex
+
+	// listenKey 续期
+	keepaliveCancel context.CancelFunc
+
+	// 日志记录器
+	logger logx.Logger
+}
+
+// NewBinanceUserStream 创建 Binance 私有数据流
+// 参数:
+//   - executor: Binance 订单执行器
+//   - wsBaseURL: WebSocket 基础地址（为空时使用生产环境地址）
+func NewBinanceUserStream(executor *BinanceExecutor, wsBaseURL string) *BinanceUserStream {
+	if wsBaseURL == "" {
+		wsBaseURL = binanceUserStreamURL
+	}
+
+	s := &BinanceUserStream{
+		executor:  executor,
+		wsBaseURL: strings.TrimRight(wsBaseURL, "/"),
+		fees:      make(map[string]decimal.Decimal),
+		logger:    logx.WithContext(context.Background()),
+	}
+
+	s.stream = &wsStream{
+		name:         "Binance 私有数据流",
+		dialURL:      s.dialURL,
+		onMessage:    s.handleMessage,
+		onReconnect:  s.emitReconnect,
+		pingInterval: 3 * time.Minute,
+		logger:       s.logger,
+	}
+
+	return s
+}
+
+// Start 申请 listenKey 并连接私有数据流
+func (s *BinanceUserStream) Start(ctx context.Context) error {
+	if err := s.stream.start(ctx); err != nil {
+		return err
+	}
+
+	keepaliveCtx, cancel := context.WithCancel(ctx)
+	s.keepaliveCancel = cancel
+	go s.keepalive(keepaliveCtx)
+
+	s.logger.Infof("Binance 私有数据流已连接")
+	return nil
+}
+
+// Stop 断开私有数据流并删除 listenKey
+func (s *BinanceUserStream) Stop() error {
+	if s.keepaliveCancel != nil {
+		s.keepaliveCancel()
+	}
+
+	if err := s.stream.stop(); err != nil {
+		return err
+	}
+
+	s.keyMu.Lock()
+	listenKey := s.listenKey
+	s.listenKey = ""
+	s.keyMu.Unlock()
+
+	if listenKey != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		params := url.Values{}
+		params.Set("listenKey", listenKey)
+		if _, err := s.executor.request(ctx, "DELETE", "/api/v3/userDataStream", params, true); err != nil {
+			s.logger.Errorf("删除 listenKey 失败: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (s *BinanceUserStream) IsConnected() bool {
+	return s.stream.isConnected()
+}
+
+// dialURL 申请新的 listenKey 并返回连接地址
+// 每次重连都重新申请，listenKey 过期后旧地址不可用
+func (s *BinanceUserStream) dialURL(ctx context.Context) (string, error) {
+	response, err := s.executor.request(ctx, "POST", "/api/v3/userDataStream", url.Values{}, true)
+	if err != nil {
+		return "", fmt.Errorf("申请 listenKey 失败: %w", err)
+	}
+
+	listenKey, _ := response["listenKey"].(string)
+	if listenKey == "" {
+		return "", fmt.Errorf("响应中缺少 listenKey")
+	}
+
+	s.keyMu.Lock()
+	s.listenKey = listenKey
+	s.keyMu.Unlock()
+
+	return s.wsBaseURL + "/" + listenKey, nil
+}
+
+// keepalive 定期续期 listenKey
+func (s *BinanceUserStream) keepalive(ctx context.Context) {
+	ticker := time.NewTicker(binanceListenKeyKeepalive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.keyMu.Lock()
+			listenKey := s.listenKey
+			s.keyMu.Unlock()
+
+			if listenKey == "" {
+				continue
+			}
+
+			params := url.Values{}
+			params.Set("listenKey", listenKey)
+			if _, err := s.executor.request(ctx, "PUT", "/api/v3/userDataStream", params, true); err != nil {
+				s.logger.Errorf("listenKey 续期失败: %v", err)
+			}
+		}
+	}
+}
+
+// handleMessage 处理推送消息
+func (s *BinanceUserStream) handleMessage(message []byte) {
+	var data map[string]interface{}
+	if err := json.Unmarshal(message, &data); err != nil {
+		s.logger.Errorf("解析 JSON 失败: %v, 消息: %s", err, string(message))
+		return
+	}
+
+	eventType, _ := data["e"].(string)
+	switch eventType {
+	case "executionReport":
+		s.emitOrder(s.parseExecutionReport(data))
+	case "outboundAccountPosition":
+		s.emitBalances(s.parseAccountPosition(data))
+	case "listenKeyExpired":
+		// 关闭连接触发重连，重连时会申请新的 listenKey
+		s.logger.Errorf("listenKey 已过期，重新连接")
+		s.stream.closeConn()
+	}
+}
+
+// parseExecutionReport 解析订单推送
+func (s *BinanceUserStream) parseExecutionReport(data map[string]interface{}) *Order {
+	symbol, _ := data["s"].(string)
+	side, _ := data["S"].(string)
+	orderType, _ := data["o"].(string)
+	clientOrderID, _ := data["c"].(string)
+
+	// 撤单推送中 c 为撤单请求的客户端ID，原始客户端ID在 C 中
+	if origClientOrderID, ok := data["C"].(string); ok && origClientOrderID != "" {
+		clientOrderID = origClientOrderID
+	}
+
+	timeInForce, _ := data["f"].(string)
+	parsedType, parsedTimeInForce, postOnly := s.executor.parseOrderType(orderType, timeInForce)
+
+	exchangeOrderID := strconv.FormatInt(int64(parseFloat(data["i"])), 10)
+	orderID := fmt.Sprintf("binance:%s:%s", symbol, exchangeOrderID)
+
+	order := &Order{
+		ID:              orderID,
+		Exchange:        "binance",
+		Symbol:          s.executor.toStandardSymbol(symbol),
+		Side:            strings.ToLower(side),
+		Type:            parsedType,
+		TimeInForce:     parsedTimeInForce,
+		PostOnly:        postOnly,
+		Price:           parseDecimal(data["p"]),
+		Amount:          parseDecimal(data["q"]),
+		FilledAmount:    parseDecimal(data["z"]),
+		Status:          s.executor.parseOrderStatus(map[string]interface{}{"status": data["X"]}),
+		ExchangeOrderID: exchangeOrderID,
+		ClientOrderID:   clientOrderID,
+	}
+
+	// 平均成交价 = 累计成交额 / 累计成交量
+	if order.FilledAmount.IsPositive() {
+		order.AveragePrice = parseDecimal(data["Z"]).Div(order.FilledAmount)
+	}
+
+	// 累计手续费
+	s.feeMu.Lock()
+	if executionType, _ := data["x"].(string); executionType == "TRADE" {
+		s.fees[orderID] = s.fees[orderID].Add(parseDecimal(data["n"]))
+	}
+	order.Fee = s.fees[orderID]
+	if IsFinalStatus(order.Status) {
+		delete(s.fees, orderID)
+	}
+	s.feeMu.Unlock()
+
+	if feeCurrency, ok := data["N"].(string); ok {
+		order.FeeCurrency = feeCurrency
+	}
+
+	if reason, ok := data["r"].(string); ok && reason != "NONE" {
+		order.ErrorMessage = reason
+	}
+
+	if createTime, ok := data["O"].(float64); ok {
+		order.CreatedAt = time.UnixMilli(int64(createTime))
+	}
+	if updateTime, ok := data["T"].(float64); ok {
+		order.UpdatedAt = time.UnixMilli(int64(updateTime))
+	}
+
+	return order
+}
+
+// parseAccountPosition 解析余额推送
+func (s *BinanceUserStream) parseAccountPosition(data map[string]interface{}) []*Balance {
+	items, ok := data["B"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	balances := make([]*Balance, 0, len(items))
+	for _, item := range items {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		asset, _ := entry["a"].(string)
+		balances = append(balances, &Balance{
+			Exchange: "binance",
+			Asset:    asset,
+			Free:     parseDecimal(entry["f"]),
+			Locked:   parseDecimal(entry["l"]),
+		})
+	}
+
+	return balances
+}
+
+// StartUserStream 启动私有数据流
+// 启动后 QueryOrder 优先返回推送的订单状态，WaitOrder 不再轮询 REST
+// 参数:
+//   - ctx: 上下文对象（取消后数据流停止）
+//   - wsBaseURL: WebSocket 基础地址（为空时使用生产环境地址）
+// 返回:
+//   - *BinanceUserStream: 私有数据流（可注册余额更新回调）
+//   - error: 错误信息
+func (b *BinanceExecutor) StartUserStream(ctx context.Context, wsBaseURL string) (*BinanceUserStream, error) {
+	stream := NewBinanceUserStream(b, wsBaseURL)
+	if err := b.attachStream(stream, b.fetchOrder); err != nil {
+		return nil, err
+	}
+
+	if err := stream.Start(ctx); err != nil {
+		b.detachStream()
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+// StopUserStream 停止私有数据流
+func (b *BinanceExecutor) StopUserStream() error {
+	stream := b.detachStream()
+	if stream == nil {
+		return fmt.Errorf("私有数据流未启动")
+	}
+	return stream.Stop()
+}
+
+// Connect 启动 EnableUserStream 启用的私有数据流（未启用时不做任何事）
+func (b *BinanceExecutor) Connect(ctx context.Context) error {
+	wsURL, ok := b.userStreamConf()
+	if !ok {
+		return nil
+	}
+	_, err := b.StartUserStream(ctx, wsURL)
+	return err
+}
+
+// Close 停止 EnableUserStream 启用的私有数据流并清零 API Secret（之后不能再发送签名请求）
+func (b *BinanceExecutor) Close() error {
+	err := b.closeUserStream()
+	b.secret.wipe()
+	return err
+}
+
+// closeUserStream 停止 EnableUserStream 启用的私有数据流
+func (b *BinanceExecutor) closeUserStream() error {
+	if _, ok := b.userStreamConf(); !ok {
+		return nil
+	}
+	return b.StopUserStream()
+}
+
+// WaitOrder 等待订单进入终态（完全成交、已撤销或失败）
+func (b *BinanceExecutor) WaitOrder(ctx context.Context, exchange, orderID string) (*Order, error) {
+	return b.waitOrder(ctx, orderID, b.fetchOrder)
+}
@@ -0,0 +1,143 @@
+// Package execution 提供订单执行功能
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// binanceWSAPIURL Binance WebSocket API 地址
+const binanceWSAPIURL = "wss://ws-api.binance.com:443/ws-api/v3"
+
+// BinanceWSExecutor Binance WebSocket 下单执行器
+// 通过 WebSocket API 的 order.place 下单，复用常驻连接省去每单的 TLS 和 HTTP 开销；
+// 撤单、查询、订单簿等其他操作沿用内嵌的 REST 执行器
+type BinanceWSExecutor struct {
+	*BinanceExecutor
+
+	// 请求/响应客户端
+	rpc *wsRPC
+
+	// WebSocket API 地址
+	wsURL string
+}
+
+// NewBinanceWSExecutor 创建 Binance WebSocket 下单执行器
+// 参数:
+//   - executor: Binance REST 执行器（提供 API 密钥、签名和其他操作）
+//   - wsURL: WebSocket API 地址（为空时使用生产环境地址）
+// 返回:
+//   - *BinanceWSExecutor: Binance WebSocket 下单执行器实例
+func NewBinanceWSExecutor(executor *BinanceExecutor, wsURL string) *BinanceWSExecutor {
+	if wsURL == "" {
+		wsURL = binanceWSAPIURL
+	}
+
+	b := &BinanceWSExecutor{
+		BinanceExecutor: executor,
+		wsURL:           wsURL,
+	}
+
+	b.rpc = newWSRPC(&wsStream{
+		name: "Binance WebSocket API",
+		dialURL: func(ctx context.Context) (string, error) {
+			return b.wsURL, nil
+		},
+		pingInterval: time.Minute,
+		logger:       executor.logger,
+	})
+
+	return b
+}
+
+// Connect 建立 WebSocket API 连接（断线后自动重连）
+// 启用了私有数据流时同时启动私有数据流
+func (b *BinanceWSExecutor) Connect(ctx context.Context) error {
+	if err := b.BinanceExecutor.Connect(ctx); err != nil {
+		return err
+	}
+	if err := b.rpc.stream.start(ctx); err != nil {
+		b.closeUserStream()
+		return err
+	}
+	return nil
+}
+
+// Close 断开 WebSocket API 连接（启用了私有数据流时同时停止）
+func (b *BinanceWSExecutor) Close() error {
+	err := b.rpc.stream.stop()
+	if streamErr := b.BinanceExecutor.Close(); err == nil {
+		err = streamErr
+	}
+	return err
+}
+
+// IsConnected 检查连接状态
+func (b *BinanceWSExecutor) IsConnected() bool {
+	return b.rpc.stream.isConnected()
+}
+
+// PlaceOrder 通过 WebSocket API 下单
+// 连接未建立时回退到 REST 下单
+func (b *BinanceWSExecutor) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*Order, error) {
+	if !b.IsConnected() {
+		b.logger.Errorf("Binance WebSocket API 未连接，使用 REST 下单")
+		return b.BinanceExecutor.PlaceOrder(ctx, req)
+	}
+
+	// 参数校验
+	if err := b.validatePlaceOrderRequest(req); err != nil {
+		return nil, fmt.Errorf("参数校验失败: %w", err)
+	}
+
+	// 构建并签名请求参数（与 REST 相同的 HMAC 签名，apiKey 放在参数中）
+	params := b.buildOrderParams(req)
+	params.Set("apiKey", b.apiKey)
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	params.Set("signature", b.generateSignature(params.Encode()))
+
+	wsParams := make(map[string]string, len(params))
+	for key := range params {
+		wsParams[key] = params.Get(key)
+	}
+
+	id := b.rpc.newID()
+	message, err := b.rpc.call(ctx, id, map[string]interface{}{
+		"id":     id,
+		"method": "order.place",
+		"params": wsParams,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("下单失败: %w", err)
+	}
+
+	// 解析响应
+	var response struct {
+		Status int                    `json:"status"`
+		Result map[string]interface{} `json:"result"`
+		Error  *struct {
+			Code int    `json:"code"`
+			Msg  string `json:"msg"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(message, &response); err != nil {
+		return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+	}
+
+	if response.Error != nil {
+		return nil, fmt.Errorf("下单失败: %d %s", response.Error.Code, response.Error.Msg)
+	}
+	if response.Status != 200 || response.Result == nil {
+		return nil, fmt.Errorf("下单失败: 状态码 %d", response.Status)
+	}
+
+	order, err := b.parseOrderResponse(response.Result, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.trackOrder(order), nil
+}
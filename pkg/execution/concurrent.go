@@ -3,11 +3,19 @@ package execution
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"go.opentelemetry.io/otel/trace"
+
+	"arbitragex/common/clock"
+	"arbitragex/common/decimal"
+	"arbitragex/common/tracing"
 )
 
 // ConcurrentExecutor 并发执行器接口
@@ -21,7 +29,7 @@ type ConcurrentExecutor interface {
 	// 返回:
 	//   - *ExecutionResult: 执行结果
 	//   - error: 错误信息
-	ExecuteArbitrage(ctx context.Context, opp *ArbitrageOpportunity, amount float64) (*ExecutionResult, error)
+	ExecuteArbitrage(ctx context.Context, opp *ArbitrageOpportunity, amount decimal.Decimal) (*ExecutionResult, error)
 
 	// GetStatus 获取执行器状态
 	// 返回:
@@ -36,6 +44,9 @@ type ConcurrentExecutor interface {
 
 // ArbitrageOpportunity 套利机会（从 pkg/engine 复制）
 type ArbitrageOpportunity struct {
+	// ID 套利机会 ID（为空时执行结果使用执行任务 ID）
+	ID string `json:"id,omitempty"`
+
 	// Symbol 交易对
 	Symbol string `json:"symbol"`
 
@@ -46,13 +57,13 @@ type ArbitrageOpportunity struct {
 	SellExchange string `json:"sell_exchange"`
 
 	// BuyPrice 买入价格
-	BuyPrice float64 `json:"buy_price"`
+	BuyPrice decimal.Decimal `json:"buy_price"`
 
 	// SellPrice 卖出价格
-	SellPrice float64 `json:"sell_price"`
+	SellPrice decimal.Decimal `json:"sell_price"`
 
 	// PriceDiff 价格差（SellPrice - BuyPrice）
-	PriceDiff float64 `json:"price_diff"`
+	PriceDiff decimal.Decimal `json:"price_diff"`
 
 	// PriceDiffRate 价差百分比
 	PriceDiffRate float64 `json:"price_diff_rate"`
@@ -61,13 +72,13 @@ type ArbitrageOpportunity struct {
 	RevenueRate float64 `json:"revenue_rate"`
 
 	// EstRevenue 预期毛收益（USDT）
-	EstRevenue float64 `json:"est_revenue"`
+	EstRevenue decimal.Decimal `json:"est_revenue"`
 
 	// EstCost 预期总成本（USDT）
-	EstCost float64 `json:"est_cost"`
+	EstCost decimal.Decimal `json:"est_cost"`
 
 	// NetProfit 预期净收益（USDT）
-	NetProfit float64 `json:"net_profit"`
+	NetProfit decimal.Decimal `json:"net_profit"`
 
 	// ProfitRate 净收益率
 	ProfitRate float64 `json:"profit_rate"`
@@ -100,7 +111,7 @@ type ExecutionResult struct {
 	SellExchange string `json:"sell_exchange"`
 
 	// TradingAmount 交易金额（USDT）
-	TradingAmount float64 `json:"trading_amount"`
+	TradingAmount decimal.Decimal `json:"trading_amount"`
 
 	// BuyOrder 买入订单
 	BuyOrder *Order `json:"buy_order"`
@@ -109,10 +120,10 @@ type ExecutionResult struct {
 	SellOrder *Order `json:"sell_order"`
 
 	// EstProfit 预期收益（USDT）
-	EstProfit float64 `json:"est_profit"`
+	EstProfit decimal.Decimal `json:"est_profit"`
 
 	// ActualProfit 实际收益（USDT）
-	ActualProfit float64 `json:"actual_profit"`
+	ActualProfit decimal.Decimal `json:"actual_profit"`
 
 	// Status 执行状态
 	Status string `json:"status"`
@@ -151,7 +162,7 @@ type ExecutorStatus struct {
 	TotalSuccess int64 `json:"total_success"`
 
 	// TotalProfit 总收益（USDT）
-	TotalProfit float64 `json:"total_profit"`
+	TotalProfit decimal.Decimal `json:"total_profit"`
 
 	// StartTime 启动时间
 	StartTime time.Time `json:"start_time"`
@@ -166,6 +177,21 @@ const (
 	ExecutionStatusCanceled   = "canceled"    // 已取消
 )
 
+// ResultHook 执行结果回调（每次执行结束都会调用，包括启动时恢复的执行；在执行协程中同步执行，不能修改结果）
+type ResultHook func(result *ExecutionResult)
+
+// defaultLegTimeout 等待单个订单进入终态的默认超时时间，超时后撤单
+const defaultLegTimeout = 10 * time.Second
+
+// defaultExecuteTimeout ExecuteArbitrage 等待执行结果的默认超时时间
+const defaultExecuteTimeout = 30 * time.Second
+
+// defaultPlaceLookupWindow 下单返回错误后确认订单的默认最长时间
+const defaultPlaceLookupWindow = 5 * time.Second
+
+// placeLookupInterval 确认订单时的首次重试间隔（之后每次翻倍）
+const placeLookupInterval = 100 * time.Millisecond
+
 // DefaultConcurrentExecutor 默认并发执行器实现
 type DefaultConcurrentExecutor struct {
 	// 互斥锁
@@ -189,6 +215,24 @@ type DefaultConcurrentExecutor struct {
 	// 订单执行器映射
 	executors map[string]OrderExecutor
 
+	// 执行日志（为空时不记录，崩溃后无法恢复）
+	journal Journal
+
+	// 等待单个订单进入终态的超时时间
+	legTimeout time.Duration
+
+	// 下单返回错误后按客户端订单ID确认订单的最长时间
+	placeLookupWindow time.Duration
+
+	// 等待一次套利执行结果的超时时间
+	executeTimeout time.Duration
+
+	// 时钟
+	clock clock.Clock
+
+	// 执行结果回调
+	resultHooks []ResultHook
+
 	// 统计数据
 	stats *ExecutorStatus
 
@@ -216,6 +260,10 @@ func NewDefaultConcurrentExecutor(maxConcurrent int, executors map[string]OrderE
 		pool:            NewWorkerPool(maxConcurrent),
 		queue:           NewTaskQueue(1000), // 默认队列大小 1000
 		executors:       executors,
+		legTimeout:      defaultLegTimeout,
+		placeLookupWindow: defaultPlaceLookupWindow,
+		executeTimeout:  defaultExecuteTimeout,
+		clock:           clock.Real,
 		stats: &ExecutorStatus{
 			Running:        false,
 			MaxConcurrent:  maxConcurrent,
@@ -227,32 +275,133 @@ func NewDefaultConcurrentExecutor(maxConcurrent int, executors map[string]OrderE
 	}
 }
 
-// ExecuteArbitrage 执行套利
-func (e *DefaultConcurrentExecutor) ExecuteArbitrage(ctx context.Context, opp *ArbitrageOpportunity, amount float64) (*ExecutionResult, error) {
+// SetJournal 设置执行日志（需在 Start 之前调用）
+func (e *DefaultConcurrentExecutor) SetJournal(journal Journal) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.journal = journal
+}
+
+// SetQueueSize 设置任务队列长度（队列满时提交失败；需在 Start 之前调用）
+func (e *DefaultConcurrentExecutor) SetQueueSize(size int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.queue = NewTaskQueue(size)
+	e.queue.SetClock(e.clock)
+}
+
+// OnResult 注册执行结果回调（如保存执行记录；需在 Start 之前调用）
+func (e *DefaultConcurrentExecutor) OnResult(hook ResultHook) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.resultHooks = append(e.resultHooks, hook)
+}
+
+// SetClock 设置时钟（测试时使用手动时钟，超时和过期不需要真实等待；需在 Start 之前调用）
+func (e *DefaultConcurrentExecutor) SetClock(clk clock.Clock) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.clock = clock.OrReal(clk)
+	e.stats.StartTime = e.clock.Now()
+	e.queue.SetClock(e.clock)
+}
+
+// Start 启动执行器
+// 先建立订单执行器的常驻连接（WebSocket 下单、私有数据流），设置了执行日志时再恢复上次进程退出时未完成的执行，
+// 之后开始接收新任务
+func (e *DefaultConcurrentExecutor) Start(ctx context.Context) error {
+	e.mu.RLock()
+	running := e.running
+	e.mu.RUnlock()
+	if running {
+		return nil
+	}
+
+	// 连接保持到 Stop（退出时等待执行中的套利结束期间仍可下单）
+	if err := e.connect(context.WithoutCancel(ctx)); err != nil {
+		return err
+	}
+
+	results, err := e.Recover(ctx)
+	if err != nil {
+		e.disconnect()
+		return fmt.Errorf("恢复未完成的执行失败: %w", err)
+	}
+	for _, result := range results {
+		if result.Status == ExecutionStatusExecuting {
+			e.logger.Errorf("执行 %s 恢复失败，下次启动时重试: %s", result.ID, result.ErrorMessage)
+		}
+		e.notifyResult(result)
+	}
+
+	e.pool.Start()
+
+	e.mu.Lock()
+	e.running = true
+	e.stats.Running = true
+	e.stats.StartTime = e.clock.Now()
+	e.mu.Unlock()
+
+	e.logger.Infof("并发执行器已启动，最大并发数: %d", e.maxConcurrent)
+	return nil
+}
+
+// ExecuteArbitrage 执行套利（提交后等待执行结果）
+func (e *DefaultConcurrentExecutor) ExecuteArbitrage(ctx context.Context, opp *ArbitrageOpportunity, amount decimal.Decimal) (*ExecutionResult, error) {
+	results, err := e.Submit(ctx, opp, amount)
+	if err != nil {
+		return nil, err
+	}
+	return e.Await(ctx, results)
+}
+
+// Submit 提交套利执行，入队后立即返回，不等待执行结束
+// 参数:
+//   - ctx: 上下文对象（用于追踪，取消后执行仍会继续）
+//   - opp: 套利机会
+//   - amount: 交易金额（USDT）
+// 返回:
+//   - <-chan *ExecutionResult: 执行结束后收到执行结果（结果同时通过 OnResult 回调通知）
+//   - error: 队列已满时返回 *QueueFullError，此时执行未被受理
+func (e *DefaultConcurrentExecutor) Submit(ctx context.Context, opp *ArbitrageOpportunity, amount decimal.Decimal) (<-chan *ExecutionResult, error) {
 	// 创建执行任务
 	task := &ExecutionTask{
-		ID:            generateID(),
-		Opportunity:   opp,
-		Amount:        amount,
-		ResultChan:    make(chan *ExecutionResult, 1),
-		CreatedAt:     time.Now(),
+		ID:          generateID(),
+		Opportunity: opp,
+		Amount:      amount,
+		ResultChan:  make(chan *ExecutionResult, 1),
+		CreatedAt:   e.clock.Now(),
 	}
+	task.traceQueued(ctx)
 
 	// 提交任务到队列
 	if err := e.queue.Enqueue(task); err != nil {
+		tracing.End(task.queueSpan, err)
 		return nil, err
 	}
+	observeQueueDepth(e.queue)
 
 	// 尝试启动任务
 	e.tryStartTask()
+	return task.ResultChan, nil
+}
 
-	// 等待结果或超时
+// Await 等待 Submit 提交的执行结果
+// 参数:
+//   - ctx: 上下文对象（取消后停止等待，执行仍会继续）
+//   - results: Submit 返回的结果通道
+// 返回:
+//   - *ExecutionResult: 执行结果
+//   - error: 等待取消或超过执行超时时间时返回
+func (e *DefaultConcurrentExecutor) Await(ctx context.Context, results <-chan *ExecutionResult) (*ExecutionResult, error) {
 	select {
-	case result := <-task.ResultChan:
+	case result := <-results:
 		return result, nil
 	case <-ctx.Done():
 		return nil, ctx.Err()
-	case <-time.After(30 * time.Second):
+	case <-e.clock.After(e.executeTimeout):
 		return nil, fmt.Errorf("执行超时")
 	}
 }
@@ -272,26 +421,56 @@ func (e *DefaultConcurrentExecutor) GetStatus() *ExecutorStatus {
 // Stop 停止执行器
 func (e *DefaultConcurrentExecutor) Stop() error {
 	e.mu.Lock()
-	defer e.mu.Unlock()
-
 	if !e.running {
+		e.mu.Unlock()
 		return nil
 	}
 
-	// 停止 Goroutine 池
-	e.pool.Stop()
-
-	// 取消上下文
-	e.cancel()
-
 	// 更新状态
 	e.running = false
 	e.stats.Running = false
+	e.mu.Unlock()
+
+	// 取消上下文（执行中的任务停止等待订单），再等待 Goroutine 池退出；
+	// 等待期间不能持有锁，任务结束时需要更新计数
+	e.cancel()
+	e.pool.Stop()
+	e.disconnect()
 
 	e.logger.Info("并发执行器已停止")
 	return nil
 }
 
+// connect 建立需要常驻连接的订单执行器（Connector）的连接，任一失败时断开已建立的连接
+func (e *DefaultConcurrentExecutor) connect(ctx context.Context) error {
+	var connected []Connector
+	for exchange, executor := range e.executors {
+		connector, ok := executor.(Connector)
+		if !ok {
+			continue
+		}
+		if err := connector.Connect(ctx); err != nil {
+			for _, c := range connected {
+				c.Close()
+			}
+			return fmt.Errorf("连接 %s 订单执行器失败: %w", exchange, err)
+		}
+		connected = append(connected, connector)
+	}
+	return nil
+}
+
+// disconnect 断开订单执行器的常驻连接
+func (e *DefaultConcurrentExecutor) disconnect() {
+	for exchange, executor := range e.executors {
+		if connector, ok := executor.(Connector); ok {
+			if err := connector.Close(); err != nil {
+				e.logger.Errorf("断开 %s 订单执行器失败: %v", exchange, err)
+			}
+		}
+	}
+}
+
 // tryStartTask 尝试启动任务（从队列中获取并执行）
 func (e *DefaultConcurrentExecutor) tryStartTask() {
 	e.mu.Lock()
@@ -308,15 +487,38 @@ func (e *DefaultConcurrentExecutor) tryStartTask() {
 		e.mu.Unlock()
 		return
 	}
+	observeQueueDepth(e.queue)
 
 	// 增加活跃任务数
 	e.activeExecutions++
 	e.mu.Unlock()
 
 	// 提交到 Goroutine 池执行
-	e.pool.Submit(func() {
+	err = e.pool.Submit(func() {
 		e.executeTask(task)
 	})
+	if err != nil {
+		e.mu.Lock()
+		e.activeExecutions--
+		e.mu.Unlock()
+		tracing.End(task.queueSpan, err)
+
+		now := e.clock.Now()
+		result := &ExecutionResult{
+			ID:            generateID(),
+			OpportunityID: task.opportunityID(),
+			Symbol:        task.Opportunity.Symbol,
+			BuyExchange:   task.Opportunity.BuyExchange,
+			SellExchange:  task.Opportunity.SellExchange,
+			TradingAmount: task.Amount,
+			Status:        ExecutionStatusFailed,
+			ErrorMessage:  fmt.Sprintf("提交任务失败: %v", err),
+			StartedAt:     now,
+			CompletedAt:   now,
+		}
+		e.notifyResult(result)
+		task.ResultChan <- result
+	}
 }
 
 // executeTask 执行单个任务
@@ -325,45 +527,474 @@ func (e *DefaultConcurrentExecutor) executeTask(task *ExecutionTask) {
 		e.mu.Lock()
 		e.activeExecutions--
 		e.mu.Unlock()
+
+		// 继续执行队列中等待的任务
+		e.tryStartTask()
 	}()
+	metricQueueWait.ObserveFloat(e.clock.Now().Sub(task.CreatedAt).Seconds())
+	task.queueSpan.End()
 
 	// 创建执行结果
 	result := &ExecutionResult{
 		ID:            generateID(),
-		OpportunityID: task.ID,
+		OpportunityID: task.opportunityID(),
 		Symbol:        task.Opportunity.Symbol,
 		BuyExchange:   task.Opportunity.BuyExchange,
 		SellExchange:  task.Opportunity.SellExchange,
 		TradingAmount: task.Amount,
 		EstProfit:     task.Opportunity.NetProfit,
 		Status:        ExecutionStatusExecuting,
-		StartedAt:     time.Now(),
+		StartedAt:     e.clock.Now(),
 	}
 
 	// 执行套利逻辑
-	e.executeArbitrageLogic(task.Opportunity, task.Amount, result)
+	ctx, span := task.traceExecution(e.ctx, result.ID)
+	e.executeArbitrageLogic(ctx, task.Opportunity, task.Amount, result)
+	endExecution(span, result)
 
 	// 更新统计
 	e.updateStats(result)
+	observeResult(result)
+	e.notifyResult(result)
 
 	// 发送结果
 	task.ResultChan <- result
 }
 
 // executeArbitrageLogic 执行套利逻辑
-func (e *DefaultConcurrentExecutor) executeArbitrageLogic(opp *ArbitrageOpportunity, amount float64, result *ExecutionResult) {
-	// TODO: 实现完整的套利执行逻辑
-	// 1. 在买入交易所下单
-	// 2. 在卖出交易所下单
-	// 3. 监控订单状态
-	// 4. 计算实际收益
+// 两边同时下 IOC 限价单（价格不差于发现机会时的报价），等待成交后对冲两边的成交数量差；
+// 每笔订单下单前先写执行日志，进程崩溃后由 Recover 接着处理
+func (e *DefaultConcurrentExecutor) executeArbitrageLogic(ctx context.Context, opp *ArbitrageOpportunity, amount decimal.Decimal, result *ExecutionResult) {
+	exec := &JournaledExecution{
+		ID:          result.ID,
+		Opportunity: opp,
+		Amount:      amount,
+		StartedAt:   result.StartedAt,
+	}
 
-	// 临时：直接返回成功状态
-	result.Status = ExecutionStatusCompleted
-	result.CompletedAt = time.Now()
-	result.ActualProfit = opp.NetProfit
+	if !opp.BuyPrice.IsPositive() || !opp.SellPrice.IsPositive() || !amount.IsPositive() {
+		e.finishExecution(exec, result, ExecutionStatusFailed, "无效的套利价格或交易金额")
+		return
+	}
+	for _, exchange := range []string{opp.BuyExchange, opp.SellExchange} {
+		if _, ok := e.executors[exchange]; !ok {
+			e.finishExecution(exec, result, ExecutionStatusFailed, fmt.Sprintf("未配置交易所执行器: %s", exchange))
+			return
+		}
+	}
 
-	e.logger.Infof("套利执行完成: %s, 收益: %.2f USDT", result.Symbol, result.ActualProfit)
+	if err := e.appendJournal(&JournalEntry{
+		Type:        JournalExecutionStarted,
+		ExecutionID: exec.ID,
+		Opportunity: opp,
+		Amount:      amount,
+		Time:        exec.StartedAt,
+	}); err != nil {
+		e.finishExecution(exec, result, ExecutionStatusFailed, fmt.Sprintf("写入执行日志失败: %v", err))
+		return
+	}
+
+	// 下单数量按 8 位小数截断（与交易所和数据库精度一致）
+	baseAmount := amount.Div(opp.BuyPrice).Truncate(amountPlaces)
+	exec.Legs = []*JournaledLeg{
+		e.newLeg(exec.ID, LegBuy, opp.BuyExchange, opp.Symbol, OrderSideBuy, OrderTypeLimit, opp.BuyPrice, baseAmount),
+		e.newLeg(exec.ID, LegSell, opp.SellExchange, opp.Symbol, OrderSideSell, OrderTypeLimit, opp.SellPrice, baseAmount),
+	}
+
+	var wg sync.WaitGroup
+	for _, leg := range exec.Legs {
+		wg.Add(1)
+		go func(leg *JournaledLeg) {
+			defer wg.Done()
+			e.placeLeg(ctx, exec.ID, leg)
+		}(leg)
+	}
+	wg.Wait()
+
+	e.settleExecution(ctx, exec, result)
+}
+
+// newLeg 构建订单腿（客户端订单ID由执行ID和订单腿确定）
+func (e *DefaultConcurrentExecutor) newLeg(executionID, leg, exchange, symbol, side, orderType string, price, amount decimal.Decimal) *JournaledLeg {
+	req := &PlaceOrderRequest{
+		Exchange:      exchange,
+		Symbol:        symbol,
+		Side:          side,
+		Type:          orderType,
+		Amount:        amount,
+		ClientOrderID: clientOrderID(executionID, leg),
+	}
+	if orderType == OrderTypeLimit {
+		req.Price = price
+		req.TimeInForce = TimeInForceIOC
+	}
+
+	return &JournaledLeg{Leg: leg, Request: req}
+}
+
+// placeLeg 记录下单意图后下单
+// 日志写入失败时不下单；下单请求出错时按客户端订单ID确认订单是否已经到达交易所，
+// 仍无法确认时 leg.Order 保持为空
+func (e *DefaultConcurrentExecutor) placeLeg(ctx context.Context, executionID string, leg *JournaledLeg) {
+	if err := e.appendJournal(&JournalEntry{
+		Type:        JournalOrderIntent,
+		ExecutionID: executionID,
+		Leg:         leg.Leg,
+		Request:     leg.Request,
+	}); err != nil {
+		leg.Order = e.failedOrder(leg.Request, fmt.Sprintf("写入执行日志失败: %v", err))
+		return
+	}
+
+	leg.placedAt = e.clock.Now()
+	spanCtx, span := traceOrder(ctx, "execution.PlaceOrder", executionID, leg)
+	executor, ok := e.executors[leg.Request.Exchange]
+	if !ok {
+		// 恢复的执行日志中可能有已不再配置的交易所
+		err := fmt.Errorf("未配置交易所执行器: %s", leg.Request.Exchange)
+		endOrder(span, nil, err)
+		e.recordLeg(executionID, leg, e.failedOrder(leg.Request, err.Error()))
+		return
+	}
+	order, err := executor.PlaceOrder(spanCtx, leg.Request)
+	endOrder(span, order, err)
+	if err != nil {
+		e.logger.Errorf("执行 %s 下单失败 (%s): %v", executionID, leg.Leg, err)
+
+		order, err = e.confirmPlacement(ctx, leg, err)
+		if err != nil {
+			e.logger.Errorf("执行 %s 无法确认订单 %s 状态: %v", executionID, leg.Request.ClientOrderID, err)
+			return
+		}
+	}
+
+	e.recordLeg(executionID, leg, order)
+}
+
+// confirmPlacement 下单返回错误后确认订单是否已到达交易所
+// 超时或连接断开时请求结果未知，订单可能稍后才能查到：查不到时按退避间隔重试，
+// 超过 placeLookupWindow 仍查不到才视为下单失败
+// 返回:
+//   - *Order: 查到的订单，或下单失败的订单
+//   - error: 无法确认订单状态（查询失败或 ctx 取消）
+func (e *DefaultConcurrentExecutor) confirmPlacement(ctx context.Context, leg *JournaledLeg, placeErr error) (*Order, error) {
+	deadline := e.clock.Now().Add(e.placeLookupWindow)
+	interval := placeLookupInterval
+
+	for {
+		order, err := e.lookupLeg(ctx, leg)
+		if !errors.Is(err, ErrOrderNotFound) {
+			return order, err
+		}
+
+		remaining := deadline.Sub(e.clock.Now())
+		if remaining <= 0 {
+			return e.failedOrder(leg.Request, fmt.Sprintf("下单失败: %v", placeErr)), nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-e.clock.After(min(interval, remaining)):
+		}
+		interval *= 2
+	}
+}
+
+// lookupLeg 按客户端订单ID在交易所上查询订单腿
+// 执行器不支持按客户端订单ID查询时，只能用已记录的订单ID查询
+func (e *DefaultConcurrentExecutor) lookupLeg(ctx context.Context, leg *JournaledLeg) (*Order, error) {
+	executor, ok := e.executors[leg.Request.Exchange]
+	if !ok {
+		return nil, fmt.Errorf("未配置交易所执行器: %s", leg.Request.Exchange)
+	}
+
+	if querier, ok := executor.(ClientOrderQuerier); ok {
+		return querier.QueryOrderByClientID(ctx, leg.Request.Symbol, leg.Request.ClientOrderID)
+	}
+	if leg.Order != nil && leg.Order.ID != "" {
+		return executor.QueryOrder(ctx, leg.Request.Exchange, leg.Order.ID)
+	}
+	return nil, fmt.Errorf("%s 执行器不支持按客户端订单ID查询", leg.Request.Exchange)
+}
+
+// recordLeg 更新订单腿并写入执行日志
+func (e *DefaultConcurrentExecutor) recordLeg(executionID string, leg *JournaledLeg, order *Order) {
+	leg.Order = order
+	observeLeg(leg, order, e.clock)
+
+	if err := e.appendJournal(&JournalEntry{
+		Type:        JournalOrderUpdated,
+		ExecutionID: executionID,
+		Leg:         leg.Leg,
+		Order:       order,
+	}); err != nil {
+		e.logger.Errorf("执行 %s 写入订单状态失败: %v", executionID, err)
+	}
+}
+
+// waitLeg 等待订单腿进入终态，超时后撤单并以撤单后的状态为准
+func (e *DefaultConcurrentExecutor) waitLeg(ctx context.Context, executionID string, leg *JournaledLeg) {
+	if leg.Order == nil || IsFinalStatus(leg.Order.Status) {
+		return
+	}
+
+	exchange := leg.Request.Exchange
+	executor, ok := e.executors[exchange]
+	if !ok {
+		e.logger.Errorf("执行 %s 无法等待订单 %s: 未配置交易所执行器: %s", executionID, leg.Order.ID, exchange)
+		return
+	}
+	orderID := leg.Order.ID
+
+	ctx, span := traceOrder(ctx, "execution.WaitFill", executionID, leg)
+	defer endWait(span, leg)
+
+	waitCtx, cancel := clock.WithTimeout(ctx, e.clock, e.legTimeout)
+	order, err := e.waitOrder(waitCtx, executor, exchange, orderID)
+	cancel()
+
+	if err != nil || order == nil || !IsFinalStatus(order.Status) {
+		if err := executor.CancelOrder(ctx, exchange, orderID); err != nil {
+			e.logger.Errorf("执行 %s 撤单失败 %s: %v", executionID, orderID, err)
+		}
+		order, err = executor.QueryOrder(ctx, exchange, orderID)
+		if err != nil {
+			e.logger.Errorf("执行 %s 查询订单失败 %s: %v", executionID, orderID, err)
+			return
+		}
+	}
+
+	e.recordLeg(executionID, leg, order)
+}
+
+// waitOrder 等待订单终态（执行器不支持等待时轮询查询）
+func (e *DefaultConcurrentExecutor) waitOrder(ctx context.Context, executor OrderExecutor, exchange, orderID string) (*Order, error) {
+	if waiter, ok := executor.(OrderWaiter); ok {
+		return waiter.WaitOrder(ctx, exchange, orderID)
+	}
+
+	ticker := time.NewTicker(orderPollInterval)
+	defer ticker.Stop()
+
+	for {
+		order, err := executor.QueryOrder(ctx, exchange, orderID)
+		if err == nil && IsFinalStatus(order.Status) {
+			return order, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return order, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// settleExecution 等待所有订单腿结束，对冲两边成交数量差，计算实际收益并结束执行
+// 仍有订单状态无法确认时不写结束记录，留给下次启动时恢复
+func (e *DefaultConcurrentExecutor) settleExecution(ctx context.Context, exec *JournaledExecution, result *ExecutionResult) {
+	for _, leg := range exec.Legs {
+		e.waitLeg(ctx, exec.ID, leg)
+	}
+	if leg := unresolvedLeg(exec); leg != nil {
+		e.leaveUnresolved(exec, result, leg)
+		return
+	}
+
+	net := netPosition(exec)
+	unwinds := 0
+	for _, leg := range exec.Legs {
+		if strings.HasPrefix(leg.Leg, LegUnwind) {
+			unwinds++
+		}
+	}
+
+	for net.Abs().GreaterThan(positionTolerance) && unwinds < maxUnwindAttempts {
+		unwinds++
+		leg := e.unwindLeg(exec, net, unwinds)
+		exec.Legs = append(exec.Legs, leg)
+
+		e.logger.Errorf("执行 %s 两边成交数量不一致 (%.8f)，%s 对冲", exec.ID, net, leg.Request.Exchange)
+		e.placeLeg(ctx, exec.ID, leg)
+		e.waitLeg(ctx, exec.ID, leg)
+		if leg.Order == nil || !IsFinalStatus(leg.Order.Status) {
+			e.leaveUnresolved(exec, result, leg)
+			return
+		}
+
+		net = netPosition(exec)
+	}
+
+	result.BuyOrder = legOrder(exec, LegBuy)
+	result.SellOrder = legOrder(exec, LegSell)
+	result.ActualProfit = realizedProfit(exec)
+
+	matched := decimal.Min(legFilled(exec, LegBuy), legFilled(exec, LegSell))
+	switch {
+	case net.Abs().GreaterThan(positionTolerance):
+		e.finishExecution(exec, result, ExecutionStatusFailed, fmt.Sprintf("对冲后仍有 %.8f 未平仓", net))
+	case matched.LessThanOrEqual(positionTolerance) && unwinds > 0:
+		e.finishExecution(exec, result, ExecutionStatusFailed, "单边成交，已对冲平仓")
+	case matched.LessThanOrEqual(positionTolerance):
+		e.finishExecution(exec, result, ExecutionStatusFailed, "两边均未成交")
+	case unwinds > 0:
+		e.finishExecution(exec, result, ExecutionStatusCompleted, fmt.Sprintf("部分成交，对冲 %d 次", unwinds))
+	default:
+		e.finishExecution(exec, result, ExecutionStatusCompleted, "")
+	}
+}
+
+// unwindLeg 构建对冲单：多买入的部分在买入交易所卖出，多卖出的部分在卖出交易所买回
+func (e *DefaultConcurrentExecutor) unwindLeg(exec *JournaledExecution, net decimal.Decimal, attempt int) *JournaledLeg {
+	opp := exec.Opportunity
+	name := fmt.Sprintf("%s%d", LegUnwind, attempt)
+
+	if net.IsPositive() {
+		return e.newLeg(exec.ID, name, opp.BuyExchange, opp.Symbol, OrderSideSell, OrderTypeMarket, decimal.Zero, net)
+	}
+	return e.newLeg(exec.ID, name, opp.SellExchange, opp.Symbol, OrderSideBuy, OrderTypeMarket, decimal.Zero, net.Neg())
+}
+
+// leaveUnresolved 订单状态无法确认，执行保持未结束
+func (e *DefaultConcurrentExecutor) leaveUnresolved(exec *JournaledExecution, result *ExecutionResult, leg *JournaledLeg) {
+	result.BuyOrder = legOrder(exec, LegBuy)
+	result.SellOrder = legOrder(exec, LegSell)
+	result.Status = ExecutionStatusFailed
+	result.ErrorMessage = fmt.Sprintf("订单 %s 状态无法确认，等待重启后恢复", leg.Request.ClientOrderID)
+	result.CompletedAt = e.clock.Now()
+
+	e.logger.Errorf("执行 %s: %s", exec.ID, result.ErrorMessage)
+}
+
+// finishExecution 结束执行并写入结束记录
+func (e *DefaultConcurrentExecutor) finishExecution(exec *JournaledExecution, result *ExecutionResult, status, message string) {
+	result.Status = status
+	result.ErrorMessage = message
+	result.CompletedAt = e.clock.Now()
+	exec.Status = status
+
+	if err := e.appendJournal(&JournalEntry{
+		Type:         JournalExecutionFinished,
+		ExecutionID:  exec.ID,
+		Status:       status,
+		ErrorMessage: message,
+		Time:         result.CompletedAt,
+	}); err != nil {
+		e.logger.Errorf("执行 %s 写入结束记录失败: %v", exec.ID, err)
+	}
+
+	if status == ExecutionStatusCompleted {
+		e.logger.Infof("套利执行完成: %s, 收益: %.2f USDT", result.Symbol, result.ActualProfit)
+	} else {
+		e.logger.Errorf("套利执行失败: %s, %s", result.Symbol, message)
+	}
+}
+
+// appendJournal 写入执行日志（未设置日志时忽略）
+func (e *DefaultConcurrentExecutor) appendJournal(entry *JournalEntry) error {
+	e.mu.RLock()
+	journal := e.journal
+	e.mu.RUnlock()
+
+	if journal == nil {
+		return nil
+	}
+	return journal.Append(entry)
+}
+
+// amountPlaces 下单数量保留的小数位数
+const amountPlaces = 8
+
+// positionTolerance 两边成交数量差不超过此值时视为已对冲
+var positionTolerance = decimal.RequireFromString("0.00000001")
+
+// maxUnwindAttempts 最多对冲次数
+const maxUnwindAttempts = 3
+
+// failedOrder 构建未到达交易所的失败订单
+func (e *DefaultConcurrentExecutor) failedOrder(req *PlaceOrderRequest, message string) *Order {
+	now := e.clock.Now()
+	return &Order{
+		Exchange:      req.Exchange,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		Price:         req.Price,
+		Amount:        req.Amount,
+		ClientOrderID: req.ClientOrderID,
+		Status:        OrderStatusFailed,
+		ErrorMessage:  message,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// unresolvedLeg 返回第一个状态未确认或未结束的订单腿
+func unresolvedLeg(exec *JournaledExecution) *JournaledLeg {
+	for _, leg := range exec.Legs {
+		if leg.Order == nil || !IsFinalStatus(leg.Order.Status) {
+			return leg
+		}
+	}
+	return nil
+}
+
+// netPosition 计算执行产生的净持仓（买入成交数量 - 卖出成交数量）
+func netPosition(exec *JournaledExecution) decimal.Decimal {
+	net := decimal.Zero
+	for _, leg := range exec.Legs {
+		if leg.Order == nil {
+			continue
+		}
+		if leg.Request.Side == OrderSideBuy {
+			net = net.Add(leg.Order.FilledAmount)
+		} else {
+			net = net.Sub(leg.Order.FilledAmount)
+		}
+	}
+	return net
+}
+
+// legOrder 获取订单腿的订单
+func legOrder(exec *JournaledExecution, name string) *Order {
+	if leg := exec.Leg(name); leg != nil {
+		return leg.Order
+	}
+	return nil
+}
+
+// legFilled 获取订单腿的成交数量
+func legFilled(exec *JournaledExecution, name string) decimal.Decimal {
+	if order := legOrder(exec, name); order != nil {
+		return order.FilledAmount
+	}
+	return decimal.Zero
+}
+
+// realizedProfit 计算实际收益（卖出金额 - 买入金额 - 手续费，包含对冲单）
+// 以基础货币收取的手续费按成交均价折算，其他币种（如 BNB）按原值计入
+func realizedProfit(exec *JournaledExecution) decimal.Decimal {
+	profit := decimal.Zero
+	for _, leg := range exec.Legs {
+		order := leg.Order
+		if order == nil || !order.FilledAmount.IsPositive() {
+			continue
+		}
+
+		quote := order.FilledAmount.Mul(order.AveragePrice)
+		if leg.Request.Side == OrderSideBuy {
+			profit = profit.Sub(quote)
+		} else {
+			profit = profit.Add(quote)
+		}
+
+		fee := order.Fee
+		if base := strings.Split(leg.Request.Symbol, "/")[0]; order.FeeCurrency == base {
+			fee = fee.Mul(order.AveragePrice)
+		}
+		profit = profit.Sub(fee)
+	}
+	return profit
 }
 
 // updateStats 更新统计数据
@@ -375,15 +1006,30 @@ func (e *DefaultConcurrentExecutor) updateStats(result *ExecutionResult) {
 
 	if result.Status == ExecutionStatusCompleted {
 		e.stats.TotalSuccess++
-		e.stats.TotalProfit += result.ActualProfit
+		e.stats.TotalProfit = e.stats.TotalProfit.Add(result.ActualProfit)
 	} else {
 		e.stats.TotalFailed++
 	}
 }
 
+// notifyResult 调用执行结果回调
+func (e *DefaultConcurrentExecutor) notifyResult(result *ExecutionResult) {
+	e.mu.RLock()
+	hooks := e.resultHooks
+	e.mu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(result)
+	}
+}
+
+// executionSeq 执行 ID 序号（同一纳秒内生成的 ID 也不重复）
+var executionSeq atomic.Uint64
+
 // generateID 生成唯一 ID
+// 执行日志按执行 ID 派生客户端订单ID，ID 重复会让恢复时匹配到其他执行的订单
 func generateID() string {
-	return fmt.Sprintf("exec-%d", time.Now().UnixNano())
+	return fmt.Sprintf("exec-%d-%d", time.Now().UnixNano(), executionSeq.Add(1))
 }
 
 // ExecutionTask 执行任务
@@ -395,11 +1041,22 @@ type ExecutionTask struct {
 	Opportunity *ArbitrageOpportunity
 
 	// Amount 交易金额
-	Amount float64
+	Amount decimal.Decimal
 
 	// ResultChan 结果通道
 	ResultChan chan *ExecutionResult
 
 	// CreatedAt 创建时间
 	CreatedAt time.Time
+
+	parent    trace.SpanContext // 提交执行时的 span（执行 span 的父 span）
+	queueSpan trace.Span        // 在队列中等待的 span（开始执行时结束）
+}
+
+// opportunityID 执行结果关联的套利机会 ID（机会没有 ID 时使用任务 ID）
+func (t *ExecutionTask) opportunityID() string {
+	if t.Opportunity != nil && t.Opportunity.ID != "" {
+		return t.Opportunity.ID
+	}
+	return t.ID
 }
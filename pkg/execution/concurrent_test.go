@@ -6,6 +6,9 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"arbitragex/common/clock"
+	"arbitragex/common/decimal"
 )
 
 // TestWorkerPool_ConstantValues 测试常量值
@@ -238,7 +241,7 @@ func TestTaskQueue_EnqueueDequeue(t *testing.T) {
 			SellExchange: "okx",
 			ProfitRate:   0.02,
 		},
-		Amount:    1000,
+		Amount:    decimal.NewFromFloat(1000),
 		ResultChan: make(chan *ExecutionResult, 1),
 		CreatedAt: time.Now(),
 	}
@@ -577,13 +580,52 @@ func TestTaskQueue_RemoveExpiredTasks(t *testing.T) {
 	}
 }
 
+// TestTaskQueue_Clock 测试按注入的时钟判断任务过期
+func TestTaskQueue_Clock(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(t0)
+
+	queue := NewTaskQueue(10)
+	queue.SetClock(clk)
+	queue.Enqueue(&ExecutionTask{
+		ID:          "task-1",
+		Opportunity: &ArbitrageOpportunity{Symbol: "BTC/USDT", ProfitRate: 0.02},
+		CreatedAt:   t0,
+		ResultChan:  make(chan *ExecutionResult, 1),
+	})
+
+	if expired := queue.GetExpiredTasks(time.Minute); len(expired) != 0 {
+		t.Errorf("过期任务数量 = %v, want 0", len(expired))
+	}
+
+	clk.Advance(2 * time.Minute)
+	if expired := queue.GetExpiredTasks(time.Minute); len(expired) != 1 {
+		t.Errorf("推进时钟后过期任务数量 = %v, want 1", len(expired))
+	}
+	if removed := queue.RemoveExpiredTasks(time.Minute); removed != 1 {
+		t.Errorf("移除数量 = %v, want 1", removed)
+	}
+}
+
 // TestConcurrentExecutorInterface 测试并发执行器接口
 func TestConcurrentExecutorInterface(t *testing.T) {
 	// 测试 DefaultConcurrentExecutor 实现了 ConcurrentExecutor 接口
 	executors := map[string]OrderExecutor{
-		"binance": NewBinanceExecutor("test-key", "test-secret", ""),
-		"okx":     NewOKXExecutor("test-key", "test-secret", "passphrase", ""),
+		"binance": NewBinanceExecutor("test-key", []byte("test-secret"), ""),
+		"okx":     NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", ""),
 	}
 
 	var _ ConcurrentExecutor = NewDefaultConcurrentExecutor(5, executors)
 }
+
+// TestGenerateID 测试同一时刻生成的执行 ID 不重复（客户端订单ID由执行 ID 派生）
+func TestGenerateID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := generateID()
+		if seen[id] {
+			t.Fatalf("generateID() returned duplicate %s", id)
+		}
+		seen[id] = true
+	}
+}
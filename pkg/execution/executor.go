@@ -3,7 +3,10 @@ package execution
 
 import (
 	"context"
+	"fmt"
 	"time"
+
+	"arbitragex/common/decimal"
 )
 
 // OrderExecutor 订单执行器接口
@@ -48,6 +51,23 @@ type OrderExecutor interface {
 	GetOrderBook(ctx context.Context, exchange, symbol string) (*OrderBook, error)
 }
 
+// ErrOrderNotFound 交易所上不存在该订单（按客户端订单ID查询时表示下单请求没有被受理）
+var ErrOrderNotFound = fmt.Errorf("order not found")
+
+// ClientOrderQuerier 支持按客户端订单ID查询订单的执行器
+// 下单请求超时或进程崩溃后，用来确认订单是否已经到达交易所
+type ClientOrderQuerier interface {
+	// QueryOrderByClientID 按客户端订单ID查询订单
+	// 参数:
+	//   - ctx: 上下文对象
+	//   - symbol: 交易对（如 BTC/USDT）
+	//   - clientOrderID: 下单时使用的客户端订单ID
+	// 返回:
+	//   - *Order: 订单信息
+	//   - error: 订单不存在时返回 ErrOrderNotFound
+	QueryOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*Order, error)
+}
+
 // PlaceOrderRequest 下单请求
 type PlaceOrderRequest struct {
 	// Exchange 交易所名称（binance, okx）
@@ -63,10 +83,25 @@ type PlaceOrderRequest struct {
 	Type string `json:"type"`
 
 	// Price 价格（限价单必需，市价单可选）
-	Price float64 `json:"price,omitempty"`
+	Price decimal.Decimal `json:"price,omitzero"`
 
 	// Amount 数量（单位为基础货币，如 BTC）
-	Amount float64 `json:"amount"`
+	Amount decimal.Decimal `json:"amount"`
+
+	// QuoteAmount 按计价货币下单的金额（如 USDT，仅市价单，与 Amount 二选一）
+	QuoteAmount decimal.Decimal `json:"quote_amount,omitzero"`
+
+	// TimeInForce 有效方式（gtc, ioc, fok；仅限价单，默认 gtc）
+	TimeInForce string `json:"time_in_force,omitempty"`
+
+	// PostOnly 只做 Maker（仅限价单，会立即成交时交易所拒绝或撤销订单）
+	PostOnly bool `json:"post_only,omitempty"`
+
+	// TradeMode 交易模式（为空或 cash 为现货；cross、isolated 为全仓、逐仓杠杆，仅 OKX 支持）
+	TradeMode string `json:"trade_mode,omitempty"`
+
+	// ReduceOnly 只减仓（仅杠杆交易模式支持）
+	ReduceOnly bool `json:"reduce_only,omitempty"`
 
 	// ClientOrderID 客户端订单ID（可选，用于幂等性）
 	ClientOrderID string `json:"client_order_id,omitempty"`
@@ -89,20 +124,26 @@ type Order struct {
 	// Type 订单类型（limit, market）
 	Type string `json:"type"`
 
+	// TimeInForce 有效方式（gtc, ioc, fok；市价单为空）
+	TimeInForce string `json:"time_in_force,omitempty"`
+
+	// PostOnly 是否只做 Maker
+	PostOnly bool `json:"post_only,omitempty"`
+
 	// Price 价格
-	Price float64 `json:"price"`
+	Price decimal.Decimal `json:"price"`
 
 	// Amount 订单数量
-	Amount float64 `json:"amount"`
+	Amount decimal.Decimal `json:"amount"`
 
 	// FilledAmount 已成交数量
-	FilledAmount float64 `json:"filled_amount"`
+	FilledAmount decimal.Decimal `json:"filled_amount"`
 
 	// AveragePrice 平均成交价格
-	AveragePrice float64 `json:"average_price"`
+	AveragePrice decimal.Decimal `json:"average_price"`
 
 	// Fee 手续费
-	Fee float64 `json:"fee"`
+	Fee decimal.Decimal `json:"fee"`
 
 	// FeeCurrency 手续费币种
 	FeeCurrency string `json:"fee_currency"`
@@ -119,6 +160,9 @@ type Order struct {
 	// ErrorMessage 错误信息（如果订单失败）
 	ErrorMessage string `json:"error_message,omitempty"`
 
+	// StatusTimes 进入各状态的时间（由订单状态机记录）
+	StatusTimes map[string]time.Time `json:"status_times,omitempty"`
+
 	// CreatedAt 创建时间
 	CreatedAt time.Time `json:"created_at"`
 
@@ -147,10 +191,10 @@ type OrderBook struct {
 // OrderBookLevel 订单簿深度级别
 type OrderBookLevel struct {
 	// Price 价格
-	Price float64 `json:"price"`
+	Price decimal.Decimal `json:"price"`
 
 	// Amount 数量
-	Amount float64 `json:"amount"`
+	Amount decimal.Decimal `json:"amount"`
 }
 
 // 订单状态常量
@@ -174,3 +218,72 @@ const (
 	OrderTypeLimit  = "limit"  // 限价单
 	OrderTypeMarket = "market" // 市价单
 )
+
+// 有效方式常量
+const (
+	TimeInForceGTC = "gtc" // 一直有效直到撤销
+	TimeInForceIOC = "ioc" // 立即成交，未成交部分撤销
+	TimeInForceFOK = "fok" // 全部成交，否则全部撤销
+)
+
+// 交易模式常量
+const (
+	TradeModeCash     = "cash"     // 现货
+	TradeModeCross    = "cross"    // 全仓杠杆
+	TradeModeIsolated = "isolated" // 逐仓杠杆
+)
+
+// validateSpotOrder 校验只支持现货交易的执行器的下单参数
+func validateSpotOrder(exchange string, req *PlaceOrderRequest) error {
+	if req.TradeMode != "" && req.TradeMode != TradeModeCash {
+		return fmt.Errorf("%s 执行器只支持现货交易，不支持交易模式 %s", exchange, req.TradeMode)
+	}
+	return validateOrderOptions(req)
+}
+
+// validateOrderOptions 校验数量、有效方式、只做 Maker、交易模式等与交易所无关的下单参数
+func validateOrderOptions(req *PlaceOrderRequest) error {
+	switch req.TradeMode {
+	case "", TradeModeCash:
+		if req.ReduceOnly {
+			return fmt.Errorf("现货交易不支持 reduce-only")
+		}
+	case TradeModeCross, TradeModeIsolated:
+	default:
+		return fmt.Errorf("无效的交易模式: %s", req.TradeMode)
+	}
+
+	switch req.Type {
+	case OrderTypeLimit:
+		if req.QuoteAmount.IsPositive() {
+			return fmt.Errorf("按金额下单只支持市价单")
+		}
+		switch req.TimeInForce {
+		case "", TimeInForceGTC:
+		case TimeInForceIOC, TimeInForceFOK:
+			if req.PostOnly {
+				return fmt.Errorf("只做 Maker 订单不能与 %s 同时使用", req.TimeInForce)
+			}
+		default:
+			return fmt.Errorf("无效的有效方式: %s", req.TimeInForce)
+		}
+	case OrderTypeMarket:
+		if req.TimeInForce != "" {
+			return fmt.Errorf("市价单不支持设置有效方式")
+		}
+		if req.PostOnly {
+			return fmt.Errorf("市价单不支持只做 Maker")
+		}
+		if req.QuoteAmount.IsPositive() {
+			if req.Amount.IsPositive() {
+				return fmt.Errorf("数量和金额只能设置一个")
+			}
+			return nil
+		}
+	}
+
+	if !req.Amount.IsPositive() {
+		return fmt.Errorf("数量必须大于 0")
+	}
+	return nil
+}
@@ -3,8 +3,12 @@ package execution
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
+
+	"arbitragex/common/decimal"
 )
 
 // TestBinanceExecutor_ConstantValues 测试 Binance 执行器的常量值
@@ -37,7 +41,7 @@ func TestBinanceExecutor_ConstantValues(t *testing.T) {
 
 // TestBinanceExecutor_SymbolConversion 测试 Binance 交易对格式转换
 func TestBinanceExecutor_SymbolConversion(t *testing.T) {
-	executor := NewBinanceExecutor("test-key", "test-secret", "")
+	executor := NewBinanceExecutor("test-key", []byte("test-secret"), "")
 
 	tests := []struct {
 		name     string
@@ -90,7 +94,7 @@ func TestBinanceExecutor_SymbolConversion(t *testing.T) {
 
 // TestBinanceExecutor_ValidatePlaceOrderRequest 测试 Binance 下单请求校验
 func TestBinanceExecutor_ValidatePlaceOrderRequest(t *testing.T) {
-	executor := NewBinanceExecutor("test-key", "test-secret", "")
+	executor := NewBinanceExecutor("test-key", []byte("test-secret"), "")
 
 	tests := []struct {
 		name    string
@@ -111,8 +115,8 @@ func TestBinanceExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideBuy,
 				Type:     OrderTypeLimit,
-				Price:    43000,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: true,
 			errMsg:  "交易所不匹配",
@@ -124,8 +128,8 @@ func TestBinanceExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "",
 				Side:     OrderSideBuy,
 				Type:     OrderTypeLimit,
-				Price:    43000,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: true,
 			errMsg:  "交易对不能为空",
@@ -137,8 +141,8 @@ func TestBinanceExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     "invalid",
 				Type:     OrderTypeLimit,
-				Price:    43000,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: true,
 			errMsg:  "无效的订单方向",
@@ -150,8 +154,8 @@ func TestBinanceExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideBuy,
 				Type:     "invalid",
-				Price:    43000,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: true,
 			errMsg:  "无效的订单类型",
@@ -163,8 +167,8 @@ func TestBinanceExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideBuy,
 				Type:     OrderTypeLimit,
-				Price:    0,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(0),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: true,
 			errMsg:  "限价单价格必须大于 0",
@@ -176,8 +180,8 @@ func TestBinanceExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideBuy,
 				Type:     OrderTypeLimit,
-				Price:    43000,
-				Amount:   0,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0),
 			},
 			wantErr: true,
 			errMsg:  "数量必须大于 0",
@@ -189,8 +193,8 @@ func TestBinanceExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideBuy,
 				Type:     OrderTypeLimit,
-				Price:    43000,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: false,
 		},
@@ -201,7 +205,7 @@ func TestBinanceExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideSell,
 				Type:     OrderTypeMarket,
-				Amount:   0.1,
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: false,
 		},
@@ -225,7 +229,7 @@ func TestBinanceExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 
 // TestOKXExecutor_SymbolConversion 测试 OKX 交易对格式转换
 func TestOKXExecutor_SymbolConversion(t *testing.T) {
-	executor := NewOKXExecutor("test-key", "test-secret", "test-passphrase", "")
+	executor := NewOKXExecutor("test-key", []byte("test-secret"), "test-passphrase", "")
 
 	tests := []struct {
 		name    string
@@ -278,7 +282,7 @@ func TestOKXExecutor_SymbolConversion(t *testing.T) {
 
 // TestOKXExecutor_ValidatePlaceOrderRequest 测试 OKX 下单请求校验
 func TestOKXExecutor_ValidatePlaceOrderRequest(t *testing.T) {
-	executor := NewOKXExecutor("test-key", "test-secret", "test-passphrase", "")
+	executor := NewOKXExecutor("test-key", []byte("test-secret"), "test-passphrase", "")
 
 	tests := []struct {
 		name    string
@@ -299,8 +303,8 @@ func TestOKXExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideBuy,
 				Type:     OrderTypeLimit,
-				Price:    43000,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: true,
 			errMsg:  "交易所不匹配",
@@ -312,8 +316,8 @@ func TestOKXExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "",
 				Side:     OrderSideBuy,
 				Type:     OrderTypeLimit,
-				Price:    43000,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: true,
 			errMsg:  "交易对不能为空",
@@ -325,8 +329,8 @@ func TestOKXExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     "invalid",
 				Type:     OrderTypeLimit,
-				Price:    43000,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: true,
 			errMsg:  "无效的订单方向",
@@ -338,8 +342,8 @@ func TestOKXExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideBuy,
 				Type:     "invalid",
-				Price:    43000,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: true,
 			errMsg:  "无效的订单类型",
@@ -351,8 +355,8 @@ func TestOKXExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideBuy,
 				Type:     OrderTypeLimit,
-				Price:    0,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(0),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: true,
 			errMsg:  "限价单价格必须大于 0",
@@ -364,8 +368,8 @@ func TestOKXExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideBuy,
 				Type:     OrderTypeLimit,
-				Price:    43000,
-				Amount:   0,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0),
 			},
 			wantErr: true,
 			errMsg:  "数量必须大于 0",
@@ -377,8 +381,8 @@ func TestOKXExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideBuy,
 				Type:     OrderTypeLimit,
-				Price:    43000,
-				Amount:   0.1,
+				Price:    decimal.NewFromFloat(43000),
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: false,
 		},
@@ -389,7 +393,7 @@ func TestOKXExecutor_ValidatePlaceOrderRequest(t *testing.T) {
 				Symbol:   "BTC/USDT",
 				Side:     OrderSideSell,
 				Type:     OrderTypeMarket,
-				Amount:   0.1,
+				Amount:   decimal.NewFromFloat(0.1),
 			},
 			wantErr: false,
 		},
@@ -463,8 +467,8 @@ func TestOrderDataStructures(t *testing.T) {
 			Symbol:        "BTC/USDT",
 			Side:          OrderSideBuy,
 			Type:          OrderTypeLimit,
-			Price:         43000.0,
-			Amount:        0.1,
+			Price:         decimal.NewFromFloat(43000.0),
+			Amount:        decimal.NewFromFloat(0.1),
 			ClientOrderID: "client-123",
 		}
 
@@ -474,7 +478,7 @@ func TestOrderDataStructures(t *testing.T) {
 		if req.Symbol != "BTC/USDT" {
 			t.Errorf("Symbol = %v, want BTC/USDT", req.Symbol)
 		}
-		if req.Price != 43000.0 {
+		if !req.Price.Equal(decimal.NewFromFloat(43000.0)) {
 			t.Errorf("Price = %v, want 43000.0", req.Price)
 		}
 	})
@@ -486,11 +490,11 @@ func TestOrderDataStructures(t *testing.T) {
 			Symbol:          "BTC/USDT",
 			Side:            OrderSideBuy,
 			Type:            OrderTypeLimit,
-			Price:           43000.0,
-			Amount:          0.1,
-			FilledAmount:    0.05,
-			AveragePrice:    43010.0,
-			Fee:             4.3,
+			Price:           decimal.NewFromFloat(43000.0),
+			Amount:          decimal.NewFromFloat(0.1),
+			FilledAmount:    decimal.NewFromFloat(0.05),
+			AveragePrice:    decimal.NewFromFloat(43010.0),
+			Fee:             decimal.NewFromFloat(4.3),
 			FeeCurrency:     "USDT",
 			Status:          OrderStatusPartiallyFilled,
 			ExchangeOrderID: "123456",
@@ -505,7 +509,7 @@ func TestOrderDataStructures(t *testing.T) {
 		if order.Status != OrderStatusPartiallyFilled {
 			t.Errorf("Status = %v, want partially_filled", order.Status)
 		}
-		if order.FilledAmount != 0.05 {
+		if !order.FilledAmount.Equal(decimal.NewFromFloat(0.05)) {
 			t.Errorf("FilledAmount = %v, want 0.05", order.FilledAmount)
 		}
 	})
@@ -514,8 +518,8 @@ func TestOrderDataStructures(t *testing.T) {
 		orderBook := &OrderBook{
 			Exchange:  "binance",
 			Symbol:    "BTC/USDT",
-			Bids:      []OrderBookLevel{{Price: 43000.0, Amount: 1.0}},
-			Asks:      []OrderBookLevel{{Price: 43100.0, Amount: 1.0}},
+			Bids:      []OrderBookLevel{{Price: decimal.NewFromFloat(43000.0), Amount: decimal.NewFromFloat(1.0)}},
+			Asks:      []OrderBookLevel{{Price: decimal.NewFromFloat(43100.0), Amount: decimal.NewFromFloat(1.0)}},
 			Timestamp: time.Now(),
 		}
 
@@ -532,14 +536,14 @@ func TestOrderDataStructures(t *testing.T) {
 
 	t.Run("OrderBookLevel 结构体", func(t *testing.T) {
 		level := OrderBookLevel{
-			Price:  43000.0,
-			Amount: 1.0,
+			Price:  decimal.NewFromFloat(43000.0),
+			Amount: decimal.NewFromFloat(1.0),
 		}
 
-		if level.Price != 43000.0 {
+		if !level.Price.Equal(decimal.NewFromFloat(43000.0)) {
 			t.Errorf("Price = %v, want 43000.0", level.Price)
 		}
-		if level.Amount != 1.0 {
+		if !level.Amount.Equal(decimal.NewFromFloat(1.0)) {
 			t.Errorf("Amount = %v, want 1.0", level.Amount)
 		}
 	})
@@ -548,11 +552,11 @@ func TestOrderDataStructures(t *testing.T) {
 // TestOrderExecutorInterface 测试 OrderExecutor 接口实现
 func TestOrderExecutorInterface(t *testing.T) {
 	t.Run("BinanceExecutor 实现了 OrderExecutor 接口", func(t *testing.T) {
-		var _ OrderExecutor = NewBinanceExecutor("test-key", "test-secret", "")
+		var _ OrderExecutor = NewBinanceExecutor("test-key", []byte("test-secret"), "")
 	})
 
 	t.Run("OKXExecutor 实现了 OrderExecutor 接口", func(t *testing.T) {
-		var _ OrderExecutor = NewOKXExecutor("test-key", "test-secret", "test-passphrase", "")
+		var _ OrderExecutor = NewOKXExecutor("test-key", []byte("test-secret"), "test-passphrase", "")
 	})
 }
 
@@ -563,15 +567,15 @@ func TestBinanceExecutor_PlaceOrder_NoAPIKey(t *testing.T) {
 		t.Skip("跳过集成测试")
 	}
 
-	executor := NewBinanceExecutor("", "", "")
+	executor := NewBinanceExecutor("", nil, "")
 
 	req := &PlaceOrderRequest{
 		Exchange: "binance",
 		Symbol:   "BTC/USDT",
 		Side:     OrderSideBuy,
 		Type:     OrderTypeLimit,
-		Price:    43000.0,
-		Amount:   0.1,
+		Price:    decimal.NewFromFloat(43000.0),
+		Amount:   decimal.NewFromFloat(0.1),
 	}
 
 	ctx := context.Background()
@@ -590,15 +594,15 @@ func TestOKXExecutor_PlaceOrder_NoAPIKey(t *testing.T) {
 		t.Skip("跳过集成测试")
 	}
 
-	executor := NewOKXExecutor("", "", "", "")
+	executor := NewOKXExecutor("", nil, "", "")
 
 	req := &PlaceOrderRequest{
 		Exchange: "okx",
 		Symbol:   "BTC/USDT",
 		Side:     OrderSideBuy,
 		Type:     OrderTypeLimit,
-		Price:    43000.0,
-		Amount:   0.1,
+		Price:    decimal.NewFromFloat(43000.0),
+		Amount:   decimal.NewFromFloat(0.1),
 	}
 
 	ctx := context.Background()
@@ -610,6 +614,246 @@ func TestOKXExecutor_PlaceOrder_NoAPIKey(t *testing.T) {
 	}
 }
 
+// TestValidateOrderOptions 测试有效方式、只做 Maker、按金额下单等参数校验
+func TestValidateOrderOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *PlaceOrderRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "IOC 限价单",
+			req:     &PlaceOrderRequest{Type: OrderTypeLimit, Price: decimal.NewFromFloat(100), Amount: decimal.NewFromFloat(1), TimeInForce: TimeInForceIOC},
+			wantErr: false,
+		},
+		{
+			name:    "只做 Maker 限价单",
+			req:     &PlaceOrderRequest{Type: OrderTypeLimit, Price: decimal.NewFromFloat(100), Amount: decimal.NewFromFloat(1), PostOnly: true},
+			wantErr: false,
+		},
+		{
+			name:    "只做 Maker 不能与 FOK 同时使用",
+			req:     &PlaceOrderRequest{Type: OrderTypeLimit, Price: decimal.NewFromFloat(100), Amount: decimal.NewFromFloat(1), PostOnly: true, TimeInForce: TimeInForceFOK},
+			wantErr: true,
+			errMsg:  "只做 Maker",
+		},
+		{
+			name:    "无效的有效方式",
+			req:     &PlaceOrderRequest{Type: OrderTypeLimit, Price: decimal.NewFromFloat(100), Amount: decimal.NewFromFloat(1), TimeInForce: "day"},
+			wantErr: true,
+			errMsg:  "无效的有效方式",
+		},
+		{
+			name:    "市价单不支持有效方式",
+			req:     &PlaceOrderRequest{Type: OrderTypeMarket, Amount: decimal.NewFromFloat(1), TimeInForce: TimeInForceIOC},
+			wantErr: true,
+			errMsg:  "市价单不支持设置有效方式",
+		},
+		{
+			name:    "按金额市价单",
+			req:     &PlaceOrderRequest{Type: OrderTypeMarket, QuoteAmount: decimal.NewFromFloat(100)},
+			wantErr: false,
+		},
+		{
+			name:    "数量和金额只能设置一个",
+			req:     &PlaceOrderRequest{Type: OrderTypeMarket, Amount: decimal.NewFromFloat(1), QuoteAmount: decimal.NewFromFloat(100)},
+			wantErr: true,
+			errMsg:  "数量和金额只能设置一个",
+		},
+		{
+			name:    "限价单不支持按金额下单",
+			req:     &PlaceOrderRequest{Type: OrderTypeLimit, Price: decimal.NewFromFloat(100), QuoteAmount: decimal.NewFromFloat(100)},
+			wantErr: true,
+			errMsg:  "按金额下单只支持市价单",
+		},
+		{
+			name:    "现货不支持 reduce-only",
+			req:     &PlaceOrderRequest{Type: OrderTypeMarket, Amount: decimal.NewFromFloat(1), ReduceOnly: true},
+			wantErr: true,
+			errMsg:  "reduce-only",
+		},
+		{
+			name:    "杠杆 reduce-only",
+			req:     &PlaceOrderRequest{Type: OrderTypeMarket, Amount: decimal.NewFromFloat(1), TradeMode: TradeModeCross, ReduceOnly: true},
+			wantErr: false,
+		},
+		{
+			name:    "无效的交易模式",
+			req:     &PlaceOrderRequest{Type: OrderTypeMarket, Amount: decimal.NewFromFloat(1), TradeMode: "futures"},
+			wantErr: true,
+			errMsg:  "无效的交易模式",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOrderOptions(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateOrderOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !containsString(err.Error(), tt.errMsg) {
+				t.Errorf("错误消息应该包含 %v, got = %v", tt.errMsg, err.Error())
+			}
+		})
+	}
+}
+
+// TestSpotExecutors_RejectMarginOrders 测试只支持现货的执行器拒绝杠杆交易模式和只减仓
+func TestSpotExecutors_RejectMarginOrders(t *testing.T) {
+	binance := NewBinanceExecutor("test-key", []byte("test-secret"), "")
+	paper := NewPaperExecutor("binance", 0.001, 0.001)
+	okx := NewOKXExecutor("test-key", []byte("test-secret"), "test-passphrase", "")
+
+	req := &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideSell, Type: OrderTypeMarket, Amount: decimal.NewFromFloat(0.1), TradeMode: TradeModeCross, ReduceOnly: true}
+
+	req.Exchange = "binance"
+	if err := binance.validatePlaceOrderRequest(req); err == nil || !containsString(err.Error(), "只支持现货交易") {
+		t.Errorf("binance validatePlaceOrderRequest() error = %v, want spot only", err)
+	}
+	if err := paper.validatePlaceOrderRequest(req); err == nil || !containsString(err.Error(), "只支持现货交易") {
+		t.Errorf("paper validatePlaceOrderRequest() error = %v, want spot only", err)
+	}
+
+	req.Exchange = "okx"
+	if err := okx.validatePlaceOrderRequest(req); err != nil {
+		t.Errorf("okx validatePlaceOrderRequest() error = %v, want nil", err)
+	}
+}
+
+// TestBinanceExecutor_BuildOrderParams 测试 Binance 下单参数映射
+func TestBinanceExecutor_BuildOrderParams(t *testing.T) {
+	executor := NewBinanceExecutor("test-key", []byte("test-secret"), "")
+
+	tests := []struct {
+		name string
+		req  *PlaceOrderRequest
+		want map[string]string
+	}{
+		{
+			name: "默认 GTC",
+			req:  &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeLimit, Price: decimal.NewFromFloat(43000), Amount: decimal.NewFromFloat(0.1)},
+			want: map[string]string{"type": "LIMIT", "timeInForce": "GTC", "price": "43000", "quantity": "0.1"},
+		},
+		{
+			name: "IOC",
+			req:  &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeLimit, Price: decimal.NewFromFloat(43000), Amount: decimal.NewFromFloat(0.1), TimeInForce: TimeInForceIOC},
+			want: map[string]string{"type": "LIMIT", "timeInForce": "IOC"},
+		},
+		{
+			name: "只做 Maker",
+			req:  &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideSell, Type: OrderTypeLimit, Price: decimal.NewFromFloat(43000), Amount: decimal.NewFromFloat(0.1), PostOnly: true},
+			want: map[string]string{"type": "LIMIT_MAKER", "timeInForce": ""},
+		},
+		{
+			name: "按金额市价单",
+			req:  &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeMarket, QuoteAmount: decimal.NewFromFloat(100)},
+			want: map[string]string{"type": "MARKET", "quoteOrderQty": "100", "quantity": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := executor.buildOrderParams(tt.req)
+			for key, want := range tt.want {
+				if got := params.Get(key); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestOKXExecutor_BuildOrderParams 测试 OKX 下单参数映射
+func TestOKXExecutor_BuildOrderParams(t *testing.T) {
+	executor := NewOKXExecutor("test-key", []byte("test-secret"), "test-passphrase", "")
+
+	tests := []struct {
+		name string
+		req  *PlaceOrderRequest
+		want map[string]interface{}
+	}{
+		{
+			name: "默认 GTC",
+			req:  &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeLimit, Price: decimal.NewFromFloat(43000), Amount: decimal.NewFromFloat(0.1)},
+			want: map[string]interface{}{"ordType": "limit", "side": "buy", "px": "43000", "sz": "0.1"},
+		},
+		{
+			name: "FOK",
+			req:  &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeLimit, Price: decimal.NewFromFloat(43000), Amount: decimal.NewFromFloat(0.1), TimeInForce: TimeInForceFOK},
+			want: map[string]interface{}{"ordType": "fok"},
+		},
+		{
+			name: "只做 Maker",
+			req:  &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideSell, Type: OrderTypeLimit, Price: decimal.NewFromFloat(43000), Amount: decimal.NewFromFloat(0.1), PostOnly: true},
+			want: map[string]interface{}{"ordType": "post_only", "side": "sell"},
+		},
+		{
+			name: "按数量市价单",
+			req:  &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeMarket, Amount: decimal.NewFromFloat(0.1)},
+			want: map[string]interface{}{"ordType": "market", "tgtCcy": "base_ccy", "sz": "0.1"},
+		},
+		{
+			name: "按金额市价单",
+			req:  &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeMarket, QuoteAmount: decimal.NewFromFloat(100)},
+			want: map[string]interface{}{"ordType": "market", "tgtCcy": "quote_ccy", "sz": "100"},
+		},
+		{
+			name: "现货",
+			req:  &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeLimit, Price: decimal.NewFromFloat(43000), Amount: decimal.NewFromFloat(0.1)},
+			want: map[string]interface{}{"tdMode": "cash", "reduceOnly": nil},
+		},
+		{
+			name: "逐仓杠杆只减仓",
+			req:  &PlaceOrderRequest{Symbol: "BTC/USDT", Side: OrderSideSell, Type: OrderTypeLimit, Price: decimal.NewFromFloat(43000), Amount: decimal.NewFromFloat(0.1), TradeMode: TradeModeIsolated, ReduceOnly: true},
+			want: map[string]interface{}{"tdMode": "isolated", "reduceOnly": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := executor.buildOrderParams(tt.req)
+			for key, want := range tt.want {
+				if got := params[key]; got != want {
+					t.Errorf("%s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestParseOrderType 测试交易所订单类型还原为订单类型、有效方式和只做 Maker
+func TestParseOrderType(t *testing.T) {
+	binance := NewBinanceExecutor("test-key", []byte("test-secret"), "")
+	okx := NewOKXExecutor("test-key", []byte("test-secret"), "test-passphrase", "")
+
+	orderType, timeInForce, postOnly := binance.parseOrderType("LIMIT_MAKER", "")
+	if orderType != OrderTypeLimit || timeInForce != TimeInForceGTC || !postOnly {
+		t.Errorf("binance LIMIT_MAKER = %s, %s, %v", orderType, timeInForce, postOnly)
+	}
+
+	orderType, timeInForce, postOnly = binance.parseOrderType("LIMIT", "IOC")
+	if orderType != OrderTypeLimit || timeInForce != TimeInForceIOC || postOnly {
+		t.Errorf("binance LIMIT IOC = %s, %s, %v", orderType, timeInForce, postOnly)
+	}
+
+	orderType, timeInForce, postOnly = okx.parseOrderType("post_only")
+	if orderType != OrderTypeLimit || !postOnly {
+		t.Errorf("okx post_only = %s, %s, %v", orderType, timeInForce, postOnly)
+	}
+
+	orderType, timeInForce, _ = okx.parseOrderType("fok")
+	if orderType != OrderTypeLimit || timeInForce != TimeInForceFOK {
+		t.Errorf("okx fok = %s, %s", orderType, timeInForce)
+	}
+
+	// IOC 未成交部分过期属于撤销而不是失败
+	if status := binance.parseOrderStatus(map[string]interface{}{"status": "EXPIRED"}); status != OrderStatusCanceled {
+		t.Errorf("binance EXPIRED = %s, want %s", status, OrderStatusCanceled)
+	}
+}
+
 // containsString 检查字符串是否包含子字符串
 func containsString(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 || (len(s) > 0 && len(substr) > 0 && findSubstring(s, substr)))
@@ -624,3 +868,34 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+// TestBinanceExecutor_CloseWipesSecret 测试执行器保存 API Secret 副本，Close 时清零
+func TestBinanceExecutor_CloseWipesSecret(t *testing.T) {
+	secret := []byte("test-secret")
+	executor := NewBinanceExecutor("test-key", secret, "")
+	want := NewBinanceExecutor("test-key", []byte("test-secret"), "").generateSignature("symbol=BTCUSDT")
+
+	// 调用方清零自己的副本不影响签名
+	clear(secret)
+	if got := executor.generateSignature("symbol=BTCUSDT"); got != want {
+		t.Errorf("generateSignature() after caller wipe = %s, want %s", got, want)
+	}
+
+	if err := executor.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if executor.secret.key != nil {
+		t.Errorf("secret after Close() = %v, want wiped", executor.secret.key)
+	}
+}
+
+// TestPlaceOrderRequest_OmitZero 测试市价单请求序列化时省略零值的价格和报价金额
+func TestPlaceOrderRequest_OmitZero(t *testing.T) {
+	data, err := json.Marshal(&PlaceOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Amount: decimal.NewFromInt(1)})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), `"price"`) || strings.Contains(string(data), `"quote_amount"`) {
+		t.Errorf("json = %s, want price and quote_amount omitted", data)
+	}
+}
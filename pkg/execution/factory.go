@@ -0,0 +1,74 @@
+package execution
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Credential 交易所 API 密钥
+// APISecret 以 []byte 传入，执行器复制后只用于计算签名，Close 时清零
+type Credential struct {
+	APIKey     string
+	APISecret  []byte
+	Passphrase string // OKX 需要
+}
+
+// String 脱敏后的密钥（只显示是否已设置，避免日志中输出明文）
+func (c Credential) String() string {
+	return fmt.Sprintf("Credential{APIKey: %s, APISecret: %s, Passphrase: %s}",
+		redact(c.APIKey != ""), redact(len(c.APISecret) > 0), redact(c.Passphrase != ""))
+}
+
+// GoString 脱敏后的密钥（%#v）
+func (c Credential) GoString() string {
+	return c.String()
+}
+
+// redact 脱敏（只显示是否已设置）
+func redact(set bool) string {
+	if !set {
+		return `""`
+	}
+	return "******"
+}
+
+// NewExchangeExecutor 按交易所名称创建订单执行器
+// 参数:
+//   - exchange: 交易所名称（binance、okx）
+//   - credential: API 密钥（只查询公开订单簿时可以为空）
+//   - baseURL: REST API 基础 URL（为空时使用生产环境地址）
+// 返回:
+//   - OrderExecutor: 订单执行器
+//   - error: 不支持的交易所
+func NewExchangeExecutor(exchange string, credential Credential, baseURL string) (OrderExecutor, error) {
+	switch strings.ToLower(exchange) {
+	case "binance":
+		return NewBinanceExecutor(credential.APIKey, credential.APISecret, baseURL), nil
+	case "okx":
+		return NewOKXExecutor(credential.APIKey, credential.APISecret, credential.Passphrase, baseURL), nil
+	default:
+		return nil, fmt.Errorf("不支持的交易所: %s", exchange)
+	}
+}
+
+// NewExchangeWSExecutor 按交易所名称创建 WebSocket 下单执行器
+// 下单走 WebSocket（连接断开时回退到 REST），撤单、查询等其他操作走 REST；
+// 返回的执行器实现 Connector，需要先 Connect
+// 参数:
+//   - exchange: 交易所名称（binance、okx）
+//   - credential: API 密钥
+//   - baseURL: REST API 基础 URL（为空时使用生产环境地址）
+//   - wsURL: WebSocket 下单地址（为空时使用生产环境地址）
+// 返回:
+//   - OrderExecutor: 订单执行器
+//   - error: 不支持的交易所
+func NewExchangeWSExecutor(exchange string, credential Credential, baseURL, wsURL string) (OrderExecutor, error) {
+	switch strings.ToLower(exchange) {
+	case "binance":
+		return NewBinanceWSExecutor(NewBinanceExecutor(credential.APIKey, credential.APISecret, baseURL), wsURL), nil
+	case "okx":
+		return NewOKXWSExecutor(NewOKXExecutor(credential.APIKey, credential.APISecret, credential.Passphrase, baseURL), wsURL), nil
+	default:
+		return nil, fmt.Errorf("不支持的交易所: %s", exchange)
+	}
+}
@@ -0,0 +1,408 @@
+// Package execution 提供订单执行功能
+package execution
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"arbitragex/common/decimal"
+)
+
+// 日志记录类型
+const (
+	JournalExecutionStarted  = "execution_started"  // 开始执行套利
+	JournalOrderIntent       = "order_intent"       // 即将下单（在调用 PlaceOrder 之前写入）
+	JournalOrderUpdated      = "order_updated"      // 订单最新状态
+	JournalExecutionFinished = "execution_finished" // 执行结束（不再需要恢复）
+)
+
+// 套利执行中的订单腿
+const (
+	LegBuy    = "buy"    // 买入交易所下单
+	LegSell   = "sell"   // 卖出交易所下单
+	LegUnwind = "unwind" // 两边成交数量不一致时的对冲单（多次对冲时加序号后缀）
+)
+
+// JournalEntry 执行日志记录
+type JournalEntry struct {
+	// Type 记录类型
+	Type string `json:"type"`
+
+	// ExecutionID 执行ID
+	ExecutionID string `json:"execution_id"`
+
+	// Opportunity 套利机会（execution_started）
+	Opportunity *ArbitrageOpportunity `json:"opportunity,omitempty"`
+
+	// Amount 交易金额（USDT，execution_started）
+	Amount decimal.Decimal `json:"amount,omitzero"`
+
+	// Leg 订单腿（order_intent / order_updated）
+	Leg string `json:"leg,omitempty"`
+
+	// Request 下单请求（order_intent）
+	Request *PlaceOrderRequest `json:"request,omitempty"`
+
+	// Order 订单状态（order_updated）
+	Order *Order `json:"order,omitempty"`
+
+	// Status 执行状态（execution_finished）
+	Status string `json:"status,omitempty"`
+
+	// ErrorMessage 错误信息
+	ErrorMessage string `json:"error_message,omitempty"`
+
+	// Time 记录时间
+	Time time.Time `json:"time"`
+}
+
+// JournaledLeg 日志中的一条订单腿
+type JournaledLeg struct {
+	// Leg 订单腿
+	Leg string `json:"leg"`
+
+	// Request 下单请求（包含确定性的客户端订单ID）
+	Request *PlaceOrderRequest `json:"request"`
+
+	// Order 最近一次记录的订单状态（下单结果未知时为空）
+	Order *Order `json:"order,omitempty"`
+
+	// placedAt 本进程下单的时间（用于记录订单腿耗时，记录后清零；恢复的订单腿为空）
+	placedAt time.Time
+}
+
+// JournaledExecution 由日志重建的执行过程
+type JournaledExecution struct {
+	// ID 执行ID
+	ID string `json:"id"`
+
+	// Opportunity 套利机会
+	Opportunity *ArbitrageOpportunity `json:"opportunity"`
+
+	// Amount 交易金额（USDT）
+	Amount decimal.Decimal `json:"amount"`
+
+	// Legs 订单腿（按下单顺序）
+	Legs []*JournaledLeg `json:"legs"`
+
+	// Status 执行状态（未结束时为空）
+	Status string `json:"status,omitempty"`
+
+	// StartedAt 开始时间
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Leg 获取订单腿（不存在时返回 nil）
+func (j *JournaledExecution) Leg(leg string) *JournaledLeg {
+	for _, l := range j.Legs {
+		if l.Leg == leg {
+			return l
+		}
+	}
+	return nil
+}
+
+// apply 将一条日志记录应用到执行过程
+func (j *JournaledExecution) apply(entry *JournalEntry) {
+	switch entry.Type {
+	case JournalExecutionStarted:
+		j.Opportunity = entry.Opportunity
+		j.Amount = entry.Amount
+		j.StartedAt = entry.Time
+	case JournalOrderIntent:
+		if j.Leg(entry.Leg) == nil {
+			j.Legs = append(j.Legs, &JournaledLeg{Leg: entry.Leg, Request: entry.Request})
+		}
+	case JournalOrderUpdated:
+		if leg := j.Leg(entry.Leg); leg != nil {
+			leg.Order = entry.Order
+		}
+	case JournalExecutionFinished:
+		j.Status = entry.Status
+	}
+}
+
+// Journal 执行日志（预写日志）
+// 每次下单前先记录意图，进程崩溃重启后据此找回已发出的订单
+type Journal interface {
+	// Append 追加一条记录（返回前必须已持久化）
+	Append(entry *JournalEntry) error
+
+	// Pending 获取未结束的执行（按开始顺序）
+	Pending() ([]*JournaledExecution, error)
+
+	// Close 关闭日志
+	Close() error
+}
+
+// FileJournal 基于本地文件的执行日志
+// 每条记录一行 JSON，写入后立即 fsync；崩溃时写了一半的最后一行在读取时被忽略
+type FileJournal struct {
+	// 日志文件路径
+	path string
+
+	// 日志文件
+	file *os.File
+
+	mu sync.Mutex
+
+	// 日志记录器
+	logger logx.Logger
+}
+
+// NewFileJournal 打开（不存在时创建）执行日志文件
+// 参数:
+//   - path: 日志文件路径
+// 返回:
+//   - *FileJournal: 文件执行日志实例
+//   - error: 错误信息
+func NewFileJournal(path string) (*FileJournal, error) {
+	if path == "" {
+		return nil, fmt.Errorf("日志文件路径不能为空")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("创建日志目录失败: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("打开日志文件失败: %w", err)
+	}
+
+	// 上次崩溃时最后一行可能没写完，补上换行，避免新记录和残缺记录连在同一行
+	if err := terminateLastLine(path, file); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &FileJournal{
+		path:   path,
+		file:   file,
+		logger: logx.WithContext(context.Background()),
+	}, nil
+}
+
+// Append 追加一条记录并 fsync
+func (j *FileJournal) Append(entry *JournalEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("序列化日志记录失败: %w", err)
+	}
+	data = append(data, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return fmt.Errorf("日志已关闭")
+	}
+	if _, err := j.file.Write(data); err != nil {
+		return fmt.Errorf("写入日志失败: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("同步日志失败: %w", err)
+	}
+	return nil
+}
+
+// Pending 读取日志，返回未结束的执行
+func (j *FileJournal) Pending() ([]*JournaledExecution, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	executions, _, err := j.load()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]*JournaledExecution, 0)
+	for _, exec := range executions {
+		if exec.Status == "" {
+			pending = append(pending, exec)
+		}
+	}
+	return pending, nil
+}
+
+// Compact 重写日志文件，只保留未结束执行的记录（恢复完成后调用，避免文件无限增长）
+func (j *FileJournal) Compact() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return fmt.Errorf("日志已关闭")
+	}
+
+	executions, lines, err := j.load()
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[string]bool)
+	for _, exec := range executions {
+		if exec.Status == "" {
+			pending[exec.ID] = true
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, line := range lines {
+		if pending[line.executionID] {
+			buf.Write(line.data)
+			buf.WriteByte('\n')
+		}
+	}
+
+	// 先写临时文件再原子替换，压缩过程中崩溃不会丢失记录
+	tmpPath := j.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("同步临时文件失败: %w", err)
+	}
+	tmp.Close()
+
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return fmt.Errorf("替换日志文件失败: %w", err)
+	}
+
+	j.file.Close()
+	file, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		j.file = nil
+		return fmt.Errorf("重新打开日志文件失败: %w", err)
+	}
+	j.file = file
+	return nil
+}
+
+// Close 关闭日志文件
+func (j *FileJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// journalLine 日志文件中的一行
+type journalLine struct {
+	executionID string
+	data        []byte
+}
+
+// load 读取整个日志文件并重建执行过程，调用方需持有锁
+func (j *FileJournal) load() ([]*JournaledExecution, []journalLine, error) {
+	file, err := os.Open(j.path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	defer file.Close()
+
+	executions := make(map[string]*JournaledExecution)
+	order := make([]string, 0)
+	lines := make([]journalLine, 0)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		data := scanner.Bytes()
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		var entry JournalEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			// 崩溃时未写完的记录，对应的下单一定还没有发出
+			j.logger.Errorf("忽略无法解析的日志记录: %v", err)
+			continue
+		}
+
+		exec, ok := executions[entry.ExecutionID]
+		if !ok {
+			exec = &JournaledExecution{ID: entry.ExecutionID}
+			executions[entry.ExecutionID] = exec
+			order = append(order, entry.ExecutionID)
+		}
+		exec.apply(&entry)
+
+		lines = append(lines, journalLine{
+			executionID: entry.ExecutionID,
+			data:        append([]byte(nil), data...),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("读取日志文件失败: %w", err)
+	}
+
+	// 按开始顺序返回
+	ordered := make([]*JournaledExecution, 0, len(order))
+	for _, id := range order {
+		ordered = append(ordered, executions[id])
+	}
+	return ordered, lines, nil
+}
+
+// terminateLastLine 文件非空且不以换行结尾时追加换行
+func terminateLastLine(path string, file *os.File) error {
+	reader, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	defer reader.Close()
+
+	info, err := reader.Stat()
+	if err != nil {
+		return fmt.Errorf("读取日志文件信息失败: %w", err)
+	}
+	if info.Size() == 0 {
+		return nil
+	}
+
+	last := make([]byte, 1)
+	if _, err := reader.ReadAt(last, info.Size()-1); err != nil {
+		return fmt.Errorf("读取日志文件失败: %w", err)
+	}
+	if last[0] == '\n' {
+		return nil
+	}
+
+	if _, err := file.Write([]byte{'\n'}); err != nil {
+		return fmt.Errorf("写入日志失败: %w", err)
+	}
+	return file.Sync()
+}
+
+// clientOrderID 生成确定性的客户端订单ID
+// 由执行ID和订单腿决定，重启后可以重新计算并按此ID查询订单；
+// 32 位字母数字，同时满足 Binance（≤36）和 OKX（字母数字，≤32）的要求
+func clientOrderID(executionID, leg string) string {
+	sum := sha256.Sum256([]byte(executionID + ":" + leg))
+	return "ax" + hex.EncodeToString(sum[:])[:30]
+}
@@ -0,0 +1,151 @@
+// Package execution 执行日志单元测试
+package execution
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"arbitragex/common/decimal"
+)
+
+// TestFileJournal_Pending 测试从日志重建未结束的执行
+func TestFileJournal_Pending(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.log")
+	journal, err := NewFileJournal(path)
+	if err != nil {
+		t.Fatalf("NewFileJournal() error = %v", err)
+	}
+	defer journal.Close()
+
+	opp := &ArbitrageOpportunity{Symbol: "BTC/USDT", BuyExchange: "binance", SellExchange: "okx"}
+	req := &PlaceOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: OrderSideBuy, ClientOrderID: clientOrderID("e1", LegBuy)}
+
+	entries := []*JournalEntry{
+		{Type: JournalExecutionStarted, ExecutionID: "e1", Opportunity: opp, Amount: decimal.NewFromFloat(1000)},
+		{Type: JournalExecutionStarted, ExecutionID: "e2", Opportunity: opp, Amount: decimal.NewFromFloat(500)},
+		{Type: JournalOrderIntent, ExecutionID: "e1", Leg: LegBuy, Request: req},
+		{Type: JournalOrderUpdated, ExecutionID: "e1", Leg: LegBuy, Order: &Order{ID: "binance:BTCUSDT:1", Status: OrderStatusOpen}},
+		{Type: JournalExecutionFinished, ExecutionID: "e2", Status: ExecutionStatusCompleted},
+	}
+	for _, entry := range entries {
+		if err := journal.Append(entry); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	pending, err := journal.Pending()
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != "e1" {
+		t.Fatalf("Pending() = %+v, want only e1", pending)
+	}
+
+	exec := pending[0]
+	if !exec.Amount.Equal(decimal.NewFromFloat(1000)) || len(exec.Legs) != 1 {
+		t.Fatalf("exec = %+v", exec)
+	}
+	leg := exec.Leg(LegBuy)
+	if leg.Request.ClientOrderID != req.ClientOrderID || leg.Order == nil || leg.Order.Status != OrderStatusOpen {
+		t.Errorf("leg = %+v", leg)
+	}
+
+	// 压缩后只保留未结束的执行
+	if err := journal.Compact(); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+	if err := journal.Append(&JournalEntry{Type: JournalExecutionFinished, ExecutionID: "e1", Status: ExecutionStatusFailed}); err != nil {
+		t.Fatalf("Append() after Compact error = %v", err)
+	}
+	if pending, _ := journal.Pending(); len(pending) != 0 {
+		t.Errorf("Pending() after finish = %d, want 0", len(pending))
+	}
+}
+
+// TestFileJournal_TornWrite 测试崩溃时写了一半的记录
+func TestFileJournal_TornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.log")
+	content := `{"type":"execution_started","execution_id":"e1","amount":100,"time":"2024-01-01T00:00:00Z"}` + "\n" +
+		`{"type":"order_intent","execution_id":"e1","le`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	journal, err := NewFileJournal(path)
+	if err != nil {
+		t.Fatalf("NewFileJournal() error = %v", err)
+	}
+	defer journal.Close()
+
+	// 重启后追加的记录不能和残缺记录连在一起
+	if err := journal.Append(&JournalEntry{Type: JournalOrderIntent, ExecutionID: "e1", Leg: LegSell, Request: &PlaceOrderRequest{}}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	pending, err := journal.Pending()
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	if len(pending) != 1 || len(pending[0].Legs) != 1 || pending[0].Legs[0].Leg != LegSell {
+		t.Errorf("Pending() = %+v", pending)
+	}
+}
+
+// TestClientOrderID 测试确定性的客户端订单ID
+func TestClientOrderID(t *testing.T) {
+	id := clientOrderID("exec-1", LegBuy)
+	if id != clientOrderID("exec-1", LegBuy) {
+		t.Error("clientOrderID() should be deterministic")
+	}
+	if id == clientOrderID("exec-1", LegSell) || id == clientOrderID("exec-2", LegBuy) {
+		t.Error("clientOrderID() should differ per execution and leg")
+	}
+	if !regexp.MustCompile(`^[a-zA-Z0-9]{1,32}$`).MatchString(id) {
+		t.Errorf("clientOrderID() = %s, want alphanumeric with at most 32 chars", id)
+	}
+}
+
+// TestQueryOrderByClientID 测试按客户端订单ID查询（订单不存在时返回 ErrOrderNotFound）
+func TestQueryOrderByClientID(t *testing.T) {
+	binanceServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("origClientOrderId") {
+		case "found":
+			w.Write([]byte(`{"symbol":"BTCUSDT","orderId":7,"clientOrderId":"found","status":"FILLED","executedQty":"0.1","cummulativeQuoteQty":"5000","side":"BUY","type":"LIMIT"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":-2013,"msg":"Order does not exist."}`))
+		}
+	}))
+	defer binanceServer.Close()
+
+	binance := NewBinanceExecutor("test-key", []byte("test-secret"), binanceServer.URL)
+	order, err := binance.QueryOrderByClientID(context.Background(), "BTC/USDT", "found")
+	if err != nil {
+		t.Fatalf("Binance QueryOrderByClientID() error = %v", err)
+	}
+	if order.ID != "binance:BTCUSDT:7" || order.Status != OrderStatusFilled {
+		t.Errorf("order = %+v", order)
+	}
+	if _, err := binance.QueryOrderByClientID(context.Background(), "BTC/USDT", "missing"); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("Binance error = %v, want ErrOrderNotFound", err)
+	}
+
+	okxServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("clOrdId") != "missing" || r.URL.Query().Get("instId") != "BTC-USDT" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"code":"51603","msg":"Order does not exist","data":[]}`))
+	}))
+	defer okxServer.Close()
+
+	okx := NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", okxServer.URL)
+	if _, err := okx.QueryOrderByClientID(context.Background(), "BTC/USDT", "missing"); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("OKX error = %v, want ErrOrderNotFound", err)
+	}
+}
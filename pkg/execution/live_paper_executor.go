@@ -0,0 +1,55 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+)
+
+// LivePaperExecutor 按交易所实时订单簿模拟成交的执行器（模拟交易使用，不下真实订单）
+// 下单前从交易所拉取最新的公开订单簿，再按订单簿模拟撮合；挂单在查询时按最新订单簿继续撮合
+type LivePaperExecutor struct {
+	*PaperExecutor
+
+	// market 提供公开订单簿的交易所执行器（不需要 API 密钥）
+	market OrderExecutor
+}
+
+// NewLivePaperExecutor 创建按实时订单簿模拟成交的执行器
+// 参数:
+//   - paper: 模拟成交执行器
+//   - market: 提供公开订单簿的交易所执行器
+// 返回:
+//   - *LivePaperExecutor: 模拟交易执行器
+func NewLivePaperExecutor(paper *PaperExecutor, market OrderExecutor) *LivePaperExecutor {
+	return &LivePaperExecutor{PaperExecutor: paper, market: market}
+}
+
+// PlaceOrder 刷新订单簿后模拟下单
+func (p *LivePaperExecutor) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*Order, error) {
+	if err := p.refresh(ctx, req.Exchange, req.Symbol); err != nil {
+		return nil, err
+	}
+	return p.PaperExecutor.PlaceOrder(ctx, req)
+}
+
+// QueryOrder 查询订单，未进入终态的订单先按最新订单簿撮合
+func (p *LivePaperExecutor) QueryOrder(ctx context.Context, exchange, orderID string) (*Order, error) {
+	order, err := p.PaperExecutor.QueryOrder(ctx, exchange, orderID)
+	if err != nil || IsFinalStatus(order.Status) {
+		return order, err
+	}
+	if err := p.refresh(ctx, exchange, order.Symbol); err != nil {
+		return order, nil
+	}
+	return p.PaperExecutor.QueryOrder(ctx, exchange, orderID)
+}
+
+// refresh 拉取交易所的最新订单簿
+func (p *LivePaperExecutor) refresh(ctx context.Context, exchange, symbol string) error {
+	book, err := p.market.GetOrderBook(ctx, exchange, symbol)
+	if err != nil {
+		return fmt.Errorf("模拟下单失败: %w", err)
+	}
+	p.UpdateOrderBook(book)
+	return nil
+}
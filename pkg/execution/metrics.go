@@ -0,0 +1,102 @@
+package execution
+
+import (
+	"time"
+
+	"arbitragex/common/clock"
+
+	"github.com/zeromicro/go-zero/core/metric"
+)
+
+// 执行指标（开启 Prometheus 后记录）
+var (
+	metricQueueDepth = metric.NewGaugeVec(&metric.GaugeVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "execution",
+		Name:      "queue_depth",
+		Help:      "Execution tasks waiting in the task queue.",
+		Labels:    []string{},
+	})
+	metricQueueWait = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "execution",
+		Name:      "queue_wait_seconds",
+		Help:      "Time an execution task waited in the queue before it started.",
+		Labels:    []string{},
+		Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+	})
+	metricLegDuration = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "execution",
+		Name:      "leg_duration_seconds",
+		Help:      "Time from placing an order leg to its final status.",
+		Labels:    []string{"exchange", "side"},
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	})
+	metricFillRatio = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "execution",
+		Name:      "fill_ratio",
+		Help:      "Filled amount divided by requested amount of finished order legs.",
+		Labels:    []string{"exchange", "side"},
+		Buckets:   []float64{0, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 1},
+	})
+	metricExecutions = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "execution",
+		Name:      "executions_total",
+		Help:      "Finished arbitrage executions by status.",
+		Labels:    []string{"status"},
+	})
+	metricExecutionDuration = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "execution",
+		Name:      "duration_seconds",
+		Help:      "Arbitrage execution duration by status.",
+		Labels:    []string{"status"},
+		Buckets:   []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60},
+	})
+	metricRealizedPnL = metric.NewGaugeVec(&metric.GaugeVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "execution",
+		Name:      "realized_pnl_usdt",
+		Help:      "Cumulative realized profit and loss in USDT, including failed executions that were unwound.",
+		Labels:    []string{"symbol"},
+	})
+	metricReconnects = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "execution",
+		Name:      "stream_reconnects_total",
+		Help:      "Reconnects of private WebSocket streams (order updates and WebSocket order APIs).",
+		Labels:    []string{"stream"},
+	})
+)
+
+// observeQueueDepth 记录队列中等待的任务数
+func observeQueueDepth(queue *TaskQueue) {
+	metricQueueDepth.Set(float64(queue.Size()))
+}
+
+// observeLeg 记录订单腿从下单到终态的耗时和成交比例（同一订单腿只记录一次，耗时按执行器的时钟计算）
+func observeLeg(leg *JournaledLeg, order *Order, clk clock.Clock) {
+	if leg.placedAt.IsZero() || !IsFinalStatus(order.Status) {
+		return
+	}
+	exchange, side := leg.Request.Exchange, leg.Request.Side
+	metricLegDuration.ObserveFloat(clk.Since(leg.placedAt).Seconds(), exchange, side)
+	if leg.Request.Amount.IsPositive() {
+		metricFillRatio.ObserveFloat(order.FilledAmount.Div(leg.Request.Amount).Float64(), exchange, side)
+	}
+	leg.placedAt = time.Time{}
+}
+
+// observeResult 记录执行结果、耗时和实际收益
+func observeResult(result *ExecutionResult) {
+	metricExecutions.Inc(result.Status)
+	if !result.StartedAt.IsZero() && !result.CompletedAt.IsZero() {
+		metricExecutionDuration.ObserveFloat(result.CompletedAt.Sub(result.StartedAt).Seconds(), result.Status)
+	}
+	if !result.ActualProfit.IsZero() {
+		metricRealizedPnL.Add(result.ActualProfit.Float64(), result.Symbol)
+	}
+}
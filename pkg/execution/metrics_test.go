@@ -0,0 +1,95 @@
+// Package execution 执行指标单元测试
+package execution
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"arbitragex/common/decimal"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/zeromicro/go-zero/core/prometheus"
+)
+
+// TestDefaultConcurrentExecutor_Metrics 测试执行记录排队时间、订单腿耗时、成交比例、执行结果和实际收益
+func TestDefaultConcurrentExecutor_Metrics(t *testing.T) {
+	prometheus.Enable()
+
+	binance := newFakeExchange("binance", 1)
+	okx := newFakeExchange("okx", 1)
+	executor, _ := newJournaledExecutor(t, filepath.Join(t.TempDir(), "journal.log"), binance, okx)
+	if err := executor.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer executor.Stop()
+
+	waits := metricValue(t, "arbitragex_execution_queue_wait_seconds", "", "")
+	legs := metricValue(t, "arbitragex_execution_leg_duration_seconds", "exchange", "binance")
+	fills := metricValue(t, "arbitragex_execution_fill_ratio", "exchange", "okx")
+	completed := metricValue(t, "arbitragex_execution_executions_total", "status", ExecutionStatusCompleted)
+	pnl := metricValue(t, "arbitragex_execution_realized_pnl_usdt", "symbol", "BTC/USDT")
+
+	result, err := executor.ExecuteArbitrage(context.Background(), testOpportunity, decimal.NewFromInt(5000))
+	if err != nil || result.Status != ExecutionStatusCompleted {
+		t.Fatalf("ExecuteArbitrage() = %v, %v, want completed", result, err)
+	}
+
+	if got := metricValue(t, "arbitragex_execution_queue_wait_seconds", "", "") - waits; got != 1 {
+		t.Errorf("queue_wait_seconds count delta = %v, want 1", got)
+	}
+	if got := metricValue(t, "arbitragex_execution_leg_duration_seconds", "exchange", "binance") - legs; got != 1 {
+		t.Errorf("leg_duration_seconds{exchange=binance} count delta = %v, want 1", got)
+	}
+	if got := metricValue(t, "arbitragex_execution_fill_ratio", "exchange", "okx") - fills; got != 1 {
+		t.Errorf("fill_ratio{exchange=okx} count delta = %v, want 1", got)
+	}
+	if got := metricValue(t, "arbitragex_execution_executions_total", "status", ExecutionStatusCompleted) - completed; got != 1 {
+		t.Errorf("executions_total{status=completed} delta = %v, want 1", got)
+	}
+	if got := metricValue(t, "arbitragex_execution_realized_pnl_usdt", "symbol", "BTC/USDT") - pnl; got != 10 {
+		t.Errorf("realized_pnl_usdt delta = %v, want 10", got)
+	}
+	if got := metricValue(t, "arbitragex_execution_queue_depth", "", ""); got != 0 {
+		t.Errorf("queue_depth = %v, want 0", got)
+	}
+}
+
+// metricValue 从默认 Registry 读取指标（Counter、Gauge 返回值，Histogram 返回样本数）
+// 参数:
+//   - label, value: 只统计该标签等于 value 的序列（label 为空时统计全部）
+func metricValue(t *testing.T, name, label, value string) float64 {
+	t.Helper()
+
+	families, err := prom.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	var total float64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			matched := label == ""
+			for _, pair := range m.GetLabel() {
+				if pair.GetName() == label && pair.GetValue() == value {
+					matched = true
+				}
+			}
+			if !matched {
+				continue
+			}
+			switch {
+			case m.Counter != nil:
+				total += m.GetCounter().GetValue()
+			case m.Gauge != nil:
+				total += m.GetGauge().GetValue()
+			case m.Histogram != nil:
+				total += float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return total
+}
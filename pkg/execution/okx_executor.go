@@ -3,8 +3,6 @@ package execution
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -16,6 +14,8 @@ import (
 	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
+
+	"arbitragex/common/decimal"
 )
 
 // OKXExecutor OKX 订单执行器
@@ -23,8 +23,8 @@ type OKXExecutor struct {
 	// API Key
 	apiKey string
 
-	// API Secret
-	apiSecret string
+	// API Secret（签名密钥，Close 时清零）
+	secret *signingKey
 
 	// Passphrase API 密钥密码
 	passphrase string
@@ -37,17 +37,20 @@ type OKXExecutor struct {
 
 	// 日志记录器
 	logger logx.Logger
+
+	// 私有数据流（订单推送）
+	orderTracking
 }
 
 // NewOKXExecutor 创建 OKX 订单执行器
 // 参数:
 //   - apiKey: API 密钥
-//   - apiSecret: API 密钥对应的 Secret
+//   - apiSecret: API 密钥对应的 Secret（执行器保存副本，调用方可以随后清零）
 //   - passphrase: API 密钥密码
 //   - baseURL: REST API 基础 URL（测试环境可使用测试网 URL）
 // 返回:
 //   - *OKXExecutor: OKX 订单执行器实例
-func NewOKXExecutor(apiKey, apiSecret, passphrase, baseURL string) *OKXExecutor {
+func NewOKXExecutor(apiKey string, apiSecret []byte, passphrase, baseURL string) *OKXExecutor {
 	// 设置默认基础 URL
 	if baseURL == "" {
 		baseURL = "https://www.okx.com"
@@ -55,7 +58,7 @@ func NewOKXExecutor(apiKey, apiSecret, passphrase, baseURL string) *OKXExecutor
 
 	return &OKXExecutor{
 		apiKey:     apiKey,
-		apiSecret:  apiSecret,
+		secret:     newSigningKey(apiSecret),
 		passphrase: passphrase,
 		baseURL:    baseURL,
 		client: &http.Client{
@@ -74,26 +77,7 @@ func (o *OKXExecutor) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*
 	}
 
 	// 构建请求参数
-	params := map[string]interface{}{
-		"instId":  o.toOKXSymbol(req.Symbol),
-		"tdMode":  "cash", // 现货交易模式
-		"side":    strings.ToUpper(req.Side),
-		"ordType": strings.ToUpper(req.Type),
-		"sz":      strconv.FormatFloat(req.Amount, 'f', -1, 64),
-	}
-
-	// 设置订单类型相关参数
-	switch strings.ToUpper(req.Type) {
-	case "LIMIT":
-		params["px"] = strconv.FormatFloat(req.Price, 'f', -1, 64)
-	case "MARKET":
-		// 市价单不需要价格
-	}
-
-	// 客户端订单 ID（可选）
-	if req.ClientOrderID != "" {
-		params["clOrdId"] = req.ClientOrderID
-	}
+	params := o.buildOrderParams(req)
 
 	// 发送请求
 	response, err := o.signAndRequest(ctx, "POST", "/api/v5/trade/order", params)
@@ -102,7 +86,12 @@ func (o *OKXExecutor) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*
 	}
 
 	// 解析响应
-	return o.parseOrderResponse(response, req)
+	order, err := o.parseOrderResponse(response, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.trackOrder(order), nil
 }
 
 // CancelOrder 撤单
@@ -157,6 +146,19 @@ func (o *OKXExecutor) QueryOrder(ctx context.Context, exchange, orderID string)
 		return nil, fmt.Errorf("无效的订单ID格式: %s", orderID)
 	}
 
+	// 私有数据流已连接时直接使用推送的订单状态
+	if order, ok := o.trackedOrder(orderID); ok {
+		return order, nil
+	}
+	return o.fetchOrder(ctx, orderID)
+}
+
+// fetchOrder 通过 REST 查询订单（不使用推送的状态，私有数据流重连后补查和等待推送超时时使用）
+func (o *OKXExecutor) fetchOrder(ctx context.Context, orderID string) (*Order, error) {
+	parts := strings.Split(orderID, ":")
+	if len(parts) != 3 || parts[0] != "okx" {
+		return nil, fmt.Errorf("无效的订单ID格式: %s", orderID)
+	}
 	symbol := parts[1]
 	exchangeOrderID := parts[2]
 
@@ -173,7 +175,45 @@ func (o *OKXExecutor) QueryOrder(ctx context.Context, exchange, orderID string)
 	}
 
 	// 解析响应
-	return o.parseOrderQueryResponse(response, symbol)
+	order, err := o.parseOrderQueryResponse(response, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// 经过状态机，过期的查询结果不会让状态倒退
+	return o.trackOrder(order), nil
+}
+
+// QueryOrderByClientID 按客户端订单ID查询订单
+func (o *OKXExecutor) QueryOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*Order, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("交易对不能为空")
+	}
+	if clientOrderID == "" {
+		return nil, fmt.Errorf("客户端订单ID不能为空")
+	}
+
+	instID := o.toOKXSymbol(symbol)
+	params := map[string]interface{}{
+		"instId":  instID,
+		"clOrdId": clientOrderID,
+	}
+
+	response, err := o.signAndRequest(ctx, "GET", "/api/v5/trade/order", params)
+	if err != nil {
+		// 51603: Order does not exist
+		if strings.Contains(err.Error(), "(51603)") {
+			return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, clientOrderID)
+		}
+		return nil, fmt.Errorf("查询订单失败: %w", err)
+	}
+
+	order, err := o.parseOrderQueryResponse(response, instID)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.trackOrder(order), nil
 }
 
 // GetOrderBook 获取订单簿深度
@@ -201,10 +241,263 @@ func (o *OKXExecutor) GetOrderBook(ctx context.Context, exchange, symbol string)
 	return o.parseOrderBookResponse(response, symbol)
 }
 
+// GetBalances 查询交易账户余额
+func (o *OKXExecutor) GetBalances(ctx context.Context) ([]*Balance, error) {
+	// 发送请求
+	response, err := o.signAndRequest(ctx, "GET", "/api/v5/account/balance", nil)
+	if err != nil {
+		return nil, fmt.Errorf("查询余额失败: %w", err)
+	}
+
+	// 解析响应
+	return o.parseBalancesResponse(response)
+}
+
+// GetDepositAddress 查询充值地址
+// network 使用 OKX 的链名称（如 USDT-TRC20），为空时返回第一个地址
+func (o *OKXExecutor) GetDepositAddress(ctx context.Context, asset, network string) (*DepositAddress, error) {
+	// 参数校验
+	if asset == "" {
+		return nil, fmt.Errorf("币种不能为空")
+	}
+
+	// 构建请求参数
+	params := map[string]interface{}{
+		"ccy": strings.ToUpper(asset),
+	}
+
+	// 发送请求
+	response, err := o.signAndRequest(ctx, "GET", "/api/v5/asset/deposit-address", params)
+	if err != nil {
+		return nil, fmt.Errorf("查询充值地址失败: %w", err)
+	}
+
+	// 解析响应
+	data, ok := response["data"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("响应数据格式错误")
+	}
+
+	for _, item := range data {
+		addrData, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		chain, _ := addrData["chain"].(string)
+		if network != "" && chain != network {
+			continue
+		}
+
+		address, _ := addrData["addr"].(string)
+		if address == "" {
+			continue
+		}
+
+		// 部分币种使用 memo 或 tag
+		tag, _ := addrData["memo"].(string)
+		if tag == "" {
+			tag, _ = addrData["tag"].(string)
+		}
+
+		return &DepositAddress{
+			Exchange: "okx",
+			Asset:    strings.ToUpper(asset),
+			Network:  chain,
+			Address:  address,
+			Tag:      tag,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("未找到充值地址: %s %s", asset, network)
+}
+
+// OKX 账户类型（资金划转使用）
+const (
+	okxFundingAccount = "6"  // 资金账户
+	okxTradingAccount = "18" // 交易账户
+)
+
+// transferFunds 在 OKX 账户之间划转资金
+// 参数:
+//   - ctx: 上下文对象
+//   - asset: 币种
+//   - amount: 划转数量
+//   - from: 转出账户（okxTradingAccount、okxFundingAccount）
+//   - to: 转入账户
+func (o *OKXExecutor) transferFunds(ctx context.Context, asset string, amount decimal.Decimal, from, to string) error {
+	_, err := o.signAndRequest(ctx, "POST", "/api/v5/asset/transfer", map[string]interface{}{
+		"ccy":  asset,
+		"amt":  amount.String(),
+		"from": from,
+		"to":   to,
+	})
+	return err
+}
+
+// Withdraw 提币
+// OKX 只能从资金账户提币，因此先将资金从交易账户划转到资金账户，提币失败时再划回交易账户；
+// OKX 的 amt 不含手续费，实际提交 amt = Amount - Fee
+func (o *OKXExecutor) Withdraw(ctx context.Context, req *WithdrawRequest) (*Withdrawal, error) {
+	// 参数校验
+	if err := validateWithdrawRequest(req, "okx"); err != nil {
+		return nil, fmt.Errorf("参数校验失败: %w", err)
+	}
+	if req.Amount.LessThanOrEqual(req.Fee) {
+		return nil, fmt.Errorf("参数校验失败: 提币数量必须大于手续费")
+	}
+
+	asset := strings.ToUpper(req.Asset)
+
+	// 1. 交易账户 -> 资金账户
+	if err := o.transferFunds(ctx, asset, req.Amount, okxTradingAccount, okxFundingAccount); err != nil {
+		return nil, fmt.Errorf("资金划转失败: %w", err)
+	}
+
+	// 2. 链上提币
+	toAddr := req.Address
+	if req.Tag != "" {
+		// OKX 使用 address:tag 格式传递标签
+		toAddr = req.Address + ":" + req.Tag
+	}
+
+	params := map[string]interface{}{
+		"ccy":    asset,
+		"amt":    req.Amount.Sub(req.Fee).String(),
+		"dest":   "4", // 链上提币
+		"toAddr": toAddr,
+		"chain":  req.Network,
+		"fee":    req.Fee.String(),
+	}
+	if req.ClientID != "" {
+		params["clientId"] = req.ClientID
+	}
+
+	response, err := o.signAndRequest(ctx, "POST", "/api/v5/asset/withdrawal", params)
+	if err != nil {
+		// 提币失败时将划转的资金转回交易账户（ctx 已取消时也要执行）
+		if backErr := o.transferFunds(context.WithoutCancel(ctx), asset, req.Amount, okxFundingAccount, okxTradingAccount); backErr != nil {
+			return nil, fmt.Errorf("提币失败: %w；%s %s 仍在资金账户中，划回交易账户失败: %v", err, req.Amount, asset, backErr)
+		}
+		return nil, fmt.Errorf("提币失败（%s %s 已划回交易账户）: %w", req.Amount, asset, err)
+	}
+
+	// 解析响应
+	data, ok := response["data"].([]interface{})
+	if !ok || len(data) == 0 {
+		return nil, fmt.Errorf("响应数据格式错误")
+	}
+	wdData, ok := data[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("提币数据格式错误")
+	}
+
+	id, _ := wdData["wdId"].(string)
+	if id == "" {
+		return nil, fmt.Errorf("提币失败: 响应中缺少提币ID")
+	}
+
+	withdrawal := &Withdrawal{
+		ID:        id,
+		Exchange:  "okx",
+		Asset:     asset,
+		Network:   req.Network,
+		Address:   req.Address,
+		Amount:    req.Amount,
+		Fee:       req.Fee,
+		ClientID:  req.ClientID,
+		CreatedAt: time.Now(),
+	}
+
+	o.logger.Infof("提币已提交: %s %.8f %s -> %s (%s)", withdrawal.ID, req.Amount, asset, req.Address, req.Network)
+	return withdrawal, nil
+}
+
+// buildOrderParams 构建下单参数（REST 和 WebSocket 下单共用）
+func (o *OKXExecutor) buildOrderParams(req *PlaceOrderRequest) map[string]interface{} {
+	params := map[string]interface{}{
+		"instId": o.toOKXSymbol(req.Symbol),
+		"tdMode": TradeModeCash,             // 默认现货交易模式
+		"side":   strings.ToLower(req.Side), // OKX 要求小写
+	}
+	// 杠杆交易模式（cross / isolated 与 OKX tdMode 取值相同），只减仓仅杠杆模式支持
+	if req.TradeMode != "" {
+		params["tdMode"] = req.TradeMode
+	}
+	if req.ReduceOnly {
+		params["reduceOnly"] = true
+	}
+
+	// 设置订单类型相关参数
+	switch req.Type {
+	case OrderTypeLimit:
+		params["ordType"] = o.toOKXLimitOrderType(req)
+		params["px"] = req.Price.String()
+		params["sz"] = req.Amount.String()
+	case OrderTypeMarket:
+		params["ordType"] = "market"
+		// 现货市价买单默认按计价货币计量，需要显式指定 tgtCcy
+		if req.QuoteAmount.IsPositive() {
+			params["tgtCcy"] = "quote_ccy"
+			params["sz"] = req.QuoteAmount.String()
+		} else {
+			params["tgtCcy"] = "base_ccy"
+			params["sz"] = req.Amount.String()
+		}
+	}
+
+	// 客户端订单 ID（可选）
+	if req.ClientOrderID != "" {
+		params["clOrdId"] = req.ClientOrderID
+	}
+
+	return params
+}
+
+// toOKXLimitOrderType 转换限价单类型
+// OKX 用 ordType 表示有效方式: limit(GTC) / ioc / fok / post_only
+func (o *OKXExecutor) toOKXLimitOrderType(req *PlaceOrderRequest) string {
+	if req.PostOnly {
+		return "post_only"
+	}
+
+	switch req.TimeInForce {
+	case TimeInForceIOC:
+		return "ioc"
+	case TimeInForceFOK:
+		return "fok"
+	default:
+		return "limit"
+	}
+}
+
+// parseOrderType 解析 OKX 订单类型
+func (o *OKXExecutor) parseOrderType(ordType string) (string, string, bool) {
+	switch ordType {
+	case "market":
+		return OrderTypeMarket, "", false
+	case "post_only":
+		return OrderTypeLimit, TimeInForceGTC, true
+	case "ioc":
+		return OrderTypeLimit, TimeInForceIOC, false
+	case "fok":
+		return OrderTypeLimit, TimeInForceFOK, false
+	case "limit":
+		return OrderTypeLimit, TimeInForceGTC, false
+	default:
+		return strings.ToLower(ordType), "", false
+	}
+}
+
 // signAndRequest 发送需要签名的请求
 func (o *OKXExecutor) signAndRequest(ctx context.Context, method, endpoint string, params map[string]interface{}) (map[string]interface{}, error) {
-	// 生成时间戳
-	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	// 生成时间戳（OKX 要求 ISO 8601 格式，精确到毫秒）
+	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+
+	// GET 请求的参数放在查询字符串中，并参与签名
+	if method == "GET" && len(params) > 0 {
+		endpoint += "?" + encodeOKXQuery(params)
+	}
 
 	// 构建签名字符串
 	signString := o.buildSignString(method, endpoint, params, timestamp)
@@ -233,9 +526,9 @@ func (o *OKXExecutor) request(ctx context.Context, method, endpoint string, para
 
 	// 如果需要签名，添加认证信息
 	if needSign {
-		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
 		queryString := params.Encode()
-		signString := timestamp + method + "/api/v5" + endpoint + "?" + queryString
+		signString := timestamp + method + endpoint + "?" + queryString
 		signature := o.generateSignature(signString)
 
 		headers["OK-ACCESS-KEY"] = o.apiKey
@@ -294,7 +587,7 @@ func (o *OKXExecutor) request(ctx context.Context, method, endpoint string, para
 	// 检查 OKX API 错误
 	if code, ok := result["code"].(string); ok && code != "0" {
 		msg, _ := result["msg"].(string)
-		return nil, fmt.Errorf("OKX API 错误: %s", msg)
+		return nil, fmt.Errorf("OKX API 错误: %s (%s)", msg, code)
 	}
 
 	return result, nil
@@ -349,14 +642,15 @@ func (o *OKXExecutor) requestWithHeaders(ctx context.Context, method, endpoint s
 	// 检查 OKX API 错误
 	if code, ok := result["code"].(string); ok && code != "0" {
 		msg, _ := result["msg"].(string)
-		return nil, fmt.Errorf("OKX API 错误: %s", msg)
+		return nil, fmt.Errorf("OKX API 错误: %s (%s)", msg, code)
 	}
 
 	return result, nil
 }
 
 // buildSignString 构建签名字符串
-func (o *OKXExecutor) buildSignString(method, endpoint string, params map[string]interface{}, timestamp string) string {
+// requestPath 为完整请求路径（如 /api/v5/trade/order），GET 请求包含查询字符串
+func (o *OKXExecutor) buildSignString(method, requestPath string, params map[string]interface{}, timestamp string) string {
 	// OKX 签名字符串格式: timestamp + method + requestPath + body
 	body := ""
 	if method == "POST" {
@@ -364,14 +658,21 @@ func (o *OKXExecutor) buildSignString(method, endpoint string, params map[string
 		body = string(jsonData)
 	}
 
-	return timestamp + method + "/api/v5" + endpoint + body
+	return timestamp + method + requestPath + body
+}
+
+// encodeOKXQuery 将参数编码为查询字符串（按键排序）
+func encodeOKXQuery(params map[string]interface{}) string {
+	values := url.Values{}
+	for key, value := range params {
+		values.Set(key, fmt.Sprintf("%v", value))
+	}
+	return values.Encode()
 }
 
 // generateSignature 生成签名
 func (o *OKXExecutor) generateSignature(signString string) string {
-	h := hmac.New(sha256.New, []byte(o.apiSecret))
-	h.Write([]byte(signString))
-	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+	return base64.StdEncoding.EncodeToString(o.secret.sum(signString))
 }
 
 // toOKXSymbol 转换为 OKX 交易对格式
@@ -403,13 +704,10 @@ func (o *OKXExecutor) validatePlaceOrderRequest(req *PlaceOrderRequest) error {
 	if req.Type != OrderTypeLimit && req.Type != OrderTypeMarket {
 		return fmt.Errorf("无效的订单类型: %s", req.Type)
 	}
-	if req.Type == OrderTypeLimit && req.Price <= 0 {
+	if req.Type == OrderTypeLimit && !req.Price.IsPositive() {
 		return fmt.Errorf("限价单价格必须大于 0")
 	}
-	if req.Amount <= 0 {
-		return fmt.Errorf("数量必须大于 0")
-	}
-	return nil
+	return validateOrderOptions(req)
 }
 
 // parseOrderResponse 解析下单响应
@@ -431,6 +729,17 @@ func (o *OKXExecutor) parseOrderResponse(response map[string]interface{}, req *P
 		return nil, fmt.Errorf("订单数据格式错误")
 	}
 
+	return o.parsePlacedOrder(orderData, req)
+}
+
+// parsePlacedOrder 解析单个下单结果（REST 和 WebSocket 下单共用）
+func (o *OKXExecutor) parsePlacedOrder(orderData map[string]interface{}, req *PlaceOrderRequest) (*Order, error) {
+	// 检查单个订单的错误码
+	if sCode, ok := orderData["sCode"].(string); ok && sCode != "0" {
+		sMsg, _ := orderData["sMsg"].(string)
+		return nil, fmt.Errorf("下单失败: %s %s", sCode, sMsg)
+	}
+
 	// 解析订单信息
 	order := &Order{
 		ID:            fmt.Sprintf("okx:%s:%v", o.toOKXSymbol(req.Symbol), orderData["ordId"]),
@@ -438,6 +747,7 @@ func (o *OKXExecutor) parseOrderResponse(response map[string]interface{}, req *P
 		Symbol:        req.Symbol,
 		Side:          req.Side,
 		Type:          req.Type,
+		PostOnly:      req.PostOnly,
 		Price:         req.Price,
 		Amount:        req.Amount,
 		ClientOrderID: req.ClientOrderID,
@@ -453,21 +763,21 @@ func (o *OKXExecutor) parseOrderResponse(response map[string]interface{}, req *P
 
 	// 解析已成交数量
 	if filledQty, ok := orderData["fillSz"].(string); ok {
-		if qty, err := strconv.ParseFloat(filledQty, 64); err == nil {
+		if qty, err := decimal.NewFromString(filledQty); err == nil {
 			order.FilledAmount = qty
 		}
 	}
 
 	// 解析平均价格
 	if avgPx, ok := orderData["avgPx"].(string); ok {
-		if price, err := strconv.ParseFloat(avgPx, 64); err == nil && price > 0 {
+		if price, err := decimal.NewFromString(avgPx); err == nil && price.IsPositive() {
 			order.AveragePrice = price
 		}
 	}
 
 	// 解析手续费
 	if fee, ok := orderData["fee"].(string); ok {
-		if f, err := strconv.ParseFloat(fee, 64); err == nil {
+		if f, err := decimal.NewFromString(fee); err == nil {
 			order.Fee = f
 		}
 	}
@@ -477,8 +787,13 @@ func (o *OKXExecutor) parseOrderResponse(response map[string]interface{}, req *P
 		order.FeeCurrency = feeCurrency
 	}
 
-	// 如果完全成交，更新状态
-	if order.FilledAmount >= order.Amount {
+	// 限价单的有效方式
+	if req.Type == OrderTypeLimit && !req.PostOnly {
+		_, order.TimeInForce, _ = o.parseOrderType(o.toOKXLimitOrderType(req))
+	}
+
+	// 如果完全成交，更新状态（按金额下单时数量未知，以查询或推送为准）
+	if order.Amount.IsPositive() && order.FilledAmount.GreaterThanOrEqual(order.Amount) {
 		order.Status = OrderStatusFilled
 	}
 
@@ -505,55 +820,70 @@ func (o *OKXExecutor) parseOrderQueryResponse(response map[string]interface{}, s
 		return nil, fmt.Errorf("订单数据格式错误")
 	}
 
+	return o.parseOrderData(orderData), nil
+}
+
+// parseOrderData 解析订单数据
+// REST 查询和 orders 频道推送的订单字段相同
+func (o *OKXExecutor) parseOrderData(orderData map[string]interface{}) *Order {
 	// 解析基本信息
 	instId, _ := orderData["instId"].(string)
 	side, _ := orderData["side"].(string)
 	orderType, _ := orderData["ordType"].(string)
 
+	parsedType, timeInForce, postOnly := o.parseOrderType(orderType)
+
 	order := &Order{
-		ID:       fmt.Sprintf("okx:%s:%v", instId, orderData["ordId"]),
-		Exchange: "okx",
-		Symbol:   o.toStandardSymbol(instId),
-		Side:     strings.ToLower(side),
-		Type:     strings.ToLower(orderType),
-		Status:   o.parseOrderStatus(orderData),
+		ID:          fmt.Sprintf("okx:%s:%v", instId, orderData["ordId"]),
+		Exchange:    "okx",
+		Symbol:      o.toStandardSymbol(instId),
+		Side:        strings.ToLower(side),
+		Type:        parsedType,
+		TimeInForce: timeInForce,
+		PostOnly:    postOnly,
+		Status:      o.parseOrderStatus(orderData),
 	}
 
 	// 解析价格
 	if px, ok := orderData["px"].(string); ok {
-		if p, err := strconv.ParseFloat(px, 64); err == nil {
+		if p, err := decimal.NewFromString(px); err == nil {
 			order.Price = p
 		}
 	}
 
 	// 解析数量
 	if sz, ok := orderData["sz"].(string); ok {
-		if s, err := strconv.ParseFloat(sz, 64); err == nil {
+		if s, err := decimal.NewFromString(sz); err == nil {
 			order.Amount = s
 		}
 	}
 
-	// 解析已成交数量
-	if filledSz, ok := orderData["fillSz"].(string); ok {
-		if s, err := strconv.ParseFloat(filledSz, 64); err == nil {
+	// 解析已成交数量（accFillSz 为累计成交量，fillSz 只是最新一笔）
+	if filledSz, ok := orderData["accFillSz"].(string); ok {
+		if s, err := decimal.NewFromString(filledSz); err == nil {
 			order.FilledAmount = s
 		}
 	}
 
 	// 解析平均价格
 	if avgPx, ok := orderData["avgPx"].(string); ok {
-		if p, err := strconv.ParseFloat(avgPx, 64); err == nil && p > 0 {
+		if p, err := decimal.NewFromString(avgPx); err == nil && p.IsPositive() {
 			order.AveragePrice = p
 		}
 	}
 
-	// 解析手续费
+	// 解析手续费（OKX 以负数表示扣除的手续费）
 	if fee, ok := orderData["fee"].(string); ok {
-		if f, err := strconv.ParseFloat(fee, 64); err == nil {
-			order.Fee = f
+		if f, err := decimal.NewFromString(fee); err == nil {
+			order.Fee = f.Abs()
 		}
 	}
 
+	// 解析客户端订单 ID
+	if clOrdID, ok := orderData["clOrdId"].(string); ok {
+		order.ClientOrderID = clOrdID
+	}
+
 	// 解析手续费币种
 	if feeCurrency, ok := orderData["feeCcy"].(string); ok {
 		order.FeeCurrency = feeCurrency
@@ -570,8 +900,21 @@ func (o *OKXExecutor) parseOrderQueryResponse(response map[string]interface{}, s
 			order.CreatedAt = time.Unix(ms/1000, 0)
 		}
 	}
+	if uTime, ok := orderData["uTime"].(string); ok {
+		if ms, err := strconv.ParseInt(uTime, 10, 64); err == nil {
+			order.UpdatedAt = time.UnixMilli(ms)
+		}
+	}
 
-	return order, nil
+	// 按金额下单的市价单 sz 为计价货币金额，基础货币数量在结束后才确定
+	if tgtCcy, _ := orderData["tgtCcy"].(string); tgtCcy == "quote_ccy" {
+		order.Amount = decimal.Zero
+		if IsFinalStatus(order.Status) {
+			order.Amount = order.FilledAmount
+		}
+	}
+
+	return order
 }
 
 // parseOrderBookResponse 解析订单簿响应
@@ -605,8 +948,8 @@ func (o *OKXExecutor) parseOrderBookResponse(response map[string]interface{}, sy
 	if bids, ok := bookData["bids"].([]interface{}); ok {
 		for _, bid := range bids {
 			if bidArray, ok := bid.([]interface{}); ok && len(bidArray) >= 2 {
-				price := parseFloat(bidArray[0])
-				amount := parseFloat(bidArray[1])
+				price := parseDecimal(bidArray[0])
+				amount := parseDecimal(bidArray[1])
 				orderBook.Bids = append(orderBook.Bids, OrderBookLevel{
 					Price:  price,
 					Amount: amount,
@@ -619,8 +962,8 @@ func (o *OKXExecutor) parseOrderBookResponse(response map[string]interface{}, sy
 	if asks, ok := bookData["asks"].([]interface{}); ok {
 		for _, ask := range asks {
 			if askArray, ok := ask.([]interface{}); ok && len(askArray) >= 2 {
-				price := parseFloat(askArray[0])
-				amount := parseFloat(askArray[1])
+				price := parseDecimal(askArray[0])
+				amount := parseDecimal(askArray[1])
 				orderBook.Asks = append(orderBook.Asks, OrderBookLevel{
 					Price:  price,
 					Amount: amount,
@@ -632,25 +975,47 @@ func (o *OKXExecutor) parseOrderBookResponse(response map[string]interface{}, sy
 	return orderBook, nil
 }
 
-// parseOrderStatus 解析订单状态
-func (o *OKXExecutor) parseOrderStatus(orderData map[string]interface{}) string {
-	state, ok := orderData["state"].(string)
+// parseBalancesResponse 解析账户余额响应
+func (o *OKXExecutor) parseBalancesResponse(response map[string]interface{}) ([]*Balance, error) {
+	// 解析数据数组
+	data, ok := response["data"].([]interface{})
+	if !ok || len(data) == 0 {
+		return nil, fmt.Errorf("响应数据格式错误")
+	}
+
+	accountData, ok := data[0].(map[string]interface{})
 	if !ok {
-		return OrderStatusPending
-	}
-
-	switch state {
-	case "live":
-		return OrderStatusOpen
-	case "partially_filled":
-		return OrderStatusPartiallyFilled
-	case "filled":
-		return OrderStatusFilled
-	case "canceled":
-		return OrderStatusCanceled
-	case "mmp":
-		return OrderStatusFailed
-	default:
-		return OrderStatusPending
+		return nil, fmt.Errorf("账户数据格式错误")
 	}
+
+	details, _ := accountData["details"].([]interface{})
+	balances := make([]*Balance, 0, len(details))
+	for _, item := range details {
+		detail, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		asset, _ := detail["ccy"].(string)
+		balance := &Balance{
+			Exchange: "okx",
+			Asset:    asset,
+			Free:     parseDecimal(detail["availBal"]),
+			Locked:   parseDecimal(detail["frozenBal"]),
+		}
+
+		// 跳过零余额
+		if !balance.Total().IsPositive() {
+			continue
+		}
+		balances = append(balances, balance)
+	}
+
+	return balances, nil
+}
+
+// parseOrderStatus 解析订单状态
+func (o *OKXExecutor) parseOrderStatus(orderData map[string]interface{}) string {
+	state, _ := orderData["state"].(string)
+	return mapExchangeStatus(okxOrderStatuses, state)
 }
@@ -0,0 +1,289 @@
+// Package execution 提供订单执行功能
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// okxPrivateWSURL OKX 私有频道 WebSocket 地址
+const okxPrivateWSURL = "wss://ws.okx.com:8443/ws/v5/private"
+
+// OKXUserStream OKX 私有数据流
+// 登录后订阅 orders（订单）和 account（余额）频道
+type OKXUserStream struct {
+	streamHandlers
+
+	// 执行器（复用其 API 密钥和签名）
+	executor *OKXExecutor
+
+	// WebSocket 地址
+	wsURL string
+
+	// WebSocket 连接
+	stream *wsStream
+
+	// 日志记录器
+	logger logx.Logger
+}
+
+// NewOKXUserStream 创建 OKX 私有数据流
+// 参数:
+//   - executor: OKX 订单执行器
+//   - wsURL: 私有频道 WebSocket 地址（为空时使用生产环境地址）
+func NewOKXUserStream(executor *OKXExecutor, wsURL string) *OKXUserStream {
+	if wsURL == "" {
+		wsURL = okxPrivateWSURL
+	}
+
+	s := &OKXUserStream{
+		executor: executor,
+		wsURL:    wsURL,
+		logger:   logx.WithContext(context.Background()),
+	}
+
+	s.stream = &wsStream{
+		name: "OKX 私有数据流",
+		dialURL: func(ctx context.Context) (string, error) {
+			return s.wsURL, nil
+		},
+		onConnect:    s.login,
+		onMessage:    s.handleMessage,
+		onReconnect:  s.emitReconnect,
+		pingInterval: 25 * time.Second, // OKX 30 秒无数据会断开连接
+		pingMessage:  []byte("ping"),
+		logger:       s.logger,
+	}
+
+	return s
+}
+
+// Start 登录并订阅私有频道
+func (s *OKXUserStream) Start(ctx context.Context) error {
+	if err := s.stream.start(ctx); err != nil {
+		return err
+	}
+
+	s.logger.Infof("OKX 私有数据流已连接")
+	return nil
+}
+
+// Stop 断开私有数据流
+func (s *OKXUserStream) Stop() error {
+	return s.stream.stop()
+}
+
+// IsConnected 检查连接状态
+func (s *OKXUserStream) IsConnected() bool {
+	return s.stream.isConnected()
+}
+
+// login 登录并订阅频道
+func (s *OKXUserStream) login(conn *websocket.Conn) error {
+	if err := okxWSLogin(conn, s.executor); err != nil {
+		return err
+	}
+
+	subscribe := map[string]interface{}{
+		"op": "subscribe",
+		"args": []map[string]string{
+			{"channel": "orders", "instType": "SPOT"},
+			{"channel": "account"},
+		},
+	}
+	if err := conn.WriteJSON(subscribe); err != nil {
+		return fmt.Errorf("订阅私有频道失败: %w", err)
+	}
+
+	return nil
+}
+
+// okxWSEvent OKX WebSocket 事件消息
+type okxWSEvent struct {
+	Event string `json:"event"`
+	Code  string `json:"code"`
+	Msg   string `json:"msg"`
+	Arg   struct {
+		Channel string `json:"channel"`
+	} `json:"arg"`
+	Data []map[string]interface{} `json:"data"`
+}
+
+// handleMessage 处理推送消息
+func (s *OKXUserStream) handleMessage(message []byte) {
+	// 心跳响应
+	if string(message) == "pong" {
+		return
+	}
+
+	var event okxWSEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		s.logger.Errorf("解析 JSON 失败: %v, 消息: %s", err, string(message))
+		return
+	}
+
+	if event.Event == "error" {
+		s.logger.Errorf("OKX 私有数据流错误: %s %s", event.Code, event.Msg)
+		return
+	}
+	if event.Event != "" {
+		// subscribe 等事件确认
+		return
+	}
+
+	switch event.Arg.Channel {
+	case "orders":
+		for _, orderData := range event.Data {
+			s.emitOrder(s.executor.parseOrderData(orderData))
+		}
+	case "account":
+		for _, accountData := range event.Data {
+			s.emitBalances(s.parseAccount(accountData))
+		}
+	}
+}
+
+// parseAccount 解析余额推送
+func (s *OKXUserStream) parseAccount(accountData map[string]interface{}) []*Balance {
+	details, ok := accountData["details"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	balances := make([]*Balance, 0, len(details))
+	for _, item := range details {
+		detail, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		ccy, _ := detail["ccy"].(string)
+		balances = append(balances, &Balance{
+			Exchange: "okx",
+			Asset:    ccy,
+			Free:     parseDecimal(detail["availBal"]),
+			Locked:   parseDecimal(detail["frozenBal"]),
+		})
+	}
+
+	return balances
+}
+
+// okxWSLogin 登录私有频道
+// 登录成功前的请求会被拒绝，所以在读循环启动前同步等待登录结果
+func okxWSLogin(conn *websocket.Conn, executor *OKXExecutor) error {
+	if err := conn.WriteJSON(executor.buildWSLoginRequest()); err != nil {
+		return fmt.Errorf("发送登录请求失败: %w", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	defer conn.SetReadDeadline(time.Time{})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("等待登录结果失败: %w", err)
+		}
+
+		var event okxWSEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			continue
+		}
+
+		if event.Event == "error" {
+			return fmt.Errorf("登录失败: %s %s", event.Code, event.Msg)
+		}
+		if event.Event == "login" {
+			if event.Code != "0" {
+				return fmt.Errorf("登录失败: %s %s", event.Code, event.Msg)
+			}
+			return nil
+		}
+	}
+}
+
+// buildWSLoginRequest 构建 WebSocket 登录请求
+// 签名字符串: timestamp + "GET" + "/users/self/verify"，timestamp 为 Unix 秒
+func (o *OKXExecutor) buildWSLoginRequest() map[string]interface{} {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := o.generateSignature(timestamp + "GET" + "/users/self/verify")
+
+	return map[string]interface{}{
+		"op": "login",
+		"args": []map[string]string{
+			{
+				"apiKey":     o.apiKey,
+				"passphrase": o.passphrase,
+				"timestamp":  timestamp,
+				"sign":       signature,
+			},
+		},
+	}
+}
+
+// StartUserStream 启动私有数据流
+// 启动后 QueryOrder 优先返回推送的订单状态，WaitOrder 不再轮询 REST
+// 参数:
+//   - ctx: 上下文对象（取消后数据流停止）
+//   - wsURL: 私有频道 WebSocket 地址（为空时使用生产环境地址）
+// 返回:
+//   - *OKXUserStream: 私有数据流（可注册余额更新回调）
+//   - error: 错误信息
+func (o *OKXExecutor) StartUserStream(ctx context.Context, wsURL string) (*OKXUserStream, error) {
+	stream := NewOKXUserStream(o, wsURL)
+	if err := o.attachStream(stream, o.fetchOrder); err != nil {
+		return nil, err
+	}
+
+	if err := stream.Start(ctx); err != nil {
+		o.detachStream()
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+// StopUserStream 停止私有数据流
+func (o *OKXExecutor) StopUserStream() error {
+	stream := o.detachStream()
+	if stream == nil {
+		return fmt.Errorf("私有数据流未启动")
+	}
+	return stream.Stop()
+}
+
+// Connect 启动 EnableUserStream 启用的私有数据流（未启用时不做任何事）
+func (o *OKXExecutor) Connect(ctx context.Context) error {
+	wsURL, ok := o.userStreamConf()
+	if !ok {
+		return nil
+	}
+	_, err := o.StartUserStream(ctx, wsURL)
+	return err
+}
+
+// Close 停止 EnableUserStream 启用的私有数据流并清零 API Secret（之后不能再发送签名请求）
+func (o *OKXExecutor) Close() error {
+	err := o.closeUserStream()
+	o.secret.wipe()
+	return err
+}
+
+// closeUserStream 停止 EnableUserStream 启用的私有数据流
+func (o *OKXExecutor) closeUserStream() error {
+	if _, ok := o.userStreamConf(); !ok {
+		return nil
+	}
+	return o.StopUserStream()
+}
+
+// WaitOrder 等待订单进入终态（完全成交、已撤销或失败）
+func (o *OKXExecutor) WaitOrder(ctx context.Context, exchange, orderID string) (*Order, error) {
+	return o.waitOrder(ctx, orderID, o.fetchOrder)
+}
@@ -0,0 +1,211 @@
+// Package execution 提供订单执行功能
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// okxBatchOrderLimit OKX 批量下单单次最多订单数
+const okxBatchOrderLimit = 20
+
+// OKXWSExecutor OKX WebSocket 下单执行器
+// 在私有频道上使用 order / batch-orders 操作下单，复用常驻的已登录连接；
+// 撤单、查询、订单簿等其他操作沿用内嵌的 REST 执行器
+type OKXWSExecutor struct {
+	*OKXExecutor
+
+	// 请求/响应客户端
+	rpc *wsRPC
+
+	// 私有频道 WebSocket 地址
+	wsURL string
+}
+
+// NewOKXWSExecutor 创建 OKX WebSocket 下单执行器
+// 参数:
+//   - executor: OKX REST 执行器（提供 API 密钥、签名和其他操作）
+//   - wsURL: 私有频道 WebSocket 地址（为空时使用生产环境地址）
+// 返回:
+//   - *OKXWSExecutor: OKX WebSocket 下单执行器实例
+func NewOKXWSExecutor(executor *OKXExecutor, wsURL string) *OKXWSExecutor {
+	if wsURL == "" {
+		wsURL = okxPrivateWSURL
+	}
+
+	o := &OKXWSExecutor{
+		OKXExecutor: executor,
+		wsURL:       wsURL,
+	}
+
+	o.rpc = newWSRPC(&wsStream{
+		name: "OKX WebSocket 下单",
+		dialURL: func(ctx context.Context) (string, error) {
+			return o.wsURL, nil
+		},
+		onConnect: func(conn *websocket.Conn) error {
+			return okxWSLogin(conn, executor)
+		},
+		pingInterval: 25 * time.Second,
+		pingMessage:  []byte("ping"),
+		logger:       executor.logger,
+	})
+
+	return o
+}
+
+// Connect 建立私有频道连接并登录（断线后自动重连）
+// 启用了私有数据流时同时启动私有数据流
+func (o *OKXWSExecutor) Connect(ctx context.Context) error {
+	if err := o.OKXExecutor.Connect(ctx); err != nil {
+		return err
+	}
+	if err := o.rpc.stream.start(ctx); err != nil {
+		o.closeUserStream()
+		return err
+	}
+	return nil
+}
+
+// Close 断开连接（启用了私有数据流时同时停止）
+func (o *OKXWSExecutor) Close() error {
+	err := o.rpc.stream.stop()
+	if streamErr := o.OKXExecutor.Close(); err == nil {
+		err = streamErr
+	}
+	return err
+}
+
+// IsConnected 检查连接状态
+func (o *OKXWSExecutor) IsConnected() bool {
+	return o.rpc.stream.isConnected()
+}
+
+// PlaceOrder 通过 WebSocket 下单
+// 连接未建立时回退到 REST 下单
+func (o *OKXWSExecutor) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*Order, error) {
+	if !o.IsConnected() {
+		o.logger.Errorf("OKX WebSocket 未连接，使用 REST 下单")
+		return o.OKXExecutor.PlaceOrder(ctx, req)
+	}
+
+	// 参数校验
+	if err := o.validatePlaceOrderRequest(req); err != nil {
+		return nil, fmt.Errorf("参数校验失败: %w", err)
+	}
+
+	data, err := o.send(ctx, "order", []*PlaceOrderRequest{req})
+	if err != nil {
+		return nil, fmt.Errorf("下单失败: %w", err)
+	}
+
+	order, err := o.parsePlacedOrder(data[0], req)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.trackOrder(order), nil
+}
+
+// PlaceOrders 通过 batch-orders 批量下单（一次往返，最多 20 个订单）
+// 单个订单失败不影响其他订单：返回的订单与请求一一对应，失败的订单状态为 failed 并带有错误信息
+// 参数:
+//   - ctx: 上下文对象
+//   - reqs: 下单请求列表
+// 返回:
+//   - []*Order: 订单列表（与 reqs 顺序一致）
+//   - error: 参数错误或整个请求失败时返回
+func (o *OKXWSExecutor) PlaceOrders(ctx context.Context, reqs []*PlaceOrderRequest) ([]*Order, error) {
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("下单请求不能为空")
+	}
+	if len(reqs) > okxBatchOrderLimit {
+		return nil, fmt.Errorf("批量下单最多 %d 个订单，当前 %d 个", okxBatchOrderLimit, len(reqs))
+	}
+	if !o.IsConnected() {
+		return nil, fmt.Errorf("OKX WebSocket 未连接")
+	}
+
+	for i, req := range reqs {
+		if err := o.validatePlaceOrderRequest(req); err != nil {
+			return nil, fmt.Errorf("第 %d 个订单参数校验失败: %w", i+1, err)
+		}
+	}
+
+	data, err := o.send(ctx, "batch-orders", reqs)
+	if err != nil {
+		return nil, fmt.Errorf("批量下单失败: %w", err)
+	}
+	if len(data) != len(reqs) {
+		return nil, fmt.Errorf("批量下单响应数量不匹配: 请求 %d 个，响应 %d 个", len(reqs), len(data))
+	}
+
+	orders := make([]*Order, len(reqs))
+	for i, req := range reqs {
+		order, err := o.parsePlacedOrder(data[i], req)
+		if err != nil {
+			order = &Order{
+				Exchange:      "okx",
+				Symbol:        req.Symbol,
+				Side:          req.Side,
+				Type:          req.Type,
+				Price:         req.Price,
+				Amount:        req.Amount,
+				ClientOrderID: req.ClientOrderID,
+				Status:        OrderStatusFailed,
+				ErrorMessage:  err.Error(),
+				CreatedAt:     time.Now(),
+				UpdatedAt:     time.Now(),
+			}
+		} else {
+			order = o.trackOrder(order)
+		}
+		orders[i] = order
+	}
+
+	return orders, nil
+}
+
+// send 发送下单操作并返回每个订单的结果
+// code 为 "0" 表示全部成功，"2" 表示部分成功（逐个检查 sCode），"1" 表示全部失败
+func (o *OKXWSExecutor) send(ctx context.Context, op string, reqs []*PlaceOrderRequest) ([]map[string]interface{}, error) {
+	args := make([]map[string]interface{}, len(reqs))
+	for i, req := range reqs {
+		args[i] = o.buildOrderParams(req)
+	}
+
+	id := o.rpc.newID()
+	message, err := o.rpc.call(ctx, id, map[string]interface{}{
+		"id":   id,
+		"op":   op,
+		"args": args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Code string                   `json:"code"`
+		Msg  string                   `json:"msg"`
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(message, &response); err != nil {
+		return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+	}
+
+	if len(response.Data) == 0 {
+		return nil, fmt.Errorf("OKX API 错误: %s %s", response.Code, response.Msg)
+	}
+
+	// 只有一个订单且全部失败时，直接返回该订单的错误
+	if response.Code == "1" && len(reqs) == 1 {
+		sMsg, _ := response.Data[0]["sMsg"].(string)
+		return nil, fmt.Errorf("OKX API 错误: %s %s", response.Msg, sMsg)
+	}
+
+	return response.Data, nil
+}
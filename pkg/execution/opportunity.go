@@ -0,0 +1,25 @@
+package execution
+
+import "arbitragex/pkg/engine"
+
+// FromEngineOpportunity 将套利引擎识别的机会转换为执行器的套利机会
+func FromEngineOpportunity(opp *engine.ArbitrageOpportunity) *ArbitrageOpportunity {
+	return &ArbitrageOpportunity{
+		ID:            opp.ID,
+		Symbol:        opp.Symbol,
+		BuyExchange:   opp.BuyExchange,
+		SellExchange:  opp.SellExchange,
+		BuyPrice:      opp.BuyPrice,
+		SellPrice:     opp.SellPrice,
+		PriceDiff:     opp.PriceDiff,
+		PriceDiffRate: opp.PriceDiffRate,
+		RevenueRate:   opp.RevenueRate,
+		EstRevenue:    opp.EstRevenue,
+		EstCost:       opp.EstCost,
+		NetProfit:     opp.NetProfit,
+		ProfitRate:    opp.ProfitRate,
+		RiskScore:     opp.RiskScore,
+		OverallScore:  opp.Score,
+		DiscoveredAt:  opp.DiscoveredAt,
+	}
+}
@@ -0,0 +1,320 @@
+// Package execution 提供订单执行功能
+package execution
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"arbitragex/common/decimal"
+)
+
+// ErrIllegalTransition 非法的订单状态迁移（如终态回到挂单）
+var ErrIllegalTransition = fmt.Errorf("illegal order status transition")
+
+// ErrFilledAmountDecreased 成交数量倒退（过期的查询结果或乱序推送）
+var ErrFilledAmountDecreased = fmt.Errorf("order filled amount decreased")
+
+// orderTransitions 合法的订单状态迁移
+//
+//	pending ──> open ──> partially_filled ──> filled
+//	   │          │             │
+//	   │          └─────────────┴──> canceled
+//	   └──> failed
+//
+// pending 可以直接进入任意状态（下单响应可能已经是成交或撤销）；
+// partially_filled 到自身表示成交数量增加
+var orderTransitions = map[string][]string{
+	OrderStatusPending:         {OrderStatusOpen, OrderStatusPartiallyFilled, OrderStatusFilled, OrderStatusCanceled, OrderStatusFailed},
+	OrderStatusOpen:            {OrderStatusPartiallyFilled, OrderStatusFilled, OrderStatusCanceled},
+	OrderStatusPartiallyFilled: {OrderStatusPartiallyFilled, OrderStatusFilled, OrderStatusCanceled},
+	OrderStatusFilled:          {},
+	OrderStatusCanceled:        {},
+	OrderStatusFailed:          {},
+}
+
+// dbOrderStatuses 订单状态与 MySQL orders.status 的对应关系
+var dbOrderStatuses = map[string]string{
+	OrderStatusPending:         "pending",
+	OrderStatusOpen:            "submitted",
+	OrderStatusPartiallyFilled: "partial",
+	OrderStatusFilled:          "filled",
+	OrderStatusCanceled:        "cancelled",
+	OrderStatusFailed:          "failed",
+}
+
+// binanceOrderStatuses Binance 订单状态映射
+var binanceOrderStatuses = map[string]string{
+	"PENDING_NEW":      OrderStatusPending,
+	"NEW":              OrderStatusOpen,
+	"PARTIALLY_FILLED": OrderStatusPartiallyFilled,
+	"FILLED":           OrderStatusFilled,
+	"CANCELED":         OrderStatusCanceled,
+	"EXPIRED":          OrderStatusCanceled, // IOC / FOK 未成交部分过期属于正常撤销
+	"EXPIRED_IN_MATCH": OrderStatusCanceled, // 自成交保护撤销
+	"REJECTED":         OrderStatusFailed,
+}
+
+// okxOrderStatuses OKX 订单状态映射
+var okxOrderStatuses = map[string]string{
+	"live":             OrderStatusOpen,
+	"partially_filled": OrderStatusPartiallyFilled,
+	"filled":           OrderStatusFilled,
+	"canceled":         OrderStatusCanceled,
+	"mmp_canceled":     OrderStatusCanceled,
+}
+
+// mapExchangeStatus 将交易所订单状态映射为统一状态
+// 未知状态（包括下单响应中没有状态字段）视为 pending，状态机不会让已有订单回到 pending
+func mapExchangeStatus(statuses map[string]string, exchangeStatus string) string {
+	if status, ok := statuses[exchangeStatus]; ok {
+		return status
+	}
+	return OrderStatusPending
+}
+
+// IsValidOrderStatus 判断是否为已知的订单状态
+func IsValidOrderStatus(status string) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// CanTransition 判断订单状态能否从 from 迁移到 to
+// from 为空表示新订单，可以进入任意已知状态
+func CanTransition(from, to string) bool {
+	if !IsValidOrderStatus(to) {
+		return false
+	}
+	if from == "" {
+		return true
+	}
+
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ToDBStatus 转换为数据库订单状态
+func ToDBStatus(status string) (string, error) {
+	dbStatus, ok := dbOrderStatuses[status]
+	if !ok {
+		return "", fmt.Errorf("未知的订单状态: %s", status)
+	}
+	return dbStatus, nil
+}
+
+// FromDBStatus 从数据库订单状态转换
+func FromDBStatus(dbStatus string) (string, error) {
+	for status, value := range dbOrderStatuses {
+		if value == dbStatus {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("未知的数据库订单状态: %s", dbStatus)
+}
+
+// OrderTransition 一次订单状态迁移
+type OrderTransition struct {
+	// OrderID 订单ID
+	OrderID string `json:"order_id"`
+
+	// From 原状态（新订单为空）
+	From string `json:"from"`
+
+	// To 新状态（与 From 相同表示部分成交数量增加）
+	To string `json:"to"`
+
+	// FilledAmount 迁移后的已成交数量
+	FilledAmount decimal.Decimal `json:"filled_amount"`
+
+	// At 迁移时间
+	At time.Time `json:"at"`
+}
+
+// OrderTransitionHook 状态迁移回调
+type OrderTransitionHook func(order *Order, transition OrderTransition)
+
+// OrderStateMachine 订单状态机
+// 所有订单状态变化（下单响应、REST 查询、私有数据流推送、持久化恢复）都经过 Apply，
+// 保证状态只按合法路径前进、成交数量只增不减
+type OrderStateMachine struct {
+	hooks []OrderTransitionHook
+	mu    sync.RWMutex
+}
+
+// NewOrderStateMachine 创建订单状态机
+func NewOrderStateMachine() *OrderStateMachine {
+	return &OrderStateMachine{}
+}
+
+// OnTransition 注册状态迁移回调
+func (m *OrderStateMachine) OnTransition(hook OrderTransitionHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+}
+
+// Apply 将订单更新应用到当前状态
+// 参数:
+//   - current: 当前订单（新订单为 nil）
+//   - update: 交易所返回的最新订单信息
+// 返回:
+//   - *Order: 合并后的订单（出错时为 current 的副本）
+//   - *OrderTransition: 发生的迁移（状态和成交数量都没变化时为 nil）
+//   - error: ErrIllegalTransition / ErrFilledAmountDecreased
+func (m *OrderStateMachine) Apply(current, update *Order) (*Order, *OrderTransition, error) {
+	if update == nil {
+		return nil, nil, fmt.Errorf("订单更新不能为空")
+	}
+	if !IsValidOrderStatus(update.Status) {
+		return current.Clone(), nil, fmt.Errorf("%w: 未知状态 %s", ErrIllegalTransition, update.Status)
+	}
+
+	at := update.UpdatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	// 新订单
+	if current == nil {
+		order := update.Clone()
+		order.UpdatedAt = at
+		order.setStatusTime(order.Status, at)
+		return order, &OrderTransition{
+			OrderID:      order.ID,
+			To:           order.Status,
+			FilledAmount: order.FilledAmount,
+			At:           at,
+		}, nil
+	}
+
+	if update.FilledAmount.LessThan(current.FilledAmount) {
+		return current.Clone(), nil, fmt.Errorf("%w: %s %.8f -> %.8f",
+			ErrFilledAmountDecreased, current.ID, current.FilledAmount, update.FilledAmount)
+	}
+
+	statusChanged := update.Status != current.Status
+	filledChanged := update.FilledAmount.GreaterThan(current.FilledAmount)
+
+	if statusChanged && !CanTransition(current.Status, update.Status) {
+		return current.Clone(), nil, fmt.Errorf("%w: %s %s -> %s",
+			ErrIllegalTransition, current.ID, current.Status, update.Status)
+	}
+	if !statusChanged && filledChanged && !CanTransition(current.Status, current.Status) {
+		return current.Clone(), nil, fmt.Errorf("%w: %s 在 %s 状态下成交数量变化",
+			ErrIllegalTransition, current.ID, current.Status)
+	}
+
+	order := current.merge(update)
+	if !statusChanged && !filledChanged {
+		return order, nil, nil
+	}
+
+	order.UpdatedAt = at
+	if statusChanged {
+		order.setStatusTime(order.Status, at)
+	}
+
+	return order, &OrderTransition{
+		OrderID:      order.ID,
+		From:         current.Status,
+		To:           order.Status,
+		FilledAmount: order.FilledAmount,
+		At:           at,
+	}, nil
+}
+
+// notify 通知状态迁移回调
+func (m *OrderStateMachine) notify(order *Order, transition *OrderTransition) {
+	if transition == nil {
+		return
+	}
+
+	m.mu.RLock()
+	hooks := m.hooks
+	m.mu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(order.Clone(), *transition)
+	}
+}
+
+// Clone 复制订单（包括状态时间）
+func (o *Order) Clone() *Order {
+	if o == nil {
+		return nil
+	}
+
+	copied := *o
+	if o.StatusTimes != nil {
+		copied.StatusTimes = make(map[string]time.Time, len(o.StatusTimes))
+		for status, at := range o.StatusTimes {
+			copied.StatusTimes[status] = at
+		}
+	}
+	return &copied
+}
+
+// setStatusTime 记录进入某个状态的时间
+func (o *Order) setStatusTime(status string, at time.Time) {
+	if o.StatusTimes == nil {
+		o.StatusTimes = make(map[string]time.Time)
+	}
+	if _, ok := o.StatusTimes[status]; !ok {
+		o.StatusTimes[status] = at
+	}
+}
+
+// merge 合并订单更新
+// 推送和查询结果可能缺少部分字段（如客户端订单ID、下单价格），缺少时保留当前值
+func (o *Order) merge(update *Order) *Order {
+	merged := o.Clone()
+
+	merged.Status = update.Status
+	merged.FilledAmount = update.FilledAmount
+
+	if update.AveragePrice.IsPositive() {
+		merged.AveragePrice = update.AveragePrice
+	}
+	if update.Fee.IsPositive() {
+		merged.Fee = update.Fee
+	}
+	if update.FeeCurrency != "" {
+		merged.FeeCurrency = update.FeeCurrency
+	}
+	if update.Amount.IsPositive() {
+		merged.Amount = update.Amount
+	}
+	if update.Price.IsPositive() {
+		merged.Price = update.Price
+	}
+	if update.ExchangeOrderID != "" {
+		merged.ExchangeOrderID = update.ExchangeOrderID
+	}
+	if update.ClientOrderID != "" {
+		merged.ClientOrderID = update.ClientOrderID
+	}
+	if update.ErrorMessage != "" {
+		merged.ErrorMessage = update.ErrorMessage
+	}
+	if merged.Symbol == "" {
+		merged.Symbol = update.Symbol
+	}
+	if merged.Side == "" {
+		merged.Side = update.Side
+	}
+	if merged.Type == "" {
+		merged.Type = update.Type
+	}
+	if merged.TimeInForce == "" {
+		merged.TimeInForce = update.TimeInForce
+	}
+	if merged.CreatedAt.IsZero() {
+		merged.CreatedAt = update.CreatedAt
+	}
+
+	return merged
+}
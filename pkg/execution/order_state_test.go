@@ -0,0 +1,175 @@
+// Package execution 订单状态机单元测试
+package execution
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"arbitragex/common/decimal"
+)
+
+// TestCanTransition 测试状态迁移表
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{"", OrderStatusFilled, true},
+		{"", "unknown", false},
+		{OrderStatusPending, OrderStatusOpen, true},
+		{OrderStatusPending, OrderStatusFailed, true},
+		{OrderStatusOpen, OrderStatusPartiallyFilled, true},
+		{OrderStatusOpen, OrderStatusPending, false},
+		{OrderStatusOpen, OrderStatusFailed, false},
+		{OrderStatusPartiallyFilled, OrderStatusPartiallyFilled, true},
+		{OrderStatusPartiallyFilled, OrderStatusOpen, false},
+		{OrderStatusFilled, OrderStatusCanceled, false},
+		{OrderStatusCanceled, OrderStatusOpen, false},
+		{OrderStatusFailed, OrderStatusPending, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+// TestOrderStateMachine_Apply 测试状态推进、成交数量单调和迁移时间
+func TestOrderStateMachine_Apply(t *testing.T) {
+	machine := NewOrderStateMachine()
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	order, transition, err := machine.Apply(nil, &Order{
+		ID: "o1", Symbol: "BTC/USDT", Price: decimal.NewFromFloat(50000), Amount: decimal.NewFromFloat(1), ClientOrderID: "c1",
+		Status: OrderStatusOpen, UpdatedAt: t0,
+	})
+	if err != nil || transition == nil || transition.From != "" || transition.To != OrderStatusOpen {
+		t.Fatalf("Apply(new) = %+v, %v", transition, err)
+	}
+
+	// 部分成交，推送不带客户端订单ID和价格
+	order, transition, err = machine.Apply(order, &Order{
+		ID: "o1", Status: OrderStatusPartiallyFilled, FilledAmount: decimal.NewFromFloat(0.4), UpdatedAt: t0.Add(time.Second),
+	})
+	if err != nil || transition == nil || transition.From != OrderStatusOpen {
+		t.Fatalf("Apply(partial) = %+v, %v", transition, err)
+	}
+	if order.ClientOrderID != "c1" || !order.Price.Equal(decimal.NewFromFloat(50000)) {
+		t.Errorf("merge lost fields: %+v", order)
+	}
+
+	// 部分成交数量增加
+	order, transition, err = machine.Apply(order, &Order{
+		ID: "o1", Status: OrderStatusPartiallyFilled, FilledAmount: decimal.NewFromFloat(0.6), UpdatedAt: t0.Add(2 * time.Second),
+	})
+	if err != nil || transition == nil || !transition.FilledAmount.Equal(decimal.NewFromFloat(0.6)) {
+		t.Fatalf("Apply(partial increase) = %+v, %v", transition, err)
+	}
+
+	// 过期的查询结果：成交数量倒退
+	_, _, err = machine.Apply(order, &Order{ID: "o1", Status: OrderStatusPartiallyFilled, FilledAmount: decimal.NewFromFloat(0.4)})
+	if !errors.Is(err, ErrFilledAmountDecreased) {
+		t.Errorf("Apply(decrease) error = %v, want ErrFilledAmountDecreased", err)
+	}
+
+	// 状态倒退
+	_, _, err = machine.Apply(order, &Order{ID: "o1", Status: OrderStatusOpen, FilledAmount: decimal.NewFromFloat(0.6)})
+	if !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("Apply(backwards) error = %v, want ErrIllegalTransition", err)
+	}
+
+	// 重复推送不产生迁移
+	_, transition, err = machine.Apply(order, &Order{ID: "o1", Status: OrderStatusPartiallyFilled, FilledAmount: decimal.NewFromFloat(0.6)})
+	if err != nil || transition != nil {
+		t.Errorf("Apply(duplicate) = %+v, %v, want no transition", transition, err)
+	}
+
+	order, _, err = machine.Apply(order, &Order{
+		ID: "o1", Status: OrderStatusFilled, FilledAmount: decimal.NewFromFloat(1), UpdatedAt: t0.Add(3 * time.Second),
+	})
+	if err != nil {
+		t.Fatalf("Apply(filled) error = %v", err)
+	}
+
+	// 终态之后不能撤销
+	_, _, err = machine.Apply(order, &Order{ID: "o1", Status: OrderStatusCanceled, FilledAmount: decimal.NewFromFloat(1)})
+	if !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("Apply(after final) error = %v, want ErrIllegalTransition", err)
+	}
+
+	wantTimes := map[string]time.Time{
+		OrderStatusOpen:            t0,
+		OrderStatusPartiallyFilled: t0.Add(time.Second),
+		OrderStatusFilled:          t0.Add(3 * time.Second),
+	}
+	for status, want := range wantTimes {
+		if got := order.StatusTimes[status]; !got.Equal(want) {
+			t.Errorf("StatusTimes[%s] = %v, want %v", status, got, want)
+		}
+	}
+}
+
+// TestOrderTracker_OnTransition 测试状态迁移回调
+func TestOrderTracker_OnTransition(t *testing.T) {
+	tracker := NewOrderTracker()
+
+	var transitions []OrderTransition
+	tracker.OnTransition(func(order *Order, transition OrderTransition) {
+		transitions = append(transitions, transition)
+	})
+
+	tracker.Update(&Order{ID: "o1", Status: OrderStatusOpen})
+	tracker.Update(&Order{ID: "o1", Status: OrderStatusFilled, FilledAmount: decimal.NewFromFloat(1)})
+	tracker.Update(&Order{ID: "o1", Status: OrderStatusOpen}) // 被拒绝
+
+	if _, err := tracker.Apply(&Order{ID: "o1", Status: OrderStatusCanceled, FilledAmount: decimal.NewFromFloat(1)}); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("Apply() error = %v, want ErrIllegalTransition", err)
+	}
+
+	if len(transitions) != 2 {
+		t.Fatalf("len(transitions) = %d, want 2", len(transitions))
+	}
+	if transitions[1].From != OrderStatusOpen || transitions[1].To != OrderStatusFilled {
+		t.Errorf("transitions[1] = %+v", transitions[1])
+	}
+	if order, _ := tracker.Get("o1"); order.Status != OrderStatusFilled {
+		t.Errorf("Status = %s, want filled", order.Status)
+	}
+}
+
+// TestDBStatus 测试数据库状态转换
+func TestDBStatus(t *testing.T) {
+	for status := range orderTransitions {
+		dbStatus, err := ToDBStatus(status)
+		if err != nil {
+			t.Fatalf("ToDBStatus(%s) error = %v", status, err)
+		}
+		back, err := FromDBStatus(dbStatus)
+		if err != nil || back != status {
+			t.Errorf("FromDBStatus(%s) = %s, %v, want %s", dbStatus, back, err, status)
+		}
+	}
+
+	if got, _ := ToDBStatus(OrderStatusPartiallyFilled); got != "partial" {
+		t.Errorf("ToDBStatus(partially_filled) = %s, want partial", got)
+	}
+	if _, err := FromDBStatus("unknown"); err == nil {
+		t.Error("FromDBStatus(unknown) should return error")
+	}
+}
+
+// TestMapExchangeStatus 测试交易所状态映射
+func TestMapExchangeStatus(t *testing.T) {
+	if got := mapExchangeStatus(binanceOrderStatuses, "EXPIRED"); got != OrderStatusCanceled {
+		t.Errorf("binance EXPIRED = %s, want canceled", got)
+	}
+	if got := mapExchangeStatus(okxOrderStatuses, "live"); got != OrderStatusOpen {
+		t.Errorf("okx live = %s, want open", got)
+	}
+	if got := mapExchangeStatus(okxOrderStatuses, ""); got != OrderStatusPending {
+		t.Errorf("okx empty = %s, want pending", got)
+	}
+}
@@ -0,0 +1,405 @@
+// Package execution 提供订单执行功能
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// maxTrackedOrders 跟踪的订单数超过此值时清理已进入终态的订单
+const maxTrackedOrders = 10000
+
+// OrderTracker 订单状态跟踪器
+// 缓存下单响应、查询结果和私有数据流推送的最新订单状态，所有更新都经过订单状态机，
+// 并支持等待订单进入终态
+type OrderTracker struct {
+	// 订单ID -> 最新订单
+	orders map[string]*Order
+
+	// 订单ID -> 等待者（订单更新时关闭通道唤醒）
+	waiters map[string][]chan struct{}
+
+	// 订单状态机
+	machine *OrderStateMachine
+
+	mu sync.RWMutex
+}
+
+// NewOrderTracker 创建订单跟踪器
+func NewOrderTracker() *OrderTracker {
+	return &OrderTracker{
+		orders:  make(map[string]*Order),
+		waiters: make(map[string][]chan struct{}),
+		machine: NewOrderStateMachine(),
+	}
+}
+
+// OnTransition 注册订单状态迁移回调（如持久化）
+func (t *OrderTracker) OnTransition(hook OrderTransitionHook) {
+	t.machine.OnTransition(hook)
+}
+
+// Apply 通过状态机更新订单
+// 返回:
+//   - *Order: 更新后的订单；非法迁移时为当前跟踪的订单
+//   - error: ErrIllegalTransition / ErrFilledAmountDecreased
+func (t *OrderTracker) Apply(order *Order) (*Order, error) {
+	merged, _, err := t.apply(order)
+	return merged, err
+}
+
+// Update 更新订单状态
+// 推送可能乱序或晚于 REST 响应，非法迁移（如终态回到挂单、成交数量倒退）会被忽略
+// 返回:
+//   - bool: 状态或成交数量是否发生了变化
+func (t *OrderTracker) Update(order *Order) bool {
+	_, transition, err := t.apply(order)
+	return err == nil && transition != nil
+}
+
+// apply 更新订单并通知等待者和状态迁移回调
+func (t *OrderTracker) apply(order *Order) (*Order, *OrderTransition, error) {
+	if order == nil || order.ID == "" {
+		return nil, nil, fmt.Errorf("订单ID不能为空")
+	}
+
+	t.mu.Lock()
+	merged, transition, err := t.machine.Apply(t.orders[order.ID], order)
+	if err != nil {
+		t.mu.Unlock()
+		return merged, nil, err
+	}
+
+	t.orders[order.ID] = merged
+	if transition != nil {
+		// 唤醒等待者
+		for _, ch := range t.waiters[order.ID] {
+			close(ch)
+		}
+		delete(t.waiters, order.ID)
+	}
+
+	if len(t.orders) > maxTrackedOrders {
+		t.pruneLocked(order.ID)
+	}
+	t.mu.Unlock()
+
+	t.machine.notify(merged, transition)
+	return merged.Clone(), transition, nil
+}
+
+// pruneLocked 清理已进入终态的订单（保留 keepID），调用方需持有写锁
+func (t *OrderTracker) pruneLocked(keepID string) {
+	for id, order := range t.orders {
+		if id != keepID && IsFinalStatus(order.Status) {
+			delete(t.orders, id)
+		}
+	}
+}
+
+// Get 获取订单的最新状态（返回副本）
+func (t *OrderTracker) Get(orderID string) (*Order, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	order, ok := t.orders[orderID]
+	if !ok {
+		return nil, false
+	}
+
+	return order.Clone(), true
+}
+
+// List 获取所有跟踪的订单（返回副本，按创建时间倒序）
+func (t *OrderTracker) List() []*Order {
+	t.mu.RLock()
+	orders := make([]*Order, 0, len(t.orders))
+	for _, order := range t.orders {
+		orders = append(orders, order.Clone())
+	}
+	t.mu.RUnlock()
+
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].ID < orders[j].ID
+		}
+		return orders[i].CreatedAt.After(orders[j].CreatedAt)
+	})
+	return orders
+}
+
+// Pending 获取未进入终态的订单（返回副本）
+func (t *OrderTracker) Pending() []*Order {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	orders := make([]*Order, 0)
+	for _, order := range t.orders {
+		if !IsFinalStatus(order.Status) {
+			orders = append(orders, order.Clone())
+		}
+	}
+	return orders
+}
+
+// Remove 移除订单（订单处理完成后调用，避免缓存无限增长）
+func (t *OrderTracker) Remove(orderID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.orders, orderID)
+}
+
+// Wait 等待订单满足条件
+// 参数:
+//   - ctx: 上下文对象（用于超时控制）
+//   - orderID: 订单ID
+//   - cond: 判断条件
+// 返回:
+//   - *Order: 满足条件时的订单
+//   - error: ctx 取消时返回 ctx.Err()
+func (t *OrderTracker) Wait(ctx context.Context, orderID string, cond func(order *Order) bool) (*Order, error) {
+	for {
+		t.mu.Lock()
+		if order, ok := t.orders[orderID]; ok && cond(order) {
+			copied := order.Clone()
+			t.mu.Unlock()
+			return copied, nil
+		}
+		ch := make(chan struct{})
+		t.waiters[orderID] = append(t.waiters[orderID], ch)
+		t.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ch:
+		}
+	}
+}
+
+// WaitFinal 等待订单进入终态（完全成交、已撤销或失败）
+func (t *OrderTracker) WaitFinal(ctx context.Context, orderID string) (*Order, error) {
+	return t.Wait(ctx, orderID, func(order *Order) bool {
+		return IsFinalStatus(order.Status)
+	})
+}
+
+// IsFinalStatus 判断订单状态是否为终态
+func IsFinalStatus(status string) bool {
+	switch status {
+	case OrderStatusFilled, OrderStatusCanceled, OrderStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrderWaiter 支持等待订单终态的执行器
+type OrderWaiter interface {
+	// WaitOrder 等待订单进入终态
+	WaitOrder(ctx context.Context, exchange, orderID string) (*Order, error)
+}
+
+// orderPollInterval 未启用私有数据流时轮询订单状态的间隔
+const orderPollInterval = 200 * time.Millisecond
+
+// orderResyncTimeout 私有数据流重连后补查订单的超时
+const orderResyncTimeout = 10 * time.Second
+
+var (
+	// streamCheckInterval 等待推送时检查私有数据流连接状态的间隔
+	streamCheckInterval = time.Second
+
+	// orderStaleAfter 等待推送时订单超过该时间没有更新则通过 REST 查询一次（推送可能丢失）
+	orderStaleAfter = 5 * time.Second
+)
+
+// OrderFetcher 通过 REST 查询订单（不使用推送的状态，结果经过状态机）
+type OrderFetcher func(ctx context.Context, orderID string) (*Order, error)
+
+// orderTracking 执行器的订单跟踪状态（订单状态机 + 私有数据流），由各交易所执行器内嵌
+type orderTracking struct {
+	// 订单跟踪器
+	tracker *OrderTracker
+
+	// 私有数据流（未启动时为空）
+	userStream UserDataStream
+
+	// 是否启用私有数据流（Connect 时启动）及其地址
+	userStreamEnabled bool
+	userStreamURL     string
+
+	streamMu sync.RWMutex
+}
+
+// EnableUserStream 启用私有数据流：Connect 时启动，Close 时停止（需在 Connect 之前调用）
+// 参数:
+//   - wsURL: 私有数据流 WebSocket 地址（为空时使用生产环境地址）
+func (s *orderTracking) EnableUserStream(wsURL string) {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+
+	s.userStreamEnabled = true
+	s.userStreamURL = wsURL
+}
+
+// userStreamConf 启用的私有数据流地址
+// 返回:
+//   - string: 私有数据流地址
+//   - bool: 是否启用了私有数据流
+func (s *orderTracking) userStreamConf() (string, bool) {
+	s.streamMu.RLock()
+	defer s.streamMu.RUnlock()
+	return s.userStreamURL, s.userStreamEnabled
+}
+
+// Tracker 获取订单跟踪器（可注册状态迁移回调）
+func (s *orderTracking) Tracker() *OrderTracker {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+
+	if s.tracker == nil {
+		s.tracker = NewOrderTracker()
+	}
+	return s.tracker
+}
+
+// attachStream 绑定私有数据流，订单推送写入跟踪器
+// 重连后通过 fetch 补查所有未结束的订单，断线期间丢失的推送不会让跟踪的状态一直过期
+func (s *orderTracking) attachStream(stream UserDataStream, fetch OrderFetcher) error {
+	tracker := s.Tracker()
+
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+
+	if s.userStream != nil {
+		return fmt.Errorf("私有数据流已启动")
+	}
+
+	stream.OnOrderUpdate(func(order *Order) {
+		tracker.Update(order)
+	})
+	stream.OnReconnect(func(ctx context.Context) {
+		resyncOrders(ctx, tracker, fetch)
+	})
+	s.userStream = stream
+	return nil
+}
+
+// resyncOrders 通过 REST 补查跟踪器中所有未结束的订单
+func resyncOrders(ctx context.Context, tracker *OrderTracker, fetch OrderFetcher) {
+	ctx, cancel := context.WithTimeout(ctx, orderResyncTimeout)
+	defer cancel()
+
+	for _, order := range tracker.Pending() {
+		if _, err := fetch(ctx, order.ID); err != nil {
+			logx.WithContext(ctx).Errorf("私有数据流重连后补查订单 %s 失败: %v", order.ID, err)
+		}
+	}
+}
+
+// detachStream 解绑私有数据流
+func (s *orderTracking) detachStream() UserDataStream {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+
+	stream := s.userStream
+	s.userStream = nil
+	return stream
+}
+
+// streamConnected 私有数据流是否已连接
+func (s *orderTracking) streamConnected() bool {
+	s.streamMu.RLock()
+	defer s.streamMu.RUnlock()
+	return s.userStream != nil && s.userStream.IsConnected()
+}
+
+// trackOrder 将交易所返回的订单交给状态机
+// 推送可能先于 REST 响应到达，过期的结果不会让状态倒退，此时返回跟踪器中的最新状态
+func (s *orderTracking) trackOrder(order *Order) *Order {
+	merged, err := s.Tracker().Apply(order)
+	if err != nil {
+		if merged != nil {
+			return merged
+		}
+		return order
+	}
+	return merged
+}
+
+// trackedOrder 获取推送的订单状态（私有数据流未连接时返回 false）
+func (s *orderTracking) trackedOrder(orderID string) (*Order, bool) {
+	if !s.streamConnected() {
+		return nil, false
+	}
+	return s.Tracker().Get(orderID)
+}
+
+// waitOrder 等待订单进入终态
+// 私有数据流已连接时等待推送，订单超过 orderStaleAfter 没有更新时通过 REST 查询一次；
+// 数据流未连接或等待中断开时轮询 fetch
+func (s *orderTracking) waitOrder(ctx context.Context, orderID string, fetch OrderFetcher) (*Order, error) {
+	tracker := s.Tracker()
+	last, _ := tracker.Get(orderID)
+	lastChange := time.Now()
+
+	for s.streamConnected() {
+		waitCtx, cancel := context.WithTimeout(ctx, streamCheckInterval)
+		order, err := tracker.WaitFinal(waitCtx, orderID)
+		cancel()
+		if err == nil {
+			return order, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		current, _ := tracker.Get(orderID)
+		if orderChanged(last, current) {
+			last, lastChange = current, time.Now()
+			continue
+		}
+		if time.Since(lastChange) < orderStaleAfter {
+			continue
+		}
+
+		// 推送可能丢失，通过 REST 确认一次
+		lastChange = time.Now()
+		if order, err := fetch(ctx, orderID); err == nil && IsFinalStatus(order.Status) {
+			return order, nil
+		}
+	}
+
+	ticker := time.NewTicker(orderPollInterval)
+	defer ticker.Stop()
+
+	for {
+		order, err := fetch(ctx, orderID)
+		if err == nil && IsFinalStatus(order.Status) {
+			return order, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return nil, fmt.Errorf("等待订单超时: %w (最近一次查询错误: %v)", ctx.Err(), err)
+			}
+			return order, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// orderChanged 订单状态或成交数量是否有变化
+func orderChanged(before, after *Order) bool {
+	if before == nil || after == nil {
+		return before != after
+	}
+	return before.Status != after.Status || !before.FilledAmount.Equal(after.FilledAmount)
+}
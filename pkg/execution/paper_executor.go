@@ -0,0 +1,335 @@
+// Package execution 提供订单执行功能
+package execution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"arbitragex/common/clock"
+	"arbitragex/common/decimal"
+)
+
+// PaperExecutor 模拟成交执行器（回测和演练使用，不连接交易所）
+// 按最新的订单簿快照撮合：买单吃卖盘、卖单吃买盘，成交会消耗快照中的深度，直到下一次快照替换。
+// 市价单和 IOC / FOK 限价单按 Taker 费率计费；未成交的 GTC 限价单挂单等待，之后的快照穿过挂单价格时按 Maker 费率成交
+type PaperExecutor struct {
+	exchange string
+	makerFee float64
+	takerFee float64
+
+	books   map[string]*OrderBook // 交易对 → 订单簿快照
+	orders  map[string]*Order     // 订单ID → 订单
+	resting []string              // 挂单中的订单ID（按下单顺序）
+	nextID  int64
+	clock   clock.Clock
+
+	mu sync.Mutex
+}
+
+// NewPaperExecutor 创建模拟成交执行器
+// 参数:
+//   - exchange: 模拟的交易所名称
+//   - makerFee: 挂单费率（如 0.001 = 0.1%）
+//   - takerFee: 吃单费率
+// 返回:
+//   - *PaperExecutor: 模拟成交执行器
+func NewPaperExecutor(exchange string, makerFee, takerFee float64) *PaperExecutor {
+	return &PaperExecutor{
+		exchange: exchange,
+		makerFee: makerFee,
+		takerFee: takerFee,
+		books:    make(map[string]*OrderBook),
+		orders:   make(map[string]*Order),
+		clock:    clock.Real,
+	}
+}
+
+// SetClock 设置时钟（回测时使用按行情时间推进的手动时钟）
+func (p *PaperExecutor) SetClock(clk clock.Clock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clock = clock.OrReal(clk)
+}
+
+// UpdateOrderBook 更新订单簿快照，并撮合穿过价格的挂单
+func (p *PaperExecutor) UpdateOrderBook(book *OrderBook) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	copied := copyOrderBook(book)
+	p.books[book.Symbol] = copied
+
+	resting := p.resting[:0]
+	for _, id := range p.resting {
+		order := p.orders[id]
+		if order.Symbol == book.Symbol {
+			p.fill(order, copied, p.makerFee, decimal.Zero)
+			if order.FilledAmount.GreaterThanOrEqual(order.Amount) {
+				order.Status = OrderStatusFilled
+				continue
+			}
+		}
+		resting = append(resting, id)
+	}
+	p.resting = resting
+}
+
+// PlaceOrder 下单（立即按当前订单簿快照撮合）
+func (p *PaperExecutor) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*Order, error) {
+	if err := p.validatePlaceOrderRequest(req); err != nil {
+		return nil, fmt.Errorf("参数校验失败: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	book, ok := p.books[req.Symbol]
+	if !ok {
+		return nil, fmt.Errorf("下单失败: 没有 %s %s 的订单簿", p.exchange, req.Symbol)
+	}
+
+	p.nextID++
+	now := p.clock.Now()
+	order := &Order{
+		ID:            fmt.Sprintf("%s:%s:%d", p.exchange, strings.ReplaceAll(req.Symbol, "/", ""), p.nextID),
+		Exchange:      p.exchange,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		PostOnly:      req.PostOnly,
+		Price:         req.Price,
+		Amount:        req.Amount,
+		FeeCurrency:   quoteAsset(req.Symbol),
+		Status:        OrderStatusOpen,
+		ClientOrderID: req.ClientOrderID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	order.ExchangeOrderID = fmt.Sprintf("%d", p.nextID)
+	if req.Type == OrderTypeLimit {
+		order.TimeInForce = req.TimeInForce
+		if order.TimeInForce == "" {
+			order.TimeInForce = TimeInForceGTC
+		}
+	}
+	p.orders[order.ID] = order
+
+	switch {
+	case req.PostOnly && crosses(order, book):
+		// 只做 Maker 订单会立即成交时被交易所撤销
+		order.Status = OrderStatusCanceled
+	case order.TimeInForce == TimeInForceFOK && fillable(order, book).LessThan(order.Amount):
+		order.Status = OrderStatusCanceled
+	default:
+		p.fill(order, book, p.takerFee, req.QuoteAmount)
+		p.settle(order)
+	}
+
+	return order.Clone(), nil
+}
+
+// settle 下单撮合后确定订单状态：全部成交、挂单或撤销剩余部分
+func (p *PaperExecutor) settle(order *Order) {
+	if order.Type == OrderTypeMarket && order.Amount.IsZero() {
+		// 按金额下单的市价单以实际成交数量为订单数量
+		order.Amount = order.FilledAmount
+	}
+
+	switch {
+	case order.FilledAmount.IsPositive() && order.FilledAmount.GreaterThanOrEqual(order.Amount):
+		order.Status = OrderStatusFilled
+	case order.Type == OrderTypeLimit && order.TimeInForce == TimeInForceGTC:
+		p.resting = append(p.resting, order.ID)
+		if order.FilledAmount.IsPositive() {
+			order.Status = OrderStatusPartiallyFilled
+		}
+	default:
+		// 市价单和 IOC 未成交的部分撤销
+		order.Status = OrderStatusCanceled
+	}
+}
+
+// fill 按订单簿撮合订单，成交会消耗订单簿深度
+// quoteAmount 大于 0 时按计价货币金额成交（按金额下单的市价单）
+func (p *PaperExecutor) fill(order *Order, book *OrderBook, feeRate float64, quoteAmount decimal.Decimal) {
+	levels := &book.Asks
+	if order.Side == OrderSideSell {
+		levels = &book.Bids
+	}
+
+	byQuote := quoteAmount.IsPositive()
+	fee := decimal.NewFromFloat(feeRate)
+	remaining := order.Amount.Sub(order.FilledAmount)
+	notional := order.AveragePrice.Mul(order.FilledAmount)
+	filled := order.FilledAmount
+
+	for len(*levels) > 0 {
+		if byQuote {
+			if !quoteAmount.IsPositive() {
+				break
+			}
+		} else if !remaining.IsPositive() {
+			break
+		}
+
+		level := &(*levels)[0]
+		if order.Type == OrderTypeLimit && !priceAcceptable(order, level.Price) {
+			break
+		}
+
+		qty := decimal.Min(remaining, level.Amount)
+		if byQuote {
+			qty = decimal.Min(level.Amount, quoteAmount.Div(level.Price))
+			quoteAmount = quoteAmount.Sub(qty.Mul(level.Price))
+		}
+		if !qty.IsPositive() {
+			break
+		}
+
+		value := qty.Mul(level.Price)
+		filled = filled.Add(qty)
+		remaining = remaining.Sub(qty)
+		notional = notional.Add(value)
+		order.Fee = order.Fee.Add(value.Mul(fee))
+
+		level.Amount = level.Amount.Sub(qty)
+		if !level.Amount.IsPositive() {
+			*levels = (*levels)[1:]
+		}
+	}
+
+	if filled.GreaterThan(order.FilledAmount) {
+		order.FilledAmount = filled
+		order.AveragePrice = notional.Div(filled)
+		order.UpdatedAt = p.clock.Now()
+		if order.Status == OrderStatusOpen {
+			order.Status = OrderStatusPartiallyFilled
+		}
+	}
+}
+
+// CancelOrder 撤单
+func (p *PaperExecutor) CancelOrder(ctx context.Context, exchange, orderID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	order, ok := p.orders[orderID]
+	if !ok {
+		return fmt.Errorf("撤单失败: %w: %s", ErrOrderNotFound, orderID)
+	}
+	if order.Status != OrderStatusOpen && order.Status != OrderStatusPartiallyFilled {
+		return fmt.Errorf("撤单失败: 订单已是终态 %s", order.Status)
+	}
+
+	order.Status = OrderStatusCanceled
+	order.UpdatedAt = p.clock.Now()
+	for i, id := range p.resting {
+		if id == orderID {
+			p.resting = append(p.resting[:i], p.resting[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// QueryOrder 查询订单状态
+func (p *PaperExecutor) QueryOrder(ctx context.Context, exchange, orderID string) (*Order, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	order, ok := p.orders[orderID]
+	if !ok {
+		return nil, fmt.Errorf("查询订单失败: %w: %s", ErrOrderNotFound, orderID)
+	}
+	return order.Clone(), nil
+}
+
+// GetOrderBook 获取订单簿快照（已扣除模拟成交消耗的深度）
+func (p *PaperExecutor) GetOrderBook(ctx context.Context, exchange, symbol string) (*OrderBook, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	book, ok := p.books[symbol]
+	if !ok {
+		return nil, fmt.Errorf("没有 %s %s 的订单簿", p.exchange, symbol)
+	}
+	return copyOrderBook(book), nil
+}
+
+// validatePlaceOrderRequest 校验下单请求参数
+func (p *PaperExecutor) validatePlaceOrderRequest(req *PlaceOrderRequest) error {
+	if req == nil {
+		return fmt.Errorf("下单请求不能为空")
+	}
+	if req.Exchange != p.exchange {
+		return fmt.Errorf("交易所不匹配: %s", req.Exchange)
+	}
+	if req.Symbol == "" {
+		return fmt.Errorf("交易对不能为空")
+	}
+	if req.Side != OrderSideBuy && req.Side != OrderSideSell {
+		return fmt.Errorf("无效的订单方向: %s", req.Side)
+	}
+	if req.Type != OrderTypeLimit && req.Type != OrderTypeMarket {
+		return fmt.Errorf("无效的订单类型: %s", req.Type)
+	}
+	if req.Type == OrderTypeLimit && !req.Price.IsPositive() {
+		return fmt.Errorf("限价单价格必须大于 0")
+	}
+	if req.QuoteAmount.IsPositive() && req.Side != OrderSideBuy {
+		return fmt.Errorf("按金额下单只支持买入")
+	}
+	return validateSpotOrder("模拟交易", req)
+}
+
+// priceAcceptable 限价单是否接受该价格
+func priceAcceptable(order *Order, price decimal.Decimal) bool {
+	if order.Side == OrderSideBuy {
+		return price.LessThanOrEqual(order.Price)
+	}
+	return price.GreaterThanOrEqual(order.Price)
+}
+
+// crosses 限价单是否会立即成交
+func crosses(order *Order, book *OrderBook) bool {
+	if order.Side == OrderSideBuy {
+		return len(book.Asks) > 0 && priceAcceptable(order, book.Asks[0].Price)
+	}
+	return len(book.Bids) > 0 && priceAcceptable(order, book.Bids[0].Price)
+}
+
+// fillable 限价单按当前订单簿可以成交的数量
+func fillable(order *Order, book *OrderBook) decimal.Decimal {
+	levels := book.Asks
+	if order.Side == OrderSideSell {
+		levels = book.Bids
+	}
+
+	total := decimal.Zero
+	for _, level := range levels {
+		if !priceAcceptable(order, level.Price) {
+			break
+		}
+		total = total.Add(level.Amount)
+	}
+	return total
+}
+
+// copyOrderBook 复制订单簿（模拟成交会修改深度，不能影响调用方的数据）
+func copyOrderBook(book *OrderBook) *OrderBook {
+	copied := *book
+	copied.Bids = append([]OrderBookLevel(nil), book.Bids...)
+	copied.Asks = append([]OrderBookLevel(nil), book.Asks...)
+	return &copied
+}
+
+// quoteAsset 交易对的计价货币（BTC/USDT -> USDT）
+func quoteAsset(symbol string) string {
+	if i := strings.Index(symbol, "/"); i >= 0 {
+		return symbol[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+// Package execution 模拟成交执行器单元测试
+package execution
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"arbitragex/common/decimal"
+)
+
+// paperBook 构造测试订单簿：买盘 99 / 98，卖盘 100 / 101，每档 1 个
+func paperBook() *OrderBook {
+	return &OrderBook{
+		Exchange: "binance",
+		Symbol:   "BTC/USDT",
+		Bids:     []OrderBookLevel{{Price: decimal.NewFromFloat(99), Amount: decimal.NewFromFloat(1)}, {Price: decimal.NewFromFloat(98), Amount: decimal.NewFromFloat(1)}},
+		Asks:     []OrderBookLevel{{Price: decimal.NewFromFloat(100), Amount: decimal.NewFromFloat(1)}, {Price: decimal.NewFromFloat(101), Amount: decimal.NewFromFloat(1)}},
+	}
+}
+
+// nearlyEqual 浮点数比较
+func nearlyEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// TestPaperExecutor_PlaceOrder 测试按订单簿撮合的各种订单
+func TestPaperExecutor_PlaceOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *PlaceOrderRequest
+		wantStatus string
+		wantFilled float64
+		wantAvg    float64
+	}{
+		{
+			name:       "市价买单吃两档",
+			req:        &PlaceOrderRequest{Side: OrderSideBuy, Type: OrderTypeMarket, Amount: decimal.NewFromFloat(1.5)},
+			wantStatus: OrderStatusFilled,
+			wantFilled: 1.5,
+			wantAvg:    (100 + 0.5*101) / 1.5,
+		},
+		{
+			name:       "按金额市价买入",
+			req:        &PlaceOrderRequest{Side: OrderSideBuy, Type: OrderTypeMarket, QuoteAmount: decimal.NewFromFloat(150.5)},
+			wantStatus: OrderStatusFilled,
+			wantFilled: 1.5,
+			wantAvg:    150.5 / 1.5,
+		},
+		{
+			name:       "IOC 部分成交后撤销剩余",
+			req:        &PlaceOrderRequest{Side: OrderSideSell, Type: OrderTypeLimit, TimeInForce: TimeInForceIOC, Price: decimal.NewFromFloat(99), Amount: decimal.NewFromFloat(2)},
+			wantStatus: OrderStatusCanceled,
+			wantFilled: 1,
+			wantAvg:    99,
+		},
+		{
+			name:       "FOK 深度不足撤销",
+			req:        &PlaceOrderRequest{Side: OrderSideBuy, Type: OrderTypeLimit, TimeInForce: TimeInForceFOK, Price: decimal.NewFromFloat(100), Amount: decimal.NewFromFloat(2)},
+			wantStatus: OrderStatusCanceled,
+		},
+		{
+			name:       "只做 Maker 会成交时撤销",
+			req:        &PlaceOrderRequest{Side: OrderSideBuy, Type: OrderTypeLimit, Price: decimal.NewFromFloat(100), Amount: decimal.NewFromFloat(1), PostOnly: true},
+			wantStatus: OrderStatusCanceled,
+		},
+		{
+			name:       "GTC 未成交挂单",
+			req:        &PlaceOrderRequest{Side: OrderSideBuy, Type: OrderTypeLimit, Price: decimal.NewFromFloat(95), Amount: decimal.NewFromFloat(1)},
+			wantStatus: OrderStatusOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewPaperExecutor("binance", 0.0005, 0.001)
+			executor.UpdateOrderBook(paperBook())
+
+			tt.req.Exchange = "binance"
+			tt.req.Symbol = "BTC/USDT"
+			order, err := executor.PlaceOrder(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("PlaceOrder() error = %v", err)
+			}
+			if order.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", order.Status, tt.wantStatus)
+			}
+			if !order.FilledAmount.Equal(decimal.NewFromFloat(tt.wantFilled)) {
+				t.Errorf("FilledAmount = %v, want %v", order.FilledAmount, tt.wantFilled)
+			}
+			if tt.wantFilled > 0 {
+				if !nearlyEqual(order.AveragePrice.Float64(), tt.wantAvg) {
+					t.Errorf("AveragePrice = %v, want %v", order.AveragePrice, tt.wantAvg)
+				}
+				if want := order.FilledAmount.Mul(order.AveragePrice).Mul(decimal.NewFromFloat(0.001)); !nearlyEqual(order.Fee.Float64(), want.Float64()) {
+					t.Errorf("Fee = %v, want taker fee %v", order.Fee, want)
+				}
+			}
+		})
+	}
+}
+
+// TestPaperExecutor_ConsumesDepth 测试成交会消耗订单簿深度，直到下一次快照
+func TestPaperExecutor_ConsumesDepth(t *testing.T) {
+	ctx := context.Background()
+	executor := NewPaperExecutor("binance", 0.001, 0.001)
+	executor.UpdateOrderBook(paperBook())
+
+	req := &PlaceOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeMarket, Amount: decimal.NewFromFloat(1)}
+	if _, err := executor.PlaceOrder(ctx, req); err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	order, err := executor.PlaceOrder(ctx, req)
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	if !order.AveragePrice.Equal(decimal.NewFromFloat(101)) {
+		t.Errorf("second AveragePrice = %v, want 101", order.AveragePrice)
+	}
+
+	executor.UpdateOrderBook(paperBook())
+	order, err = executor.PlaceOrder(ctx, req)
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	if !order.AveragePrice.Equal(decimal.NewFromFloat(100)) {
+		t.Errorf("AveragePrice after new snapshot = %v, want 100", order.AveragePrice)
+	}
+}
+
+// TestPaperExecutor_RestingOrder 测试挂单在之后的快照穿过价格时按 Maker 费率成交
+func TestPaperExecutor_RestingOrder(t *testing.T) {
+	ctx := context.Background()
+	executor := NewPaperExecutor("binance", 0.0005, 0.001)
+	executor.UpdateOrderBook(paperBook())
+
+	order, err := executor.PlaceOrder(ctx, &PlaceOrderRequest{
+		Exchange: "binance", Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeLimit, Price: decimal.NewFromFloat(97), Amount: decimal.NewFromFloat(1),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+
+	executor.UpdateOrderBook(&OrderBook{
+		Exchange: "binance",
+		Symbol:   "BTC/USDT",
+		Bids:     []OrderBookLevel{{Price: decimal.NewFromFloat(95), Amount: decimal.NewFromFloat(1)}},
+		Asks:     []OrderBookLevel{{Price: decimal.NewFromFloat(96.5), Amount: decimal.NewFromFloat(1)}},
+	})
+
+	got, err := executor.QueryOrder(ctx, "binance", order.ID)
+	if err != nil {
+		t.Fatalf("QueryOrder() error = %v", err)
+	}
+	if got.Status != OrderStatusFilled || !got.AveragePrice.Equal(decimal.NewFromFloat(96.5)) {
+		t.Errorf("order = %s @ %v, want filled @ 96.5", got.Status, got.AveragePrice)
+	}
+	if !got.Fee.Equal(decimal.NewFromFloat(96.5 * 0.0005)) {
+		t.Errorf("Fee = %v, want maker fee %v", got.Fee, 96.5*0.0005)
+	}
+
+	if err := executor.CancelOrder(ctx, "binance", order.ID); err == nil {
+		t.Error("CancelOrder() on filled order error = nil, want error")
+	}
+	if _, err := executor.QueryOrder(ctx, "binance", "unknown"); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("QueryOrder(unknown) error = %v, want ErrOrderNotFound", err)
+	}
+}
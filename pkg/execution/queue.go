@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
+
+	"arbitragex/common/clock"
 )
 
 // TaskQueue 任务队列
@@ -24,6 +26,9 @@ type TaskQueue struct {
 	// 当前大小
 	size int
 
+	// 时钟（判断任务是否过期）
+	clock clock.Clock
+
 	// 日志记录器
 	logger logx.Logger
 }
@@ -64,10 +69,19 @@ func NewTaskQueue(maxSize int) *TaskQueue {
 		queue:   &pq,
 		maxSize: maxSize,
 		size:    0,
+		clock:   clock.Real,
 		logger:  logx.WithContext(nil),
 	}
 }
 
+// SetClock 设置时钟（测试时使用手动时钟）
+func (q *TaskQueue) SetClock(clk clock.Clock) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.clock = clock.OrReal(clk)
+}
+
 // Enqueue 入队
 // 参数:
 //   - task: 执行任务
@@ -317,7 +331,7 @@ func (q *TaskQueue) GetExpiredTasks(timeout time.Duration) []*ExecutionTask {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
 
-	now := time.Now()
+	now := q.clock.Now()
 	expiredTasks := make([]*ExecutionTask, 0)
 
 	for _, item := range q.queue.items {
@@ -338,7 +352,7 @@ func (q *TaskQueue) RemoveExpiredTasks(timeout time.Duration) int {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	now := time.Now()
+	now := q.clock.Now()
 	removedCount := 0
 
 	newItems := make([]*Item, 0)
@@ -0,0 +1,135 @@
+// Package execution 提供订单执行功能
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Recover 恢复上次进程退出时未完成的执行
+// 对日志中每个记录了下单意图的订单，按确定性的客户端订单ID在交易所上查询并同步状态：
+//   - 买卖两边的订单都已到达交易所：继续等待成交，之后按正常流程对冲成交数量差
+//   - 任一边没有到达交易所（或崩溃时还没来得及下单）：不再补下（报价已过期），
+//     撤销仍在挂单的订单并对冲已成交的部分
+// 参数:
+//   - ctx: 上下文对象
+// 返回:
+//   - []*ExecutionResult: 每个被恢复的执行的结果（订单状态仍无法确认的保持未结束，下次启动时重试）
+//   - error: 读取执行日志失败时返回
+func (e *DefaultConcurrentExecutor) Recover(ctx context.Context) ([]*ExecutionResult, error) {
+	e.mu.RLock()
+	journal := e.journal
+	e.mu.RUnlock()
+
+	if journal == nil {
+		return nil, nil
+	}
+
+	pending, err := journal.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ExecutionResult, 0, len(pending))
+	for _, exec := range pending {
+		e.logger.Infof("恢复未完成的执行: %s (%d 个订单)", exec.ID, len(exec.Legs))
+		results = append(results, e.recoverExecution(ctx, exec))
+	}
+
+	// 已结束的执行不再需要保留
+	if compactor, ok := journal.(interface{ Compact() error }); ok {
+		if err := compactor.Compact(); err != nil {
+			e.logger.Errorf("压缩执行日志失败: %v", err)
+		}
+	}
+
+	return results, nil
+}
+
+// recoverExecution 恢复单个执行
+func (e *DefaultConcurrentExecutor) recoverExecution(ctx context.Context, exec *JournaledExecution) *ExecutionResult {
+	result := &ExecutionResult{
+		ID:            exec.ID,
+		TradingAmount: exec.Amount,
+		Status:        ExecutionStatusExecuting,
+		StartedAt:     exec.StartedAt,
+	}
+
+	if exec.Opportunity == nil {
+		e.finishExecution(exec, result, ExecutionStatusFailed, "执行日志缺少开始记录")
+		return result
+	}
+	result.OpportunityID = exec.Opportunity.ID
+	result.Symbol = exec.Opportunity.Symbol
+	result.BuyExchange = exec.Opportunity.BuyExchange
+	result.SellExchange = exec.Opportunity.SellExchange
+	result.EstProfit = exec.Opportunity.NetProfit
+
+	for _, leg := range exec.Legs {
+		if err := e.reconcileLeg(ctx, exec.ID, leg); err != nil {
+			result.ErrorMessage = fmt.Sprintf("无法确认订单 %s 状态: %v", leg.Request.ClientOrderID, err)
+			e.logger.Errorf("执行 %s: %s", exec.ID, result.ErrorMessage)
+			return result
+		}
+	}
+
+	// 只有一边到达交易所时，另一边挂着的订单立即撤销，不等待成交
+	if !legPlaced(exec, LegBuy) || !legPlaced(exec, LegSell) {
+		for _, leg := range exec.Legs {
+			if leg.Order != nil && !IsFinalStatus(leg.Order.Status) {
+				e.cancelLeg(ctx, exec.ID, leg)
+			}
+		}
+	}
+
+	e.settleExecution(ctx, exec, result)
+	return result
+}
+
+// reconcileLeg 在交易所上查询订单腿的最新状态
+// 订单不存在说明下单请求没有被交易所受理，记为失败
+func (e *DefaultConcurrentExecutor) reconcileLeg(ctx context.Context, executionID string, leg *JournaledLeg) error {
+	if leg.Request == nil {
+		return fmt.Errorf("订单腿 %s 缺少下单请求", leg.Leg)
+	}
+	if leg.Order != nil && IsFinalStatus(leg.Order.Status) {
+		return nil
+	}
+
+	order, err := e.lookupLeg(ctx, leg)
+	if errors.Is(err, ErrOrderNotFound) {
+		order, err = e.failedOrder(leg.Request, "下单请求未到达交易所"), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	e.recordLeg(executionID, leg, order)
+	return nil
+}
+
+// cancelLeg 撤销订单腿并记录撤单后的状态
+func (e *DefaultConcurrentExecutor) cancelLeg(ctx context.Context, executionID string, leg *JournaledLeg) {
+	exchange := leg.Request.Exchange
+	executor, ok := e.executors[exchange]
+	if !ok {
+		e.logger.Errorf("执行 %s 无法撤单 %s: 未配置交易所执行器: %s", executionID, leg.Order.ID, exchange)
+		return
+	}
+
+	if err := executor.CancelOrder(ctx, exchange, leg.Order.ID); err != nil {
+		e.logger.Errorf("执行 %s 撤单失败 %s: %v", executionID, leg.Order.ID, err)
+	}
+
+	// 撤单结果以交易所返回的状态为准（可能在撤单前已经成交）
+	if order, err := executor.QueryOrder(ctx, exchange, leg.Order.ID); err == nil {
+		e.recordLeg(executionID, leg, order)
+	}
+}
+
+// legPlaced 订单腿是否已到达交易所
+func legPlaced(exec *JournaledExecution, name string) bool {
+	order := legOrder(exec, name)
+	return order != nil && order.Status != OrderStatusFailed
+}
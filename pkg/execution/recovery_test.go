@@ -0,0 +1,406 @@
+// Package execution 套利执行与崩溃恢复单元测试
+package execution
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"arbitragex/common/clock"
+	"arbitragex/common/decimal"
+)
+
+// fakeExchange 内存中的交易所，按客户端订单ID保存订单
+type fakeExchange struct {
+	name string
+
+	// 下单时的成交比例（0 表示不成交，IOC 直接撤销）
+	fillRatio float64
+
+	// 下单返回的错误
+	placeErr error
+
+	// 查询返回的错误
+	queryErr error
+
+	// 下单已到达交易所但返回超时错误（请求结果未知）
+	placeTimeout bool
+
+	// 按客户端订单ID查询时前几次返回不存在（订单稍后才能查到）
+	lookupMisses int
+
+	orders map[string]*Order
+	placed []*PlaceOrderRequest
+	mu     sync.Mutex
+}
+
+func newFakeExchange(name string, fillRatio float64) *fakeExchange {
+	return &fakeExchange{name: name, fillRatio: fillRatio, orders: make(map[string]*Order)}
+}
+
+func (f *fakeExchange) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.placed = append(f.placed, req)
+	if f.placeErr != nil {
+		return nil, f.placeErr
+	}
+
+	order := f.fill(req, f.fillRatio)
+	f.orders[req.ClientOrderID] = order
+	if f.placeTimeout {
+		return nil, fmt.Errorf("timeout")
+	}
+	return order.Clone(), nil
+}
+
+// fill 按成交比例生成终态订单（市价对冲单全部成交）
+func (f *fakeExchange) fill(req *PlaceOrderRequest, ratio float64) *Order {
+	if req.Type == OrderTypeMarket {
+		ratio = 1
+	}
+	price := req.Price
+	if price.IsZero() {
+		price = decimal.NewFromInt(50000)
+	}
+
+	status := OrderStatusFilled
+	switch {
+	case ratio == 0:
+		status = OrderStatusCanceled
+	case ratio < 1:
+		status = OrderStatusCanceled // IOC 未成交部分撤销
+	}
+
+	return &Order{
+		ID:            fmt.Sprintf("%s:%s", f.name, req.ClientOrderID),
+		Exchange:      f.name,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		Price:         req.Price,
+		Amount:        req.Amount,
+		FilledAmount:  req.Amount.Mul(decimal.NewFromFloat(ratio)),
+		AveragePrice:  price,
+		Status:        status,
+		ClientOrderID: req.ClientOrderID,
+	}
+}
+
+func (f *fakeExchange) CancelOrder(ctx context.Context, exchange, orderID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, order := range f.orders {
+		if order.ID == orderID && !IsFinalStatus(order.Status) {
+			order.Status = OrderStatusCanceled
+		}
+	}
+	return nil
+}
+
+func (f *fakeExchange) QueryOrder(ctx context.Context, exchange, orderID string) (*Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, order := range f.orders {
+		if order.ID == orderID {
+			return order.Clone(), nil
+		}
+	}
+	return nil, fmt.Errorf("订单不存在: %s", orderID)
+}
+
+func (f *fakeExchange) QueryOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.queryErr != nil {
+		return nil, f.queryErr
+	}
+	if f.lookupMisses > 0 {
+		f.lookupMisses--
+		return nil, ErrOrderNotFound
+	}
+	order, ok := f.orders[clientOrderID]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+	return order.Clone(), nil
+}
+
+func (f *fakeExchange) GetOrderBook(ctx context.Context, exchange, symbol string) (*OrderBook, error) {
+	return &OrderBook{Exchange: exchange, Symbol: symbol}, nil
+}
+
+// newJournaledExecutor 创建带执行日志的并发执行器
+func newJournaledExecutor(t *testing.T, path string, exchanges ...*fakeExchange) (*DefaultConcurrentExecutor, *FileJournal) {
+	t.Helper()
+
+	journal, err := NewFileJournal(path)
+	if err != nil {
+		t.Fatalf("NewFileJournal() error = %v", err)
+	}
+	t.Cleanup(func() { journal.Close() })
+
+	executors := make(map[string]OrderExecutor)
+	for _, exchange := range exchanges {
+		executors[exchange.name] = exchange
+	}
+
+	executor := NewDefaultConcurrentExecutor(2, executors)
+	executor.SetJournal(journal)
+	executor.legTimeout = 100 * time.Millisecond
+	executor.placeLookupWindow = 100 * time.Millisecond
+	return executor, journal
+}
+
+var testOpportunity = &ArbitrageOpportunity{
+	Symbol:       "BTC/USDT",
+	BuyExchange:  "binance",
+	SellExchange: "okx",
+	BuyPrice:     decimal.NewFromFloat(50000),
+	SellPrice:    decimal.NewFromFloat(50100),
+	NetProfit:    decimal.NewFromFloat(1),
+}
+
+// TestDefaultConcurrentExecutor_ExecuteArbitrage 测试两边全部成交
+func TestDefaultConcurrentExecutor_ExecuteArbitrage(t *testing.T) {
+	binance := newFakeExchange("binance", 1)
+	okx := newFakeExchange("okx", 1)
+	executor, journal := newJournaledExecutor(t, filepath.Join(t.TempDir(), "journal.log"), binance, okx)
+
+	if err := executor.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer executor.Stop()
+
+	result, err := executor.ExecuteArbitrage(context.Background(), testOpportunity, decimal.NewFromInt(5000))
+	if err != nil {
+		t.Fatalf("ExecuteArbitrage() error = %v", err)
+	}
+	if result.Status != ExecutionStatusCompleted {
+		t.Fatalf("Status = %s (%s), want completed", result.Status, result.ErrorMessage)
+	}
+	if !result.ActualProfit.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("ActualProfit = %v, want 10", result.ActualProfit)
+	}
+
+	buy := binance.placed[0]
+	if buy.TimeInForce != TimeInForceIOC || buy.ClientOrderID != clientOrderID(result.ID, LegBuy) || !buy.Amount.Equal(decimal.NewFromFloat(0.1)) {
+		t.Errorf("buy request = %+v", buy)
+	}
+	if pending, _ := journal.Pending(); len(pending) != 0 {
+		t.Errorf("Pending() = %d, want 0", len(pending))
+	}
+}
+
+// TestDefaultConcurrentExecutor_OnResult 测试执行结果回调和套利机会 ID
+func TestDefaultConcurrentExecutor_OnResult(t *testing.T) {
+	binance := newFakeExchange("binance", 1)
+	okx := newFakeExchange("okx", 1)
+	executor, _ := newJournaledExecutor(t, filepath.Join(t.TempDir(), "journal.log"), binance, okx)
+
+	var results []*ExecutionResult
+	executor.OnResult(func(result *ExecutionResult) {
+		results = append(results, result)
+	})
+	if err := executor.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer executor.Stop()
+
+	opp := *testOpportunity
+	opp.ID = "BTC/USDT_binance_okx_1"
+	result, err := executor.ExecuteArbitrage(context.Background(), &opp, decimal.NewFromInt(5000))
+	if err != nil {
+		t.Fatalf("ExecuteArbitrage() error = %v", err)
+	}
+	if result.OpportunityID != opp.ID {
+		t.Errorf("OpportunityID = %s, want %s", result.OpportunityID, opp.ID)
+	}
+	if len(results) != 1 || results[0] != result {
+		t.Errorf("OnResult() received %d results, want the returned result", len(results))
+	}
+}
+
+// TestDefaultConcurrentExecutor_Unwind 测试单边成交后对冲
+func TestDefaultConcurrentExecutor_Unwind(t *testing.T) {
+	binance := newFakeExchange("binance", 1)
+	okx := newFakeExchange("okx", 0)
+	okx.placeErr = fmt.Errorf("insufficient balance")
+	executor, _ := newJournaledExecutor(t, filepath.Join(t.TempDir(), "journal.log"), binance, okx)
+
+	if err := executor.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer executor.Stop()
+
+	result, err := executor.ExecuteArbitrage(context.Background(), testOpportunity, decimal.NewFromInt(5000))
+	if err != nil {
+		t.Fatalf("ExecuteArbitrage() error = %v", err)
+	}
+	if result.Status != ExecutionStatusFailed || result.SellOrder.Status != OrderStatusFailed {
+		t.Errorf("result = %+v", result)
+	}
+
+	// 买入的 0.1 在买入交易所市价卖出
+	if len(binance.placed) != 2 {
+		t.Fatalf("binance orders = %d, want 2", len(binance.placed))
+	}
+	unwind := binance.placed[1]
+	if unwind.Side != OrderSideSell || unwind.Type != OrderTypeMarket || !unwind.Amount.Equal(decimal.NewFromFloat(0.1)) {
+		t.Errorf("unwind request = %+v", unwind)
+	}
+}
+
+// TestDefaultConcurrentExecutor_AmbiguousPlacement 测试下单超时后订单稍后才能查到时不记为失败
+func TestDefaultConcurrentExecutor_AmbiguousPlacement(t *testing.T) {
+	binance := newFakeExchange("binance", 1)
+	binance.placeTimeout = true
+	binance.lookupMisses = 2
+	okx := newFakeExchange("okx", 1)
+	executor, _ := newJournaledExecutor(t, filepath.Join(t.TempDir(), "journal.log"), binance, okx)
+	executor.placeLookupWindow = 5 * time.Second
+
+	if err := executor.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer executor.Stop()
+
+	result, err := executor.ExecuteArbitrage(context.Background(), testOpportunity, decimal.NewFromInt(5000))
+	if err != nil {
+		t.Fatalf("ExecuteArbitrage() error = %v", err)
+	}
+	if result.Status != ExecutionStatusCompleted || result.BuyOrder.Status != OrderStatusFilled {
+		t.Errorf("result = %+v, want completed with filled buy order", result)
+	}
+	// 买单已成交，不需要对冲
+	if len(binance.placed) != 1 {
+		t.Errorf("binance orders = %d, want 1", len(binance.placed))
+	}
+}
+
+// TestDefaultConcurrentExecutor_FailedOrderClock 测试失败订单和订单腿下单时间取自执行器的时钟
+func TestDefaultConcurrentExecutor_FailedOrderClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	okx := newFakeExchange("okx", 1)
+	okx.placeErr = fmt.Errorf("insufficient balance")
+	executor, _ := newJournaledExecutor(t, filepath.Join(t.TempDir(), "journal.log"), okx)
+	executor.SetClock(clock.NewManual(start))
+	executor.placeLookupWindow = 0
+
+	leg := &JournaledLeg{Leg: LegSell, Request: &PlaceOrderRequest{Exchange: "okx", Symbol: "BTC/USDT", Side: OrderSideSell,
+		Amount: decimal.NewFromFloat(0.1), ClientOrderID: clientOrderID("e1", LegSell)}}
+	executor.placeLeg(context.Background(), "e1", leg)
+
+	if leg.Order == nil || leg.Order.Status != OrderStatusFailed {
+		t.Fatalf("Order = %+v, want failed", leg.Order)
+	}
+	if !leg.Order.CreatedAt.Equal(start) || !leg.Order.UpdatedAt.Equal(start) {
+		t.Errorf("CreatedAt = %v, UpdatedAt = %v, want %v", leg.Order.CreatedAt, leg.Order.UpdatedAt, start)
+	}
+}
+
+// TestDefaultConcurrentExecutor_Recover 测试崩溃后恢复
+func TestDefaultConcurrentExecutor_Recover(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.log")
+	binance := newFakeExchange("binance", 1)
+	okx := newFakeExchange("okx", 1)
+
+	// 模拟崩溃前的状态：买单已发出并成交，卖单只写了意图
+	journal, err := NewFileJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buyReq := &PlaceOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeLimit,
+		Price: decimal.NewFromFloat(50000), Amount: decimal.NewFromFloat(0.1), TimeInForce: TimeInForceIOC, ClientOrderID: clientOrderID("e1", LegBuy)}
+	sellReq := &PlaceOrderRequest{Exchange: "okx", Symbol: "BTC/USDT", Side: OrderSideSell, Type: OrderTypeLimit,
+		Price: decimal.NewFromFloat(50100), Amount: decimal.NewFromFloat(0.1), TimeInForce: TimeInForceIOC, ClientOrderID: clientOrderID("e1", LegSell)}
+	journal.Append(&JournalEntry{Type: JournalExecutionStarted, ExecutionID: "e1", Opportunity: testOpportunity, Amount: decimal.NewFromFloat(5000)})
+	journal.Append(&JournalEntry{Type: JournalOrderIntent, ExecutionID: "e1", Leg: LegBuy, Request: buyReq})
+	journal.Append(&JournalEntry{Type: JournalOrderIntent, ExecutionID: "e1", Leg: LegSell, Request: sellReq})
+	journal.Close()
+	binance.PlaceOrder(context.Background(), buyReq)
+
+	executor, journal := newJournaledExecutor(t, path, binance, okx)
+	results, err := executor.Recover(context.Background())
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("len(results) = %d, want 1", len(results))
+	}
+	if results[0].SellOrder == nil || results[0].SellOrder.Status != OrderStatusFailed {
+		t.Errorf("SellOrder = %+v, want failed (never reached exchange)", results[0].SellOrder)
+	}
+
+	// 不补下卖单，在买入交易所对冲
+	if len(okx.placed) != 0 {
+		t.Errorf("okx orders = %d, want 0", len(okx.placed))
+	}
+	if len(binance.placed) != 2 || binance.placed[1].ClientOrderID != clientOrderID("e1", LegUnwind+"1") {
+		t.Errorf("binance orders = %+v", binance.placed)
+	}
+	if pending, _ := journal.Pending(); len(pending) != 0 {
+		t.Errorf("Pending() = %d, want 0", len(pending))
+	}
+}
+
+// TestDefaultConcurrentExecutor_RecoverUnknown 测试订单状态无法确认时保留执行
+func TestDefaultConcurrentExecutor_RecoverUnknown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.log")
+	binance := newFakeExchange("binance", 1)
+	binance.queryErr = fmt.Errorf("timeout")
+
+	executor, journal := newJournaledExecutor(t, path, binance, newFakeExchange("okx", 1))
+	journal.Append(&JournalEntry{Type: JournalExecutionStarted, ExecutionID: "e1", Opportunity: testOpportunity, Amount: decimal.NewFromFloat(5000)})
+	journal.Append(&JournalEntry{Type: JournalOrderIntent, ExecutionID: "e1", Leg: LegBuy,
+		Request: &PlaceOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: OrderSideBuy, ClientOrderID: clientOrderID("e1", LegBuy)}})
+
+	results, err := executor.Recover(context.Background())
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Status != ExecutionStatusExecuting {
+		t.Fatalf("results = %+v", results)
+	}
+	if pending, _ := journal.Pending(); len(pending) != 1 {
+		t.Errorf("Pending() = %d, want 1 (retry on next start)", len(pending))
+	}
+}
+
+// TestDefaultConcurrentExecutor_RecoverRemovedExchange 测试恢复时对冲单所在的交易所已不再配置，订单腿失败而不是 panic
+func TestDefaultConcurrentExecutor_RecoverRemovedExchange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.log")
+	binance := newFakeExchange("binance", 1)
+	buyReq := &PlaceOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeLimit,
+		Price: decimal.NewFromFloat(50000), Amount: decimal.NewFromFloat(0.1), TimeInForce: TimeInForceIOC, ClientOrderID: clientOrderID("e1", LegBuy)}
+	sellReq := &PlaceOrderRequest{Exchange: "okx", Symbol: "BTC/USDT", Side: OrderSideSell, Type: OrderTypeLimit,
+		Price: decimal.NewFromFloat(50100), Amount: decimal.NewFromFloat(0.1), TimeInForce: TimeInForceIOC, ClientOrderID: clientOrderID("e1", LegSell)}
+	buyOrder, _ := binance.PlaceOrder(context.Background(), buyReq)
+
+	// 买单已成交、卖单失败，重启后只配置了 OKX
+	executor, journal := newJournaledExecutor(t, path, newFakeExchange("okx", 1))
+	journal.Append(&JournalEntry{Type: JournalExecutionStarted, ExecutionID: "e1", Opportunity: testOpportunity, Amount: decimal.NewFromFloat(5000)})
+	journal.Append(&JournalEntry{Type: JournalOrderIntent, ExecutionID: "e1", Leg: LegBuy, Request: buyReq})
+	journal.Append(&JournalEntry{Type: JournalOrderUpdated, ExecutionID: "e1", Leg: LegBuy, Order: buyOrder})
+	journal.Append(&JournalEntry{Type: JournalOrderIntent, ExecutionID: "e1", Leg: LegSell, Request: sellReq})
+	journal.Append(&JournalEntry{Type: JournalOrderUpdated, ExecutionID: "e1", Leg: LegSell, Order: executor.failedOrder(sellReq, "rejected")})
+
+	results, err := executor.Recover(context.Background())
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("len(results) = %d, want 1", len(results))
+	}
+	if results[0].Status != ExecutionStatusFailed || !strings.Contains(results[0].ErrorMessage, "未平仓") {
+		t.Errorf("result = %s %q, want failed with open position", results[0].Status, results[0].ErrorMessage)
+	}
+}
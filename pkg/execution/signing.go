@@ -0,0 +1,40 @@
+// Package execution 请求签名密钥
+package execution
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"sync"
+)
+
+// signingKey HMAC-SHA256 签名密钥
+// 以 []byte 保存，不产生无法清零的 string 副本；执行器 Close 时清零，之后不能再签名
+type signingKey struct {
+	key []byte
+	mu  sync.RWMutex
+}
+
+// newSigningKey 创建签名密钥（复制 secret，调用方可以随后清零自己的副本）
+func newSigningKey(secret []byte) *signingKey {
+	return &signingKey{key: bytes.Clone(secret)}
+}
+
+// sum 计算 payload 的 HMAC-SHA256
+func (k *signingKey) sum(payload string) []byte {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	h := hmac.New(sha256.New, k.key)
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
+
+// wipe 清零密钥
+func (k *signingKey) wipe() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	clear(k.key)
+	k.key = nil
+}
@@ -0,0 +1,93 @@
+package execution
+
+import (
+	"context"
+	"errors"
+
+	"arbitragex/common/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// errLegUnresolved 等待成交结束时订单仍未进入终态
+var errLegUnresolved = errors.New("订单状态未确认")
+
+// traceQueued 记录提交执行时的 span 并开始排队 span（ctx 中有套利机会 ID 时沿用，否则使用机会的 ID）
+func (t *ExecutionTask) traceQueued(ctx context.Context) {
+	ctx = t.traceContext(ctx)
+	t.parent = trace.SpanContextFromContext(ctx)
+	_, t.queueSpan = tracing.Start(ctx, "execution.queue_wait", trace.WithAttributes(tracing.Symbol.String(t.Opportunity.Symbol)))
+}
+
+// traceExecution 开始执行 span（以提交执行时的 span 为父 span）
+// 参数:
+//   - ctx: 执行器的上下文（停止时取消）
+//   - executionID: 执行 ID
+// 返回:
+//   - context.Context: 包含执行 span 和套利机会 ID 的上下文，下单和等待成交的 span 以此为父 span
+//   - trace.Span: 执行 span（调用 endExecution 结束）
+func (t *ExecutionTask) traceExecution(ctx context.Context, executionID string) (context.Context, trace.Span) {
+	ctx = tracing.ContextWithParent(t.traceContext(ctx), t.parent)
+	return tracing.Start(ctx, "execution.execute", trace.WithAttributes(
+		tracing.ExecutionID.String(executionID),
+		tracing.Symbol.String(t.Opportunity.Symbol),
+		attribute.String("arbitragex.buy_exchange", t.Opportunity.BuyExchange),
+		attribute.String("arbitragex.sell_exchange", t.Opportunity.SellExchange),
+		attribute.Float64("arbitragex.amount", t.Amount.Float64()),
+	))
+}
+
+// traceContext 包含套利机会 ID 的上下文
+func (t *ExecutionTask) traceContext(ctx context.Context) context.Context {
+	if tracing.OpportunityIDFromContext(ctx) != "" {
+		return ctx
+	}
+	return tracing.WithOpportunityID(ctx, t.opportunityID())
+}
+
+// endExecution 记录执行结果并结束执行 span
+func endExecution(span trace.Span, result *ExecutionResult) {
+	span.SetAttributes(
+		attribute.String("arbitragex.status", result.Status),
+		attribute.Float64("arbitragex.actual_profit", result.ActualProfit.Float64()),
+	)
+	if result.Status != ExecutionStatusCompleted {
+		span.SetStatus(codes.Error, result.ErrorMessage)
+	}
+	span.End()
+}
+
+// traceOrder 开始订单腿的 span（下单、等待成交）
+func traceOrder(ctx context.Context, name, executionID string, leg *JournaledLeg) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		tracing.ExecutionID.String(executionID),
+		tracing.Exchange.String(leg.Request.Exchange),
+		tracing.Symbol.String(leg.Request.Symbol),
+		attribute.String("arbitragex.leg", leg.Leg),
+		attribute.String("arbitragex.side", leg.Request.Side),
+		attribute.String("arbitragex.client_order_id", leg.Request.ClientOrderID),
+	))
+}
+
+// endOrder 记录订单状态并结束订单腿的 span
+func endOrder(span trace.Span, order *Order, err error) {
+	if order != nil {
+		span.SetAttributes(
+			attribute.String("arbitragex.order_id", order.ID),
+			attribute.String("arbitragex.order_status", order.Status),
+			attribute.Float64("arbitragex.filled_amount", order.FilledAmount.Float64()),
+		)
+	}
+	tracing.End(span, err)
+}
+
+// endWait 结束等待成交的 span（订单仍未进入终态时记为错误）
+func endWait(span trace.Span, leg *JournaledLeg) {
+	var err error
+	if leg.Order == nil || !IsFinalStatus(leg.Order.Status) {
+		err = errLegUnresolved
+	}
+	endOrder(span, leg.Order, err)
+}
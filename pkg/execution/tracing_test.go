@@ -0,0 +1,89 @@
+// Package execution 执行链路追踪单元测试
+package execution
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"arbitragex/common/decimal"
+	"arbitragex/common/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestDefaultConcurrentExecutor_Tracing 测试排队、执行和每笔下单的 span 在提交执行的链路中，并带有套利机会 ID
+func TestDefaultConcurrentExecutor_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	binance := newFakeExchange("binance", 1)
+	okx := newFakeExchange("okx", 0.5)
+	executor, _ := newJournaledExecutor(t, filepath.Join(t.TempDir(), "journal.log"), binance, okx)
+	if err := executor.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer executor.Stop()
+
+	opp := *testOpportunity
+	opp.ID = "BTC/USDT_binance_okx_1"
+	ctx, detect := otel.Tracer("test").Start(context.Background(), "engine.detect")
+	result, err := executor.ExecuteArbitrage(ctx, &opp, decimal.NewFromInt(5000))
+	detect.End()
+	if err != nil {
+		t.Fatalf("ExecuteArbitrage() error = %v", err)
+	}
+
+	spans := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() != detect.SpanContext().TraceID() {
+			t.Errorf("span %s in trace %s, want %s", span.Name(), span.SpanContext().TraceID(), detect.SpanContext().TraceID())
+		}
+		spans[span.Name()] = append(spans[span.Name()], span)
+	}
+
+	if len(spans["execution.queue_wait"]) != 1 || len(spans["execution.execute"]) != 1 {
+		t.Fatalf("spans = %v, want one queue_wait and one execute", spans)
+	}
+	queued, execute := spans["execution.queue_wait"][0], spans["execution.execute"][0]
+	if queued.Parent().SpanID() != detect.SpanContext().SpanID() || execute.Parent().SpanID() != detect.SpanContext().SpanID() {
+		t.Errorf("queue_wait / execute parents = %v / %v, want engine.detect", queued.Parent().SpanID(), execute.Parent().SpanID())
+	}
+	if got := spanAttribute(execute, "arbitragex.status"); got != result.Status {
+		t.Errorf("execute status = %q, want %q", got, result.Status)
+	}
+
+	// 买卖两边和部分成交后的对冲单
+	if got := len(spans["execution.PlaceOrder"]); got != 3 {
+		t.Fatalf("PlaceOrder spans = %d, want 3 (buy, sell, unwind)", got)
+	}
+	for _, span := range spans["execution.PlaceOrder"] {
+		if span.Parent().SpanID() != execute.SpanContext().SpanID() {
+			t.Errorf("PlaceOrder %s parent = %v, want execute", spanAttribute(span, "arbitragex.leg"), span.Parent().SpanID())
+		}
+		if spanAttribute(span, "arbitragex.order_status") == "" {
+			t.Errorf("PlaceOrder %s has no order status", spanAttribute(span, "arbitragex.leg"))
+		}
+	}
+
+	for name, list := range spans {
+		for _, span := range list {
+			if name != "engine.detect" && spanAttribute(span, tracing.OpportunityID) != opp.ID {
+				t.Errorf("%s %s = %q, want %q", name, tracing.OpportunityID, spanAttribute(span, tracing.OpportunityID), opp.ID)
+			}
+		}
+	}
+}
+
+// spanAttribute span 的字符串属性（不存在时为空）
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.AsString()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+// Package execution 提供资金划转功能（余额查询、充值地址、提币）
+package execution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"arbitragex/common/decimal"
+)
+
+// TransferExecutor 资金划转执行器接口
+// 定义了余额查询、充值地址查询、提币等资金操作，供持仓再平衡使用
+type TransferExecutor interface {
+	// GetBalances 查询账户余额
+	// 参数:
+	//   - ctx: 上下文对象
+	// 返回:
+	//   - []*Balance: 各币种余额（仅包含非零余额）
+	//   - error: 错误信息
+	GetBalances(ctx context.Context) ([]*Balance, error)
+
+	// GetDepositAddress 查询充值地址
+	// 参数:
+	//   - ctx: 上下文对象
+	//   - asset: 币种（如 USDT）
+	//   - network: 网络名称（交易所自己的命名，如 Binance 的 TRX、OKX 的 USDT-TRC20）
+	// 返回:
+	//   - *DepositAddress: 充值地址
+	//   - error: 错误信息
+	GetDepositAddress(ctx context.Context, asset, network string) (*DepositAddress, error)
+
+	// Withdraw 提币
+	// 参数:
+	//   - ctx: 上下文对象
+	//   - req: 提币请求
+	// 返回:
+	//   - *Withdrawal: 提币记录
+	//   - error: 错误信息
+	Withdraw(ctx context.Context, req *WithdrawRequest) (*Withdrawal, error)
+}
+
+// Balance 账户余额
+type Balance struct {
+	// Exchange 交易所名称
+	Exchange string `json:"exchange"`
+
+	// Asset 币种
+	Asset string `json:"asset"`
+
+	// Free 可用余额
+	Free decimal.Decimal `json:"free"`
+
+	// Locked 冻结余额（挂单占用）
+	Locked decimal.Decimal `json:"locked"`
+}
+
+// Total 总余额（可用 + 冻结）
+func (b *Balance) Total() decimal.Decimal {
+	return b.Free.Add(b.Locked)
+}
+
+// DepositAddress 充值地址
+type DepositAddress struct {
+	// Exchange 交易所名称
+	Exchange string `json:"exchange"`
+
+	// Asset 币种
+	Asset string `json:"asset"`
+
+	// Network 网络名称
+	Network string `json:"network"`
+
+	// Address 充值地址
+	Address string `json:"address"`
+
+	// Tag 地址标签 / Memo（部分币种需要）
+	Tag string `json:"tag,omitempty"`
+}
+
+// WithdrawRequest 提币请求
+type WithdrawRequest struct {
+	// Exchange 交易所名称（binance, okx）
+	Exchange string `json:"exchange"`
+
+	// Asset 币种
+	Asset string `json:"asset"`
+
+	// Network 网络名称（提币交易所的命名）
+	Network string `json:"network"`
+
+	// Address 目标地址
+	Address string `json:"address"`
+
+	// Tag 地址标签 / Memo（可选）
+	Tag string `json:"tag,omitempty"`
+
+	// Amount 提币数量（含手续费）
+	Amount decimal.Decimal `json:"amount"`
+
+	// Fee 提币手续费（OKX 需要显式传入，Binance 忽略）
+	Fee decimal.Decimal `json:"fee,omitzero"`
+
+	// ClientID 客户端提币ID（可选，用于幂等性）
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// Withdrawal 提币记录
+type Withdrawal struct {
+	// ID 交易所提币ID
+	ID string `json:"id"`
+
+	// Exchange 交易所名称
+	Exchange string `json:"exchange"`
+
+	// Asset 币种
+	Asset string `json:"asset"`
+
+	// Network 网络名称
+	Network string `json:"network"`
+
+	// Address 目标地址
+	Address string `json:"address"`
+
+	// Amount 提币数量
+	Amount decimal.Decimal `json:"amount"`
+
+	// Fee 提币手续费
+	Fee decimal.Decimal `json:"fee"`
+
+	// ClientID 客户端提币ID
+	ClientID string `json:"client_id,omitempty"`
+
+	// CreatedAt 创建时间
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// validateWithdrawRequest 校验提币请求参数
+func validateWithdrawRequest(req *WithdrawRequest, exchange string) error {
+	if req == nil {
+		return fmt.Errorf("提币请求不能为空")
+	}
+	if req.Exchange != exchange {
+		return fmt.Errorf("交易所不匹配: %s", req.Exchange)
+	}
+	if req.Asset == "" {
+		return fmt.Errorf("币种不能为空")
+	}
+	if req.Network == "" {
+		return fmt.Errorf("网络不能为空")
+	}
+	if req.Address == "" {
+		return fmt.Errorf("提币地址不能为空")
+	}
+	if !req.Amount.IsPositive() {
+		return fmt.Errorf("提币数量必须大于 0")
+	}
+	if req.Fee.IsNegative() {
+		return fmt.Errorf("提币手续费不能为负数")
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+// Package execution 资金划转单元测试
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"arbitragex/common/decimal"
+)
+
+// TestTransferExecutorInterface 测试执行器实现了 TransferExecutor 接口
+func TestTransferExecutorInterface(t *testing.T) {
+	var _ TransferExecutor = NewBinanceExecutor("test-key", []byte("test-secret"), "")
+	var _ TransferExecutor = NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", "")
+}
+
+// TestValidateWithdrawRequest 测试提币请求校验
+func TestValidateWithdrawRequest(t *testing.T) {
+	valid := func() *WithdrawRequest {
+		return &WithdrawRequest{
+			Exchange: "binance",
+			Asset:    "USDT",
+			Network:  "TRX",
+			Address:  "TXxxxx",
+			Amount:   decimal.NewFromFloat(100),
+			Fee:      decimal.NewFromFloat(1),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(req *WithdrawRequest) *WithdrawRequest
+		wantErr bool
+	}{
+		{"有效请求", func(req *WithdrawRequest) *WithdrawRequest { return req }, false},
+		{"请求为空", func(req *WithdrawRequest) *WithdrawRequest { return nil }, true},
+		{"交易所不匹配", func(req *WithdrawRequest) *WithdrawRequest { req.Exchange = "okx"; return req }, true},
+		{"缺少网络", func(req *WithdrawRequest) *WithdrawRequest { req.Network = ""; return req }, true},
+		{"缺少地址", func(req *WithdrawRequest) *WithdrawRequest { req.Address = ""; return req }, true},
+		{"数量为 0", func(req *WithdrawRequest) *WithdrawRequest { req.Amount = decimal.Zero; return req }, true},
+		{"手续费为负", func(req *WithdrawRequest) *WithdrawRequest { req.Fee = decimal.NewFromInt(-1); return req }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWithdrawRequest(tt.modify(valid()), "binance")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateWithdrawRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestBinanceExecutor_GetBalances 测试 Binance 余额解析
+func TestBinanceExecutor_GetBalances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/account" {
+			t.Errorf("path = %s, want /api/v3/account", r.URL.Path)
+		}
+		if r.URL.Query().Get("signature") == "" {
+			t.Error("request is not signed")
+		}
+		w.Write([]byte(`{"balances":[
+			{"asset":"USDT","free":"1000.5","locked":"20"},
+			{"asset":"BTC","free":"0","locked":"0"},
+			{"asset":"ETH","free":"1.25","locked":"0"}]}`))
+	}))
+	defer server.Close()
+
+	executor := NewBinanceExecutor("test-key", []byte("test-secret"), server.URL)
+	balances, err := executor.GetBalances(context.Background())
+	if err != nil {
+		t.Fatalf("GetBalances() error = %v", err)
+	}
+
+	if len(balances) != 2 {
+		t.Fatalf("len(balances) = %d, want 2 (zero balance skipped)", len(balances))
+	}
+	if balances[0].Asset != "USDT" || !balances[0].Free.Equal(decimal.NewFromFloat(1000.5)) || !balances[0].Total().Equal(decimal.NewFromFloat(1020.5)) {
+		t.Errorf("balances[0] = %+v", balances[0])
+	}
+}
+
+// TestBinanceExecutor_Withdraw 测试 Binance 提币
+func TestBinanceExecutor_Withdraw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sapi/v1/capital/withdraw/apply" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		r.ParseForm()
+		if r.PostForm.Get("coin") != "USDT" || r.PostForm.Get("network") != "TRX" || r.PostForm.Get("amount") != "100" {
+			t.Errorf("unexpected params: %v", r.PostForm)
+		}
+		w.Write([]byte(`{"id":"7213fea8e94b4a5593d507237e5a555b"}`))
+	}))
+	defer server.Close()
+
+	executor := NewBinanceExecutor("test-key", []byte("test-secret"), server.URL)
+	withdrawal, err := executor.Withdraw(context.Background(), &WithdrawRequest{
+		Exchange: "binance",
+		Asset:    "usdt",
+		Network:  "TRX",
+		Address:  "TXxxxx",
+		Amount:   decimal.NewFromFloat(100),
+	})
+	if err != nil {
+		t.Fatalf("Withdraw() error = %v", err)
+	}
+	if withdrawal.ID != "7213fea8e94b4a5593d507237e5a555b" || withdrawal.Asset != "USDT" {
+		t.Errorf("withdrawal = %+v", withdrawal)
+	}
+}
+
+// TestOKXExecutor_Withdraw_TransferBack 测试 OKX 提币失败时将资金划回交易账户，划回失败时错误中包含滞留的数量
+func TestOKXExecutor_Withdraw_TransferBack(t *testing.T) {
+	var transfers []string
+	failTransferBack := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/api/v5/asset/transfer":
+			transfers = append(transfers, body["from"]+"->"+body["to"]+" "+body["amt"])
+			if failTransferBack && body["from"] == "6" {
+				w.Write([]byte(`{"code":"58350","msg":"Insufficient balance","data":[]}`))
+				return
+			}
+			w.Write([]byte(`{"code":"0","msg":"","data":[{"transId":"1"}]}`))
+		case "/api/v5/asset/withdrawal":
+			w.Write([]byte(`{"code":"58207","msg":"Withdrawal address is not whitelisted","data":[]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	executor := NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", server.URL)
+	req := &WithdrawRequest{
+		Exchange: "okx",
+		Asset:    "USDT",
+		Network:  "USDT-TRC20",
+		Address:  "TXxxxx",
+		Amount:   decimal.NewFromFloat(100),
+		Fee:      decimal.NewFromFloat(1),
+	}
+	if _, err := executor.Withdraw(context.Background(), req); err == nil || !strings.Contains(err.Error(), "已划回交易账户") {
+		t.Errorf("Withdraw() error = %v, want transferred back", err)
+	}
+	if want := []string{"18->6 100", "6->18 100"}; !reflect.DeepEqual(transfers, want) {
+		t.Errorf("transfers = %v, want %v", transfers, want)
+	}
+
+	failTransferBack = true
+	if _, err := executor.Withdraw(context.Background(), req); err == nil || !strings.Contains(err.Error(), "100 USDT 仍在资金账户中") {
+		t.Errorf("Withdraw() error = %v, want stranded amount", err)
+	}
+}
+
+// TestOKXExecutor_GetDepositAddress 测试 OKX 充值地址查询（GET 参数需要进入查询字符串）
+func TestOKXExecutor_GetDepositAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v5/asset/deposit-address" || r.URL.Query().Get("ccy") != "USDT" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		if r.Header.Get("OK-ACCESS-SIGN") == "" {
+			t.Error("request is not signed")
+		}
+		w.Write([]byte(`{"code":"0","msg":"","data":[
+			{"chain":"USDT-ERC20","addr":"0xabc","memo":""},
+			{"chain":"USDT-TRC20","addr":"TXyyyy","memo":""}]}`))
+	}))
+	defer server.Close()
+
+	executor := NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", server.URL)
+	address, err := executor.GetDepositAddress(context.Background(), "USDT", "USDT-TRC20")
+	if err != nil {
+		t.Fatalf("GetDepositAddress() error = %v", err)
+	}
+	if address.Address != "TXyyyy" || address.Network != "USDT-TRC20" {
+		t.Errorf("address = %+v", address)
+	}
+
+	if _, err := executor.GetDepositAddress(context.Background(), "USDT", "USDT-SOL"); err == nil {
+		t.Error("GetDepositAddress() with unknown chain should return error")
+	}
+}
+
+// TestOKXExecutor_GetBalances 测试 OKX 余额解析
+func TestOKXExecutor_GetBalances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":"0","msg":"","data":[{"details":[
+			{"ccy":"USDT","availBal":"500","frozenBal":"10"},
+			{"ccy":"BTC","availBal":"0","frozenBal":"0"}]}]}`))
+	}))
+	defer server.Close()
+
+	executor := NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", server.URL)
+	balances, err := executor.GetBalances(context.Background())
+	if err != nil {
+		t.Fatalf("GetBalances() error = %v", err)
+	}
+	if len(balances) != 1 || balances[0].Asset != "USDT" || !balances[0].Locked.Equal(decimal.NewFromFloat(10)) {
+		t.Errorf("balances = %+v", balances)
+	}
+}
+
+// TestOKXExecutor_BuildSignString 测试 OKX 签名字符串格式
+func TestOKXExecutor_BuildSignString(t *testing.T) {
+	executor := NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", "")
+
+	got := executor.buildSignString("GET", "/api/v5/asset/deposit-address?ccy=USDT", nil, "2026-01-01T00:00:00.000Z")
+	want := "2026-01-01T00:00:00.000ZGET/api/v5/asset/deposit-address?ccy=USDT"
+	if got != want {
+		t.Errorf("buildSignString() = %s, want %s", got, want)
+	}
+
+	got = executor.buildSignString("POST", "/api/v5/trade/order", map[string]interface{}{"instId": "BTC-USDT"}, "2026-01-01T00:00:00.000Z")
+	want = `2026-01-01T00:00:00.000ZPOST/api/v5/trade/order{"instId":"BTC-USDT"}`
+	if got != want {
+		t.Errorf("buildSignString() = %s, want %s", got, want)
+	}
+}
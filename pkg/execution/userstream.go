@@ -0,0 +1,400 @@
+// Package execution 提供订单执行功能
+package execution
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// OrderUpdateHandler 订单更新回调
+type OrderUpdateHandler func(order *Order)
+
+// BalanceUpdateHandler 余额更新回调（只包含本次变化的币种）
+type BalanceUpdateHandler func(balances []*Balance)
+
+// ReconnectHandler 重连回调（重连成功、恢复接收推送之前调用，用于补查断线期间丢失的推送）
+type ReconnectHandler func(ctx context.Context)
+
+// UserDataStream 私有数据流接口
+// 通过 WebSocket 推送订单和余额变化，替代 REST 轮询
+type UserDataStream interface {
+	// Start 建立连接并开始接收推送
+	// 首次连接失败时返回错误；之后断线会自动重连
+	Start(ctx context.Context) error
+
+	// Stop 停止接收并断开连接
+	Stop() error
+
+	// IsConnected 检查连接状态
+	IsConnected() bool
+
+	// OnOrderUpdate 注册订单更新回调
+	OnOrderUpdate(handler OrderUpdateHandler)
+
+	// OnBalanceUpdate 注册余额更新回调
+	OnBalanceUpdate(handler BalanceUpdateHandler)
+
+	// OnReconnect 注册重连回调（断线期间的推送不会补发，需要通过 REST 补查）
+	OnReconnect(handler ReconnectHandler)
+}
+
+// UserStreamExecutor 支持私有数据流的订单执行器（Binance、OKX 及其 WebSocket 下单执行器）
+type UserStreamExecutor interface {
+	Connector
+
+	// EnableUserStream 启用私有数据流（Connect 时启动，推送的订单写入 Tracker，等待成交不再轮询 REST）
+	EnableUserStream(wsURL string)
+
+	// Tracker 获取订单跟踪器
+	Tracker() *OrderTracker
+}
+
+// streamHandlers 回调注册表，由各交易所的数据流内嵌
+type streamHandlers struct {
+	mu                sync.RWMutex
+	orderHandlers     []OrderUpdateHandler
+	balanceHandlers   []BalanceUpdateHandler
+	reconnectHandlers []ReconnectHandler
+}
+
+// OnOrderUpdate 注册订单更新回调
+func (h *streamHandlers) OnOrderUpdate(handler OrderUpdateHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.orderHandlers = append(h.orderHandlers, handler)
+}
+
+// OnBalanceUpdate 注册余额更新回调
+func (h *streamHandlers) OnBalanceUpdate(handler BalanceUpdateHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.balanceHandlers = append(h.balanceHandlers, handler)
+}
+
+// OnReconnect 注册重连回调
+func (h *streamHandlers) OnReconnect(handler ReconnectHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reconnectHandlers = append(h.reconnectHandlers, handler)
+}
+
+// emitReconnect 分发重连事件
+func (h *streamHandlers) emitReconnect(ctx context.Context) {
+	h.mu.RLock()
+	handlers := h.reconnectHandlers
+	h.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx)
+	}
+}
+
+// emitOrder 分发订单更新
+func (h *streamHandlers) emitOrder(order *Order) {
+	h.mu.RLock()
+	handlers := h.orderHandlers
+	h.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(order)
+	}
+}
+
+// emitBalances 分发余额更新
+func (h *streamHandlers) emitBalances(balances []*Balance) {
+	if len(balances) == 0 {
+		return
+	}
+
+	h.mu.RLock()
+	handlers := h.balanceHandlers
+	h.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(balances)
+	}
+}
+
+// wsStream 自动重连的 WebSocket 连接
+// 负责拨号、心跳、读循环和断线重连，具体协议由回调实现
+type wsStream struct {
+	// 名称（用于日志）
+	name string
+
+	// 获取连接地址（每次重连都会调用，Binance 需要重新申请 listenKey）
+	dialURL func(ctx context.Context) (string, error)
+
+	// 连接建立后的握手（登录、订阅等），在读循环启动前执行
+	onConnect func(conn *websocket.Conn) error
+
+	// 消息处理
+	onMessage func(message []byte)
+
+	// 连接断开（可选，在重连前调用）
+	onDisconnect func()
+
+	// 重连成功（可选，在标记为已连接、恢复读取之前调用）
+	onReconnect func(ctx context.Context)
+
+	// 心跳间隔
+	pingInterval time.Duration
+
+	// 心跳消息（为空时发送 WebSocket ping 帧）
+	pingMessage []byte
+
+	// 当前连接
+	conn   *websocket.Conn
+	connMu sync.Mutex
+
+	// 写锁（gorilla/websocket 不支持并发写）
+	writeMu sync.Mutex
+
+	// 运行状态
+	connected  bool
+	cancelFunc context.CancelFunc
+	done       chan struct{}
+	mu         sync.RWMutex
+
+	// 日志记录器
+	logger logx.Logger
+}
+
+// start 建立首次连接并启动后台循环
+func (s *wsStream) start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.cancelFunc != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("%s 数据流已启动", s.name)
+	}
+	s.mu.Unlock()
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	s.mu.Lock()
+	s.cancelFunc = cancel
+	s.done = make(chan struct{})
+	s.connected = true
+	s.mu.Unlock()
+
+	go s.run(ctx, conn)
+	go s.heartbeat(ctx)
+
+	return nil
+}
+
+// stop 停止后台循环并关闭连接
+func (s *wsStream) stop() error {
+	s.mu.Lock()
+	cancel := s.cancelFunc
+	done := s.done
+	s.cancelFunc = nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return fmt.Errorf("%s 数据流未启动", s.name)
+	}
+
+	cancel()
+	s.closeConn()
+	<-done
+
+	return nil
+}
+
+// isConnected 检查连接状态
+func (s *wsStream) isConnected() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.connected
+}
+
+// writeJSON 发送 JSON 消息
+func (s *wsStream) writeJSON(v interface{}) error {
+	s.connMu.Lock()
+	conn := s.conn
+	s.connMu.Unlock()
+
+	if conn == nil {
+		return fmt.Errorf("%s WebSocket 未连接", s.name)
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return conn.WriteJSON(v)
+}
+
+// dial 拨号并完成握手
+func (s *wsStream) dial(ctx context.Context) (*websocket.Conn, error) {
+	wsURL, err := s.dialURL(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取 %s 连接地址失败: %w", s.name, err)
+	}
+
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 10 * time.Second,
+	}
+
+	conn, _, err := dialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("连接 %s WebSocket 失败: %w", s.name, err)
+	}
+
+	// 收到 pong 帧时延长读取超时（pong 不会从 ReadMessage 返回）
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(s.readTimeout()))
+	})
+
+	if s.onConnect != nil {
+		if err := s.onConnect(conn); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s 握手失败: %w", s.name, err)
+		}
+	}
+
+	s.connMu.Lock()
+	s.conn = conn
+	s.connMu.Unlock()
+
+	return conn, nil
+}
+
+// closeConn 关闭当前连接
+func (s *wsStream) closeConn() {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// run 读循环，断线后指数退避重连
+func (s *wsStream) run(ctx context.Context, conn *websocket.Conn) {
+	defer func() {
+		s.mu.Lock()
+		s.connected = false
+		close(s.done)
+		s.mu.Unlock()
+	}()
+
+	backoff := time.Second
+	for {
+		s.readLoop(ctx, conn)
+		s.closeConn()
+
+		s.mu.Lock()
+		s.connected = false
+		s.mu.Unlock()
+
+		if s.onDisconnect != nil {
+			s.onDisconnect()
+		}
+
+		// 重连
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			newConn, err := s.dial(ctx)
+			if err == nil {
+				conn = newConn
+				backoff = time.Second
+				break
+			}
+
+			s.logger.Errorf("%s 重连失败: %v", s.name, err)
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+		}
+
+		// 断线期间的推送已丢失，先补查再标记为已连接（此前 QueryOrder 不会返回过期的推送状态）
+		if s.onReconnect != nil {
+			s.onReconnect(ctx)
+		}
+
+		s.mu.Lock()
+		s.connected = true
+		s.mu.Unlock()
+		metricReconnects.Inc(s.name)
+		s.logger.Infof("%s 已重连", s.name)
+	}
+}
+
+// readLoop 读取消息直到连接出错或 ctx 取消
+func (s *wsStream) readLoop(ctx context.Context, conn *websocket.Conn) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		conn.SetReadDeadline(time.Now().Add(s.readTimeout()))
+
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				s.logger.Errorf("%s 读取超时，准备重连", s.name)
+			} else if ctx.Err() == nil {
+				s.logger.Errorf("%s 读取消息失败: %v", s.name, err)
+			}
+			return
+		}
+
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		s.onMessage(message)
+	}
+}
+
+// readTimeout 读取超时，超过两个心跳周期没有数据视为断线
+func (s *wsStream) readTimeout() time.Duration {
+	return 2*s.pingInterval + 10*time.Second
+}
+
+// heartbeat 心跳保活
+func (s *wsStream) heartbeat(ctx context.Context) {
+	ticker := time.NewTicker(s.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.connMu.Lock()
+			conn := s.conn
+			s.connMu.Unlock()
+
+			if conn == nil {
+				continue
+			}
+
+			s.writeMu.Lock()
+			if s.pingMessage != nil {
+				conn.WriteMessage(websocket.TextMessage, s.pingMessage)
+			} else {
+				conn.WriteMessage(websocket.PingMessage, nil)
+			}
+			s.writeMu.Unlock()
+		}
+	}
+}
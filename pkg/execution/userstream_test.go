@@ -0,0 +1,478 @@
+// Package execution 私有数据流单元测试
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"arbitragex/common/decimal"
+)
+
+// TestUserDataStreamInterface 测试私有数据流实现了 UserDataStream 接口
+func TestUserDataStreamInterface(t *testing.T) {
+	var _ UserDataStream = NewBinanceUserStream(NewBinanceExecutor("test-key", []byte("test-secret"), ""), "")
+	var _ UserDataStream = NewOKXUserStream(NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", ""), "")
+	var _ OrderWaiter = NewBinanceExecutor("test-key", []byte("test-secret"), "")
+	var _ OrderWaiter = NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", "")
+}
+
+// TestOrderTracker_Update 测试订单状态合并规则
+func TestOrderTracker_Update(t *testing.T) {
+	tracker := NewOrderTracker()
+
+	if !tracker.Update(&Order{ID: "binance:BTCUSDT:1", Status: OrderStatusPartiallyFilled, FilledAmount: decimal.NewFromFloat(0.5)}) {
+		t.Fatal("first update should be applied")
+	}
+
+	// 成交数量不能倒退
+	if tracker.Update(&Order{ID: "binance:BTCUSDT:1", Status: OrderStatusOpen, FilledAmount: decimal.NewFromFloat(0)}) {
+		t.Error("update with smaller filled amount should be ignored")
+	}
+
+	if !tracker.Update(&Order{ID: "binance:BTCUSDT:1", Status: OrderStatusFilled, FilledAmount: decimal.NewFromFloat(1)}) {
+		t.Error("filled update should be applied")
+	}
+
+	// 终态不能被非终态覆盖
+	if tracker.Update(&Order{ID: "binance:BTCUSDT:1", Status: OrderStatusPartiallyFilled, FilledAmount: decimal.NewFromFloat(1)}) {
+		t.Error("non-final update after final state should be ignored")
+	}
+
+	order, ok := tracker.Get("binance:BTCUSDT:1")
+	if !ok || order.Status != OrderStatusFilled || !order.FilledAmount.Equal(decimal.NewFromFloat(1)) {
+		t.Errorf("Get() = %+v, %v", order, ok)
+	}
+
+	tracker.Remove("binance:BTCUSDT:1")
+	if _, ok := tracker.Get("binance:BTCUSDT:1"); ok {
+		t.Error("order should be removed")
+	}
+}
+
+// TestOrderTracker_WaitFinal 测试等待订单终态
+func TestOrderTracker_WaitFinal(t *testing.T) {
+	tracker := NewOrderTracker()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		tracker.Update(&Order{ID: "okx:BTC-USDT:1", Status: OrderStatusPartiallyFilled, FilledAmount: decimal.NewFromFloat(0.5)})
+		time.Sleep(10 * time.Millisecond)
+		tracker.Update(&Order{ID: "okx:BTC-USDT:1", Status: OrderStatusFilled, FilledAmount: decimal.NewFromFloat(1)})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	order, err := tracker.WaitFinal(ctx, "okx:BTC-USDT:1")
+	if err != nil {
+		t.Fatalf("WaitFinal() error = %v", err)
+	}
+	if order.Status != OrderStatusFilled {
+		t.Errorf("Status = %s, want %s", order.Status, OrderStatusFilled)
+	}
+
+	// 超时
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := tracker.WaitFinal(ctx, "okx:BTC-USDT:2"); err != context.DeadlineExceeded {
+		t.Errorf("WaitFinal() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+// fakeUserStream 可控制连接状态和触发重连的私有数据流
+type fakeUserStream struct {
+	streamHandlers
+	connected bool
+	mu        sync.Mutex
+}
+
+func (f *fakeUserStream) Start(ctx context.Context) error { return nil }
+func (f *fakeUserStream) Stop() error                     { return nil }
+
+func (f *fakeUserStream) IsConnected() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connected
+}
+
+func (f *fakeUserStream) setConnected(connected bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.connected = connected
+}
+
+// filledFetcher 模拟 REST 查询：返回已完全成交的订单并写入跟踪器，记录查询次数
+func filledFetcher(tracking *orderTracking, calls *int32) OrderFetcher {
+	return func(ctx context.Context, orderID string) (*Order, error) {
+		atomic.AddInt32(calls, 1)
+		return tracking.trackOrder(&Order{ID: orderID, Status: OrderStatusFilled, Amount: decimal.NewFromInt(1), FilledAmount: decimal.NewFromInt(1)}), nil
+	}
+}
+
+// TestOrderTracking_ResyncOnReconnect 测试私有数据流重连后通过 REST 补查未结束的订单
+func TestOrderTracking_ResyncOnReconnect(t *testing.T) {
+	var tracking orderTracking
+	var calls int32
+	stream := &fakeUserStream{connected: true}
+	if err := tracking.attachStream(stream, filledFetcher(&tracking, &calls)); err != nil {
+		t.Fatalf("attachStream() error = %v", err)
+	}
+
+	tracking.trackOrder(&Order{ID: "binance:BTCUSDT:1", Status: OrderStatusOpen, Amount: decimal.NewFromInt(1)})
+	tracking.trackOrder(&Order{ID: "binance:BTCUSDT:2", Status: OrderStatusFilled, Amount: decimal.NewFromInt(1), FilledAmount: decimal.NewFromInt(1)})
+
+	// 断线期间成交的推送丢失，重连后补查
+	stream.emitReconnect(context.Background())
+
+	if calls != 1 {
+		t.Errorf("fetch calls = %d, want 1 (only the open order)", calls)
+	}
+	order, ok := tracking.trackedOrder("binance:BTCUSDT:1")
+	if !ok || order.Status != OrderStatusFilled {
+		t.Errorf("trackedOrder() = %+v, %v, want filled", order, ok)
+	}
+}
+
+// TestOrderTracking_WaitOrderStreamDropped 测试等待推送时数据流断开后改为轮询 REST
+func TestOrderTracking_WaitOrderStreamDropped(t *testing.T) {
+	defer func(interval time.Duration) { streamCheckInterval = interval }(streamCheckInterval)
+	streamCheckInterval = 10 * time.Millisecond
+
+	var tracking orderTracking
+	var calls int32
+	stream := &fakeUserStream{connected: true}
+	fetch := filledFetcher(&tracking, &calls)
+	if err := tracking.attachStream(stream, fetch); err != nil {
+		t.Fatalf("attachStream() error = %v", err)
+	}
+	tracking.trackOrder(&Order{ID: "okx:BTC-USDT:1", Status: OrderStatusOpen, Amount: decimal.NewFromInt(1)})
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		stream.setConnected(false)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	order, err := tracking.waitOrder(ctx, "okx:BTC-USDT:1", fetch)
+	if err != nil || order.Status != OrderStatusFilled {
+		t.Fatalf("waitOrder() = %+v, %v, want filled", order, err)
+	}
+	if atomic.LoadInt32(&calls) == 0 {
+		t.Error("waitOrder() did not fall back to REST")
+	}
+}
+
+// TestOrderTracking_WaitOrderStale 测试数据流已连接但订单长时间没有推送时通过 REST 确认
+func TestOrderTracking_WaitOrderStale(t *testing.T) {
+	defer func(interval, stale time.Duration) {
+		streamCheckInterval, orderStaleAfter = interval, stale
+	}(streamCheckInterval, orderStaleAfter)
+	streamCheckInterval, orderStaleAfter = 10*time.Millisecond, 50*time.Millisecond
+
+	var tracking orderTracking
+	var calls int32
+	stream := &fakeUserStream{connected: true}
+	fetch := filledFetcher(&tracking, &calls)
+	if err := tracking.attachStream(stream, fetch); err != nil {
+		t.Fatalf("attachStream() error = %v", err)
+	}
+	tracking.trackOrder(&Order{ID: "okx:BTC-USDT:1", Status: OrderStatusOpen, Amount: decimal.NewFromInt(1)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	order, err := tracking.waitOrder(ctx, "okx:BTC-USDT:1", fetch)
+	if err != nil || order.Status != OrderStatusFilled {
+		t.Fatalf("waitOrder() = %+v, %v, want filled", order, err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("fetch calls = %d, want 1", got)
+	}
+}
+
+// TestBinanceUserStream_ParseExecutionReport 测试 executionReport 解析
+func TestBinanceUserStream_ParseExecutionReport(t *testing.T) {
+	stream := NewBinanceUserStream(NewBinanceExecutor("test-key", []byte("test-secret"), ""), "")
+
+	report := func(execType, status, filled, quote, fee string) map[string]interface{} {
+		return map[string]interface{}{
+			"e": "executionReport", "s": "BTCUSDT", "c": "client-1", "C": "",
+			"S": "BUY", "o": "LIMIT", "q": "1.00000000", "p": "50000.00",
+			"x": execType, "X": status, "r": "NONE", "i": float64(4293153),
+			"z": filled, "Z": quote, "n": fee, "N": "BNB",
+			"O": float64(1700000000000), "T": float64(1700000001000),
+		}
+	}
+
+	order := stream.parseExecutionReport(report("TRADE", "PARTIALLY_FILLED", "0.4", "20000", "0.001"))
+	if order.ID != "binance:BTCUSDT:4293153" {
+		t.Errorf("ID = %s, want binance:BTCUSDT:4293153", order.ID)
+	}
+	if order.Symbol != "BTC/USDT" || order.Side != OrderSideBuy || order.Type != OrderTypeLimit {
+		t.Errorf("order = %+v", order)
+	}
+	if order.Status != OrderStatusPartiallyFilled || !order.FilledAmount.Equal(decimal.NewFromFloat(0.4)) || !order.AveragePrice.Equal(decimal.NewFromFloat(50000)) {
+		t.Errorf("order = %+v", order)
+	}
+
+	order = stream.parseExecutionReport(report("TRADE", "FILLED", "1", "49900", "0.0015"))
+	if order.Status != OrderStatusFilled {
+		t.Errorf("Status = %s, want %s", order.Status, OrderStatusFilled)
+	}
+	// 手续费为两笔成交之和
+	if order.Fee.LessThan(decimal.NewFromFloat(0.00249)) || order.Fee.GreaterThan(decimal.NewFromFloat(0.00251)) {
+		t.Errorf("Fee = %f, want 0.0025", order.Fee)
+	}
+	if order.ClientOrderID != "client-1" || order.FeeCurrency != "BNB" {
+		t.Errorf("order = %+v", order)
+	}
+}
+
+// wsUpgrader 测试用 WebSocket 升级器
+var wsUpgrader = websocket.Upgrader{}
+
+// TestBinanceExecutor_UserStream 测试 Binance 私有数据流推送订单和余额
+func TestBinanceExecutor_UserStream(t *testing.T) {
+	var deleted sync.WaitGroup
+	deleted.Add(1)
+
+	pushes := make(chan string, 4)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/userDataStream", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-MBX-APIKEY") != "test-key" {
+			t.Error("missing api key header")
+		}
+		switch r.Method {
+		case http.MethodPost:
+			w.Write([]byte(`{"listenKey":"test-listen-key"}`))
+		case http.MethodDelete:
+			deleted.Done()
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	})
+	mux.HandleFunc("/ws/test-listen-key", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wsUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for push := range pushes {
+			conn.WriteMessage(websocket.TextMessage, []byte(push))
+		}
+		conn.ReadMessage()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	executor := NewBinanceExecutor("test-key", []byte("test-secret"), server.URL)
+	stream, err := executor.StartUserStream(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+	if err != nil {
+		t.Fatalf("StartUserStream() error = %v", err)
+	}
+
+	balances := make(chan []*Balance, 1)
+	stream.OnBalanceUpdate(func(b []*Balance) {
+		balances <- b
+	})
+
+	pushes <- `{"e":"executionReport","s":"BTCUSDT","c":"c1","S":"SELL","o":"MARKET","q":"0.5","p":"0","x":"NEW","X":"NEW","i":12345,"z":"0","Z":"0","n":"0","N":null}`
+	pushes <- `{"e":"executionReport","s":"BTCUSDT","c":"c1","S":"SELL","o":"MARKET","q":"0.5","p":"0","x":"TRADE","X":"FILLED","i":12345,"z":"0.5","Z":"25000","n":"25","N":"USDT"}`
+	pushes <- `{"e":"outboundAccountPosition","B":[{"a":"USDT","f":"25000","l":"0"},{"a":"BTC","f":"0","l":"0"}]}`
+	close(pushes)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	order, err := executor.WaitOrder(ctx, "binance", "binance:BTCUSDT:12345")
+	if err != nil {
+		t.Fatalf("WaitOrder() error = %v", err)
+	}
+	if order.Status != OrderStatusFilled || !order.FilledAmount.Equal(decimal.NewFromFloat(0.5)) || !order.AveragePrice.Equal(decimal.NewFromFloat(50000)) || !order.Fee.Equal(decimal.NewFromFloat(25)) {
+		t.Errorf("order = %+v", order)
+	}
+
+	// QueryOrder 使用推送的状态，不访问 REST
+	queried, err := executor.QueryOrder(ctx, "binance", "binance:BTCUSDT:12345")
+	if err != nil || queried.Status != OrderStatusFilled {
+		t.Errorf("QueryOrder() = %+v, %v", queried, err)
+	}
+
+	select {
+	case b := <-balances:
+		if len(b) != 2 || b[0].Asset != "USDT" || !b[0].Free.Equal(decimal.NewFromFloat(25000)) {
+			t.Errorf("balances = %+v", b)
+		}
+	case <-ctx.Done():
+		t.Fatal("balance update not received")
+	}
+
+	if err := executor.StopUserStream(); err != nil {
+		t.Fatalf("StopUserStream() error = %v", err)
+	}
+	deleted.Wait()
+}
+
+// TestOKXExecutor_UserStream 测试 OKX 私有数据流登录、订阅和推送
+func TestOKXExecutor_UserStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wsUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// 登录
+		var login struct {
+			Op   string              `json:"op"`
+			Args []map[string]string `json:"args"`
+		}
+		if err := conn.ReadJSON(&login); err != nil {
+			return
+		}
+		if login.Op != "login" || len(login.Args) != 1 || login.Args[0]["apiKey"] != "test-key" || login.Args[0]["sign"] == "" {
+			t.Errorf("unexpected login request: %+v", login)
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"login","code":"0","msg":""}`))
+
+		// 订阅
+		var subscribe map[string]interface{}
+		if err := conn.ReadJSON(&subscribe); err != nil {
+			return
+		}
+		if subscribe["op"] != "subscribe" {
+			t.Errorf("unexpected subscribe request: %+v", subscribe)
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"subscribe","arg":{"channel":"orders","instType":"SPOT"}}`))
+
+		// 推送
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"arg":{"channel":"orders"},"data":[{"instId":"BTC-USDT","ordId":"998","clOrdId":"c2","side":"buy","ordType":"limit","px":"50000","sz":"1","accFillSz":"1","fillSz":"0.3","avgPx":"49990","fee":"-0.001","feeCcy":"BTC","state":"filled","cTime":"1700000000000","uTime":"1700000001000"}]}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"arg":{"channel":"account"},"data":[{"details":[{"ccy":"BTC","availBal":"1.999","frozenBal":"0"}]}]}`))
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	executor := NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", server.URL)
+	stream, err := executor.StartUserStream(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"))
+	if err != nil {
+		t.Fatalf("StartUserStream() error = %v", err)
+	}
+	defer executor.StopUserStream()
+
+	balances := make(chan []*Balance, 1)
+	stream.OnBalanceUpdate(func(b []*Balance) {
+		balances <- b
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	order, err := executor.WaitOrder(ctx, "okx", "okx:BTC-USDT:998")
+	if err != nil {
+		t.Fatalf("WaitOrder() error = %v", err)
+	}
+	if order.Status != OrderStatusFilled || !order.FilledAmount.Equal(decimal.NewFromFloat(1)) || !order.AveragePrice.Equal(decimal.NewFromFloat(49990)) {
+		t.Errorf("order = %+v", order)
+	}
+	if !order.Fee.Equal(decimal.NewFromFloat(0.001)) || order.ClientOrderID != "c2" {
+		t.Errorf("order = %+v", order)
+	}
+
+	select {
+	case b := <-balances:
+		if len(b) != 1 || b[0].Asset != "BTC" || !b[0].Free.Equal(decimal.NewFromFloat(1.999)) {
+			t.Errorf("balances = %+v", b)
+		}
+	case <-ctx.Done():
+		t.Fatal("balance update not received")
+	}
+}
+
+// TestOKXExecutor_UserStreamLoginFailed 测试登录失败时启动返回错误
+func TestOKXExecutor_UserStreamLoginFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wsUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var login json.RawMessage
+		conn.ReadJSON(&login)
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"error","code":"60009","msg":"Login failed."}`))
+		conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	executor := NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", server.URL)
+	if _, err := executor.StartUserStream(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http")); err == nil {
+		t.Fatal("StartUserStream() should fail when login is rejected")
+	}
+
+	// 启动失败后可以重新启动
+	if err := executor.StopUserStream(); err == nil {
+		t.Error("StopUserStream() should fail when stream is not started")
+	}
+}
+
+// TestDefaultConcurrentExecutor_StartsUserStream 测试启用的私有数据流随并发执行器启动和停止，推送写入执行器的订单跟踪器
+func TestDefaultConcurrentExecutor_StartsUserStream(t *testing.T) {
+	var deleted sync.WaitGroup
+	deleted.Add(1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/userDataStream", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted.Done()
+		}
+		w.Write([]byte(`{"listenKey":"test-listen-key"}`))
+	})
+	mux.HandleFunc("/ws/test-listen-key", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wsUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"e":"executionReport","s":"BTCUSDT","c":"c1","S":"BUY","o":"MARKET","q":"0.5","p":"0","x":"TRADE","X":"FILLED","i":7,"z":"0.5","Z":"25000","n":"0","N":null}`))
+		conn.ReadMessage()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	binance := NewBinanceExecutor("test-key", []byte("test-secret"), server.URL)
+	binance.EnableUserStream("ws" + strings.TrimPrefix(server.URL, "http") + "/ws")
+	executor := NewDefaultConcurrentExecutor(1, map[string]OrderExecutor{"binance": binance})
+	if err := executor.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	order, err := binance.WaitOrder(ctx, "binance", "binance:BTCUSDT:7")
+	if err != nil || order.Status != OrderStatusFilled {
+		t.Errorf("WaitOrder() = %+v, %v, want filled order from the user stream", order, err)
+	}
+
+	executor.Stop()
+	if binance.streamConnected() {
+		t.Error("user stream still connected after Stop()")
+	}
+	deleted.Wait()
+}
@@ -0,0 +1,117 @@
+// Package execution 提供订单执行功能
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Connector 需要常驻连接的订单执行器（WebSocket 下单、私有数据流）
+// 并发执行器启动时建立连接，停止时断开
+type Connector interface {
+	// Connect 建立连接（首次连接失败时返回错误，之后断线自动重连）
+	Connect(ctx context.Context) error
+
+	// Close 断开连接
+	Close() error
+}
+
+// wsRPC 基于 WebSocket 的请求/响应客户端
+// 按请求 ID 匹配响应，由 WebSocket 下单执行器使用
+type wsRPC struct {
+	// WebSocket 连接
+	stream *wsStream
+
+	// 请求ID -> 响应通道（连接断开时收到 nil）
+	pending map[string]chan []byte
+	mu      sync.Mutex
+
+	// 请求ID 计数器
+	nextID uint64
+}
+
+// newWSRPC 创建 WebSocket 请求/响应客户端
+func newWSRPC(stream *wsStream) *wsRPC {
+	r := &wsRPC{
+		stream:  stream,
+		pending: make(map[string]chan []byte),
+	}
+
+	stream.onMessage = r.handleMessage
+	stream.onDisconnect = r.failPending
+
+	return r
+}
+
+// newID 生成请求ID（交易所要求字母数字，不超过 32 位）
+func (r *wsRPC) newID() string {
+	return strconv.FormatUint(atomic.AddUint64(&r.nextID, 1), 10)
+}
+
+// call 发送请求并等待对应 ID 的响应
+// 连接在等待期间断开时返回错误，此时请求是否已被交易所处理未知，需要按客户端订单ID查询确认
+func (r *wsRPC) call(ctx context.Context, id string, request interface{}) ([]byte, error) {
+	ch := make(chan []byte, 1)
+
+	r.mu.Lock()
+	r.pending[id] = ch
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, id)
+		r.mu.Unlock()
+	}()
+
+	if err := r.stream.writeJSON(request); err != nil {
+		return nil, fmt.Errorf("发送请求失败: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case response := <-ch:
+		if response == nil {
+			return nil, fmt.Errorf("等待响应时连接断开，请求 %s 结果未知", id)
+		}
+		return response, nil
+	}
+}
+
+// handleMessage 将响应分发给等待的请求
+func (r *wsRPC) handleMessage(message []byte) {
+	var envelope struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil || envelope.ID == "" {
+		return
+	}
+
+	r.mu.Lock()
+	ch, ok := r.pending[envelope.ID]
+	r.mu.Unlock()
+
+	if ok {
+		select {
+		case ch <- message:
+		default:
+		}
+	}
+}
+
+// failPending 连接断开时唤醒所有等待中的请求
+func (r *wsRPC) failPending() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ch := range r.pending {
+		select {
+		case ch <- nil:
+		default:
+		}
+	}
+}
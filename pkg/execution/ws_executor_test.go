@@ -0,0 +1,320 @@
+// Package execution WebSocket 下单单元测试
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"arbitragex/common/decimal"
+)
+
+// TestWSExecutorInterface 测试 WebSocket 下单执行器实现了 OrderExecutor 接口
+func TestWSExecutorInterface(t *testing.T) {
+	var _ OrderExecutor = NewBinanceWSExecutor(NewBinanceExecutor("test-key", []byte("test-secret"), ""), "")
+	var _ OrderExecutor = NewOKXWSExecutor(NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", ""), "")
+}
+
+// wsURL 将 httptest 地址转换为 WebSocket 地址
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// TestBinanceWSExecutor_PlaceOrder 测试 Binance order.place 下单
+func TestBinanceWSExecutor_PlaceOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wsUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var request struct {
+				ID     string            `json:"id"`
+				Method string            `json:"method"`
+				Params map[string]string `json:"params"`
+			}
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+
+			if request.Method != "order.place" {
+				t.Errorf("method = %s, want order.place", request.Method)
+			}
+
+			// 校验签名
+			values := url.Values{}
+			for key, value := range request.Params {
+				if key != "signature" {
+					values.Set(key, value)
+				}
+			}
+			executor := NewBinanceExecutor("test-key", []byte("test-secret"), "")
+			if request.Params["signature"] != executor.generateSignature(values.Encode()) {
+				t.Error("invalid signature")
+			}
+			if request.Params["apiKey"] != "test-key" || request.Params["timeInForce"] != "GTC" {
+				t.Errorf("unexpected params: %v", request.Params)
+			}
+
+			if request.Params["quantity"] == "999" {
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"`+request.ID+`","status":400,"error":{"code":-2010,"msg":"Account has insufficient balance for requested action."}}`))
+				continue
+			}
+
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"`+request.ID+`","status":200,"result":{"symbol":"BTCUSDT","orderId":12569099453,"clientOrderId":"c1","status":"NEW","executedQty":"0.00000000"}}`))
+		}
+	}))
+	defer server.Close()
+
+	executor := NewBinanceWSExecutor(NewBinanceExecutor("test-key", []byte("test-secret"), ""), wsURL(server))
+	if err := executor.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer executor.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	order, err := executor.PlaceOrder(ctx, &PlaceOrderRequest{
+		Exchange:      "binance",
+		Symbol:        "BTC/USDT",
+		Side:          OrderSideBuy,
+		Type:          OrderTypeLimit,
+		Price:         decimal.NewFromFloat(50000),
+		Amount:        decimal.NewFromFloat(0.1),
+		ClientOrderID: "c1",
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	if order.ID != "binance:BTCUSDT:12569099453" || order.Status != OrderStatusOpen {
+		t.Errorf("order = %+v", order)
+	}
+
+	_, err = executor.PlaceOrder(ctx, &PlaceOrderRequest{
+		Exchange: "binance",
+		Symbol:   "BTC/USDT",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeLimit,
+		Price:    decimal.NewFromFloat(50000),
+		Amount:   decimal.NewFromFloat(999),
+	})
+	if err == nil || !strings.Contains(err.Error(), "insufficient balance") {
+		t.Errorf("PlaceOrder() error = %v, want insufficient balance", err)
+	}
+}
+
+// TestBinanceWSExecutor_FallbackToREST 测试未连接时回退到 REST 下单
+func TestBinanceWSExecutor_FallbackToREST(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/order" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"symbol":"BTCUSDT","orderId":42,"status":"NEW","executedQty":"0"}`))
+	}))
+	defer server.Close()
+
+	executor := NewBinanceWSExecutor(NewBinanceExecutor("test-key", []byte("test-secret"), server.URL), "")
+	order, err := executor.PlaceOrder(context.Background(), &PlaceOrderRequest{
+		Exchange: "binance",
+		Symbol:   "BTC/USDT",
+		Side:     OrderSideSell,
+		Type:     OrderTypeMarket,
+		Amount:   decimal.NewFromFloat(0.1),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	if order.ID != "binance:BTCUSDT:42" {
+		t.Errorf("ID = %s, want binance:BTCUSDT:42", order.ID)
+	}
+}
+
+// TestOKXWSExecutor_PlaceOrders 测试 OKX order / batch-orders 下单
+func TestOKXWSExecutor_PlaceOrders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wsUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// 登录
+		var login map[string]interface{}
+		if err := conn.ReadJSON(&login); err != nil {
+			return
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"login","code":"0","msg":""}`))
+
+		for {
+			var request struct {
+				ID   string              `json:"id"`
+				Op   string              `json:"op"`
+				Args []map[string]string `json:"args"`
+			}
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+
+			for _, arg := range request.Args {
+				if arg["side"] != "buy" && arg["side"] != "sell" {
+					t.Errorf("side = %s, want lowercase", arg["side"])
+				}
+				if arg["tdMode"] != "cash" {
+					t.Errorf("tdMode = %s, want cash", arg["tdMode"])
+				}
+			}
+
+			switch request.Op {
+			case "order":
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"`+request.ID+`","op":"order","code":"0","msg":"","data":[{"clOrdId":"c1","ordId":"1001","sCode":"0","sMsg":""}]}`))
+			case "batch-orders":
+				if len(request.Args) != 2 {
+					t.Errorf("len(args) = %d, want 2", len(request.Args))
+				}
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"`+request.ID+`","op":"batch-orders","code":"2","msg":"","data":[
+					{"clOrdId":"c2","ordId":"1002","sCode":"0","sMsg":""},
+					{"clOrdId":"c3","ordId":"","sCode":"51008","sMsg":"Insufficient balance"}]}`))
+			}
+		}
+	}))
+	defer server.Close()
+
+	executor := NewOKXWSExecutor(NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", ""), wsURL(server))
+	if err := executor.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer executor.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	order, err := executor.PlaceOrder(ctx, &PlaceOrderRequest{
+		Exchange:      "okx",
+		Symbol:        "BTC/USDT",
+		Side:          OrderSideBuy,
+		Type:          OrderTypeLimit,
+		Price:         decimal.NewFromFloat(50000),
+		Amount:        decimal.NewFromFloat(0.1),
+		ClientOrderID: "c1",
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	if order.ID != "okx:BTC-USDT:1001" {
+		t.Errorf("ID = %s, want okx:BTC-USDT:1001", order.ID)
+	}
+
+	orders, err := executor.PlaceOrders(ctx, []*PlaceOrderRequest{
+		{Exchange: "okx", Symbol: "BTC/USDT", Side: OrderSideBuy, Type: OrderTypeMarket, Amount: decimal.NewFromFloat(0.1), ClientOrderID: "c2"},
+		{Exchange: "okx", Symbol: "ETH/USDT", Side: OrderSideSell, Type: OrderTypeMarket, Amount: decimal.NewFromFloat(1), ClientOrderID: "c3"},
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrders() error = %v", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("len(orders) = %d, want 2", len(orders))
+	}
+	if orders[0].ID != "okx:BTC-USDT:1002" || orders[0].Status == OrderStatusFailed {
+		t.Errorf("orders[0] = %+v", orders[0])
+	}
+	if orders[1].Status != OrderStatusFailed || !strings.Contains(orders[1].ErrorMessage, "Insufficient balance") {
+		t.Errorf("orders[1] = %+v", orders[1])
+	}
+}
+
+// TestOKXWSExecutor_PlaceOrdersLimit 测试批量下单数量限制
+func TestOKXWSExecutor_PlaceOrdersLimit(t *testing.T) {
+	executor := NewOKXWSExecutor(NewOKXExecutor("test-key", []byte("test-secret"), "passphrase", ""), "")
+
+	reqs := make([]*PlaceOrderRequest, okxBatchOrderLimit+1)
+	if _, err := executor.PlaceOrders(context.Background(), reqs); err == nil {
+		t.Error("PlaceOrders() with too many orders should return error")
+	}
+	if _, err := executor.PlaceOrders(context.Background(), nil); err == nil {
+		t.Error("PlaceOrders() with no orders should return error")
+	}
+}
+
+// TestWSRPC_Disconnect 测试连接断开时等待中的请求立即返回
+func TestWSRPC_Disconnect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wsUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		// 收到请求后直接断开
+		conn.ReadMessage()
+		conn.Close()
+	}))
+	defer server.Close()
+
+	executor := NewBinanceWSExecutor(NewBinanceExecutor("test-key", []byte("test-secret"), ""), wsURL(server))
+	if err := executor.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer executor.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := executor.PlaceOrder(ctx, &PlaceOrderRequest{
+		Exchange: "binance",
+		Symbol:   "BTC/USDT",
+		Side:     OrderSideBuy,
+		Type:     OrderTypeMarket,
+		Amount:   decimal.NewFromFloat(0.1),
+	})
+	if err == nil {
+		t.Fatal("PlaceOrder() should fail when connection drops")
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("PlaceOrder() took %v, want fast failure on disconnect", time.Since(start))
+	}
+}
+
+// TestDefaultConcurrentExecutor_ConnectsWSExecutors 测试并发执行器启动时建立 WebSocket 下单连接，停止时断开，连接失败时启动失败
+func TestDefaultConcurrentExecutor_ConnectsWSExecutors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wsUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	binance := NewBinanceWSExecutor(NewBinanceExecutor("test-key", []byte("test-secret"), ""), wsURL(server))
+	executor := NewDefaultConcurrentExecutor(1, map[string]OrderExecutor{"binance": binance, "okx": newFakeExchange("okx", 1)})
+	if err := executor.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if !binance.IsConnected() {
+		t.Error("WebSocket executor not connected after Start()")
+	}
+	executor.Stop()
+	if binance.IsConnected() {
+		t.Error("WebSocket executor still connected after Stop()")
+	}
+
+	unreachable := NewBinanceWSExecutor(NewBinanceExecutor("test-key", []byte("test-secret"), ""), "ws://127.0.0.1:1")
+	executor = NewDefaultConcurrentExecutor(1, map[string]OrderExecutor{"binance": unreachable})
+	if err := executor.Start(context.Background()); err == nil {
+		executor.Stop()
+		t.Error("Start() with unreachable WebSocket error = nil")
+	}
+}
@@ -0,0 +1,98 @@
+package feed
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"arbitragex/pkg/exchange"
+)
+
+// Endpoints 交易所行情端点（为空时使用默认的公共行情端点）
+type Endpoints struct {
+	WebSocket string // WebSocket 地址（如 wss://stream.binance.com:9443/ws）
+	REST      string // REST API 基础地址（如 https://api.binance.com）
+}
+
+// NewAdapter 按默认公共行情端点创建交易所适配器
+// 参数:
+//   - name: 交易所名称（binance、okx）
+//   - symbols: 交易对（标准格式，如 BTC/USDT）
+// 返回:
+//   - exchange.ExchangeAdapter: 交易所适配器
+//   - error: 不支持的交易所
+func NewAdapter(name string, symbols []string) (exchange.ExchangeAdapter, error) {
+	return NewAdapterWithEndpoints(name, symbols, Endpoints{})
+}
+
+// NewAdapterWithEndpoints 按指定的行情端点创建交易所适配器（如测试网或代理地址）
+// 参数:
+//   - name: 交易所名称（binance、okx）
+//   - symbols: 交易对（标准格式，如 BTC/USDT）
+//   - endpoints: 行情端点（为空的字段使用默认端点）
+// 返回:
+//   - exchange.ExchangeAdapter: 交易所适配器
+//   - error: 不支持的交易所
+func NewAdapterWithEndpoints(name string, symbols []string, endpoints Endpoints) (exchange.ExchangeAdapter, error) {
+	switch strings.ToLower(name) {
+	case "binance":
+		return exchange.NewBinanceAdapter(&exchange.ExchangeConfig{
+			Name: "binance",
+			WebSocket: exchange.WebSocketConfig{
+				ExchangeName: "binance",
+				BaseURL:      orDefault(endpoints.WebSocket, "wss://stream.binance.com:9443/ws"),
+				PingInterval: 30 * time.Second,
+			},
+			REST: exchange.RESTConfig{
+				BaseURL:    orDefault(endpoints.REST, "https://api.binance.com"),
+				Timeout:    10 * time.Second,
+				MaxRetries: 3,
+			},
+			Symbols: symbols,
+			Enabled: true,
+		}), nil
+	case "okx":
+		return exchange.NewOKXAdapter(&exchange.ExchangeConfig{
+			Name: "okx",
+			WebSocket: exchange.WebSocketConfig{
+				ExchangeName: "okx",
+				BaseURL:      orDefault(endpoints.WebSocket, "wss://ws.okx.com:8443/ws/v5/public"),
+				PingInterval: 30 * time.Second,
+			},
+			REST: exchange.RESTConfig{
+				BaseURL:    orDefault(endpoints.REST, "https://www.okx.com"),
+				Timeout:    10 * time.Second,
+				MaxRetries: 3,
+			},
+			Symbols: symbols,
+			Enabled: true,
+		}), nil
+	default:
+		return nil, fmt.Errorf("不支持的交易所: %s", name)
+	}
+}
+
+// orDefault 为空时返回默认值
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// NewReplayAdapters 为每个交易所创建行情回放适配器（共用同一个回放，按录制顺序和同一时间线推送各交易所行情）
+// 参数:
+//   - exchanges: 交易所名称
+//   - symbols: 交易对（标准格式）
+//   - files: 录制文件
+//   - speed: 回放倍速（≤ 0 表示尽快回放）
+// 返回:
+//   - map[string]exchange.ExchangeAdapter: 交易所名称 → 回放适配器
+func NewReplayAdapters(exchanges, symbols, files []string, speed float64) map[string]exchange.ExchangeAdapter {
+	replay := exchange.NewReplay(files, speed)
+	adapters := make(map[string]exchange.ExchangeAdapter, len(exchanges))
+	for _, name := range exchanges {
+		adapters[name] = replay.Adapter(&exchange.ExchangeConfig{Name: name, Symbols: symbols})
+	}
+	return adapters
+}
@@ -0,0 +1,337 @@
+// Package feed 行情源
+// 职责：连接交易所适配器、订阅交易对行情并写入价格缓存，供价格服务、监控程序和单进程模式共用
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/clock"
+	"arbitragex/common/tracing"
+	"arbitragex/pkg/exchange"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// PriceListener 价格更新回调（在适配器的回调协程中调用，不能阻塞）
+type PriceListener func(price *cache.PriceData)
+
+// ExchangeStatus 交易所行情状态
+type ExchangeStatus struct {
+	Name        string    `json:"name"`            // 交易所名称
+	Connected   bool      `json:"connected"`       // WebSocket 是否已连接
+	Symbols     []string  `json:"symbols"`         // 订阅的交易对
+	Updates     int64     `json:"updates"`         // 收到的行情数
+	LastUpdate  time.Time `json:"last_update"`     // 最近一次收到行情的时间
+	Disconnects int64     `json:"disconnects"`     // 意外断开的次数
+	Reconnects  int64     `json:"reconnects"`      // 重连成功的次数
+	Error       string    `json:"error,omitempty"` // 启动失败或最近一次重连失败的原因
+}
+
+// DefaultReconnectInterval 默认的断线检查和重连间隔
+const DefaultReconnectInterval = 5 * time.Second
+
+// finisher 会自行结束的适配器（如行情回放），结束后不再重连
+type finisher interface {
+	Done() <-chan struct{}
+}
+
+// source 单个交易所的行情源
+type source struct {
+	name        string
+	adapter     exchange.ExchangeAdapter
+	updates     int64
+	lastUpdate  time.Time
+	disconnects int64
+	reconnects  int64
+	err         error
+}
+
+// Feed 行情源
+// 每个交易所对应一个适配器，行情以交易所名称（如 binance）和标准交易对（如 BTC/USDT）写入价格缓存；
+// 适配器读取失败后保持断开，由行情源定期检查并重新连接、订阅
+type Feed struct {
+	priceCache        cache.PriceCache
+	symbols           []string
+	sources           map[string]*source
+	listeners         []PriceListener
+	clock             clock.Clock
+	reconnectInterval time.Duration
+	stop              chan struct{} // Stop 时关闭，停止重连
+	stopOnce          sync.Once
+	mu                sync.RWMutex
+	logger            logx.Logger
+}
+
+// New 创建行情源
+// 参数:
+//   - priceCache: 价格缓存
+//   - symbols: 订阅的交易对（标准格式，如 BTC/USDT）
+// 返回:
+//   - *Feed: 行情源（通过 AddAdapter 添加交易所后调用 Start）
+func New(priceCache cache.PriceCache, symbols []string) *Feed {
+	return &Feed{
+		priceCache: priceCache,
+		symbols:    append([]string(nil), symbols...),
+		sources:    make(map[string]*source),
+		clock:      clock.Real,
+		stop:       make(chan struct{}),
+		logger:     logx.WithContext(context.Background()),
+
+		reconnectInterval: DefaultReconnectInterval,
+	}
+}
+
+// SetClock 设置时钟（用于记录最近一次收到行情的时间）
+func (f *Feed) SetClock(clk clock.Clock) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.clock = clock.OrReal(clk)
+}
+
+// SetReconnectInterval 设置断线检查和重连间隔（≤ 0 时不重连；需在 Start 之前调用）
+func (f *Feed) SetReconnectInterval(interval time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.reconnectInterval = interval
+}
+
+// AddAdapter 添加交易所适配器（需在 Start 之前调用）
+// 参数:
+//   - name: 交易所名称（写入价格缓存时使用，如 binance）
+//   - adapter: 交易所适配器
+func (f *Feed) AddAdapter(name string, adapter exchange.ExchangeAdapter) {
+	name = strings.ToLower(name)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sources[name] = &source{name: name, adapter: adapter}
+}
+
+// OnPrice 注册价格更新回调（需在 Start 之前调用）
+func (f *Feed) OnPrice(listener PriceListener) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.listeners = append(f.listeners, listener)
+}
+
+// Start 连接所有交易所并订阅行情
+// 各交易所并行启动，单个交易所失败不影响其他交易所；全部失败时返回错误。
+// 启动成功的交易所断开后按重连间隔重新连接，直到 ctx 结束或调用 Stop
+func (f *Feed) Start(ctx context.Context) error {
+	f.mu.RLock()
+	sources := make([]*source, 0, len(f.sources))
+	for _, src := range f.sources {
+		sources = append(sources, src)
+	}
+	f.mu.RUnlock()
+
+	if len(sources) == 0 {
+		return fmt.Errorf("没有可用的交易所")
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(sources))
+	for i, src := range sources {
+		wg.Add(1)
+		go func(i int, src *source) {
+			defer wg.Done()
+			errs[i] = f.startSource(ctx, src)
+		}(i, src)
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			continue
+		}
+		go f.watchSource(ctx, sources[i])
+	}
+	if failed == len(sources) {
+		return fmt.Errorf("行情源启动失败: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// startSource 连接单个交易所并订阅行情
+func (f *Feed) startSource(ctx context.Context, src *source) error {
+	err := src.adapter.Connect(ctx)
+	if err != nil {
+		err = fmt.Errorf("%s 连接失败: %w", src.name, err)
+	} else if err = src.adapter.SubscribeTicker(ctx, f.symbols, func(ticker *exchange.Ticker) {
+		f.onTicker(src, ticker)
+	}); err != nil {
+		err = fmt.Errorf("%s 订阅失败: %w", src.name, err)
+	}
+
+	f.mu.Lock()
+	src.err = err
+	f.mu.Unlock()
+
+	if err != nil {
+		f.logger.Errorf("行情源启动失败: %v", err)
+		return err
+	}
+	f.logger.Infof("行情源已启动: %s, 交易对 %d 个", src.name, len(f.symbols))
+	return nil
+}
+
+// watchSource 定期检查交易所连接，断开后重新连接并订阅行情，记录断开和重连次数
+func (f *Feed) watchSource(ctx context.Context, src *source) {
+	f.mu.RLock()
+	interval := f.reconnectInterval
+	clk := f.clock
+	f.mu.RUnlock()
+
+	if interval <= 0 {
+		return
+	}
+	var done <-chan struct{}
+	if finite, ok := src.adapter.(finisher); ok {
+		done = finite.Done()
+	}
+
+	disconnected := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.stop:
+			return
+		case <-done:
+			return
+		case <-clk.After(interval):
+		}
+
+		if src.adapter.IsConnected() {
+			continue
+		}
+		if !disconnected {
+			disconnected = true
+			metricDisconnects.Inc(src.name)
+			f.mu.Lock()
+			src.disconnects++
+			f.mu.Unlock()
+			f.logger.Errorf("行情源 %s 连接已断开，开始重连", src.name)
+		}
+
+		// 重新订阅前移除旧的回调，避免重复推送（连接已断开，取消订阅消息发送失败可以忽略）
+		_ = src.adapter.UnsubscribeTicker(f.symbols)
+		if err := f.startSource(ctx, src); err != nil {
+			if src.adapter.IsConnected() {
+				_ = src.adapter.Disconnect()
+			}
+			continue
+		}
+
+		disconnected = false
+		metricReconnects.Inc(src.name)
+		f.mu.Lock()
+		src.reconnects++
+		f.mu.Unlock()
+	}
+}
+
+// onTicker 行情回调：写入价格缓存并通知监听者
+func (f *Feed) onTicker(src *source, ticker *exchange.Ticker) {
+	observeTicker(src.name, ticker)
+
+	price := &cache.PriceData{
+		Exchange:  src.name,
+		Symbol:    ticker.Symbol,
+		BidPrice:  ticker.BidPrice,
+		AskPrice:  ticker.AskPrice,
+		LastPrice: ticker.LastPrice,
+		Volume24h: ticker.Volume24h,
+		Timestamp: ticker.Timestamp,
+	}
+
+	ctx := tracing.ContextWithParent(context.Background(), ticker.SpanContext)
+	if err := f.priceCache.SetPrice(ctx, src.name, ticker.Symbol, price); err != nil {
+		f.logger.Errorf("存储价格失败 %s %s: %v", src.name, ticker.Symbol, err)
+		return
+	}
+
+	f.mu.Lock()
+	src.updates++
+	src.lastUpdate = f.clock.Now()
+	listeners := f.listeners
+	f.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(price)
+	}
+}
+
+// Stop 停止重连并断开所有交易所连接
+func (f *Feed) Stop() {
+	f.stopOnce.Do(func() { close(f.stop) })
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, src := range f.sources {
+		if !src.adapter.IsConnected() {
+			continue
+		}
+		if err := src.adapter.Disconnect(); err != nil {
+			f.logger.Errorf("断开 %s 失败: %v", src.name, err)
+		}
+	}
+}
+
+// Symbols 订阅的交易对
+func (f *Feed) Symbols() []string {
+	return append([]string(nil), f.symbols...)
+}
+
+// Exchanges 交易所名称（按名称排序）
+func (f *Feed) Exchanges() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	names := make([]string, 0, len(f.sources))
+	for name := range f.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Status 各交易所的行情状态（按名称排序）
+func (f *Feed) Status() []*ExchangeStatus {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	statuses := make([]*ExchangeStatus, 0, len(f.sources))
+	for _, src := range f.sources {
+		status := &ExchangeStatus{
+			Name:       src.name,
+			Connected:  src.adapter.IsConnected(),
+			Symbols:    append([]string(nil), f.symbols...),
+			Updates:    src.updates,
+			LastUpdate: src.lastUpdate,
+
+			Disconnects: src.disconnects,
+			Reconnects:  src.reconnects,
+		}
+		if src.err != nil {
+			status.Error = src.err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
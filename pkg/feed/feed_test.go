@@ -0,0 +1,177 @@
+// Package feed 行情源单元测试
+package feed
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/exchange"
+)
+
+// mockAdapter 模拟交易所适配器（同步调用处理器）
+type mockAdapter struct {
+	connectErr error
+	connected  bool
+	symbols    []string
+	handler    exchange.TickerHandler
+	subscribes int
+	mu         sync.Mutex
+}
+
+func (m *mockAdapter) GetName() string                  { return "mock" }
+func (m *mockAdapter) GetSupportedSymbols() []string    { return m.symbols }
+func (m *mockAdapter) UnsubscribeTicker([]string) error { return nil }
+func (m *mockAdapter) Ping(context.Context) error       { return nil }
+
+func (m *mockAdapter) IsConnected() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.connected
+}
+
+func (m *mockAdapter) Connect(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.connectErr != nil {
+		return m.connectErr
+	}
+	m.connected = true
+	return nil
+}
+
+func (m *mockAdapter) Disconnect() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connected = false
+	return nil
+}
+
+func (m *mockAdapter) SubscribeTicker(ctx context.Context, symbols []string, handler exchange.TickerHandler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.symbols = symbols
+	m.handler = handler
+	m.subscribes++
+	return nil
+}
+
+func (m *mockAdapter) GetTicker(ctx context.Context, symbol string) (*exchange.Ticker, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAdapter) GetTickers(ctx context.Context, symbols []string) ([]*exchange.Ticker, error) {
+	return nil, errors.New("not implemented")
+}
+
+// TestFeed_Start 测试行情写入缓存、通知监听者，单个交易所失败不影响其他交易所
+func TestFeed_Start(t *testing.T) {
+	ctx := context.Background()
+	priceCache := cache.NewMemoryPriceCache(time.Minute)
+	binance := &mockAdapter{}
+	okx := &mockAdapter{connectErr: errors.New("dial timeout")}
+
+	f := New(priceCache, []string{"BTC/USDT"})
+	f.AddAdapter("Binance", binance)
+	f.AddAdapter("okx", okx)
+
+	var received []*cache.PriceData
+	f.OnPrice(func(price *cache.PriceData) {
+		received = append(received, price)
+	})
+
+	if err := f.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if len(binance.symbols) != 1 || binance.symbols[0] != "BTC/USDT" {
+		t.Errorf("subscribed symbols = %v, want [BTC/USDT]", binance.symbols)
+	}
+
+	binance.handler(&exchange.Ticker{
+		Exchange: "Binance", Symbol: "BTC/USDT",
+		BidPrice: decimal.RequireFromString("43000.1"), AskPrice: decimal.RequireFromString("43000.2"),
+		Timestamp: time.Now(),
+	})
+
+	price, err := priceCache.GetPrice(ctx, "binance", "BTC/USDT")
+	if err != nil || price.AskPrice.String() != "43000.2" {
+		t.Fatalf("GetPrice() = %+v, %v, want ask 43000.2", price, err)
+	}
+	if len(received) != 1 || received[0].Exchange != "binance" {
+		t.Errorf("listener received %+v, want one binance price", received)
+	}
+
+	statuses := f.Status()
+	if len(statuses) != 2 || statuses[0].Name != "binance" || statuses[1].Name != "okx" {
+		t.Fatalf("Status() = %+v, want binance and okx", statuses)
+	}
+	if !statuses[0].Connected || statuses[0].Updates != 1 || statuses[0].LastUpdate.IsZero() {
+		t.Errorf("binance status = %+v, want connected with 1 update", statuses[0])
+	}
+	if statuses[1].Connected || statuses[1].Error == "" {
+		t.Errorf("okx status = %+v, want disconnected with error", statuses[1])
+	}
+
+	f.Stop()
+	if binance.connected {
+		t.Error("Stop() did not disconnect binance")
+	}
+}
+
+// TestFeed_Reconnect 测试交易所断开后重新连接、订阅并记录断开和重连次数
+func TestFeed_Reconnect(t *testing.T) {
+	binance := &mockAdapter{}
+	f := New(cache.NewMemoryPriceCache(time.Minute), []string{"BTC/USDT"})
+	f.SetReconnectInterval(10 * time.Millisecond)
+	f.AddAdapter("binance", binance)
+
+	if err := f.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer f.Stop()
+
+	binance.Disconnect()
+	deadline := time.Now().Add(time.Second)
+	for f.Status()[0].Reconnects == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	status := f.Status()[0]
+	if status.Disconnects != 1 || status.Reconnects != 1 || !status.Connected {
+		t.Errorf("status = %+v, want reconnected once", status)
+	}
+	binance.mu.Lock()
+	defer binance.mu.Unlock()
+	if binance.subscribes != 2 {
+		t.Errorf("subscribes = %d, want 2", binance.subscribes)
+	}
+}
+
+// TestFeed_StartAllFailed 测试所有交易所都启动失败时返回错误
+func TestFeed_StartAllFailed(t *testing.T) {
+	f := New(cache.NewMemoryPriceCache(time.Minute), []string{"BTC/USDT"})
+	if err := f.Start(context.Background()); err == nil {
+		t.Error("Start() without adapters error = nil, want error")
+	}
+
+	f.AddAdapter("okx", &mockAdapter{connectErr: errors.New("dial timeout")})
+	if err := f.Start(context.Background()); err == nil {
+		t.Error("Start() with failing adapter error = nil, want error")
+	}
+}
+
+// TestNewAdapter 测试按名称创建适配器
+func TestNewAdapter(t *testing.T) {
+	for _, name := range []string{"binance", "OKX"} {
+		if _, err := NewAdapter(name, []string{"BTC/USDT"}); err != nil {
+			t.Errorf("NewAdapter(%s) error = %v", name, err)
+		}
+	}
+	if _, err := NewAdapter("uniswap", nil); err == nil {
+		t.Error("NewAdapter(uniswap) error = nil, want error")
+	}
+}
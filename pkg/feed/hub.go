@@ -0,0 +1,209 @@
+package feed
+
+import (
+	"strings"
+	"sync"
+
+	"arbitragex/common/cache"
+)
+
+// Update 带序号的价格更新
+type Update struct {
+	Seq   uint64           `json:"seq"`   // 递增序号（从 1 开始，进程重启后重新计数）
+	Price *cache.PriceData `json:"price"` // 价格
+}
+
+// Filter 订阅过滤条件（为空表示不过滤）
+type Filter struct {
+	Exchanges []string // 交易所（如 binance）
+	Symbols   []string // 交易对（标准格式，如 BTC/USDT）
+}
+
+// Match 价格是否满足过滤条件
+func (f Filter) Match(price *cache.PriceData) bool {
+	return matchAny(f.Exchanges, price.Exchange) && matchAny(f.Symbols, price.Symbol)
+}
+
+// matchAny values 为空或包含 value（忽略大小写）
+func matchAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription 价格更新订阅
+type Subscription struct {
+	// Updates 价格更新（订阅关闭或因消费过慢被断开后关闭）
+	Updates <-chan *Update
+
+	// Gap 请求续传的序号已不在历史记录中，中间的更新已丢失，客户端需要重新获取价格快照
+	Gap bool
+
+	hub    *Hub
+	filter Filter
+	ch     chan *Update
+	lagged bool
+}
+
+// Lagged 是否因消费过慢被断开（Updates 关闭后有效）
+func (s *Subscription) Lagged() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	return s.lagged
+}
+
+// Close 取消订阅
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
+
+// Hub 价格更新广播
+// 为每次更新分配递增序号并保留最近的更新，断线重连的客户端可以从最后收到的序号续传；
+// 每个订阅者有独立的缓冲队列，队列满时断开该订阅者而不阻塞行情回调，客户端重连后续传
+type Hub struct {
+	seq         uint64
+	history     []*Update // 环形缓冲区
+	next        int       // 下一次写入 history 的位置
+	bufferSize  int
+	subscribers map[*Subscription]struct{}
+	mu          sync.Mutex
+}
+
+// NewHub 创建价格更新广播
+// 参数:
+//   - historySize: 保留的历史更新数（可续传的范围）
+//   - bufferSize: 每个订阅者的缓冲队列长度（超过后断开该订阅者）
+// 返回:
+//   - *Hub: 广播（通过 feed.OnPrice(hub.Publish) 注册）
+func NewHub(historySize, bufferSize int) *Hub {
+	if historySize <= 0 {
+		historySize = 1
+	}
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+
+	return &Hub{
+		history:     make([]*Update, historySize),
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish 广播价格更新（PriceListener）
+func (h *Hub) Publish(price *cache.PriceData) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	update := &Update{Seq: h.seq, Price: price}
+	h.history[h.next] = update
+	h.next = (h.next + 1) % len(h.history)
+
+	for sub := range h.subscribers {
+		if !sub.filter.Match(price) {
+			continue
+		}
+		select {
+		case sub.ch <- update:
+		default:
+			// 慢消费者：断开，由客户端从最后收到的序号续传
+			sub.lagged = true
+			h.remove(sub)
+		}
+	}
+}
+
+// Seq 最近一次更新的序号
+func (h *Hub) Seq() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.seq
+}
+
+// Subscribers 当前订阅者数量
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.subscribers)
+}
+
+// Subscribe 订阅价格更新
+// 参数:
+//   - filter: 过滤条件
+//   - lastSeq: 客户端最后收到的序号（0 表示只接收新的更新），之后的历史更新会先补发
+// 返回:
+//   - *Subscription: 订阅（使用完毕后调用 Close）
+func (h *Hub) Subscribe(filter Filter, lastSeq uint64) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var missed []*Update
+	gap := false
+	if lastSeq > 0 {
+		missed, gap = h.since(lastSeq)
+	}
+
+	var replay []*Update
+	for _, update := range missed {
+		if filter.Match(update.Price) {
+			replay = append(replay, update)
+		}
+	}
+
+	ch := make(chan *Update, h.bufferSize+len(replay))
+	for _, update := range replay {
+		ch <- update
+	}
+
+	sub := &Subscription{
+		Updates: ch,
+		Gap:     gap,
+		hub:     h,
+		filter:  filter,
+		ch:      ch,
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// since 返回序号大于 lastSeq 的历史更新；lastSeq 之后的更新已被覆盖（或序号来自重启前）时 gap 为 true
+func (h *Hub) since(lastSeq uint64) (updates []*Update, gap bool) {
+	if lastSeq > h.seq {
+		// 序号来自重启前的进程
+		return nil, true
+	}
+
+	for i := 0; i < len(h.history); i++ {
+		update := h.history[(h.next+i)%len(h.history)]
+		if update == nil || update.Seq <= lastSeq {
+			continue
+		}
+		if len(updates) == 0 && update.Seq != lastSeq+1 {
+			gap = true
+		}
+		updates = append(updates, update)
+	}
+	return updates, gap
+}
+
+// remove 移除订阅者并关闭其队列（调用方持有锁）
+func (h *Hub) remove(sub *Subscription) {
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	close(sub.ch)
+}
@@ -0,0 +1,103 @@
+// Package feed 价格更新广播单元测试
+package feed
+
+import (
+	"testing"
+
+	"arbitragex/common/cache"
+)
+
+// hubPrice 构造价格
+func hubPrice(exchange, symbol string) *cache.PriceData {
+	return &cache.PriceData{Exchange: exchange, Symbol: symbol}
+}
+
+// drain 读取队列中已有的更新序号
+func drain(sub *Subscription) []uint64 {
+	var seqs []uint64
+	for {
+		select {
+		case update, ok := <-sub.Updates:
+			if !ok {
+				return seqs
+			}
+			seqs = append(seqs, update.Seq)
+		default:
+			return seqs
+		}
+	}
+}
+
+// TestHub_Filter 测试按交易所和交易对过滤
+func TestHub_Filter(t *testing.T) {
+	hub := NewHub(10, 10)
+	all := hub.Subscribe(Filter{}, 0)
+	okxBTC := hub.Subscribe(Filter{Exchanges: []string{"OKX"}, Symbols: []string{"BTC/USDT"}}, 0)
+
+	hub.Publish(hubPrice("binance", "BTC/USDT"))
+	hub.Publish(hubPrice("okx", "ETH/USDT"))
+	hub.Publish(hubPrice("okx", "BTC/USDT"))
+
+	if got := drain(all); len(got) != 3 {
+		t.Errorf("unfiltered updates = %v, want 3", got)
+	}
+	if got := drain(okxBTC); len(got) != 1 || got[0] != 3 {
+		t.Errorf("okx BTC/USDT updates = %v, want [3]", got)
+	}
+
+	okxBTC.Close()
+	okxBTC.Close()
+	if hub.Subscribers() != 1 {
+		t.Errorf("Subscribers() = %d, want 1 after Close", hub.Subscribers())
+	}
+}
+
+// TestHub_Resume 测试从序号续传，以及历史已被覆盖时标记缺口
+func TestHub_Resume(t *testing.T) {
+	hub := NewHub(3, 10)
+	for i := 0; i < 5; i++ {
+		hub.Publish(hubPrice("binance", "BTC/USDT"))
+	}
+
+	sub := hub.Subscribe(Filter{}, 3)
+	if got := drain(sub); sub.Gap || len(got) != 2 || got[0] != 4 || got[1] != 5 {
+		t.Errorf("resume from 3 = %v gap %v, want [4 5] without gap", got, sub.Gap)
+	}
+
+	sub = hub.Subscribe(Filter{}, 1)
+	if got := drain(sub); !sub.Gap || len(got) != 3 || got[0] != 3 {
+		t.Errorf("resume from 1 = %v gap %v, want [3 4 5] with gap", got, sub.Gap)
+	}
+
+	sub = hub.Subscribe(Filter{}, 5)
+	if got := drain(sub); sub.Gap || len(got) != 0 {
+		t.Errorf("resume from latest = %v gap %v, want none", got, sub.Gap)
+	}
+
+	sub = hub.Subscribe(Filter{}, 100)
+	if !sub.Gap {
+		t.Error("resume from future seq gap = false, want true")
+	}
+}
+
+// TestHub_SlowConsumer 测试队列满时断开慢消费者，不影响其他订阅者
+func TestHub_SlowConsumer(t *testing.T) {
+	hub := NewHub(10, 2)
+	slow := hub.Subscribe(Filter{}, 0)
+	fast := hub.Subscribe(Filter{}, 0)
+
+	for i := 0; i < 3; i++ {
+		hub.Publish(hubPrice("binance", "BTC/USDT"))
+		drain(fast)
+	}
+
+	if got := drain(slow); len(got) != 2 {
+		t.Errorf("slow consumer updates = %v, want 2 before disconnect", got)
+	}
+	if _, ok := <-slow.Updates; ok || !slow.Lagged() {
+		t.Error("slow consumer should be disconnected and lagged")
+	}
+	if fast.Lagged() || hub.Subscribers() != 1 {
+		t.Errorf("fast lagged = %v subscribers = %d, want false 1", fast.Lagged(), hub.Subscribers())
+	}
+}
@@ -0,0 +1,54 @@
+package feed
+
+import (
+	"arbitragex/pkg/exchange"
+
+	"github.com/zeromicro/go-zero/core/metric"
+)
+
+// 行情指标（开启 Prometheus 后记录，见 DevServer / Prometheus 配置）
+var (
+	metricMessages = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "feed",
+		Name:      "messages_total",
+		Help:      "Ticker messages received from exchange WebSockets.",
+		Labels:    []string{"exchange", "symbol"},
+	})
+	metricTickLatency = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "feed",
+		Name:      "tick_latency_seconds",
+		Help:      "Delay from the exchange event time to local receipt.",
+		Labels:    []string{"exchange"},
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	})
+	metricDisconnects = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "feed",
+		Name:      "disconnects_total",
+		Help:      "Unexpected exchange WebSocket disconnects.",
+		Labels:    []string{"exchange"},
+	})
+	metricReconnects = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "arbitragex",
+		Subsystem: "feed",
+		Name:      "reconnects_total",
+		Help:      "Successful exchange WebSocket reconnects.",
+		Labels:    []string{"exchange"},
+	})
+)
+
+// observeTicker 记录行情消息数和延迟（行情没有交易所事件时间时只计数；时钟偏差导致的负延迟按 0 记录）
+func observeTicker(exchangeName string, ticker *exchange.Ticker) {
+	metricMessages.Inc(exchangeName, ticker.Symbol)
+
+	if ticker.ExchangeTime.IsZero() || ticker.Timestamp.IsZero() {
+		return
+	}
+	latency := ticker.Timestamp.Sub(ticker.ExchangeTime)
+	if latency < 0 {
+		latency = 0
+	}
+	metricTickLatency.ObserveFloat(latency.Seconds(), exchangeName)
+}
@@ -0,0 +1,185 @@
+// Package marketdata 行情录制文件
+package marketdata
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 录制文件后缀（写入中的文件带 partSuffix，关闭后去掉）
+const (
+	fileSuffix = ".jsonl.gz"
+	partSuffix = ".part"
+)
+
+// FileWriterConfig 录制文件配置
+type FileWriterConfig struct {
+	// Dir 输出目录（不存在时创建）
+	Dir string `json:"dir"`
+
+	// Prefix 文件名前缀（文件名为 <prefix>-<打开时间>-<序号>.jsonl.gz，按文件名排序即按时间排序）
+	Prefix string `json:"prefix"`
+
+	// RotateInterval 按时间切分文件（≤ 0 表示不按时间切分）
+	RotateInterval time.Duration `json:"rotate_interval"`
+
+	// MaxBytes 单个文件压缩后的大小上限（按已写入磁盘的字节数判断，缓冲中的数据不计入；≤ 0 表示不限制）
+	MaxBytes int64 `json:"max_bytes"`
+}
+
+// FileWriter 按时间和大小切分的 gzip 压缩录制文件
+// 写入中的文件以 .part 结尾，切分或关闭后重命名为 .jsonl.gz，因此目录中完整的文件都可以直接回放
+type FileWriter struct {
+	config FileWriterConfig
+
+	file     *os.File
+	counter  *countingWriter
+	gz       *gzip.Writer
+	writer   *Writer
+	path     string
+	openedAt time.Time
+	seq      int
+	closed   bool
+	now      func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewFileWriter 创建录制文件写入器（第一次写入时创建文件）
+// 参数:
+//   - config: 录制文件配置
+// 返回:
+//   - *FileWriter: 录制文件写入器
+//   - error: 创建目录失败时返回错误
+func NewFileWriter(config FileWriterConfig) (*FileWriter, error) {
+	if config.Dir == "" {
+		config.Dir = "."
+	}
+	if config.Prefix == "" {
+		config.Prefix = "marketdata"
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建录制目录失败: %w", err)
+	}
+
+	return &FileWriter{config: config, now: time.Now}, nil
+}
+
+// Write 写入一个事件（需要时切分文件）
+func (w *FileWriter) Write(event *Event) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return fmt.Errorf("录制文件已关闭")
+	}
+	if w.writer != nil && w.shouldRotate() {
+		if err := w.closeFile(); err != nil {
+			return err
+		}
+	}
+	if w.writer == nil {
+		if err := w.openFile(); err != nil {
+			return err
+		}
+	}
+	return w.writer.Write(event)
+}
+
+// Flush 将缓冲的数据压缩写入文件（进程崩溃时最多丢失上次 Flush 之后的数据）
+func (w *FileWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.writer == nil {
+		return nil
+	}
+	if err := w.writer.Flush(); err != nil {
+		return fmt.Errorf("写入录制文件失败: %w", err)
+	}
+	if err := w.gz.Flush(); err != nil {
+		return fmt.Errorf("写入录制文件失败: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭当前文件（之后的写入返回错误）
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.writer == nil {
+		return nil
+	}
+	return w.closeFile()
+}
+
+// shouldRotate 当前文件是否需要切分
+func (w *FileWriter) shouldRotate() bool {
+	if w.config.RotateInterval > 0 && w.now().Sub(w.openedAt) >= w.config.RotateInterval {
+		return true
+	}
+	return w.config.MaxBytes > 0 && w.counter.n >= w.config.MaxBytes
+}
+
+// openFile 创建新文件
+func (w *FileWriter) openFile() error {
+	w.openedAt = w.now()
+	w.seq++
+	stamp := strings.Replace(w.openedAt.UTC().Format("20060102T150405.000"), ".", "", 1)
+	w.path = filepath.Join(w.config.Dir, fmt.Sprintf("%s-%s-%d%s", w.config.Prefix, stamp, w.seq, fileSuffix))
+
+	file, err := os.OpenFile(w.path+partSuffix, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("创建录制文件失败: %w", err)
+	}
+
+	w.file = file
+	w.counter = &countingWriter{w: file}
+	w.gz = gzip.NewWriter(w.counter)
+	w.writer = NewWriter(w.gz)
+	return nil
+}
+
+// closeFile 写完并关闭当前文件，去掉 .part 后缀
+func (w *FileWriter) closeFile() error {
+	defer func() {
+		w.file, w.counter, w.gz, w.writer = nil, nil, nil, nil
+	}()
+
+	if err := w.writer.Flush(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("写入录制文件失败: %w", err)
+	}
+	if err := w.gz.Close(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("写入录制文件失败: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("关闭录制文件失败: %w", err)
+	}
+	if err := os.Rename(w.path+partSuffix, w.path); err != nil {
+		return fmt.Errorf("重命名录制文件失败: %w", err)
+	}
+	return nil
+}
+
+// countingWriter 统计写入的字节数
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write 写入并累计字节数
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,276 @@
+// Package marketdata 行情数据的录制格式
+// 职责：定义录制文件中的行情事件（JSON Lines，每行一个事件），提供读写和多文件按时间合并
+package marketdata
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/pkg/execution"
+)
+
+// 行情事件类型
+const (
+	EventTicker    = "ticker"    // 最优买卖价
+	EventOrderBook = "orderbook" // 订单簿快照
+)
+
+// Event 行情事件
+type Event struct {
+	// Type 事件类型（ticker, orderbook）
+	Type string `json:"type"`
+
+	// Exchange 交易所名称
+	Exchange string `json:"exchange"`
+
+	// Symbol 交易对（如 BTC/USDT）
+	Symbol string `json:"symbol"`
+
+	// Time 行情时间（录制时为本地接收时间，回放时按此时间推进模拟时钟）
+	Time time.Time `json:"time"`
+
+	// ExchangeTime 交易所推送的事件时间（可选，与 Time 的差值即推送延迟）
+	ExchangeTime time.Time `json:"exchange_time,omitempty"`
+
+	// Ticker 最优买卖价（Type 为 ticker 时）
+	Ticker *cache.PriceData `json:"ticker,omitempty"`
+
+	// OrderBook 订单簿快照（Type 为 orderbook 时）
+	OrderBook *execution.OrderBook `json:"orderbook,omitempty"`
+
+	// Raw 原始 WebSocket 消息（可选，用于复现解析问题）
+	Raw json.RawMessage `json:"raw,omitempty"`
+}
+
+// NewTickerEvent 创建最优买卖价事件
+func NewTickerEvent(ticker *cache.PriceData) *Event {
+	return &Event{
+		Type:     EventTicker,
+		Exchange: ticker.Exchange,
+		Symbol:   ticker.Symbol,
+		Time:     ticker.Timestamp,
+		Ticker:   ticker,
+	}
+}
+
+// NewOrderBookEvent 创建订单簿快照事件
+func NewOrderBookEvent(book *execution.OrderBook) *Event {
+	return &Event{
+		Type:      EventOrderBook,
+		Exchange:  book.Exchange,
+		Symbol:    book.Symbol,
+		Time:      book.Timestamp,
+		OrderBook: book,
+	}
+}
+
+// validate 校验事件内容与类型一致
+func (e *Event) validate() error {
+	switch e.Type {
+	case EventTicker:
+		if e.Ticker == nil {
+			return fmt.Errorf("ticker 事件缺少 ticker 字段")
+		}
+	case EventOrderBook:
+		if e.OrderBook == nil {
+			return fmt.Errorf("orderbook 事件缺少 orderbook 字段")
+		}
+	default:
+		return fmt.Errorf("未知的行情事件类型: %s", e.Type)
+	}
+	if e.Time.IsZero() {
+		return fmt.Errorf("行情事件缺少时间")
+	}
+	return nil
+}
+
+// EventReader 行情事件读取器
+type EventReader interface {
+	// Next 读取下一个事件
+	// 返回:
+	//   - *Event: 行情事件
+	//   - error: 读完时返回 io.EOF
+	Next() (*Event, error)
+}
+
+// Reader 从 JSON Lines 读取行情事件
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// maxLineSize 单行最大长度（深度较大的订单簿一行可能超过 bufio 默认的 64KB）
+const maxLineSize = 4 * 1024 * 1024
+
+// NewReader 创建行情事件读取器
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	return &Reader{scanner: scanner}
+}
+
+// Next 读取下一个事件（跳过空行）
+func (r *Reader) Next() (*Event, error) {
+	for r.scanner.Scan() {
+		r.line++
+		data := r.scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("第 %d 行: 解析行情事件失败: %w", r.line, err)
+		}
+		if err := event.validate(); err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", r.line, err)
+		}
+		return &event, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取行情数据失败: %w", err)
+	}
+	return nil, io.EOF
+}
+
+// Writer 以 JSON Lines 写入行情事件
+type Writer struct {
+	w       *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewWriter 创建行情事件写入器（写完需要调用 Flush）
+func NewWriter(w io.Writer) *Writer {
+	buffered := bufio.NewWriter(w)
+	return &Writer{w: buffered, encoder: json.NewEncoder(buffered)}
+}
+
+// Write 写入一个事件
+func (w *Writer) Write(event *Event) error {
+	if err := event.validate(); err != nil {
+		return err
+	}
+	if err := w.encoder.Encode(event); err != nil {
+		return fmt.Errorf("写入行情事件失败: %w", err)
+	}
+	return nil
+}
+
+// Flush 将缓冲的数据写入底层 io.Writer
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// MergeReader 将多个按时间排序的读取器合并为一个按时间排序的事件流
+// 时间相同时按读取器的顺序输出
+type MergeReader struct {
+	readers []EventReader
+	heads   []*Event
+	started bool
+}
+
+// NewMergeReader 创建合并读取器
+func NewMergeReader(readers ...EventReader) *MergeReader {
+	return &MergeReader{
+		readers: readers,
+		heads:   make([]*Event, len(readers)),
+	}
+}
+
+// Next 读取时间最早的事件
+func (m *MergeReader) Next() (*Event, error) {
+	if !m.started {
+		m.started = true
+		for i := range m.readers {
+			if err := m.advance(i); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	earliest := -1
+	for i, head := range m.heads {
+		if head == nil {
+			continue
+		}
+		if earliest < 0 || head.Time.Before(m.heads[earliest].Time) {
+			earliest = i
+		}
+	}
+	if earliest < 0 {
+		return nil, io.EOF
+	}
+
+	event := m.heads[earliest]
+	if err := m.advance(earliest); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// advance 读取第 i 个读取器的下一个事件
+func (m *MergeReader) advance(i int) error {
+	event, err := m.readers[i].Next()
+	switch {
+	case err == io.EOF:
+		m.heads[i] = nil
+	case err != nil:
+		return err
+	default:
+		m.heads[i] = event
+	}
+	return nil
+}
+
+// OpenFiles 打开多个录制文件并按时间合并
+// 以 .gz 结尾的文件按 gzip 解压读取
+// 参数:
+//   - paths: 录制文件路径（每个文件内部按时间排序）
+// 返回:
+//   - *MergeReader: 合并读取器
+//   - func() error: 关闭所有文件
+//   - error: 错误信息
+func OpenFiles(paths ...string) (*MergeReader, func() error, error) {
+	closers := make([]io.Closer, 0, len(paths))
+	closeAll := func() error {
+		var firstErr error
+		for i := len(closers) - 1; i >= 0; i-- {
+			if err := closers[i].Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+
+	readers := make([]EventReader, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("打开行情文件失败: %w", err)
+		}
+		closers = append(closers, f)
+
+		var r io.Reader = f
+		if strings.HasSuffix(path, ".gz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				closeAll()
+				return nil, nil, fmt.Errorf("打开行情文件 %s 失败: %w", path, err)
+			}
+			closers = append(closers, gz)
+			r = gz
+		}
+		readers = append(readers, NewReader(r))
+	}
+
+	return NewMergeReader(readers...), closeAll, nil
+}
@@ -0,0 +1,179 @@
+// Package marketdata 行情录制格式单元测试
+package marketdata
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/execution"
+)
+
+// TestWriterReader 测试写入后读回
+func TestWriterReader(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	events := []*Event{
+		NewTickerEvent(&cache.PriceData{Exchange: "binance", Symbol: "BTC/USDT", BidPrice: decimal.NewFromFloat(43000), AskPrice: decimal.NewFromFloat(43001), Timestamp: t0}),
+		NewOrderBookEvent(&execution.OrderBook{
+			Exchange:  "okx",
+			Symbol:    "BTC/USDT",
+			Bids:      []execution.OrderBookLevel{{Price: decimal.NewFromFloat(43100), Amount: decimal.NewFromFloat(0.5)}},
+			Asks:      []execution.OrderBookLevel{{Price: decimal.NewFromFloat(43102), Amount: decimal.NewFromFloat(0.7)}},
+			Timestamp: t0.Add(time.Second),
+		}),
+	}
+	for _, event := range events {
+		if err := w.Write(event); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	r := NewReader(&buf)
+	first, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if first.Type != EventTicker || !first.Ticker.AskPrice.Equal(decimal.NewFromFloat(43001)) || !first.Time.Equal(t0) {
+		t.Errorf("first = %+v, want binance ticker at %v", first, t0)
+	}
+	second, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if second.Type != EventOrderBook || !second.OrderBook.Asks[0].Amount.Equal(decimal.NewFromFloat(0.7)) {
+		t.Errorf("second = %+v, want okx orderbook", second)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next() at end error = %v, want io.EOF", err)
+	}
+
+	if err := w.Write(&Event{Type: EventTicker, Time: t0}); err == nil {
+		t.Error("Write() ticker event without ticker error = nil, want error")
+	}
+}
+
+// TestReader_InvalidLine 测试错误信息包含行号
+func TestReader_InvalidLine(t *testing.T) {
+	input := `{"type":"ticker","exchange":"binance","symbol":"BTC/USDT","time":"2024-01-01T00:00:00Z","ticker":{"bid_price":1}}
+
+{"type":"trade","time":"2024-01-01T00:00:01Z"}
+`
+	r := NewReader(strings.NewReader(input))
+	if _, err := r.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	_, err := r.Next()
+	if err == nil || !strings.Contains(err.Error(), "第 3 行") {
+		t.Errorf("Next() error = %v, want error on line 3", err)
+	}
+}
+
+// TestMergeReader 测试多个文件按时间合并，时间相同时按读取器顺序
+func TestMergeReader(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ticker := func(exchange string, offset time.Duration) *Event {
+		return NewTickerEvent(&cache.PriceData{Exchange: exchange, Symbol: "BTC/USDT", Timestamp: t0.Add(offset)})
+	}
+
+	encode := func(events ...*Event) EventReader {
+		var buf bytes.Buffer
+		w := NewWriter(&buf)
+		for _, event := range events {
+			if err := w.Write(event); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+		}
+		w.Flush()
+		return NewReader(&buf)
+	}
+
+	m := NewMergeReader(
+		encode(ticker("binance", 0), ticker("binance", 2*time.Second)),
+		encode(),
+		encode(ticker("okx", time.Second), ticker("okx", 2*time.Second), ticker("okx", 3*time.Second)),
+	)
+
+	var got []string
+	for {
+		event, err := m.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		got = append(got, event.Exchange+"@"+event.Time.Sub(t0).String())
+	}
+
+	want := "binance@0s okx@1s binance@2s okx@2s okx@3s"
+	if strings.Join(got, " ") != want {
+		t.Errorf("merged = %v, want %v", got, want)
+	}
+}
+
+// TestFileWriter 测试按大小切分的压缩文件可以直接回放
+func TestFileWriter(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewFileWriter(FileWriterConfig{Dir: dir, Prefix: "test", MaxBytes: 1})
+	if err != nil {
+		t.Fatalf("NewFileWriter() error = %v", err)
+	}
+
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		event := NewTickerEvent(&cache.PriceData{Exchange: "binance", Symbol: "BTC/USDT", BidPrice: decimal.NewFromInt(int64(i)), Timestamp: t0.Add(time.Duration(i) * time.Second)})
+		event.Raw = []byte(`{"s":"BTCUSDT"}`)
+		if err := w.Write(event); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush() error = %v", err)
+		}
+	}
+
+	parts, _ := filepath.Glob(filepath.Join(dir, "*"+partSuffix))
+	if len(parts) != 1 {
+		t.Errorf("part files before Close = %v, want 1", parts)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "test-*"+fileSuffix))
+	if len(files) != 3 {
+		t.Fatalf("files = %v, want 3", files)
+	}
+	if parts, _ := filepath.Glob(filepath.Join(dir, "*"+partSuffix)); len(parts) != 0 {
+		t.Errorf("part files after Close = %v, want none", parts)
+	}
+
+	reader, closeFiles, err := OpenFiles(files...)
+	if err != nil {
+		t.Fatalf("OpenFiles() error = %v", err)
+	}
+	defer closeFiles()
+
+	for i := 0; i < 3; i++ {
+		event, err := reader.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if !event.Ticker.BidPrice.Equal(decimal.NewFromInt(int64(i))) || string(event.Raw) != `{"s":"BTCUSDT"}` {
+			t.Errorf("event %d = bid %v raw %s", i, event.Ticker.BidPrice, event.Raw)
+		}
+	}
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("Next() at end error = %v, want io.EOF", err)
+	}
+}
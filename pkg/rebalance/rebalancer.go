@@ -0,0 +1,540 @@
+// Package rebalance 持仓再平衡
+// 职责：监控各交易所之间的资产分布，在失衡超过阈值时生成（并可选执行）跨交易所转账计划
+package rebalance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/execution"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// NetworkConfig 某交易所某币种的提币网络配置
+type NetworkConfig struct {
+	Network     string          `json:"network"`      // 网络名称（交易所自己的命名，如 Binance 的 TRX、OKX 的 USDT-TRC20）
+	WithdrawFee decimal.Decimal `json:"withdraw_fee"` // 提币手续费（单位: 币）
+	MinWithdraw decimal.Decimal `json:"min_withdraw"` // 最小提币数量（单位: 币）
+}
+
+// AssetConfig 单个币种的再平衡配置
+type AssetConfig struct {
+	Asset         string                    `json:"asset"`          // 币种（如 USDT、BTC）
+	Threshold     float64                   `json:"threshold"`      // 失衡阈值（如 0.2 = 偏离目标 20% 时触发）
+	MinTransfer   decimal.Decimal           `json:"min_transfer"`   // 最小转账数量（低于此数量不转账）
+	TargetWeights map[string]float64        `json:"target_weights"` // 目标分布（exchange -> 权重），为空时平均分配
+	Networks      map[string]*NetworkConfig `json:"networks"`       // 各交易所的网络配置（exchange -> 网络）
+}
+
+// Config 再平衡配置
+type Config struct {
+	Exchanges []string       `json:"exchanges"` // 参与再平衡的交易所
+	Assets    []*AssetConfig `json:"assets"`    // 需要再平衡的币种
+	DryRun    bool           `json:"dry_run"`   // 仅生成计划，不执行转账
+	Interval  time.Duration  `json:"interval"`  // 检查间隔
+
+	// PendingTimeout 在途转账的最长等待时间，超过后不再计入在途数量（<= 0 表示一直等待到账）
+	PendingTimeout time.Duration `json:"pending_timeout"`
+}
+
+// DefaultConfig 默认再平衡配置（Binance <-> OKX，USDT 走 TRC20）
+func DefaultConfig() *Config {
+	return &Config{
+		Exchanges: []string{"binance", "okx"},
+		Assets: []*AssetConfig{
+			{
+				Asset:       "USDT",
+				Threshold:   0.2, // 偏离 20% 触发
+				MinTransfer: decimal.NewFromInt(100),
+				Networks: map[string]*NetworkConfig{
+					"binance": {Network: "TRX", WithdrawFee: decimal.NewFromInt(1), MinWithdraw: decimal.NewFromInt(10)},
+					"okx":     {Network: "USDT-TRC20", WithdrawFee: decimal.NewFromInt(1), MinWithdraw: decimal.NewFromInt(10)},
+				},
+			},
+		},
+		DryRun:         true, // 默认只输出计划
+		Interval:       5 * time.Minute,
+		PendingTimeout: 2 * time.Hour,
+	}
+}
+
+// AssetSkew 单个币种的分布情况
+type AssetSkew struct {
+	Asset     string                     `json:"asset"`                // 币种
+	Total     decimal.Decimal            `json:"total"`                // 所有交易所合计
+	Balances  map[string]decimal.Decimal `json:"balances"`             // 各交易所余额（含在途转入）
+	InTransit map[string]decimal.Decimal `json:"in_transit,omitempty"` // 各交易所尚未到账的转入数量
+	Targets   map[string]decimal.Decimal `json:"targets"`              // 各交易所目标余额
+	Skew      float64                    `json:"skew"`                 // 最大偏离比例（|余额 - 目标| / 目标）
+	Breached  bool                       `json:"breached"`             // 是否超过阈值
+}
+
+// Transfer 单笔转账计划
+type Transfer struct {
+	Asset        string          `json:"asset"`                   // 币种
+	From         string          `json:"from"`                    // 转出交易所
+	To           string          `json:"to"`                      // 转入交易所
+	Network      string          `json:"network"`                 // 转出交易所的提币网络
+	DepositNet   string          `json:"deposit_network"`         // 转入交易所的充值网络
+	Amount       decimal.Decimal `json:"amount"`                  // 提币数量（含手续费）
+	Fee          decimal.Decimal `json:"fee"`                     // 提币手续费
+	Received     decimal.Decimal `json:"received"`                // 预计到账数量
+	Status       string          `json:"status"`                  // 状态
+	WithdrawalID string          `json:"withdrawal_id,omitempty"` // 提币ID（执行后）
+	Error        string          `json:"error,omitempty"`         // 错误信息（执行失败时）
+}
+
+// Plan 再平衡计划
+type Plan struct {
+	CreatedAt time.Time    `json:"created_at"` // 生成时间
+	DryRun    bool         `json:"dry_run"`    // 是否为演练模式
+	Skews     []*AssetSkew `json:"skews"`      // 各币种分布
+	Transfers []*Transfer  `json:"transfers"`  // 转账计划
+	Skipped   []string     `json:"skipped"`    // 因手续费 / 最小提币量而跳过的说明
+}
+
+// amountPlaces 转账数量保留的小数位数
+const amountPlaces = 8
+
+// 转账状态常量
+const (
+	TransferStatusPlanned   = "planned"   // 已计划
+	TransferStatusSubmitted = "submitted" // 已提交提币
+	TransferStatusFailed    = "failed"    // 失败
+)
+
+// pendingTransfer 已提交提币但尚未确认到账的转账
+type pendingTransfer struct {
+	transfer    *Transfer
+	baseline    decimal.Decimal // 提交时转入交易所的余额（含更早的在途转入）
+	submittedAt time.Time
+}
+
+// Rebalancer 持仓再平衡器
+type Rebalancer struct {
+	config    *Config
+	executors map[string]execution.TransferExecutor
+	pending   []*pendingTransfer // 在途转账，到账或超时前计入转入交易所的余额
+	mu        sync.Mutex
+	logger    logx.Logger
+}
+
+// NewRebalancer 创建再平衡器
+// executors: exchange -> TransferExecutor（BinanceExecutor、OKXExecutor 均已实现）
+func NewRebalancer(config *Config, executors map[string]execution.TransferExecutor) *Rebalancer {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Rebalancer{
+		config:    config,
+		executors: executors,
+		logger:    logx.WithContext(context.Background()),
+	}
+}
+
+// FetchBalances 从各交易所获取可用余额
+// 返回: exchange -> asset -> 可用余额
+func (r *Rebalancer) FetchBalances(ctx context.Context) (map[string]map[string]decimal.Decimal, error) {
+	balances := make(map[string]map[string]decimal.Decimal)
+
+	for _, exchange := range r.config.Exchanges {
+		executor, ok := r.executors[exchange]
+		if !ok {
+			return nil, fmt.Errorf("executor not found: %s", exchange)
+		}
+
+		items, err := executor.GetBalances(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get balances from %s: %w", exchange, err)
+		}
+
+		balances[exchange] = make(map[string]decimal.Decimal)
+		for _, item := range items {
+			asset := strings.ToUpper(item.Asset)
+			balances[exchange][asset] = balances[exchange][asset].Add(item.Free)
+		}
+	}
+
+	return balances, nil
+}
+
+// Plan 获取余额并生成再平衡计划
+// 先用最新余额确认在途转账是否到账，未到账的转账计入转入交易所的余额
+func (r *Rebalancer) Plan(ctx context.Context) (*Plan, error) {
+	balances, err := r.FetchBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.settlePending(balances)
+	return r.BuildPlan(balances), nil
+}
+
+// BuildPlan 根据余额生成再平衡计划
+// balances: exchange -> asset -> 可用余额（在途转账由再平衡器自动计入）
+func (r *Rebalancer) BuildPlan(balances map[string]map[string]decimal.Decimal) *Plan {
+	plan := &Plan{
+		CreatedAt: time.Now(),
+		DryRun:    r.config.DryRun,
+	}
+
+	inTransit := r.inTransit()
+	for _, asset := range r.config.Assets {
+		skew := r.calculateSkew(asset, balances, inTransit)
+		plan.Skews = append(plan.Skews, skew)
+
+		if !skew.Breached {
+			continue
+		}
+
+		transfers, skipped := r.planAsset(asset, skew)
+		plan.Transfers = append(plan.Transfers, transfers...)
+		plan.Skipped = append(plan.Skipped, skipped...)
+	}
+
+	return plan
+}
+
+// calculateSkew 计算单个币种在各交易所的分布和偏离度
+// 在途转入计入转入交易所的余额（转出交易所的可用余额在提币时已扣除）
+func (r *Rebalancer) calculateSkew(asset *AssetConfig, balances, inTransit map[string]map[string]decimal.Decimal) *AssetSkew {
+	skew := &AssetSkew{
+		Asset:    asset.Asset,
+		Balances: make(map[string]decimal.Decimal),
+		Targets:  make(map[string]decimal.Decimal),
+	}
+
+	for _, exchange := range r.config.Exchanges {
+		balance := balances[exchange][strings.ToUpper(asset.Asset)]
+		if pending := inTransit[exchange][strings.ToUpper(asset.Asset)]; pending.IsPositive() {
+			if skew.InTransit == nil {
+				skew.InTransit = make(map[string]decimal.Decimal)
+			}
+			skew.InTransit[exchange] = pending
+			balance = balance.Add(pending)
+		}
+		skew.Balances[exchange] = balance
+		skew.Total = skew.Total.Add(balance)
+	}
+
+	if !skew.Total.IsPositive() {
+		return skew
+	}
+
+	weights := r.targetWeights(asset)
+	for _, exchange := range r.config.Exchanges {
+		target := skew.Total.Mul(decimal.NewFromFloat(weights[exchange]))
+		skew.Targets[exchange] = target
+
+		if !target.IsPositive() {
+			continue
+		}
+		deviation := skew.Balances[exchange].Sub(target).Abs().Div(target).Float64()
+		if deviation > skew.Skew {
+			skew.Skew = deviation
+		}
+	}
+
+	skew.Breached = skew.Skew > asset.Threshold
+	return skew
+}
+
+// targetWeights 归一化目标权重（未配置时各交易所平均分配）
+func (r *Rebalancer) targetWeights(asset *AssetConfig) map[string]float64 {
+	weights := make(map[string]float64)
+
+	sum := 0.0
+	for _, exchange := range r.config.Exchanges {
+		w := 1.0
+		if len(asset.TargetWeights) > 0 {
+			w = asset.TargetWeights[exchange]
+		}
+		weights[exchange] = w
+		sum += w
+	}
+
+	if sum <= 0 {
+		return weights
+	}
+	for exchange := range weights {
+		weights[exchange] /= sum
+	}
+	return weights
+}
+
+// planAsset 为失衡的币种生成转账计划
+// 贪心匹配：余额最多的交易所优先转给缺口最大的交易所，转出数量包含手续费，使到账后接近目标
+func (r *Rebalancer) planAsset(asset *AssetConfig, skew *AssetSkew) ([]*Transfer, []string) {
+	type position struct {
+		exchange string
+		amount   decimal.Decimal
+	}
+
+	var surpluses, deficits []*position
+	for _, exchange := range r.config.Exchanges {
+		diff := skew.Balances[exchange].Sub(skew.Targets[exchange])
+		if diff.IsPositive() {
+			surpluses = append(surpluses, &position{exchange: exchange, amount: diff})
+		} else if diff.IsNegative() {
+			deficits = append(deficits, &position{exchange: exchange, amount: diff.Neg()})
+		}
+	}
+
+	sort.Slice(surpluses, func(i, j int) bool { return surpluses[i].amount.GreaterThan(surpluses[j].amount) })
+	sort.Slice(deficits, func(i, j int) bool { return deficits[i].amount.GreaterThan(deficits[j].amount) })
+
+	var transfers []*Transfer
+	var skipped []string
+
+	for _, deficit := range deficits {
+		for _, surplus := range surpluses {
+			if !deficit.amount.IsPositive() {
+				break
+			}
+			if !surplus.amount.IsPositive() {
+				continue
+			}
+
+			network := asset.Networks[surplus.exchange]
+			depositNet := asset.Networks[deficit.exchange]
+			if network == nil || depositNet == nil {
+				skipped = append(skipped, fmt.Sprintf("%s %s -> %s: network not configured",
+					asset.Asset, surplus.exchange, deficit.exchange))
+				continue
+			}
+
+			// 转出数量 = 缺口 + 手续费，不超过盈余（按 8 位小数截断）
+			amount := decimal.Min(deficit.amount.Add(network.WithdrawFee), surplus.amount).Truncate(amountPlaces)
+			received := amount.Sub(network.WithdrawFee)
+
+			if !received.IsPositive() {
+				skipped = append(skipped, fmt.Sprintf("%s %s -> %s: amount %.8f does not cover fee %.8f",
+					asset.Asset, surplus.exchange, deficit.exchange, amount, network.WithdrawFee))
+				continue
+			}
+			if amount.LessThan(network.MinWithdraw) {
+				skipped = append(skipped, fmt.Sprintf("%s %s -> %s: amount %.8f below network minimum %.8f",
+					asset.Asset, surplus.exchange, deficit.exchange, amount, network.MinWithdraw))
+				continue
+			}
+			if amount.LessThan(asset.MinTransfer) {
+				skipped = append(skipped, fmt.Sprintf("%s %s -> %s: amount %.8f below min transfer %.8f",
+					asset.Asset, surplus.exchange, deficit.exchange, amount, asset.MinTransfer))
+				continue
+			}
+
+			transfers = append(transfers, &Transfer{
+				Asset:      asset.Asset,
+				From:       surplus.exchange,
+				To:         deficit.exchange,
+				Network:    network.Network,
+				DepositNet: depositNet.Network,
+				Amount:     amount,
+				Fee:        network.WithdrawFee,
+				Received:   received,
+				Status:     TransferStatusPlanned,
+			})
+
+			surplus.amount = surplus.amount.Sub(amount)
+			deficit.amount = deficit.amount.Sub(received)
+		}
+	}
+
+	return transfers, skipped
+}
+
+// Execute 执行再平衡计划
+// 对每笔转账：查询转入交易所的充值地址，然后在转出交易所提币。
+// 演练模式下直接返回错误，避免误操作
+func (r *Rebalancer) Execute(ctx context.Context, plan *Plan) error {
+	if plan == nil {
+		return fmt.Errorf("plan is nil")
+	}
+	if r.config.DryRun || plan.DryRun {
+		return fmt.Errorf("rebalancer is in dry-run mode")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var firstErr error
+	for _, transfer := range plan.Transfers {
+		if transfer.Status != TransferStatusPlanned {
+			continue
+		}
+
+		if err := r.executeTransfer(ctx, transfer); err != nil {
+			transfer.Status = TransferStatusFailed
+			transfer.Error = err.Error()
+			r.logger.Errorf("再平衡转账失败: %s %s -> %s: %v", transfer.Asset, transfer.From, transfer.To, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		r.pending = append(r.pending, &pendingTransfer{
+			transfer:    transfer,
+			baseline:    plan.balance(transfer.Asset, transfer.To),
+			submittedAt: time.Now(),
+		})
+	}
+
+	return firstErr
+}
+
+// balance 计划生成时某币种在某交易所的余额（含在途转入）
+func (p *Plan) balance(asset, exchange string) decimal.Decimal {
+	for _, skew := range p.Skews {
+		if skew.Asset == asset {
+			return skew.Balances[exchange]
+		}
+	}
+	return decimal.Zero
+}
+
+// inTransit 汇总在途转账的预计到账数量
+// 返回: exchange -> asset -> 在途数量
+func (r *Rebalancer) inTransit() map[string]map[string]decimal.Decimal {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	amounts := make(map[string]map[string]decimal.Decimal)
+	for _, p := range r.pending {
+		asset := strings.ToUpper(p.transfer.Asset)
+		if amounts[p.transfer.To] == nil {
+			amounts[p.transfer.To] = make(map[string]decimal.Decimal)
+		}
+		amounts[p.transfer.To][asset] = amounts[p.transfer.To][asset].Add(p.transfer.Received)
+	}
+	return amounts
+}
+
+// settlePending 根据最新余额确认在途转账是否到账
+// 转入交易所的余额达到 提交时余额 + 预计到账数量 即视为到账；超过 PendingTimeout 仍未到账的记录错误后不再计入
+func (r *Rebalancer) settlePending(balances map[string]map[string]decimal.Decimal) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	kept := r.pending[:0]
+	for _, p := range r.pending {
+		transfer := p.transfer
+		balance := balances[transfer.To][strings.ToUpper(transfer.Asset)]
+
+		if balance.GreaterThanOrEqual(p.baseline.Add(transfer.Received)) {
+			r.logger.Infof("再平衡转账已到账: %s %.8f %s -> %s, 提币ID: %s",
+				transfer.Asset, transfer.Received, transfer.From, transfer.To, transfer.WithdrawalID)
+			continue
+		}
+		if r.config.PendingTimeout > 0 && now.Sub(p.submittedAt) > r.config.PendingTimeout {
+			r.logger.Errorf("再平衡转账超过 %v 未到账，不再计入在途: %s %.8f %s -> %s, 提币ID: %s",
+				r.config.PendingTimeout, transfer.Asset, transfer.Received, transfer.From, transfer.To, transfer.WithdrawalID)
+			continue
+		}
+		kept = append(kept, p)
+	}
+	r.pending = kept
+}
+
+// executeTransfer 执行单笔转账
+func (r *Rebalancer) executeTransfer(ctx context.Context, transfer *Transfer) error {
+	source, ok := r.executors[transfer.From]
+	if !ok {
+		return fmt.Errorf("executor not found: %s", transfer.From)
+	}
+	dest, ok := r.executors[transfer.To]
+	if !ok {
+		return fmt.Errorf("executor not found: %s", transfer.To)
+	}
+
+	address, err := dest.GetDepositAddress(ctx, transfer.Asset, transfer.DepositNet)
+	if err != nil {
+		return fmt.Errorf("failed to get deposit address: %w", err)
+	}
+
+	withdrawal, err := source.Withdraw(ctx, &execution.WithdrawRequest{
+		Exchange: transfer.From,
+		Asset:    transfer.Asset,
+		Network:  transfer.Network,
+		Address:  address.Address,
+		Tag:      address.Tag,
+		Amount:   transfer.Amount,
+		Fee:      transfer.Fee,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to withdraw: %w", err)
+	}
+
+	transfer.Status = TransferStatusSubmitted
+	transfer.WithdrawalID = withdrawal.ID
+	r.logger.Infof("再平衡转账已提交: %s %.8f %s -> %s (%s), 提币ID: %s",
+		transfer.Asset, transfer.Amount, transfer.From, transfer.To, transfer.Network, withdrawal.ID)
+	return nil
+}
+
+// Run 定期检查并再平衡，直到 ctx 取消
+// 演练模式下只记录计划；否则执行计划
+func (r *Rebalancer) Run(ctx context.Context) {
+	interval := r.config.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce 执行一次检查
+func (r *Rebalancer) runOnce(ctx context.Context) {
+	plan, err := r.Plan(ctx)
+	if err != nil {
+		r.logger.Errorf("生成再平衡计划失败: %v", err)
+		return
+	}
+
+	r.logPlan(plan)
+
+	if plan.DryRun || len(plan.Transfers) == 0 {
+		return
+	}
+
+	if err := r.Execute(ctx, plan); err != nil {
+		r.logger.Errorf("执行再平衡计划失败: %v", err)
+	}
+}
+
+// logPlan 输出再平衡计划
+func (r *Rebalancer) logPlan(plan *Plan) {
+	for _, skew := range plan.Skews {
+		r.logger.Infof("持仓分布 %s: 合计 %.8f, 最大偏离 %.2f%%, 余额 %v",
+			skew.Asset, skew.Total, skew.Skew*100, skew.Balances)
+	}
+
+	for _, transfer := range plan.Transfers {
+		r.logger.Infof("再平衡计划 [dry-run=%v]: %s %.8f %s -> %s (%s), 手续费 %.8f, 预计到账 %.8f",
+			plan.DryRun, transfer.Asset, transfer.Amount, transfer.From, transfer.To,
+			transfer.Network, transfer.Fee, transfer.Received)
+	}
+
+	for _, reason := range plan.Skipped {
+		r.logger.Infof("再平衡跳过: %s", reason)
+	}
+}
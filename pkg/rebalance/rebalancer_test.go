@@ -0,0 +1,276 @@
+// Package rebalance 持仓再平衡测试
+package rebalance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/execution"
+)
+
+// mockTransferExecutor 模拟资金划转执行器
+type mockTransferExecutor struct {
+	exchange    string
+	balances    []*execution.Balance
+	address     string
+	withdrawals []*execution.WithdrawRequest
+	withdrawErr error
+}
+
+func (m *mockTransferExecutor) GetBalances(ctx context.Context) ([]*execution.Balance, error) {
+	return m.balances, nil
+}
+
+func (m *mockTransferExecutor) GetDepositAddress(ctx context.Context, asset, network string) (*execution.DepositAddress, error) {
+	return &execution.DepositAddress{
+		Exchange: m.exchange,
+		Asset:    asset,
+		Network:  network,
+		Address:  m.address,
+	}, nil
+}
+
+func (m *mockTransferExecutor) Withdraw(ctx context.Context, req *execution.WithdrawRequest) (*execution.Withdrawal, error) {
+	if m.withdrawErr != nil {
+		return nil, m.withdrawErr
+	}
+	m.withdrawals = append(m.withdrawals, req)
+	return &execution.Withdrawal{
+		ID:       fmt.Sprintf("wd-%d", len(m.withdrawals)),
+		Exchange: m.exchange,
+		Asset:    req.Asset,
+		Amount:   req.Amount,
+	}, nil
+}
+
+// TestBuildPlan_Balanced 测试分布均衡时不生成转账
+func TestBuildPlan_Balanced(t *testing.T) {
+	r := NewRebalancer(DefaultConfig(), nil)
+
+	plan := r.BuildPlan(map[string]map[string]decimal.Decimal{
+		"binance": {"USDT": decimal.NewFromInt(5000)},
+		"okx":     {"USDT": decimal.NewFromInt(4500)},
+	})
+
+	if len(plan.Transfers) != 0 {
+		t.Errorf("Transfers = %d, want 0", len(plan.Transfers))
+	}
+	if len(plan.Skews) != 1 || plan.Skews[0].Breached {
+		t.Errorf("skew should not be breached: %+v", plan.Skews)
+	}
+}
+
+// TestBuildPlan_Skewed 测试失衡时生成包含手续费的转账
+func TestBuildPlan_Skewed(t *testing.T) {
+	r := NewRebalancer(DefaultConfig(), nil)
+
+	plan := r.BuildPlan(map[string]map[string]decimal.Decimal{
+		"binance": {"USDT": decimal.NewFromInt(9000)},
+		"okx":     {"USDT": decimal.NewFromInt(1000)},
+	})
+
+	if len(plan.Transfers) != 1 {
+		t.Fatalf("Transfers = %d, want 1", len(plan.Transfers))
+	}
+
+	transfer := plan.Transfers[0]
+	if transfer.From != "binance" || transfer.To != "okx" {
+		t.Errorf("direction = %s -> %s, want binance -> okx", transfer.From, transfer.To)
+	}
+	if transfer.Network != "TRX" || transfer.DepositNet != "USDT-TRC20" {
+		t.Errorf("networks = %s / %s, want TRX / USDT-TRC20", transfer.Network, transfer.DepositNet)
+	}
+	// 缺口 4000 + 手续费 1 超出盈余 4000，按盈余转出，到账扣除手续费
+	if !transfer.Amount.Equal(decimal.NewFromInt(4000)) {
+		t.Errorf("Amount = %s, want 4000", transfer.Amount)
+	}
+	if !transfer.Received.Equal(decimal.NewFromInt(3999)) {
+		t.Errorf("Received = %s, want 3999", transfer.Received)
+	}
+	if transfer.Status != TransferStatusPlanned {
+		t.Errorf("Status = %s, want %s", transfer.Status, TransferStatusPlanned)
+	}
+}
+
+// TestBuildPlan_TargetWeights 测试自定义目标权重
+func TestBuildPlan_TargetWeights(t *testing.T) {
+	config := DefaultConfig()
+	config.Assets[0].TargetWeights = map[string]float64{"binance": 3, "okx": 1}
+	r := NewRebalancer(config, nil)
+
+	plan := r.BuildPlan(map[string]map[string]decimal.Decimal{
+		"binance": {"USDT": decimal.NewFromInt(7500)},
+		"okx":     {"USDT": decimal.NewFromInt(2500)},
+	})
+
+	if len(plan.Transfers) != 0 {
+		t.Errorf("Transfers = %d, want 0 (already at 75/25 target)", len(plan.Transfers))
+	}
+}
+
+// TestBuildPlan_BelowMinimum 测试低于网络最小提币量时跳过
+func TestBuildPlan_BelowMinimum(t *testing.T) {
+	config := DefaultConfig()
+	config.Assets[0].MinTransfer = decimal.Zero
+	config.Assets[0].Networks["binance"].MinWithdraw = decimal.NewFromInt(500)
+	r := NewRebalancer(config, nil)
+
+	plan := r.BuildPlan(map[string]map[string]decimal.Decimal{
+		"binance": {"USDT": decimal.NewFromInt(600)},
+		"okx":     {"USDT": decimal.NewFromInt(200)},
+	})
+
+	if len(plan.Transfers) != 0 {
+		t.Errorf("Transfers = %d, want 0", len(plan.Transfers))
+	}
+	if len(plan.Skipped) != 1 {
+		t.Errorf("Skipped = %v, want 1 entry", plan.Skipped)
+	}
+}
+
+// TestBuildPlan_FeeExceedsAmount 测试手续费大于转账数量时跳过
+func TestBuildPlan_FeeExceedsAmount(t *testing.T) {
+	config := DefaultConfig()
+	config.Assets[0].MinTransfer = decimal.Zero
+	config.Assets[0].Networks["binance"].WithdrawFee = decimal.NewFromInt(50)
+	config.Assets[0].Networks["binance"].MinWithdraw = decimal.Zero
+	r := NewRebalancer(config, nil)
+
+	plan := r.BuildPlan(map[string]map[string]decimal.Decimal{
+		"binance": {"USDT": decimal.NewFromInt(60)},
+		"okx":     {"USDT": decimal.NewFromInt(20)},
+	})
+
+	if len(plan.Transfers) != 0 {
+		t.Errorf("Transfers = %d, want 0", len(plan.Transfers))
+	}
+}
+
+// TestExecute_DryRun 测试演练模式拒绝执行
+func TestExecute_DryRun(t *testing.T) {
+	r := NewRebalancer(DefaultConfig(), nil)
+	plan := r.BuildPlan(map[string]map[string]decimal.Decimal{
+		"binance": {"USDT": decimal.NewFromInt(9000)},
+		"okx":     {"USDT": decimal.NewFromInt(1000)},
+	})
+
+	if err := r.Execute(context.Background(), plan); err == nil {
+		t.Error("Execute() in dry-run mode should return error")
+	}
+}
+
+// TestPlanAndExecute 测试获取余额、生成计划并执行
+func TestPlanAndExecute(t *testing.T) {
+	binance := &mockTransferExecutor{
+		exchange: "binance",
+		balances: []*execution.Balance{{Exchange: "binance", Asset: "USDT", Free: decimal.NewFromInt(9000)}},
+		address:  "binance-deposit",
+	}
+	okx := &mockTransferExecutor{
+		exchange: "okx",
+		balances: []*execution.Balance{{Exchange: "okx", Asset: "USDT", Free: decimal.NewFromInt(1000)}},
+		address:  "okx-deposit",
+	}
+
+	config := DefaultConfig()
+	config.DryRun = false
+	r := NewRebalancer(config, map[string]execution.TransferExecutor{
+		"binance": binance,
+		"okx":     okx,
+	})
+
+	ctx := context.Background()
+	plan, err := r.Plan(ctx)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	if err := r.Execute(ctx, plan); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(binance.withdrawals) != 1 {
+		t.Fatalf("withdrawals = %d, want 1", len(binance.withdrawals))
+	}
+	req := binance.withdrawals[0]
+	if req.Address != "okx-deposit" || req.Network != "TRX" {
+		t.Errorf("withdraw request = %+v", req)
+	}
+	if plan.Transfers[0].Status != TransferStatusSubmitted || plan.Transfers[0].WithdrawalID == "" {
+		t.Errorf("transfer = %+v, want submitted with withdrawal id", plan.Transfers[0])
+	}
+}
+
+// TestExecute_WithdrawError 测试提币失败时记录错误
+func TestExecute_WithdrawError(t *testing.T) {
+	binance := &mockTransferExecutor{exchange: "binance", withdrawErr: fmt.Errorf("insufficient balance")}
+	okx := &mockTransferExecutor{exchange: "okx", address: "okx-deposit"}
+
+	config := DefaultConfig()
+	config.DryRun = false
+	r := NewRebalancer(config, map[string]execution.TransferExecutor{
+		"binance": binance,
+		"okx":     okx,
+	})
+
+	plan := r.BuildPlan(map[string]map[string]decimal.Decimal{
+		"binance": {"USDT": decimal.NewFromInt(9000)},
+		"okx":     {"USDT": decimal.NewFromInt(1000)},
+	})
+
+	if err := r.Execute(context.Background(), plan); err == nil {
+		t.Fatal("Execute() should return error")
+	}
+	if plan.Transfers[0].Status != TransferStatusFailed || plan.Transfers[0].Error == "" {
+		t.Errorf("transfer = %+v, want failed with error", plan.Transfers[0])
+	}
+}
+
+// TestRunOnce_PendingTransfer 测试在途转账计入转入交易所，未到账前不会重复提币
+func TestRunOnce_PendingTransfer(t *testing.T) {
+	binance := &mockTransferExecutor{
+		exchange: "binance",
+		balances: []*execution.Balance{{Exchange: "binance", Asset: "USDT", Free: decimal.NewFromInt(9000)}},
+		address:  "binance-deposit",
+	}
+	okx := &mockTransferExecutor{
+		exchange: "okx",
+		balances: []*execution.Balance{{Exchange: "okx", Asset: "USDT", Free: decimal.NewFromInt(1000)}},
+		address:  "okx-deposit",
+	}
+
+	config := DefaultConfig()
+	config.DryRun = false
+	r := NewRebalancer(config, map[string]execution.TransferExecutor{
+		"binance": binance,
+		"okx":     okx,
+	})
+
+	ctx := context.Background()
+	r.runOnce(ctx)
+	if len(binance.withdrawals) != 1 {
+		t.Fatalf("withdrawals = %d, want 1", len(binance.withdrawals))
+	}
+
+	// 提币后转出交易所余额已扣除，转入交易所尚未到账
+	binance.balances[0].Free = decimal.NewFromInt(5000)
+	r.runOnce(ctx)
+	if len(binance.withdrawals) != 1 {
+		t.Fatalf("withdrawals = %d after unconfirmed transfer, want 1", len(binance.withdrawals))
+	}
+	if len(r.pending) != 1 {
+		t.Fatalf("pending = %d, want 1", len(r.pending))
+	}
+
+	// 到账后不再计入在途
+	okx.balances[0].Free = decimal.NewFromInt(4999)
+	r.runOnce(ctx)
+	if len(binance.withdrawals) != 1 {
+		t.Errorf("withdrawals = %d after arrival, want 1", len(binance.withdrawals))
+	}
+	if len(r.pending) != 0 {
+		t.Errorf("pending = %d after arrival, want 0", len(r.pending))
+	}
+}
@@ -0,0 +1,242 @@
+// Package risk 交易前风控
+// 职责：提交套利执行前检查单笔金额、当日累计交易金额和亏损、机会的风险评分、收益率与时效，
+// 并按执行结果累计当日盈亏；供单进程模式和交易服务共用
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"arbitragex/common/clock"
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/execution"
+)
+
+// ErrRejected 风控拒绝执行
+var ErrRejected = errors.New("风控拒绝")
+
+// Limits 风控限额（零值表示不限制）
+type Limits struct {
+	MaxTradeAmount    decimal.Decimal `json:"max_trade_amount"`    // 单笔最大交易金额（USDT）
+	MaxDailyVolume    decimal.Decimal `json:"max_daily_volume"`    // 当日累计最大交易金额（USDT）
+	MaxDailyLoss      decimal.Decimal `json:"max_daily_loss"`      // 当日最大亏损（USDT，达到后停止交易）
+	MaxRiskScore      float64         `json:"max_risk_score"`      // 最大风险评分（0-100）
+	MinProfitRate     float64         `json:"min_profit_rate"`     // 最小净收益率
+	MaxOpportunityAge time.Duration   `json:"max_opportunity_age"` // 机会发现后超过该时间不再执行
+}
+
+// Validate 校验限额
+func (l *Limits) Validate() error {
+	amounts := []struct {
+		name  string
+		value decimal.Decimal
+	}{
+		{"单笔最大交易金额", l.MaxTradeAmount},
+		{"当日累计最大交易金额", l.MaxDailyVolume},
+		{"当日最大亏损", l.MaxDailyLoss},
+	}
+	for _, amount := range amounts {
+		if amount.value.IsNegative() {
+			return fmt.Errorf("%s不能为负数: %s", amount.name, amount.value)
+		}
+	}
+	if l.MaxRiskScore < 0 || l.MaxRiskScore > 100 {
+		return fmt.Errorf("最大风险评分必须在 [0, 100] 之间: %v", l.MaxRiskScore)
+	}
+	if l.MinProfitRate < 0 {
+		return fmt.Errorf("最小净收益率不能为负数: %v", l.MinProfitRate)
+	}
+	if l.MaxOpportunityAge < 0 {
+		return fmt.Errorf("机会最大时效不能为负数: %v", l.MaxOpportunityAge)
+	}
+	return nil
+}
+
+// Stats 当日风控统计
+type Stats struct {
+	Day      string          `json:"day"`      // 日期（UTC，YYYY-MM-DD）
+	Volume   decimal.Decimal `json:"volume"`   // 交易金额（USDT，执行中的按批准金额、已结束的按实际成交金额）
+	PnL      decimal.Decimal `json:"pnl"`      // 已结束执行的实际盈亏（USDT）
+	Approved int             `json:"approved"` // 批准次数
+	Rejected int             `json:"rejected"` // 拒绝次数
+}
+
+// Checker 交易前风控检查
+// 批准的交易金额立即预留并计入当日累计（并发提交时不会超出限额）：未能提交时通过 Release 释放，
+// 执行结束后由 Record 调整为实际成交金额并计入实际盈亏；统计按 UTC 日期在零点重置
+type Checker struct {
+	limits   Limits
+	stats    Stats
+	reserved map[string]decimal.Decimal // 套利机会 ID -> 尚未结束的预留交易金额
+	clock    clock.Clock
+	mu       sync.Mutex
+}
+
+// NewChecker 创建风控检查
+func NewChecker(limits Limits) *Checker {
+	return &Checker{
+		limits: limits,
+		clock:  clock.Real,
+	}
+}
+
+// SetClock 设置时钟（用于计算机会时效和当日统计）
+func (c *Checker) SetClock(clk clock.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clock = clock.OrReal(clk)
+}
+
+// Limits 获取当前限额
+func (c *Checker) Limits() Limits {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.limits
+}
+
+// SetLimits 更新限额（校验失败时保留原限额）
+func (c *Checker) SetLimits(limits Limits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.limits = limits
+	return nil
+}
+
+// Stats 获取当日统计
+func (c *Checker) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rollover()
+	return c.stats
+}
+
+// Check 检查套利执行是否在限额内，批准时预留交易金额并计入当日累计
+// 参数:
+//   - opp: 套利机会
+//   - amount: 交易金额（USDT）
+// 返回:
+//   - error: 拒绝原因（errors.Is(err, ErrRejected)）
+func (c *Checker) Check(opp *execution.ArbitrageOpportunity, amount decimal.Decimal) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rollover()
+	if err := c.check(opp, amount); err != nil {
+		c.stats.Rejected++
+		return fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	c.stats.Volume = c.stats.Volume.Add(amount)
+	c.stats.Approved++
+	if c.reserved == nil {
+		c.reserved = make(map[string]decimal.Decimal)
+	}
+	c.reserved[opp.ID] = c.reserved[opp.ID].Add(amount)
+	return nil
+}
+
+// Release 释放 Check 为未能提交的执行预留的交易金额（如执行队列已满）
+// 参数:
+//   - opp: 套利机会
+//   - amount: Check 批准的交易金额（USDT）
+func (c *Checker) Release(opp *execution.ArbitrageOpportunity, amount decimal.Decimal) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rollover()
+	c.stats.Volume = c.stats.Volume.Sub(c.unreserve(opp.ID, amount))
+}
+
+// check 按限额逐项检查（需持有锁）
+func (c *Checker) check(opp *execution.ArbitrageOpportunity, amount decimal.Decimal) error {
+	limits := c.limits
+
+	if !amount.IsPositive() {
+		return fmt.Errorf("交易金额必须大于 0: %s", amount)
+	}
+	if limits.MaxTradeAmount.IsPositive() && amount.GreaterThan(limits.MaxTradeAmount) {
+		return fmt.Errorf("交易金额 %s 超过单笔上限 %s", amount, limits.MaxTradeAmount)
+	}
+	if limits.MaxDailyVolume.IsPositive() && c.stats.Volume.Add(amount).GreaterThan(limits.MaxDailyVolume) {
+		return fmt.Errorf("当日累计交易金额 %s 加上 %s 超过上限 %s", c.stats.Volume, amount, limits.MaxDailyVolume)
+	}
+	if limits.MaxDailyLoss.IsPositive() && c.stats.PnL.Neg().GreaterThanOrEqual(limits.MaxDailyLoss) {
+		return fmt.Errorf("当日亏损 %s 已达到上限 %s", c.stats.PnL.Neg(), limits.MaxDailyLoss)
+	}
+	if limits.MaxRiskScore > 0 && opp.RiskScore > limits.MaxRiskScore {
+		return fmt.Errorf("风险评分 %.0f 超过上限 %.0f", opp.RiskScore, limits.MaxRiskScore)
+	}
+	if limits.MinProfitRate > 0 && opp.ProfitRate < limits.MinProfitRate {
+		return fmt.Errorf("净收益率 %.4f%% 低于下限 %.4f%%", opp.ProfitRate*100, limits.MinProfitRate*100)
+	}
+	if limits.MaxOpportunityAge > 0 && !opp.DiscoveredAt.IsZero() {
+		if age := c.clock.Since(opp.DiscoveredAt); age > limits.MaxOpportunityAge {
+			return fmt.Errorf("机会已发现 %s，超过时效 %s", age.Round(time.Millisecond), limits.MaxOpportunityAge)
+		}
+	}
+	return nil
+}
+
+// Record 计入执行结果的实际盈亏（execution.ResultHook）
+// 经 Check 批准的执行，当日累计中的预留金额同时调整为实际成交金额
+func (c *Checker) Record(result *execution.ExecutionResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rollover()
+	c.stats.PnL = c.stats.PnL.Add(result.ActualProfit)
+	if released := c.unreserve(result.OpportunityID, result.TradingAmount); released.IsPositive() {
+		c.stats.Volume = c.stats.Volume.Sub(released).Add(filledNotional(result))
+	}
+}
+
+// unreserve 扣减套利机会的预留金额（需持有锁）
+// 返回:
+//   - decimal.Decimal: 实际扣减的金额（不超过预留金额，没有预留时为 0）
+func (c *Checker) unreserve(opportunityID string, amount decimal.Decimal) decimal.Decimal {
+	reserved, ok := c.reserved[opportunityID]
+	if !ok {
+		return decimal.Zero
+	}
+
+	released := decimal.Min(reserved, amount)
+	if remaining := reserved.Sub(released); remaining.IsPositive() {
+		c.reserved[opportunityID] = remaining
+	} else {
+		delete(c.reserved, opportunityID)
+	}
+	return released
+}
+
+// filledNotional 执行结果的实际成交金额（按买入腿计算，买入未成交时按卖出腿；未知成交均价时按委托价格估算）
+func filledNotional(result *execution.ExecutionResult) decimal.Decimal {
+	for _, order := range []*execution.Order{result.BuyOrder, result.SellOrder} {
+		if order == nil || !order.FilledAmount.IsPositive() {
+			continue
+		}
+		price := order.AveragePrice
+		if !price.IsPositive() {
+			price = order.Price
+		}
+		return order.FilledAmount.Mul(price)
+	}
+	return decimal.Zero
+}
+
+// rollover 日期变化时重置当日统计和预留金额（需持有锁）
+func (c *Checker) rollover() {
+	day := c.clock.Now().UTC().Format("2006-01-02")
+	if c.stats.Day != day {
+		c.stats = Stats{Day: day}
+		c.reserved = nil
+	}
+}
@@ -0,0 +1,136 @@
+// Package risk 交易前风控单元测试
+package risk
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"arbitragex/common/clock"
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/execution"
+)
+
+// TestChecker_Check 测试各项限额
+func TestChecker_Check(t *testing.T) {
+	clk := clock.NewManual(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	checker := NewChecker(Limits{
+		MaxTradeAmount:    decimal.NewFromInt(1000),
+		MaxDailyVolume:    decimal.NewFromInt(1500),
+		MaxRiskScore:      50,
+		MinProfitRate:     0.001,
+		MaxOpportunityAge: 5 * time.Second,
+	})
+	checker.SetClock(clk)
+
+	opp := func(mutate func(*execution.ArbitrageOpportunity)) *execution.ArbitrageOpportunity {
+		o := &execution.ArbitrageOpportunity{RiskScore: 20, ProfitRate: 0.002, DiscoveredAt: clk.Now()}
+		if mutate != nil {
+			mutate(o)
+		}
+		return o
+	}
+
+	tests := []struct {
+		name   string
+		opp    *execution.ArbitrageOpportunity
+		amount int64
+	}{
+		{"零金额", opp(nil), 0},
+		{"超过单笔上限", opp(nil), 1001},
+		{"风险评分过高", opp(func(o *execution.ArbitrageOpportunity) { o.RiskScore = 60 }), 100},
+		{"收益率过低", opp(func(o *execution.ArbitrageOpportunity) { o.ProfitRate = 0.0005 }), 100},
+		{"机会过期", opp(func(o *execution.ArbitrageOpportunity) { o.DiscoveredAt = clk.Now().Add(-10 * time.Second) }), 100},
+	}
+	for _, tt := range tests {
+		if err := checker.Check(tt.opp, decimal.NewFromInt(tt.amount)); !errors.Is(err, ErrRejected) {
+			t.Errorf("%s: Check() error = %v, want ErrRejected", tt.name, err)
+		}
+	}
+
+	if err := checker.Check(opp(nil), decimal.NewFromInt(1000)); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	// 当日累计 1000 + 600 超过 1500
+	if err := checker.Check(opp(nil), decimal.NewFromInt(600)); !errors.Is(err, ErrRejected) {
+		t.Errorf("Check() over daily volume error = %v, want ErrRejected", err)
+	}
+	if err := checker.Check(opp(nil), decimal.NewFromInt(500)); err != nil {
+		t.Errorf("Check() within daily volume error = %v", err)
+	}
+
+	stats := checker.Stats()
+	if stats.Day != "2025-01-01" || !stats.Volume.Equal(decimal.NewFromInt(1500)) || stats.Approved != 2 || stats.Rejected != 6 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+
+	// 次日重置统计
+	clk.Advance(24 * time.Hour)
+	if err := checker.Check(opp(nil), decimal.NewFromInt(1000)); err != nil {
+		t.Errorf("Check() next day error = %v", err)
+	}
+	if stats := checker.Stats(); stats.Day != "2025-01-02" || !stats.Volume.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("Stats() next day = %+v", stats)
+	}
+}
+
+// TestChecker_DailyLoss 测试当日亏损达到上限后停止交易
+func TestChecker_DailyLoss(t *testing.T) {
+	checker := NewChecker(Limits{MaxDailyLoss: decimal.NewFromInt(10)})
+	opp := &execution.ArbitrageOpportunity{}
+
+	checker.Record(&execution.ExecutionResult{ActualProfit: decimal.NewFromInt(-6)})
+	if err := checker.Check(opp, decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	checker.Record(&execution.ExecutionResult{ActualProfit: decimal.NewFromInt(-4)})
+	if err := checker.Check(opp, decimal.NewFromInt(100)); !errors.Is(err, ErrRejected) {
+		t.Errorf("Check() after daily loss error = %v, want ErrRejected", err)
+	}
+
+	if err := checker.SetLimits(Limits{MaxRiskScore: 120}); err == nil {
+		t.Error("SetLimits() with invalid risk score error = nil")
+	}
+	if err := checker.SetLimits(Limits{MaxDailyLoss: decimal.NewFromInt(20)}); err != nil {
+		t.Fatalf("SetLimits() error = %v", err)
+	}
+	if err := checker.Check(opp, decimal.NewFromInt(100)); err != nil {
+		t.Errorf("Check() after raising limit error = %v", err)
+	}
+}
+
+// TestChecker_Reservation 测试未能提交时释放预留金额，执行结束后按实际成交金额计入
+func TestChecker_Reservation(t *testing.T) {
+	checker := NewChecker(Limits{MaxDailyVolume: decimal.NewFromInt(1000)})
+	amount := decimal.NewFromInt(600)
+
+	queued := &execution.ArbitrageOpportunity{ID: "opp-1"}
+	if err := checker.Check(queued, amount); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	checker.Release(queued, amount)
+	if volume := checker.Stats().Volume; !volume.IsZero() {
+		t.Errorf("Volume after Release() = %s, want 0", volume)
+	}
+
+	executed := &execution.ArbitrageOpportunity{ID: "opp-2"}
+	if err := checker.Check(executed, amount); err != nil {
+		t.Fatalf("Check() after Release() error = %v", err)
+	}
+	// 只成交了一部分：买入 0.005 @ 40000 = 200
+	checker.Record(&execution.ExecutionResult{
+		OpportunityID: "opp-2",
+		TradingAmount: amount,
+		BuyOrder:      &execution.Order{FilledAmount: decimal.RequireFromString("0.005"), AveragePrice: decimal.NewFromInt(40000)},
+		SellOrder:     &execution.Order{},
+	})
+	if volume := checker.Stats().Volume; !volume.Equal(decimal.NewFromInt(200)) {
+		t.Errorf("Volume after Record() = %s, want 200", volume)
+	}
+
+	// 重复的结果不会再次调整
+	checker.Record(&execution.ExecutionResult{OpportunityID: "opp-2", TradingAmount: amount})
+	if volume := checker.Stats().Volume; !volume.Equal(decimal.NewFromInt(200)) {
+		t.Errorf("Volume after duplicate Record() = %s, want 200", volume)
+	}
+}
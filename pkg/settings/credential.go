@@ -0,0 +1,83 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"arbitragex/pkg/credential"
+)
+
+// CredentialConf API 密钥来源（不在配置文件中保存明文密钥）
+// 先查找环境变量 <NAME>_API_KEY、<NAME>_API_SECRET、<NAME>_PASSPHRASE（子账户为 <NAME>_<ACCOUNT>_API_KEY 等），
+// 未设置时查找加密密钥库（使用 cmd/keystore 创建和修改）
+type CredentialConf struct {
+	Keystore       string `json:",optional"`                               // 加密密钥库文件（为空时只使用环境变量）
+	PassphraseEnv  string `json:",default=ARBITRAGEX_KEYSTORE_PASSPHRASE"` // 保存密钥库口令的环境变量
+	PassphraseFile string `json:",optional"`                               // 保存密钥库口令的文件（如 Docker secret，配置后不读取环境变量）
+}
+
+// Validate 校验密钥来源配置
+func (c CredentialConf) Validate() error {
+	if c.Keystore == "" && c.PassphraseFile != "" {
+		return fmt.Errorf("Credentials.PassphraseFile 需要配置 Credentials.Keystore")
+	}
+	if c.Keystore != "" && c.PassphraseFile == "" && c.PassphraseEnv == "" {
+		return fmt.Errorf("Credentials: 需要配置 PassphraseEnv 或 PassphraseFile 来解锁密钥库")
+	}
+	return nil
+}
+
+// NewProvider 创建密钥来源（配置了 Keystore 时使用口令解锁密钥库）
+// 返回:
+//   - credential.Provider: 使用完后调用 Close 清零密钥库
+//   - error: 缺少口令、口令错误或密钥库无法读取
+func (c CredentialConf) NewProvider() (credential.Provider, error) {
+	if c.Keystore == "" {
+		return credential.NewEnvProvider(), nil
+	}
+
+	passphrase, err := c.passphrase()
+	if err != nil {
+		return nil, err
+	}
+	defer credential.Wipe(passphrase)
+
+	keystore, err := credential.OpenKeystore(c.Keystore, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("打开密钥库 %s 失败: %w", c.Keystore, err)
+	}
+	return credential.NewChain(credential.NewEnvProvider(), keystore), nil
+}
+
+// passphrase 读取密钥库口令（调用方使用完后清零）
+func (c CredentialConf) passphrase() ([]byte, error) {
+	if c.PassphraseFile != "" {
+		return credential.ReadPassphraseFile(c.PassphraseFile)
+	}
+	if value := os.Getenv(c.PassphraseEnv); value != "" {
+		return []byte(value), nil
+	}
+	return nil, fmt.Errorf("打开密钥库 %s 需要口令: 设置环境变量 %s 或配置 Credentials.PassphraseFile", c.Keystore, c.PassphraseEnv)
+}
+
+// loadCredential 读取交易所的 API 密钥并校验完整（OKX 还需要 Passphrase）
+// 返回:
+//   - *credential.Credential: 调用方使用完后调用 Zero
+func loadCredential(provider credential.Provider, ex ExchangeConf) (*credential.Credential, error) {
+	secret, err := provider.Credential(context.Background(), ex.Key(), ex.Account)
+	if err != nil {
+		prefix := credential.EnvPrefix(ex.Key(), ex.Account)
+		return nil, fmt.Errorf("真实交易需要配置 %s 的 API 密钥（密钥库或环境变量 %sAPI_KEY、%sAPI_SECRET）: %w",
+			ex.Name, prefix, prefix, err)
+	}
+	if len(secret.APIKey) == 0 || len(secret.APISecret) == 0 {
+		secret.Zero()
+		return nil, fmt.Errorf("%s 的 API 密钥不完整（需要 API Key 和 Secret）", ex.Name)
+	}
+	if ex.Key() == "okx" && len(secret.Passphrase) == 0 {
+		secret.Zero()
+		return nil, fmt.Errorf("真实交易需要配置 %s 的 Passphrase", ex.Name)
+	}
+	return secret, nil
+}
@@ -0,0 +1,69 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+)
+
+// EngineConf 套利引擎阈值（金额为 USDT，配置文件中使用字符串避免精度损失）
+type EngineConf struct {
+	MinProfitRate    float64       `json:",default=0.005"` // 最小收益率（0.005 = 0.5%）
+	MinProfitAmount  string        `json:",default=10"`    // 最小收益金额
+	MaxRiskScore     float64       `json:",default=50"`    // 最大风险评分（0-100）
+	OpportunityTTL   time.Duration `json:",default=5s"`    // 机会有效期
+	SlippageRate     float64       `json:",default=0.001"` // 滑点率
+	GasFee           string        `json:",default=0"`     // Gas 费（仅 DEX）
+	MinVolume        string        `json:",default=1000"`  // 按此金额估算收益
+	MinConfirmations int           `json:",default=1"`     // 连续扫描到多少次才输出机会
+}
+
+// EngineConfig 转换为套利引擎配置
+// 参数:
+//   - fees: 各交易所手续费（Exchanges.TradingFees）
+// 返回:
+//   - *engine.EngineConfig: 已校验的引擎配置
+//   - error: 金额格式错误或配置无效
+func (c EngineConf) EngineConfig(fees []engine.TradingFee) (*engine.EngineConfig, error) {
+	config := &engine.EngineConfig{
+		MinProfitRate:    c.MinProfitRate,
+		MaxRiskScore:     c.MaxRiskScore,
+		OpportunityTTL:   c.OpportunityTTL,
+		TradingFees:      fees,
+		SlippageRate:     c.SlippageRate,
+		MinConfirmations: c.MinConfirmations,
+	}
+	amounts := []struct {
+		name  string
+		value string
+		dst   *decimal.Decimal
+	}{
+		{"Engine.MinProfitAmount", c.MinProfitAmount, &config.MinProfitAmount},
+		{"Engine.GasFee", c.GasFee, &config.GasFee},
+		{"Engine.MinVolume", c.MinVolume, &config.MinVolume},
+	}
+	for _, amount := range amounts {
+		if err := parseAmount(amount.name, amount.value, amount.dst); err != nil {
+			return nil, err
+		}
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("Engine: %w", err)
+	}
+	return config, nil
+}
+
+// parseAmount 解析金额（为空时保持零值）
+func parseAmount(name, value string, dst *decimal.Decimal) error {
+	if value == "" {
+		return nil
+	}
+	amount, err := decimal.NewFromString(value)
+	if err != nil {
+		return fmt.Errorf("无效的 %s: %q", name, value)
+	}
+	*dst = amount
+	return nil
+}
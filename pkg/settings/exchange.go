@@ -0,0 +1,206 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"arbitragex/pkg/credential"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/exchange"
+	"arbitragex/pkg/execution"
+	"arbitragex/pkg/feed"
+)
+
+// ExchangeConf 交易所配置
+type ExchangeConf struct {
+	Name             string
+	Type             string   `json:",default=cex,options=cex|dex"`
+	Enabled          bool     `json:",default=true"`
+	WebSocketBaseURL string   `json:",optional"`                     // 行情 WebSocket 地址（为空时使用默认的公共行情端点）
+	RESTBaseURL      string   `json:",optional"`                     // REST API 基础地址（为空时使用生产环境地址）
+	OrderTransport   string   `json:",default=rest,options=rest|ws"` // 真实交易的下单方式（ws: 通过常驻 WebSocket 连接下单，断开时回退到 REST；模拟交易不使用）
+	OrderWSURL       string   `json:",optional"`                     // WebSocket 下单地址（为空时使用生产环境地址）
+	UserStream       bool     `json:",default=true"`                 // 真实交易时通过私有数据流接收订单推送（等待成交不再轮询 REST；模拟交易不使用）
+	UserStreamURL    string   `json:",optional"`                     // 私有数据流 WebSocket 地址（为空时使用生产环境地址）
+	Account          string   `json:",optional"`                     // 子账户（按 交易所/子账户 从密钥来源读取 API 密钥，为空时为 default）
+	Symbols          []string `json:",optional"`                     // 订阅的交易对（为空时使用全局配置）
+	MakerFee         float64  `json:",default=0.001"`
+	TakerFee         float64  `json:",default=0.001"`
+}
+
+// Key 交易所名称（小写，用于适配器和执行器的映射）
+func (c ExchangeConf) Key() string {
+	return strings.ToLower(c.Name)
+}
+
+// NewAdapter 创建行情适配器
+// 参数:
+//   - symbols: 全局交易对（交易所配置了 Symbols 时使用交易所的配置）
+func (c ExchangeConf) NewAdapter(symbols []string) (exchange.ExchangeAdapter, error) {
+	if len(c.Symbols) > 0 {
+		symbols = c.Symbols
+	}
+	return feed.NewAdapterWithEndpoints(c.Key(), symbols, feed.Endpoints{
+		WebSocket: c.WebSocketBaseURL,
+		REST:      c.RESTBaseURL,
+	})
+}
+
+// Validate 校验交易所配置
+func (c ExchangeConf) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("交易所名称不能为空")
+	}
+	if c.MakerFee < 0 || c.MakerFee >= 1 || c.TakerFee < 0 || c.TakerFee >= 1 {
+		return fmt.Errorf("%s 手续费率必须在 [0, 1) 之间: maker %v, taker %v", c.Name, c.MakerFee, c.TakerFee)
+	}
+	return nil
+}
+
+// Exchanges 交易所列表
+type Exchanges []ExchangeConf
+
+// Enabled 已启用的中心化交易所（DEX 暂不支持行情和下单）
+func (e Exchanges) Enabled() Exchanges {
+	var enabled Exchanges
+	for _, ex := range e {
+		if ex.Enabled && ex.Type != "dex" {
+			enabled = append(enabled, ex)
+		}
+	}
+	return enabled
+}
+
+// Names 交易所名称（小写）
+func (e Exchanges) Names() []string {
+	names := make([]string, 0, len(e))
+	for _, ex := range e {
+		names = append(names, ex.Key())
+	}
+	return names
+}
+
+// TradingFees 各交易所手续费（套利引擎计算净收益使用）
+func (e Exchanges) TradingFees() []engine.TradingFee {
+	fees := make([]engine.TradingFee, 0, len(e))
+	for _, ex := range e {
+		fees = append(fees, engine.TradingFee{Exchange: ex.Key(), MakerFee: ex.MakerFee, TakerFee: ex.TakerFee})
+	}
+	return fees
+}
+
+// Validate 校验交易所配置（至少启用一个中心化交易所，名称不重复）
+func (e Exchanges) Validate() error {
+	seen := make(map[string]bool, len(e))
+	for i, ex := range e {
+		if err := ex.Validate(); err != nil {
+			return fmt.Errorf("Exchanges[%d]: %w", i, err)
+		}
+		if seen[ex.Key()] {
+			return fmt.Errorf("Exchanges[%d]: 交易所重复配置: %s", i, ex.Name)
+		}
+		seen[ex.Key()] = true
+	}
+	if len(e.Enabled()) == 0 {
+		return fmt.Errorf("Exchanges: 没有启用的交易所")
+	}
+	return nil
+}
+
+// ValidateCredentials 校验已启用的交易所都能从密钥来源读取到 API 密钥（真实交易需要）
+// 参数:
+//   - credentials: 密钥来源配置
+func (e Exchanges) ValidateCredentials(credentials CredentialConf) error {
+	provider, err := credentials.NewProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+
+	for _, ex := range e.Enabled() {
+		secret, err := loadCredential(provider, ex)
+		if err != nil {
+			return err
+		}
+		secret.Zero()
+	}
+	return nil
+}
+
+// NewAdapters 为已启用的交易所创建行情适配器（exchange -> adapter）
+func (e Exchanges) NewAdapters(symbols []string) (map[string]exchange.ExchangeAdapter, error) {
+	adapters := make(map[string]exchange.ExchangeAdapter)
+	for _, ex := range e.Enabled() {
+		adapter, err := ex.NewAdapter(symbols)
+		if err != nil {
+			return nil, err
+		}
+		adapters[ex.Key()] = adapter
+	}
+	return adapters, nil
+}
+
+// NewExecutors 为已启用的交易所创建订单执行器（exchange -> executor）
+// 模拟交易时按交易所公开订单簿撮合（使用配置的手续费率），不读取 API 密钥，不下真实订单；
+// 真实交易时从密钥来源读取密钥并关闭密钥来源；执行器以 []byte 保存 API Secret，Close 时清零
+// 参数:
+//   - c: 执行器配置
+//   - credentials: 密钥来源配置（模拟交易时不使用）
+func (e Exchanges) NewExecutors(c ExecutorConf, credentials CredentialConf) (map[string]execution.OrderExecutor, error) {
+	var provider credential.Provider
+	if !c.Paper {
+		var err error
+		if provider, err = credentials.NewProvider(); err != nil {
+			return nil, err
+		}
+		defer provider.Close()
+	}
+
+	executors := make(map[string]execution.OrderExecutor)
+	for _, ex := range e.Enabled() {
+		executor, err := newExecutor(ex, provider)
+		if err != nil {
+			return nil, err
+		}
+		if c.Paper {
+			executor = execution.NewLivePaperExecutor(execution.NewPaperExecutor(ex.Key(), ex.MakerFee, ex.TakerFee), executor)
+		}
+		executors[ex.Key()] = executor
+	}
+	return executors, nil
+}
+
+// newExecutor 创建交易所订单执行器（provider 为空时不使用 API 密钥，只能查询公开行情）
+// 配置了 OrderTransport: ws 时创建 WebSocket 下单执行器，配置了 UserStream 时启用私有数据流，
+// 两者都由并发执行器启动时建立连接
+func newExecutor(ex ExchangeConf, provider credential.Provider) (execution.OrderExecutor, error) {
+	if provider == nil {
+		return execution.NewExchangeExecutor(ex.Key(), execution.Credential{}, ex.RESTBaseURL)
+	}
+
+	secret, err := loadCredential(provider, ex)
+	if err != nil {
+		return nil, err
+	}
+	// 执行器复制 API Secret，读取的副本创建后即清零
+	defer secret.Zero()
+
+	credential := execution.Credential{
+		APIKey:     string(secret.APIKey),
+		APISecret:  secret.APISecret,
+		Passphrase: string(secret.Passphrase),
+	}
+	var executor execution.OrderExecutor
+	if ex.OrderTransport == "ws" {
+		executor, err = execution.NewExchangeWSExecutor(ex.Key(), credential, ex.RESTBaseURL, ex.OrderWSURL)
+	} else {
+		executor, err = execution.NewExchangeExecutor(ex.Key(), credential, ex.RESTBaseURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if streaming, ok := executor.(execution.UserStreamExecutor); ok && ex.UserStream {
+		streaming.EnableUserStream(ex.UserStreamURL)
+	}
+	return executor, nil
+}
@@ -0,0 +1,48 @@
+package settings
+
+import (
+	"fmt"
+
+	"arbitragex/pkg/execution"
+)
+
+// ExecutorConf 套利执行器配置
+type ExecutorConf struct {
+	Paper         bool   `json:",default=true"` // 模拟交易：按交易所公开订单簿撮合，不下真实订单
+	MaxConcurrent int    `json:",default=5"`    // 最大并发执行数
+	QueueSize     int    `json:",default=1000"` // 等待执行的任务队列长度（队列满时提交失败）
+	Journal       string `json:",optional"`     // 执行日志路径（为空时不记录，崩溃后无法恢复未完成的执行）
+}
+
+// Validate 校验执行器配置
+func (c ExecutorConf) Validate() error {
+	if c.MaxConcurrent <= 0 {
+		return fmt.Errorf("Executor.MaxConcurrent 必须大于 0: %d", c.MaxConcurrent)
+	}
+	if c.QueueSize <= 0 {
+		return fmt.Errorf("Executor.QueueSize 必须大于 0: %d", c.QueueSize)
+	}
+	return nil
+}
+
+// NewConcurrentExecutor 创建并发执行器（配置了 Journal 时打开执行日志，调用方负责在停止执行器后关闭）
+// 参数:
+//   - executors: 订单执行器映射（exchange -> OrderExecutor）
+// 返回:
+//   - *execution.DefaultConcurrentExecutor: 并发执行器
+//   - *execution.FileJournal: 执行日志（未配置时为 nil）
+//   - error: 打开执行日志失败
+func (c ExecutorConf) NewConcurrentExecutor(executors map[string]execution.OrderExecutor) (*execution.DefaultConcurrentExecutor, *execution.FileJournal, error) {
+	executor := execution.NewDefaultConcurrentExecutor(c.MaxConcurrent, executors)
+	executor.SetQueueSize(c.QueueSize)
+	if c.Journal == "" {
+		return executor, nil, nil
+	}
+
+	journal, err := execution.NewFileJournal(c.Journal)
+	if err != nil {
+		return nil, nil, err
+	}
+	executor.SetJournal(journal)
+	return executor, journal, nil
+}
@@ -0,0 +1,447 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/risk"
+	"arbitragex/pkg/store"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gopkg.in/yaml.v2"
+)
+
+// ReloadConf 参数热更新配置（File 和 SystemConfig 二选一，都不配置时不热更新）
+type ReloadConf struct {
+	File         string        `json:",optional"`      // 参数文件（YAML，键值与 system_config 表相同）
+	SystemConfig bool          `json:",default=false"` // 从 MySQL system_config 表读取（需要配置 MySQL）
+	Interval     time.Duration `json:",default=5s"`    // 检查间隔
+}
+
+// Enabled 是否开启热更新
+func (c ReloadConf) Enabled() bool {
+	return c.File != "" || c.SystemConfig
+}
+
+// Validate 校验热更新配置
+// 参数:
+//   - mysql: 读取 system_config 表使用的 MySQL 配置
+func (c ReloadConf) Validate(mysql MySQLConf) error {
+	if c.File != "" && c.SystemConfig {
+		return fmt.Errorf("Reload: File 和 SystemConfig 只能配置一个")
+	}
+	if c.SystemConfig && mysql.DataSource == "" {
+		return fmt.Errorf("Reload.SystemConfig 需要配置 MySQL.DataSource")
+	}
+	if c.Enabled() && c.Interval <= 0 {
+		return fmt.Errorf("Reload.Interval 必须大于 0: %s", c.Interval)
+	}
+	return nil
+}
+
+// NewSource 创建参数来源（未开启热更新时返回 nil）
+// 参数:
+//   - configs: system_config 仓储（SystemConfig 开启时使用）
+func (c ReloadConf) NewSource(configs store.ConfigRepository) Source {
+	switch {
+	case c.File != "":
+		return NewFileSource(c.File)
+	case c.SystemConfig:
+		return NewSystemConfigSource(configs)
+	default:
+		return nil
+	}
+}
+
+// Source 可热更新参数的来源
+// 返回的键值覆盖启动配置中的对应参数，未知的键被忽略（system_config 表中还有其他组件的配置）；
+// 支持的键:
+//
+//	min_profit_rate、min_profit_amount、max_risk_score、opportunity_ttl、slippage_rate、
+//	gas_fee、min_volume、min_confirmations                       套利引擎阈值
+//	fee.<exchange>.maker、fee.<exchange>.taker                   交易所手续费率（如 fee.binance.taker）
+//	max_single_trade_amount、max_daily_volume、max_daily_loss、
+//	risk.max_risk_score、risk.min_profit_rate、max_opportunity_age  风控限额
+type Source interface {
+	// Name 来源名称（用于审计日志）
+	Name() string
+
+	// Values 读取当前的参数（键 -> 值）
+	Values(ctx context.Context) (map[string]string, error)
+}
+
+// fileSource YAML 参数文件
+type fileSource struct {
+	path string
+}
+
+// NewFileSource 创建从 YAML 文件读取参数的来源（文件不存在时没有参数覆盖）
+func NewFileSource(path string) Source {
+	return &fileSource{path: path}
+}
+
+// Name 来源名称
+func (s *fileSource) Name() string {
+	return "file:" + s.path
+}
+
+// Values 读取参数文件
+func (s *fileSource) Values(ctx context.Context) (map[string]string, error) {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("解析参数文件 %s 失败: %w", s.path, err)
+	}
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		values[key] = fmt.Sprint(value)
+	}
+	return values, nil
+}
+
+// systemConfigSource system_config 表
+type systemConfigSource struct {
+	configs store.ConfigRepository
+}
+
+// NewSystemConfigSource 创建从 system_config 表读取参数的来源
+func NewSystemConfigSource(configs store.ConfigRepository) Source {
+	return &systemConfigSource{configs: configs}
+}
+
+// Name 来源名称
+func (s *systemConfigSource) Name() string {
+	return "system_config"
+}
+
+// Values 读取 system_config 表中的所有配置
+func (s *systemConfigSource) Values(ctx context.Context) (map[string]string, error) {
+	return s.configs.List(ctx)
+}
+
+// Params 可热更新的参数
+type Params struct {
+	Engine *engine.EngineConfig
+	Risk   risk.Limits
+}
+
+// paramSetter 将参数值写入 Params
+type paramSetter func(p *Params, value string) error
+
+// paramSetters 支持热更新的参数（手续费率另外按 fee.<exchange>.maker|taker 处理）
+var paramSetters = map[string]paramSetter{
+	"min_profit_rate":   floatParam(func(p *Params) *float64 { return &p.Engine.MinProfitRate }),
+	"min_profit_amount": decimalParam(func(p *Params) *decimal.Decimal { return &p.Engine.MinProfitAmount }),
+	"max_risk_score":    floatParam(func(p *Params) *float64 { return &p.Engine.MaxRiskScore }),
+	"opportunity_ttl":   durationParam(func(p *Params) *time.Duration { return &p.Engine.OpportunityTTL }),
+	"slippage_rate":     floatParam(func(p *Params) *float64 { return &p.Engine.SlippageRate }),
+	"gas_fee":           decimalParam(func(p *Params) *decimal.Decimal { return &p.Engine.GasFee }),
+	"min_volume":        decimalParam(func(p *Params) *decimal.Decimal { return &p.Engine.MinVolume }),
+	"min_confirmations": intParam(func(p *Params) *int { return &p.Engine.MinConfirmations }),
+
+	"max_single_trade_amount": decimalParam(func(p *Params) *decimal.Decimal { return &p.Risk.MaxTradeAmount }),
+	"max_daily_volume":        decimalParam(func(p *Params) *decimal.Decimal { return &p.Risk.MaxDailyVolume }),
+	"max_daily_loss":          decimalParam(func(p *Params) *decimal.Decimal { return &p.Risk.MaxDailyLoss }),
+	"risk.max_risk_score":     floatParam(func(p *Params) *float64 { return &p.Risk.MaxRiskScore }),
+	"risk.min_profit_rate":    floatParam(func(p *Params) *float64 { return &p.Risk.MinProfitRate }),
+	"max_opportunity_age":     durationParam(func(p *Params) *time.Duration { return &p.Risk.MaxOpportunityAge }),
+}
+
+func floatParam(field func(*Params) *float64) paramSetter {
+	return func(p *Params, value string) error {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("无效的数值 %q", value)
+		}
+		*field(p) = v
+		return nil
+	}
+}
+
+func intParam(field func(*Params) *int) paramSetter {
+	return func(p *Params, value string) error {
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("无效的整数 %q", value)
+		}
+		*field(p) = v
+		return nil
+	}
+}
+
+func decimalParam(field func(*Params) *decimal.Decimal) paramSetter {
+	return func(p *Params, value string) error {
+		v, err := decimal.NewFromString(value)
+		if err != nil {
+			return fmt.Errorf("无效的金额 %q", value)
+		}
+		*field(p) = v
+		return nil
+	}
+}
+
+func durationParam(field func(*Params) *time.Duration) paramSetter {
+	return func(p *Params, value string) error {
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("无效的时长 %q", value)
+		}
+		*field(p) = v
+		return nil
+	}
+}
+
+// setFee 设置交易所手续费率（key 格式 fee.<exchange>.maker|taker，未配置的交易所新增一项）
+func setFee(p *Params, key, value string) error {
+	parts := strings.Split(key, ".")
+	if len(parts) != 3 || parts[1] == "" || (parts[2] != "maker" && parts[2] != "taker") {
+		return fmt.Errorf("手续费配置键格式为 fee.<exchange>.maker|taker")
+	}
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("无效的费率 %q", value)
+	}
+
+	exchange := strings.ToLower(parts[1])
+	fees := p.Engine.TradingFees
+	i := 0
+	for i < len(fees) && fees[i].Exchange != exchange {
+		i++
+	}
+	if i == len(fees) {
+		fees = append(fees, engine.TradingFee{Exchange: exchange})
+	}
+	if parts[2] == "maker" {
+		fees[i].MakerFee = rate
+	} else {
+		fees[i].TakerFee = rate
+	}
+	p.Engine.TradingFees = fees
+	return nil
+}
+
+// isParamKey 是否为支持热更新的参数
+func isParamKey(key string) bool {
+	_, ok := paramSetters[key]
+	return ok || strings.HasPrefix(key, "fee.")
+}
+
+// Apply 在参数副本上应用覆盖值并校验（不修改 p）
+// 参数:
+//   - values: 覆盖值（只包含支持的键）
+// 返回:
+//   - Params: 应用后的参数
+//   - error: 值格式错误或参数无效
+func (p Params) Apply(values map[string]string) (Params, error) {
+	engineConfig := *p.Engine
+	engineConfig.TradingFees = append([]engine.TradingFee(nil), p.Engine.TradingFees...)
+	applied := Params{Engine: &engineConfig, Risk: p.Risk}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var err error
+		if setter, ok := paramSetters[key]; ok {
+			err = setter(&applied, values[key])
+		} else {
+			err = setFee(&applied, key, values[key])
+		}
+		if err != nil {
+			return p, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if err := applied.Engine.Validate(); err != nil {
+		return p, err
+	}
+	if err := applied.Risk.Validate(); err != nil {
+		return p, err
+	}
+	return applied, nil
+}
+
+// Reloader 参数热更新
+// 定期从参数来源读取覆盖值，有变化时在启动配置的基础上重新计算套利引擎配置和风控限额，
+// 两者都校验通过后才一起生效（任意一项无效时整次变更被拒绝，保留当前参数）；每项变更和拒绝都记录审计日志。
+// 从来源中删除的键恢复为启动配置的值；通过接口修改的引擎配置会在下一次参数变更时被覆盖
+type Reloader struct {
+	source Source
+	base   Params
+	engine *engine.ArbitrageEngine // 为空时不更新引擎
+	risk   *risk.Checker           // 为空时不更新风控
+
+	seen    map[string]string // 最近一次读取到的覆盖值
+	applied map[string]string // 当前生效的覆盖值
+	ignored map[string]bool   // 已记录过的未知键
+	mu      sync.Mutex
+
+	done   chan struct{}
+	wg     sync.WaitGroup
+	logger logx.Logger
+}
+
+// NewReloader 创建参数热更新
+// 参数:
+//   - source: 参数来源
+//   - base: 启动配置中的参数（来源中没有的键使用这里的值，Engine 不能为空）
+//   - arbitrageEngine: 套利引擎（为空时只更新风控）
+//   - checker: 风控检查（为空时只更新引擎）
+// 返回:
+//   - *Reloader: 调用 Start 后开始检查
+func NewReloader(source Source, base Params, arbitrageEngine *engine.ArbitrageEngine, checker *risk.Checker) *Reloader {
+	return &Reloader{
+		source:  source,
+		base:    base,
+		engine:  arbitrageEngine,
+		risk:    checker,
+		applied: map[string]string{},
+		ignored: map[string]bool{},
+		logger:  logx.WithContext(context.Background()),
+	}
+}
+
+// Reload 读取一次参数来源，有变化时校验并应用（失败和拒绝都已记录日志）
+// 返回:
+//   - error: 读取失败或变更被拒绝
+func (r *Reloader) Reload(ctx context.Context) error {
+	raw, err := r.source.Values(ctx)
+	if err != nil {
+		r.logger.Errorf("读取参数来源 %s 失败: %v", r.source.Name(), err)
+		return fmt.Errorf("读取参数来源 %s 失败: %w", r.source.Name(), err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if isParamKey(key) {
+			values[key] = strings.TrimSpace(value)
+		} else if !r.ignored[key] {
+			r.ignored[key] = true
+			r.logger.Infof("参数来源 %s 中的 %s 不支持热更新，已忽略", r.source.Name(), key)
+		}
+	}
+	if r.seen != nil && equalValues(values, r.seen) {
+		return nil
+	}
+	r.seen = values
+
+	params, err := r.base.Apply(values)
+	if err != nil {
+		r.logger.Errorf("[审计] 参数变更被拒绝（来源 %s，保留当前参数）: %v", r.source.Name(), err)
+		return fmt.Errorf("参数变更被拒绝: %w", err)
+	}
+	if r.engine != nil {
+		if err := r.engine.UpdateConfig(params.Engine); err != nil {
+			r.logger.Errorf("[审计] 参数变更被拒绝（来源 %s，保留当前参数）: %v", r.source.Name(), err)
+			return fmt.Errorf("参数变更被拒绝: %w", err)
+		}
+	}
+	if r.risk != nil {
+		// 限额已在 Apply 中校验，SetLimits 不会失败
+		r.risk.SetLimits(params.Risk)
+	}
+
+	r.audit(values)
+	r.applied = values
+	return nil
+}
+
+// audit 记录生效的每项变更
+func (r *Reloader) audit(values map[string]string) {
+	keys := make([]string, 0, len(values)+len(r.applied))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	for key := range r.applied {
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		old, hadOld := r.applied[key]
+		value, hasValue := values[key]
+		switch {
+		case !hadOld:
+			r.logger.Infof("[审计] 参数 %s 设置为 %s（来源 %s）", key, value, r.source.Name())
+		case !hasValue:
+			r.logger.Infof("[审计] 参数 %s 从 %s 恢复为启动配置（来源 %s）", key, old, r.source.Name())
+		case old != value:
+			r.logger.Infof("[审计] 参数 %s 从 %s 修改为 %s（来源 %s）", key, old, value, r.source.Name())
+		}
+	}
+}
+
+// Start 立即读取一次参数来源，之后按间隔定期检查
+// 返回:
+//   - error: 首次读取失败或参数无效（此时使用启动配置，之后仍会定期检查）
+func (r *Reloader) Start(interval time.Duration) error {
+	err := r.Reload(context.Background())
+
+	r.done = make(chan struct{})
+	r.wg.Add(1)
+	go r.loop(interval)
+	return err
+}
+
+// Stop 停止定期检查
+func (r *Reloader) Stop() {
+	if r.done == nil {
+		return
+	}
+	close(r.done)
+	r.wg.Wait()
+	r.done = nil
+}
+
+// loop 定期检查参数来源
+func (r *Reloader) loop(interval time.Duration) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.Reload(context.Background())
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// equalValues 两组覆盖值是否相同
+func equalValues(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package settings
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/risk"
+	"arbitragex/pkg/store"
+)
+
+// newTestReloader 创建使用参数文件的热更新（启动配置为默认引擎配置和单笔限额 1000）
+func newTestReloader(t *testing.T) (*Reloader, string, *engine.ArbitrageEngine, *risk.Checker) {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "params.yaml")
+	base := Params{
+		Engine: engine.DefaultEngineConfig(),
+		Risk:   risk.Limits{MaxTradeAmount: decimal.NewFromInt(1000), MaxRiskScore: 50},
+	}
+	arbitrageEngine := engine.NewArbitrageEngine(base.Engine, cache.NewMemoryPriceCache(5*time.Second))
+	checker := risk.NewChecker(base.Risk)
+	return NewReloader(NewFileSource(file), base, arbitrageEngine, checker), file, arbitrageEngine, checker
+}
+
+// writeParams 写入参数文件
+func writeParams(t *testing.T, file, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// takerFee 查找交易所的 taker 费率
+func takerFee(config *engine.EngineConfig, exchange string) float64 {
+	for _, fee := range config.TradingFees {
+		if fee.Exchange == exchange {
+			return fee.TakerFee
+		}
+	}
+	return -1
+}
+
+// TestReloader_File 测试参数文件变更生效、无效变更被拒绝、删除的键恢复启动配置
+func TestReloader_File(t *testing.T) {
+	reloader, file, arbitrageEngine, checker := newTestReloader(t)
+	ctx := context.Background()
+	baseRate := arbitrageEngine.Config().MinProfitRate
+
+	// 参数文件不存在时使用启动配置
+	if err := reloader.Reload(ctx); err != nil {
+		t.Fatalf("Reload() without file error = %v", err)
+	}
+
+	writeParams(t, file, `
+min_profit_rate: 0.01
+fee.binance.taker: 0.0005
+max_single_trade_amount: "500"
+unrelated_key: ignored
+`)
+	if err := reloader.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := arbitrageEngine.Config().MinProfitRate; got != 0.01 {
+		t.Errorf("MinProfitRate = %v, want 0.01", got)
+	}
+	if got := takerFee(arbitrageEngine.Config(), "binance"); got != 0.0005 {
+		t.Errorf("binance TakerFee = %v, want 0.0005", got)
+	}
+	if got := checker.Limits().MaxTradeAmount; !got.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("MaxTradeAmount = %s, want 500", got)
+	}
+
+	// 任意一项无效时整次变更被拒绝
+	writeParams(t, file, "min_profit_rate: 0.02\nrisk.max_risk_score: 150\n")
+	if err := reloader.Reload(ctx); err == nil || !strings.Contains(err.Error(), "拒绝") {
+		t.Errorf("Reload() invalid error = %v", err)
+	}
+	if got := arbitrageEngine.Config().MinProfitRate; got != 0.01 {
+		t.Errorf("MinProfitRate after rejected change = %v, want 0.01", got)
+	}
+
+	// 删除的键恢复为启动配置
+	writeParams(t, file, "min_profit_rate: 0.02\n")
+	if err := reloader.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := arbitrageEngine.Config().MinProfitRate; got != 0.02 {
+		t.Errorf("MinProfitRate = %v, want 0.02", got)
+	}
+	if got := checker.Limits().MaxTradeAmount; !got.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("MaxTradeAmount after removal = %s, want 1000", got)
+	}
+	if got := takerFee(arbitrageEngine.Config(), "binance"); got != takerFee(engine.DefaultEngineConfig(), "binance") {
+		t.Errorf("binance TakerFee after removal = %v", got)
+	}
+
+	writeParams(t, file, "")
+	if err := reloader.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := arbitrageEngine.Config().MinProfitRate; got != baseRate {
+		t.Errorf("MinProfitRate after clear = %v, want %v", got, baseRate)
+	}
+}
+
+// TestReloader_SystemConfig 测试从 system_config 表读取参数并定期检查
+func TestReloader_SystemConfig(t *testing.T) {
+	ctx := context.Background()
+	configs := store.NewMemoryStore().Configs
+	if err := configs.Set(ctx, "min_confirmations", "3", "连续确认次数"); err != nil {
+		t.Fatal(err)
+	}
+
+	base := Params{Engine: engine.DefaultEngineConfig()}
+	arbitrageEngine := engine.NewArbitrageEngine(base.Engine, cache.NewMemoryPriceCache(5*time.Second))
+	reloader := NewReloader(ReloadConf{SystemConfig: true}.NewSource(configs), base, arbitrageEngine, nil)
+	if err := reloader.Start(10 * time.Millisecond); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer reloader.Stop()
+
+	if got := arbitrageEngine.Config().MinConfirmations; got != 3 {
+		t.Errorf("MinConfirmations = %d, want 3", got)
+	}
+
+	if err := configs.Set(ctx, "min_confirmations", "5", "连续确认次数"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for arbitrageEngine.Config().MinConfirmations != 5 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := arbitrageEngine.Config().MinConfirmations; got != 5 {
+		t.Errorf("MinConfirmations after update = %d, want 5", got)
+	}
+}
+
+// TestReloadConf_Validate 测试热更新配置校验
+func TestReloadConf_Validate(t *testing.T) {
+	dsn := MySQLConf{DataSource: "user:pass@tcp(localhost:3306)/arbitragex"}
+	tests := []struct {
+		name    string
+		conf    ReloadConf
+		mysql   MySQLConf
+		wantErr bool
+	}{
+		{"未开启", ReloadConf{}, MySQLConf{}, false},
+		{"参数文件", ReloadConf{File: "params.yaml", Interval: time.Second}, MySQLConf{}, false},
+		{"system_config", ReloadConf{SystemConfig: true, Interval: time.Second}, dsn, false},
+		{"同时配置两种来源", ReloadConf{File: "params.yaml", SystemConfig: true, Interval: time.Second}, dsn, true},
+		{"缺少 MySQL", ReloadConf{SystemConfig: true, Interval: time.Second}, MySQLConf{}, true},
+		{"间隔为 0", ReloadConf{File: "params.yaml"}, MySQLConf{}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.conf.Validate(tt.mysql); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
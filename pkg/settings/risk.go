@@ -0,0 +1,49 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/risk"
+)
+
+// RiskConf 交易前风控（金额为 USDT，为空或 0 表示不限制）
+type RiskConf struct {
+	MaxTradeAmount    string        `json:",optional"`   // 单笔最大交易金额
+	MaxDailyVolume    string        `json:",optional"`   // 当日累计最大交易金额
+	MaxDailyLoss      string        `json:",optional"`   // 当日最大亏损（达到后停止交易）
+	MaxRiskScore      float64       `json:",default=50"` // 最大风险评分（0-100）
+	MinProfitRate     float64       `json:",optional"`   // 最小净收益率
+	MaxOpportunityAge time.Duration `json:",default=3s"` // 机会发现后超过该时间不再执行
+}
+
+// Limits 转换为风控限额
+// 返回:
+//   - risk.Limits: 已校验的限额
+//   - error: 金额格式错误或限额无效
+func (c RiskConf) Limits() (risk.Limits, error) {
+	limits := risk.Limits{
+		MaxRiskScore:      c.MaxRiskScore,
+		MinProfitRate:     c.MinProfitRate,
+		MaxOpportunityAge: c.MaxOpportunityAge,
+	}
+	amounts := []struct {
+		name  string
+		value string
+		dst   *decimal.Decimal
+	}{
+		{"Risk.MaxTradeAmount", c.MaxTradeAmount, &limits.MaxTradeAmount},
+		{"Risk.MaxDailyVolume", c.MaxDailyVolume, &limits.MaxDailyVolume},
+		{"Risk.MaxDailyLoss", c.MaxDailyLoss, &limits.MaxDailyLoss},
+	}
+	for _, amount := range amounts {
+		if err := parseAmount(amount.name, amount.value, amount.dst); err != nil {
+			return limits, err
+		}
+	}
+	if err := limits.Validate(); err != nil {
+		return limits, fmt.Errorf("Risk: %w", err)
+	}
+	return limits, nil
+}
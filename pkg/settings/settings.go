@@ -0,0 +1,40 @@
+// Package settings 各组件共用的配置结构
+// 职责：定义交易所、套利引擎、风控、执行器和存储的配置（go-zero conf 标签，可嵌入各服务和单进程模式的配置），
+// 校验配置并转换为各组件使用的参数；Load 加载配置文件时展开环境变量并执行校验
+package settings
+
+import (
+	"log"
+
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+// Validator 加载后需要校验的配置
+type Validator interface {
+	Validate() error
+}
+
+// Load 加载配置文件
+// 文件中的 ${VAR} 按环境变量展开（密钥可以不写入文件）；v 实现 Validator 时加载后执行校验
+// （go-zero 的 conf.UseEnv 不会调用 Validate，因此不直接使用 conf.Load）
+// 参数:
+//   - file: 配置文件路径（.yaml、.yml 或 .json）
+//   - v: 配置结构指针
+// 返回:
+//   - error: 读取、解析或校验失败
+func Load(file string, v any) error {
+	if err := conf.Load(file, v, conf.UseEnv()); err != nil {
+		return err
+	}
+	if validator, ok := v.(Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// MustLoad 加载配置文件，失败时退出进程
+func MustLoad(file string, v any) {
+	if err := Load(file, v); err != nil {
+		log.Fatalf("error: config file %s, %s", file, err.Error())
+	}
+}
@@ -0,0 +1,204 @@
+// Package settings 共用配置单元测试
+package settings
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/credential"
+	"arbitragex/pkg/execution"
+)
+
+// testConfig 测试用的顶层配置
+type testConfig struct {
+	Exchanges Exchanges
+	Engine    EngineConf
+	Executor  ExecutorConf
+	Risk      RiskConf
+}
+
+// Validate 校验配置
+func (c testConfig) Validate() error {
+	if err := c.Exchanges.Validate(); err != nil {
+		return err
+	}
+	if _, err := c.Engine.EngineConfig(c.Exchanges.TradingFees()); err != nil {
+		return err
+	}
+	if err := c.Executor.Validate(); err != nil {
+		return err
+	}
+	_, err := c.Risk.Limits()
+	return err
+}
+
+// writeConfig 写入临时配置文件
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// TestLoad 测试默认值、${VAR} 展开和组件配置转换
+func TestLoad(t *testing.T) {
+	t.Setenv("TEST_OKX_WS", "wss://ws.example.com/ws/v5/public")
+	file := writeConfig(t, `
+Exchanges:
+  - Name: Binance
+    MakerFee: 0.0002
+    TakerFee: 0.0004
+  - Name: okx
+    WebSocketBaseURL: ${TEST_OKX_WS}
+  - Name: uniswap
+    Type: dex
+Engine:
+  MinVolume: "500"
+Risk:
+  MaxTradeAmount: "200"
+`)
+
+	var c testConfig
+	if err := Load(file, &c); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := c.Exchanges.Enabled().Names(); len(got) != 2 || got[0] != "binance" || got[1] != "okx" {
+		t.Errorf("Enabled().Names() = %v, want [binance okx]", got)
+	}
+	if got := c.Exchanges[1].WebSocketBaseURL; got != "wss://ws.example.com/ws/v5/public" {
+		t.Errorf("WebSocketBaseURL = %q", got)
+	}
+
+	engineConfig, err := c.Engine.EngineConfig(c.Exchanges.Enabled().TradingFees())
+	if err != nil {
+		t.Fatalf("EngineConfig() error = %v", err)
+	}
+	if engineConfig.MinProfitRate != 0.005 || !engineConfig.MinVolume.Equal(decimal.NewFromInt(500)) || engineConfig.OpportunityTTL != 5*time.Second {
+		t.Errorf("EngineConfig() = %+v", engineConfig)
+	}
+	if fees := engineConfig.TradingFees; len(fees) != 2 || fees[0].Exchange != "binance" || fees[0].TakerFee != 0.0004 || fees[1].TakerFee != 0.001 {
+		t.Errorf("TradingFees = %+v", fees)
+	}
+
+	limits, err := c.Risk.Limits()
+	if err != nil {
+		t.Fatalf("Limits() error = %v", err)
+	}
+	if !limits.MaxTradeAmount.Equal(decimal.NewFromInt(200)) || !limits.MaxDailyLoss.IsZero() || limits.MaxRiskScore != 50 {
+		t.Errorf("Limits() = %+v", limits)
+	}
+	if c.Executor.MaxConcurrent != 5 || c.Executor.QueueSize != 1000 || !c.Executor.Paper {
+		t.Errorf("Executor = %+v", c.Executor)
+	}
+}
+
+// TestLoad_Invalid 测试校验失败时返回指明字段的错误
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"缺少交易所", "Engine:\n  MinVolume: \"500\"\n", "Exchanges"},
+		{"交易所名称为空", "Exchanges:\n  - Type: cex\n", "Exchanges"},
+		{"手续费率无效", "Exchanges:\n  - Name: binance\n    MakerFee: -0.1\n", "Exchanges[0]"},
+		{"金额格式错误", "Exchanges:\n  - Name: binance\nEngine:\n  MinProfitAmount: ten\n", "Engine.MinProfitAmount"},
+		{"引擎阈值无效", "Exchanges:\n  - Name: binance\nEngine:\n  MinProfitRate: 2\n", "Engine"},
+		{"并发数为 0", "Exchanges:\n  - Name: binance\nExecutor:\n  MaxConcurrent: 0\n", "Executor.MaxConcurrent"},
+		{"风控限额无效", "Exchanges:\n  - Name: binance\nRisk:\n  MaxRiskScore: 150\n", "Risk"},
+	}
+	for _, tt := range tests {
+		var c testConfig
+		err := Load(writeConfig(t, tt.yaml), &c)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Load() error = %v, want containing %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+// TestExchanges_ValidateCredentials 测试从环境变量和加密密钥库读取 API 密钥
+func TestExchanges_ValidateCredentials(t *testing.T) {
+	okx := Exchanges{{Name: "okx", Type: "cex", Enabled: true}}
+	t.Setenv("OKX_API_KEY", "env-key")
+	t.Setenv("OKX_API_SECRET", "env-secret")
+	t.Setenv("OKX_PASSPHRASE", "")
+	if err := okx.ValidateCredentials(CredentialConf{}); err == nil || !strings.Contains(err.Error(), "Passphrase") {
+		t.Errorf("ValidateCredentials() without passphrase error = %v", err)
+	}
+	t.Setenv("OKX_PASSPHRASE", "env-passphrase")
+	if err := okx.ValidateCredentials(CredentialConf{}); err != nil {
+		t.Errorf("ValidateCredentials() error = %v", err)
+	}
+
+	// 子账户的密钥保存在密钥库中
+	file := filepath.Join(t.TempDir(), "keystore.json")
+	keystore := credential.NewKeystore()
+	keystore.Set("binance", "sub1", &credential.Credential{APIKey: []byte("store-key"), APISecret: []byte("store-secret")})
+	if err := keystore.Save(file, []byte("test-passphrase")); err != nil {
+		t.Fatal(err)
+	}
+	keystore.Close()
+
+	binance := Exchanges{{Name: "Binance", Type: "cex", Enabled: true, Account: "sub1"}}
+	credentials := CredentialConf{Keystore: file, PassphraseEnv: "TEST_KEYSTORE_PASSPHRASE"}
+	t.Setenv("TEST_KEYSTORE_PASSPHRASE", "")
+	if err := binance.ValidateCredentials(credentials); err == nil || !strings.Contains(err.Error(), "TEST_KEYSTORE_PASSPHRASE") {
+		t.Errorf("ValidateCredentials() without keystore passphrase error = %v", err)
+	}
+	t.Setenv("TEST_KEYSTORE_PASSPHRASE", "wrong")
+	if err := binance.ValidateCredentials(credentials); !errors.Is(err, credential.ErrWrongPassphrase) {
+		t.Errorf("ValidateCredentials() wrong passphrase error = %v", err)
+	}
+	t.Setenv("TEST_KEYSTORE_PASSPHRASE", "test-passphrase")
+	if err := binance.ValidateCredentials(credentials); err != nil {
+		t.Errorf("ValidateCredentials() keystore error = %v", err)
+	}
+	if err := binance.ValidateCredentials(CredentialConf{}); err == nil {
+		t.Error("ValidateCredentials() without keystore error = nil")
+	}
+
+	executors, err := binance.NewExecutors(ExecutorConf{Paper: false}, credentials)
+	if err != nil {
+		t.Fatalf("NewExecutors(live) error = %v", err)
+	}
+	if _, ok := executors["binance"].(*execution.BinanceExecutor); !ok {
+		t.Errorf("NewExecutors(live) = %v, want binance executor", executors)
+	}
+
+	binance[0].OrderTransport = "ws"
+	executors, err = binance.NewExecutors(ExecutorConf{Paper: false}, credentials)
+	if err != nil {
+		t.Fatalf("NewExecutors(ws) error = %v", err)
+	}
+	if _, ok := executors["binance"].(*execution.BinanceWSExecutor); !ok {
+		t.Errorf("NewExecutors(ws) = %v, want binance WebSocket executor", executors)
+	}
+}
+
+// TestExchanges_NewExecutors 测试模拟交易不需要密钥，真实交易缺少密钥时失败
+func TestExchanges_NewExecutors(t *testing.T) {
+	exchanges := Exchanges{
+		{Name: "binance", Type: "cex", Enabled: true, MakerFee: 0.001, TakerFee: 0.001},
+		{Name: "okx", Type: "cex", Enabled: false},
+	}
+
+	executors, err := exchanges.NewExecutors(ExecutorConf{Paper: true}, CredentialConf{})
+	if err != nil {
+		t.Fatalf("NewExecutors(paper) error = %v", err)
+	}
+	if _, ok := executors["binance"].(*execution.LivePaperExecutor); !ok || len(executors) != 1 {
+		t.Errorf("NewExecutors(paper) = %v, want binance live paper executor", executors)
+	}
+
+	if _, err := exchanges.NewExecutors(ExecutorConf{Paper: false}, CredentialConf{}); err == nil {
+		t.Error("NewExecutors(live) without credentials error = nil")
+	}
+}
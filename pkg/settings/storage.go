@@ -0,0 +1,16 @@
+package settings
+
+import "arbitragex/pkg/store"
+
+// MySQLConf MySQL 存储配置
+type MySQLConf struct {
+	DataSource string `json:",optional"` // DSN（为空时套利机会和执行记录只保存在内存中）
+}
+
+// NewStore 创建仓储（配置了 DataSource 时为 MySQL，否则为内存）
+func (c MySQLConf) NewStore() *store.Store {
+	if c.DataSource == "" {
+		return store.NewMemoryStore()
+	}
+	return store.NewMySQLStoreFromDSN(c.DataSource)
+}
@@ -0,0 +1,476 @@
+// Package store 提供持久化功能
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/execution"
+)
+
+// NewMemoryStore 创建内存仓储集合（用于测试和不连接数据库的本地运行）
+// 与 MySQL 实现的行为一致：数值按 DECIMAL 精度截断，订单写入经过状态机
+func NewMemoryStore() *Store {
+	m := &memoryStore{
+		opportunities: make(map[string]*OpportunityRecord),
+		executions:    make(map[string]*execution.ExecutionResult),
+		orders:        make(map[string]*memoryOrder),
+		balances:      make(map[string]*execution.Balance),
+		configs:       make(map[string]string),
+	}
+
+	return &Store{
+		Opportunities: &memoryOpportunityRepository{m},
+		Executions:    &memoryExecutionRepository{m},
+		Orders:        &memoryOrderRepository{m},
+		Balances:      &memoryBalanceRepository{m},
+		Configs:       &memoryConfigRepository{m},
+	}
+}
+
+// memoryStore 内存仓储共享的数据
+type memoryStore struct {
+	opportunities map[string]*OpportunityRecord
+	executions    map[string]*execution.ExecutionResult
+	orders        map[string]*memoryOrder
+	balances      map[string]*execution.Balance
+	configs       map[string]string
+
+	mu sync.RWMutex
+}
+
+// memoryOrder 内存中的订单
+type memoryOrder struct {
+	executionID string
+	order       *execution.Order
+}
+
+// roundDecimal 按 DECIMAL 精度舍入，保证与从 MySQL 读回的值一致
+func roundDecimal(v decimal.Decimal, scale int32) decimal.Decimal {
+	return v.Round(scale)
+}
+
+// roundRate 按 DECIMAL 精度舍入比率
+func roundRate(v float64, scale int32) float64 {
+	rounded, _ := parseRate(formatRate(v, scale))
+	return rounded
+}
+
+// memoryOpportunityRepository 套利机会内存仓储
+type memoryOpportunityRepository struct {
+	*memoryStore
+}
+
+// Save 保存套利机会
+func (r *memoryOpportunityRepository) Save(ctx context.Context, opp *engine.ArbitrageOpportunity) error {
+	if opp == nil || opp.ID == "" {
+		return fmt.Errorf("套利机会ID不能为空")
+	}
+
+	record := roundRecord(newOpportunityRecord(opp))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.opportunities[opp.ID]
+	if !ok {
+		r.opportunities[opp.ID] = record
+		return nil
+	}
+
+	// 与 ON DUPLICATE KEY UPDATE 一致：只更新价格、收益和峰值价差
+	updated := *record.Opportunity
+	updated.Symbol = existing.Opportunity.Symbol
+	updated.BuyExchange = existing.Opportunity.BuyExchange
+	updated.SellExchange = existing.Opportunity.SellExchange
+	updated.DiscoveredAt = existing.Opportunity.DiscoveredAt
+	existing.Opportunity = &updated
+	existing.PeakPriceDiffRate = math.Max(existing.PeakPriceDiffRate, record.PeakPriceDiffRate)
+	return nil
+}
+
+// Record 保存套利机会的持续记录
+func (r *memoryOpportunityRepository) Record(ctx context.Context, record *OpportunityRecord) error {
+	if record == nil || record.Opportunity == nil || record.Opportunity.ID == "" {
+		return fmt.Errorf("套利机会ID不能为空")
+	}
+
+	rounded := roundRecord(record)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.opportunities[rounded.Opportunity.ID]
+	if !ok {
+		rounded.Executed = false
+		r.opportunities[rounded.Opportunity.ID] = rounded
+		return nil
+	}
+
+	if rounded.LastSeenAt.After(existing.LastSeenAt) {
+		existing.LastSeenAt = rounded.LastSeenAt
+	}
+	existing.PeakPriceDiffRate = math.Max(existing.PeakPriceDiffRate, rounded.PeakPriceDiffRate)
+	if rounded.Sightings > existing.Sightings {
+		existing.Sightings = rounded.Sightings
+	}
+	return nil
+}
+
+// roundRecord 复制记录并按 DECIMAL 精度截断
+func roundRecord(record *OpportunityRecord) *OpportunityRecord {
+	opp := *record.Opportunity
+	opp.BuyPrice = roundDecimal(opp.BuyPrice, amountScale)
+	opp.SellPrice = roundDecimal(opp.SellPrice, amountScale)
+	opp.PriceDiff = roundDecimal(opp.PriceDiff, amountScale)
+	opp.PriceDiffRate = roundRate(opp.PriceDiffRate, rateScale)
+	opp.RevenueRate = roundRate(opp.RevenueRate, rateScale)
+	opp.EstRevenue = roundDecimal(opp.EstRevenue, amountScale)
+
+	// 表中只保存这些字段，其余字段读回时为零值
+	stored := &engine.ArbitrageOpportunity{
+		ID:            opp.ID,
+		Symbol:        opp.Symbol,
+		BuyExchange:   opp.BuyExchange,
+		SellExchange:  opp.SellExchange,
+		BuyPrice:      opp.BuyPrice,
+		SellPrice:     opp.SellPrice,
+		PriceDiff:     opp.PriceDiff,
+		PriceDiffRate: opp.PriceDiffRate,
+		RevenueRate:   opp.RevenueRate,
+		EstRevenue:    opp.EstRevenue,
+		DiscoveredAt:  opp.DiscoveredAt,
+	}
+
+	return &OpportunityRecord{
+		Opportunity:       stored,
+		LastSeenAt:        record.LastSeenAt,
+		PeakPriceDiffRate: roundRate(record.PeakPriceDiffRate, rateScale),
+		Sightings:         record.Sightings,
+		Executed:          record.Executed,
+	}
+}
+
+// Get 获取套利机会记录
+func (r *memoryOpportunityRepository) Get(ctx context.Context, id string) (*OpportunityRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.opportunities[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: 套利机会 %s", ErrNotFound, id)
+	}
+	return roundRecord(stored), nil
+}
+
+// MarkExecuted 标记套利机会已执行
+func (r *memoryOpportunityRepository) MarkExecuted(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.opportunities[id]
+	if !ok {
+		return fmt.Errorf("%w: 套利机会 %s", ErrNotFound, id)
+	}
+	stored.Executed = true
+	return nil
+}
+
+// ListSince 按首次发现时间倒序查询套利机会记录
+func (r *memoryOpportunityRepository) ListSince(ctx context.Context, symbol string, since time.Time, limit int) ([]*OpportunityRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := make([]*OpportunityRecord, 0)
+	for _, stored := range r.opportunities {
+		if symbol != "" && stored.Opportunity.Symbol != symbol {
+			continue
+		}
+		if stored.Opportunity.DiscoveredAt.Before(since) {
+			continue
+		}
+		records = append(records, roundRecord(stored))
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Opportunity.DiscoveredAt.After(records[j].Opportunity.DiscoveredAt)
+	})
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+// memoryExecutionRepository 交易执行内存仓储
+type memoryExecutionRepository struct {
+	*memoryStore
+}
+
+// Save 保存执行结果及其买卖订单
+func (r *memoryExecutionRepository) Save(ctx context.Context, result *execution.ExecutionResult) error {
+	if result == nil || result.ID == "" {
+		return fmt.Errorf("执行ID不能为空")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 先检查订单，保证与事务一样要么全部写入要么都不写
+	updates := make(map[string]*execution.Order)
+	for _, order := range []*execution.Order{result.BuyOrder, result.SellOrder} {
+		if order == nil {
+			continue
+		}
+		merged, err := r.advanceLocked(order)
+		if err != nil {
+			if isStaleOrder(err) {
+				continue
+			}
+			return err
+		}
+		updates[merged.ID] = merged
+	}
+
+	stored := *result
+	stored.BuyOrder = nil
+	stored.SellOrder = nil
+	stored.TradingAmount = roundDecimal(result.TradingAmount, amountScale)
+	stored.EstProfit = roundDecimal(result.EstProfit, amountScale)
+	stored.ActualProfit = roundDecimal(result.ActualProfit, amountScale)
+	if stored.StartedAt.IsZero() {
+		stored.StartedAt = time.Now()
+	}
+	if existing, ok := r.executions[result.ID]; ok {
+		stored.StartedAt = existing.StartedAt
+	}
+	r.executions[result.ID] = &stored
+
+	for id, order := range updates {
+		r.orders[id] = &memoryOrder{executionID: result.ID, order: order}
+	}
+	return nil
+}
+
+// Get 获取执行结果（包含买卖订单）
+func (r *memoryExecutionRepository) Get(ctx context.Context, id string) (*execution.ExecutionResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.executions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: 执行 %s", ErrNotFound, id)
+	}
+
+	result := *stored
+	attachOrders(&result, r.listOrdersLocked(id))
+	return &result, nil
+}
+
+// ListByStatus 查询指定状态的执行结果
+func (r *memoryExecutionRepository) ListByStatus(ctx context.Context, status string, limit int) ([]*execution.ExecutionResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*execution.ExecutionResult, 0)
+	for _, stored := range r.executions {
+		if status == "" || stored.Status == status {
+			copied := *stored
+			results = append(results, &copied)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].StartedAt.After(results[j].StartedAt)
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// memoryOrderRepository 订单内存仓储
+type memoryOrderRepository struct {
+	*memoryStore
+}
+
+// Save 保存订单
+func (r *memoryOrderRepository) Save(ctx context.Context, executionID string, order *execution.Order) error {
+	if order == nil {
+		return fmt.Errorf("订单不能为空")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.executions[executionID]; !ok {
+		// 与外键约束一致
+		return fmt.Errorf("保存订单失败: %w: 执行 %s", ErrNotFound, executionID)
+	}
+
+	merged, err := r.advanceLocked(order)
+	if err != nil {
+		return err
+	}
+	r.orders[merged.ID] = &memoryOrder{executionID: executionID, order: merged}
+	return nil
+}
+
+// advanceLocked 通过状态机合并订单并按 DECIMAL 精度截断，调用方需持有写锁
+func (r *memoryStore) advanceLocked(order *execution.Order) (*execution.Order, error) {
+	update := order.Clone()
+	update.ID = orderID(order)
+	if update.ID == "" {
+		return nil, fmt.Errorf("订单ID不能为空")
+	}
+	if _, err := execution.ToDBStatus(update.Status); err != nil {
+		return nil, err
+	}
+
+	update.Price = roundDecimal(update.Price, amountScale)
+	update.Amount = roundDecimal(update.Amount, amountScale)
+	update.FilledAmount = roundDecimal(update.FilledAmount, amountScale)
+	update.AveragePrice = roundDecimal(update.AveragePrice, amountScale)
+	update.Fee = roundDecimal(update.Fee, amountScale)
+
+	var current *execution.Order
+	if stored, ok := r.orders[update.ID]; ok {
+		current = stored.order
+	}
+
+	merged, err := advanceOrder(current, update)
+	if err != nil {
+		return nil, err
+	}
+	if merged.CreatedAt.IsZero() {
+		merged.CreatedAt = time.Now()
+	}
+	return merged, nil
+}
+
+// Get 获取订单
+func (r *memoryOrderRepository) Get(ctx context.Context, id string) (*execution.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.orders[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: 订单 %s", ErrNotFound, id)
+	}
+	return stored.order.Clone(), nil
+}
+
+// ListByExecution 查询执行的所有订单
+func (r *memoryOrderRepository) ListByExecution(ctx context.Context, executionID string) ([]*execution.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.listOrdersLocked(executionID), nil
+}
+
+// listOrdersLocked 查询执行的所有订单（按创建时间排序），调用方需持有读锁
+func (r *memoryStore) listOrdersLocked(executionID string) []*execution.Order {
+	orders := make([]*execution.Order, 0)
+	for _, stored := range r.orders {
+		if stored.executionID == executionID {
+			orders = append(orders, stored.order.Clone())
+		}
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].ID < orders[j].ID
+		}
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+	return orders
+}
+
+// memoryBalanceRepository 账户余额内存仓储
+type memoryBalanceRepository struct {
+	*memoryStore
+}
+
+// Save 保存余额快照
+func (r *memoryBalanceRepository) Save(ctx context.Context, balances []*execution.Balance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, balance := range balances {
+		r.balances[balance.Exchange+":"+balance.Asset] = &execution.Balance{
+			Exchange: balance.Exchange,
+			Asset:    balance.Asset,
+			Free:     roundDecimal(balance.Free, amountScale),
+			Locked:   roundDecimal(balance.Locked, amountScale),
+		}
+	}
+	return nil
+}
+
+// List 查询余额
+func (r *memoryBalanceRepository) List(ctx context.Context, exchange string) ([]*execution.Balance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	balances := make([]*execution.Balance, 0)
+	for _, balance := range r.balances {
+		if exchange == "" || balance.Exchange == exchange {
+			copied := *balance
+			balances = append(balances, &copied)
+		}
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Exchange != balances[j].Exchange {
+			return balances[i].Exchange < balances[j].Exchange
+		}
+		return balances[i].Asset < balances[j].Asset
+	})
+	return balances, nil
+}
+
+// memoryConfigRepository 系统配置内存仓储
+type memoryConfigRepository struct {
+	*memoryStore
+}
+
+// Get 获取配置值
+func (r *memoryConfigRepository) Get(ctx context.Context, key string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	value, ok := r.configs[key]
+	if !ok {
+		return "", fmt.Errorf("%w: 配置 %s", ErrNotFound, key)
+	}
+	return value, nil
+}
+
+// Set 设置配置值
+func (r *memoryConfigRepository) Set(ctx context.Context, key, value, description string) error {
+	if key == "" {
+		return fmt.Errorf("配置键不能为空")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs[key] = value
+	return nil
+}
+
+// List 获取所有配置
+func (r *memoryConfigRepository) List(ctx context.Context) (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	configs := make(map[string]string, len(r.configs))
+	for key, value := range r.configs {
+		configs[key] = value
+	}
+	return configs, nil
+}
@@ -0,0 +1,227 @@
+// Package store 内存仓储单元测试
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/execution"
+)
+
+// TestMemoryOpportunityRepository 测试套利机会的保存、查询和标记执行
+func TestMemoryOpportunityRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryStore().Opportunities
+	now := time.Now()
+
+	for i, id := range []string{"opp-1", "opp-2", "opp-3"} {
+		err := repo.Save(ctx, &engine.ArbitrageOpportunity{
+			ID:            id,
+			Symbol:        "BTC/USDT",
+			BuyExchange:   "binance",
+			SellExchange:  "okx",
+			BuyPrice:      decimal.NewFromFloat(43000.123456789),
+			SellPrice:     decimal.NewFromFloat(43100),
+			PriceDiffRate: 0.2325581395,
+			DiscoveredAt:  now.Add(time.Duration(i) * time.Second),
+		})
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	record, err := repo.Get(ctx, "opp-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	opp := record.Opportunity
+	if !opp.BuyPrice.Equal(decimal.NewFromFloat(43000.12345679)) {
+		t.Errorf("BuyPrice = %v, want 43000.12345679 (DECIMAL(20,8))", opp.BuyPrice)
+	}
+	if opp.PriceDiffRate != 0.232558 {
+		t.Errorf("PriceDiffRate = %v, want 0.232558 (DECIMAL(10,6))", opp.PriceDiffRate)
+	}
+
+	list, err := repo.ListSince(ctx, "BTC/USDT", now.Add(time.Second), 10)
+	if err != nil {
+		t.Fatalf("ListSince() error = %v", err)
+	}
+	if len(list) != 2 || list[0].Opportunity.ID != "opp-3" || list[1].Opportunity.ID != "opp-2" {
+		t.Errorf("ListSince() = %v, want [opp-3 opp-2]", list)
+	}
+
+	if err := repo.MarkExecuted(ctx, "opp-1"); err != nil {
+		t.Errorf("MarkExecuted() error = %v", err)
+	}
+	if record, _ := repo.Get(ctx, "opp-1"); !record.Executed {
+		t.Error("Executed = false after MarkExecuted()")
+	}
+	if err := repo.MarkExecuted(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("MarkExecuted(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := repo.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+// TestMemoryExecutionRepository 测试执行结果连同买卖订单一起保存
+func TestMemoryExecutionRepository(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	result := &execution.ExecutionResult{
+		ID:            "exec-1",
+		OpportunityID: "opp-1",
+		Symbol:        "BTC/USDT",
+		BuyExchange:   "binance",
+		SellExchange:  "okx",
+		TradingAmount: decimal.NewFromFloat(0.1),
+		Status:        execution.ExecutionStatusExecuting,
+		StartedAt:     time.Now(),
+		BuyOrder: &execution.Order{
+			ID: "binance:BTCUSDT:1", Exchange: "binance", Symbol: "BTC/USDT",
+			Side: execution.OrderSideBuy, Type: execution.OrderTypeLimit,
+			Price: decimal.NewFromFloat(43000), Amount: decimal.NewFromFloat(0.1), Status: execution.OrderStatusOpen,
+		},
+		SellOrder: &execution.Order{
+			ID: "okx:BTC-USDT:2", Exchange: "okx", Symbol: "BTC/USDT",
+			Side: execution.OrderSideSell, Type: execution.OrderTypeLimit,
+			Price: decimal.NewFromFloat(43100), Amount: decimal.NewFromFloat(0.1), Status: execution.OrderStatusOpen,
+		},
+	}
+	if err := store.Executions.Save(ctx, result); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// 私有数据流先写入成交
+	filled := result.BuyOrder.Clone()
+	filled.Status = execution.OrderStatusFilled
+	filled.FilledAmount = decimal.NewFromFloat(0.1)
+	filled.AveragePrice = decimal.NewFromFloat(42999.5)
+	if err := store.Orders.Save(ctx, "exec-1", filled); err != nil {
+		t.Fatalf("Orders.Save() error = %v", err)
+	}
+
+	// 之后保存的执行结果仍带着旧的订单状态，不能覆盖已成交的订单
+	result.Status = execution.ExecutionStatusCompleted
+	result.CompletedAt = time.Now()
+	if err := store.Executions.Save(ctx, result); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := store.Executions.Get(ctx, "exec-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status != execution.ExecutionStatusCompleted {
+		t.Errorf("Status = %s, want completed", got.Status)
+	}
+	if got.BuyOrder == nil || got.BuyOrder.Status != execution.OrderStatusFilled || !got.BuyOrder.AveragePrice.Equal(decimal.NewFromFloat(42999.5)) {
+		t.Errorf("BuyOrder = %+v, want filled at 42999.5", got.BuyOrder)
+	}
+	if got.SellOrder == nil || got.SellOrder.ID != "okx:BTC-USDT:2" {
+		t.Errorf("SellOrder = %+v, want okx:BTC-USDT:2", got.SellOrder)
+	}
+
+	list, err := store.Executions.ListByStatus(ctx, execution.ExecutionStatusCompleted, 10)
+	if err != nil || len(list) != 1 {
+		t.Errorf("ListByStatus() = %v, %v, want 1 result", list, err)
+	}
+	if list, _ := store.Executions.ListByStatus(ctx, execution.ExecutionStatusFailed, 10); len(list) != 0 {
+		t.Errorf("ListByStatus(failed) = %d results, want 0", len(list))
+	}
+	if list, _ := store.Executions.ListByStatus(ctx, "", 10); len(list) != 1 {
+		t.Errorf("ListByStatus(\"\") = %d results, want 1", len(list))
+	}
+}
+
+// TestMemoryOrderRepository 测试订单状态不会倒退
+func TestMemoryOrderRepository(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	order := &execution.Order{
+		ID: "binance:BTCUSDT:1", Exchange: "binance", Symbol: "BTC/USDT",
+		Side: execution.OrderSideBuy, Type: execution.OrderTypeLimit,
+		Price: decimal.NewFromFloat(43000), Amount: decimal.NewFromFloat(0.1), Status: execution.OrderStatusOpen,
+	}
+	if err := store.Orders.Save(ctx, "exec-1", order); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Save() without execution error = %v, want ErrNotFound", err)
+	}
+
+	if err := store.Executions.Save(ctx, &execution.ExecutionResult{ID: "exec-1"}); err != nil {
+		t.Fatalf("Executions.Save() error = %v", err)
+	}
+	if err := store.Orders.Save(ctx, "exec-1", order); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	canceled := order.Clone()
+	canceled.Status = execution.OrderStatusCanceled
+	if err := store.Orders.Save(ctx, "exec-1", canceled); err != nil {
+		t.Fatalf("Save(canceled) error = %v", err)
+	}
+
+	reopened := order.Clone()
+	if err := store.Orders.Save(ctx, "exec-1", reopened); !errors.Is(err, execution.ErrIllegalTransition) {
+		t.Errorf("Save(open after canceled) error = %v, want ErrIllegalTransition", err)
+	}
+
+	got, err := store.Orders.Get(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status != execution.OrderStatusCanceled {
+		t.Errorf("Status = %s, want canceled", got.Status)
+	}
+
+	// 未到达交易所的失败订单以客户端订单ID保存
+	failed := &execution.Order{
+		ClientOrderID: "ax0123", Exchange: "okx", Symbol: "BTC/USDT",
+		Side: execution.OrderSideSell, Type: execution.OrderTypeLimit, Status: execution.OrderStatusFailed,
+	}
+	if err := store.Orders.Save(ctx, "exec-1", failed); err != nil {
+		t.Fatalf("Save(failed) error = %v", err)
+	}
+	orders, err := store.Orders.ListByExecution(ctx, "exec-1")
+	if err != nil || len(orders) != 2 {
+		t.Errorf("ListByExecution() = %v, %v, want 2 orders", orders, err)
+	}
+}
+
+// TestMemoryBalanceAndConfigRepository 测试余额和配置仓储
+func TestMemoryBalanceAndConfigRepository(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	err := store.Balances.Save(ctx, []*execution.Balance{
+		{Exchange: "okx", Asset: "USDT", Free: decimal.NewFromFloat(1000)},
+		{Exchange: "binance", Asset: "BTC", Free: decimal.NewFromFloat(0.5), Locked: decimal.NewFromFloat(0.1)},
+		{Exchange: "binance", Asset: "USDT", Free: decimal.NewFromFloat(2000)},
+	})
+	if err != nil {
+		t.Fatalf("Balances.Save() error = %v", err)
+	}
+
+	balances, err := store.Balances.List(ctx, "binance")
+	if err != nil {
+		t.Fatalf("Balances.List() error = %v", err)
+	}
+	if len(balances) != 2 || balances[0].Asset != "BTC" || !balances[0].Total().Equal(decimal.NewFromFloat(0.6)) {
+		t.Errorf("Balances.List(binance) = %+v", balances)
+	}
+
+	if err := store.Configs.Set(ctx, "min_profit_rate", "0.5", "最小收益率"); err != nil {
+		t.Fatalf("Configs.Set() error = %v", err)
+	}
+	if value, err := store.Configs.Get(ctx, "min_profit_rate"); err != nil || value != "0.5" {
+		t.Errorf("Configs.Get() = %q, %v, want 0.5", value, err)
+	}
+	if _, err := store.Configs.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Configs.Get(missing) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,728 @@
+// Package store 提供持久化功能
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/execution"
+)
+
+// NewMySQLStore 创建 MySQL 仓储集合
+// 参数:
+//   - conn: 数据库连接（DSN 需要带 parseTime=true，TIMESTAMP 才能扫描为 time.Time）
+//
+// 返回:
+//   - *Store: 仓储集合
+func NewMySQLStore(conn sqlx.SqlConn) *Store {
+	orders := &mysqlOrderRepository{conn: conn}
+
+	return &Store{
+		Opportunities: &mysqlOpportunityRepository{conn: conn},
+		Executions:    &mysqlExecutionRepository{conn: conn, orders: orders},
+		Orders:        orders,
+		Balances:      &mysqlBalanceRepository{conn: conn},
+		Configs:       &mysqlConfigRepository{conn: conn},
+	}
+}
+
+// NewMySQLStoreFromDSN 按 DSN 创建 MySQL 仓储集合
+// 参数:
+//   - dsn: 数据源（如 root:password@tcp(127.0.0.1:3306)/arbitragex?parseTime=true&loc=Local）
+//
+// 返回:
+//   - *Store: 仓储集合
+func NewMySQLStoreFromDSN(dsn string) *Store {
+	return NewMySQLStore(sqlx.NewMysql(dsn))
+}
+
+// notFound 将 sqlx.ErrNotFound 转换为 ErrNotFound
+func notFound(err error, format string, args ...interface{}) error {
+	if errors.Is(err, sqlx.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrNotFound, fmt.Sprintf(format, args...))
+	}
+	return err
+}
+
+// ================================================================================
+// arbitrage_opportunities
+// ================================================================================
+
+const opportunityFields = "`id`,`symbol`,`buy_exchange`,`sell_exchange`,`buy_price`,`sell_price`,`price_diff`,`price_diff_rate`,`revenue_rate`,`est_revenue`,`discovered_at`,`last_seen_at`,`peak_price_diff_rate`,`sightings`,`executed`"
+
+// opportunityRow arbitrage_opportunities 表的一行
+type opportunityRow struct {
+	ID                string    `db:"id"`
+	Symbol            string    `db:"symbol"`
+	BuyExchange       string    `db:"buy_exchange"`
+	SellExchange      string    `db:"sell_exchange"`
+	BuyPrice          string    `db:"buy_price"`
+	SellPrice         string    `db:"sell_price"`
+	PriceDiff         string    `db:"price_diff"`
+	PriceDiffRate     string    `db:"price_diff_rate"`
+	RevenueRate       string    `db:"revenue_rate"`
+	EstRevenue        string    `db:"est_revenue"`
+	DiscoveredAt      time.Time `db:"discovered_at"`
+	LastSeenAt        time.Time `db:"last_seen_at"`
+	PeakPriceDiffRate string    `db:"peak_price_diff_rate"`
+	Sightings         int       `db:"sightings"`
+	Executed          bool      `db:"executed"`
+}
+
+// toRecord 转换为套利机会记录
+func (r *opportunityRow) toRecord() (*OpportunityRecord, error) {
+	opp := &engine.ArbitrageOpportunity{
+		ID:           r.ID,
+		Symbol:       r.Symbol,
+		BuyExchange:  r.BuyExchange,
+		SellExchange: r.SellExchange,
+		DiscoveredAt: r.DiscoveredAt,
+	}
+	record := &OpportunityRecord{
+		Opportunity: opp,
+		LastSeenAt:  r.LastSeenAt,
+		Sightings:   r.Sightings,
+		Executed:    r.Executed,
+	}
+
+	var err error
+	for _, field := range []struct {
+		dst *decimal.Decimal
+		src string
+	}{
+		{&opp.BuyPrice, r.BuyPrice},
+		{&opp.SellPrice, r.SellPrice},
+		{&opp.PriceDiff, r.PriceDiff},
+		{&opp.EstRevenue, r.EstRevenue},
+	} {
+		if *field.dst, err = parseDecimal(field.src); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range []struct {
+		dst *float64
+		src string
+	}{
+		{&opp.PriceDiffRate, r.PriceDiffRate},
+		{&opp.RevenueRate, r.RevenueRate},
+		{&record.PeakPriceDiffRate, r.PeakPriceDiffRate},
+	} {
+		if *field.dst, err = parseRate(field.src); err != nil {
+			return nil, err
+		}
+	}
+
+	return record, nil
+}
+
+// opportunityArgs 套利机会记录的写入参数（顺序与 opportunityFields 去掉 executed 一致）
+func opportunityArgs(record *OpportunityRecord) []interface{} {
+	opp := record.Opportunity
+	return []interface{}{
+		opp.ID, opp.Symbol, opp.BuyExchange, opp.SellExchange,
+		formatDecimal(opp.BuyPrice, amountScale),
+		formatDecimal(opp.SellPrice, amountScale),
+		formatDecimal(opp.PriceDiff, amountScale),
+		formatRate(opp.PriceDiffRate, rateScale),
+		formatRate(opp.RevenueRate, rateScale),
+		formatDecimal(opp.EstRevenue, amountScale),
+		opp.DiscoveredAt, record.LastSeenAt,
+		formatRate(record.PeakPriceDiffRate, rateScale),
+		record.Sightings,
+	}
+}
+
+// mysqlOpportunityRepository 套利机会 MySQL 仓储
+type mysqlOpportunityRepository struct {
+	conn sqlx.SqlConn
+}
+
+// opportunityInsert 套利机会写入语句（ON DUPLICATE KEY UPDATE 部分由调用方补充）
+const opportunityInsert = "INSERT INTO `arbitrage_opportunities` (`id`,`symbol`,`buy_exchange`,`sell_exchange`,`buy_price`,`sell_price`,`price_diff`,`price_diff_rate`," +
+	"`revenue_rate`,`est_revenue`,`discovered_at`,`last_seen_at`,`peak_price_diff_rate`,`sightings`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?) "
+
+// Save 保存套利机会
+func (r *mysqlOpportunityRepository) Save(ctx context.Context, opp *engine.ArbitrageOpportunity) error {
+	if opp == nil || opp.ID == "" {
+		return fmt.Errorf("套利机会ID不能为空")
+	}
+
+	query := opportunityInsert +
+		"ON DUPLICATE KEY UPDATE `buy_price`=VALUES(`buy_price`),`sell_price`=VALUES(`sell_price`),`price_diff`=VALUES(`price_diff`)," +
+		"`price_diff_rate`=VALUES(`price_diff_rate`),`revenue_rate`=VALUES(`revenue_rate`),`est_revenue`=VALUES(`est_revenue`)," +
+		"`peak_price_diff_rate`=GREATEST(`peak_price_diff_rate`,VALUES(`peak_price_diff_rate`))"
+
+	if _, err := r.conn.ExecCtx(ctx, query, opportunityArgs(newOpportunityRecord(opp))...); err != nil {
+		return fmt.Errorf("保存套利机会失败: %w", err)
+	}
+	return nil
+}
+
+// Record 保存套利机会的持续记录
+func (r *mysqlOpportunityRepository) Record(ctx context.Context, record *OpportunityRecord) error {
+	if record == nil || record.Opportunity == nil || record.Opportunity.ID == "" {
+		return fmt.Errorf("套利机会ID不能为空")
+	}
+
+	query := opportunityInsert +
+		"ON DUPLICATE KEY UPDATE `last_seen_at`=GREATEST(`last_seen_at`,VALUES(`last_seen_at`))," +
+		"`peak_price_diff_rate`=GREATEST(`peak_price_diff_rate`,VALUES(`peak_price_diff_rate`)),`sightings`=GREATEST(`sightings`,VALUES(`sightings`))"
+
+	if _, err := r.conn.ExecCtx(ctx, query, opportunityArgs(record)...); err != nil {
+		return fmt.Errorf("保存套利机会记录失败: %w", err)
+	}
+	return nil
+}
+
+// Get 获取套利机会记录
+func (r *mysqlOpportunityRepository) Get(ctx context.Context, id string) (*OpportunityRecord, error) {
+	var row opportunityRow
+	query := "SELECT " + opportunityFields + " FROM `arbitrage_opportunities` WHERE `id` = ? LIMIT 1"
+	if err := r.conn.QueryRowCtx(ctx, &row, query, id); err != nil {
+		return nil, notFound(err, "套利机会 %s", id)
+	}
+	return row.toRecord()
+}
+
+// MarkExecuted 标记套利机会已执行
+func (r *mysqlOpportunityRepository) MarkExecuted(ctx context.Context, id string) error {
+	result, err := r.conn.ExecCtx(ctx, "UPDATE `arbitrage_opportunities` SET `executed` = TRUE WHERE `id` = ?", id)
+	if err != nil {
+		return fmt.Errorf("更新套利机会失败: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		// 已经是 TRUE 时影响行数也为 0，需要确认记录是否存在
+		if _, err := r.Get(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListSince 按首次发现时间倒序查询套利机会记录
+func (r *mysqlOpportunityRepository) ListSince(ctx context.Context, symbol string, since time.Time, limit int) ([]*OpportunityRecord, error) {
+	var rows []*opportunityRow
+	var err error
+	if symbol == "" {
+		query := "SELECT " + opportunityFields + " FROM `arbitrage_opportunities` WHERE `discovered_at` >= ? ORDER BY `discovered_at` DESC LIMIT ?"
+		err = r.conn.QueryRowsCtx(ctx, &rows, query, since, limit)
+	} else {
+		query := "SELECT " + opportunityFields + " FROM `arbitrage_opportunities` WHERE `symbol` = ? AND `discovered_at` >= ? ORDER BY `discovered_at` DESC LIMIT ?"
+		err = r.conn.QueryRowsCtx(ctx, &rows, query, symbol, since, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询套利机会失败: %w", err)
+	}
+
+	records := make([]*OpportunityRecord, 0, len(rows))
+	for _, row := range rows {
+		record, err := row.toRecord()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// ================================================================================
+// trade_executions
+// ================================================================================
+
+const executionFields = "`id`,`opportunity_id`,`symbol`,`buy_exchange`,`sell_exchange`,`buy_price`,`sell_price`,`amount`,`est_profit`,`actual_profit`,`status`,`error_message`,`started_at`,`completed_at`"
+
+// executionRow trade_executions 表的一行
+type executionRow struct {
+	ID            string         `db:"id"`
+	OpportunityID string         `db:"opportunity_id"`
+	Symbol        string         `db:"symbol"`
+	BuyExchange   string         `db:"buy_exchange"`
+	SellExchange  string         `db:"sell_exchange"`
+	BuyPrice      string         `db:"buy_price"`
+	SellPrice     string         `db:"sell_price"`
+	Amount        string         `db:"amount"`
+	EstProfit     string         `db:"est_profit"`
+	ActualProfit  sql.NullString `db:"actual_profit"`
+	Status        string         `db:"status"`
+	ErrorMessage  sql.NullString `db:"error_message"`
+	StartedAt     time.Time      `db:"started_at"`
+	CompletedAt   sql.NullTime   `db:"completed_at"`
+}
+
+// toResult 转换为执行结果
+func (r *executionRow) toResult() (*execution.ExecutionResult, error) {
+	result := &execution.ExecutionResult{
+		ID:            r.ID,
+		OpportunityID: r.OpportunityID,
+		Symbol:        r.Symbol,
+		BuyExchange:   r.BuyExchange,
+		SellExchange:  r.SellExchange,
+		Status:        r.Status,
+		ErrorMessage:  r.ErrorMessage.String,
+		StartedAt:     r.StartedAt,
+		CompletedAt:   r.CompletedAt.Time,
+	}
+
+	var err error
+	if result.TradingAmount, err = parseDecimal(r.Amount); err != nil {
+		return nil, err
+	}
+	if result.EstProfit, err = parseDecimal(r.EstProfit); err != nil {
+		return nil, err
+	}
+	if result.ActualProfit, err = parseDecimal(r.ActualProfit.String); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// executionArgs 执行结果的写入参数（顺序与 executionFields 一致）
+// buy_price / sell_price 取买卖订单的成交均价（未成交时取下单价格）
+func executionArgs(result *execution.ExecutionResult) []interface{} {
+	actualProfit := sql.NullString{}
+	completedAt := sql.NullTime{}
+	if !result.CompletedAt.IsZero() {
+		actualProfit = sql.NullString{String: formatDecimal(result.ActualProfit, amountScale), Valid: true}
+		completedAt = sql.NullTime{Time: result.CompletedAt, Valid: true}
+	}
+
+	errorMessage := sql.NullString{String: result.ErrorMessage, Valid: result.ErrorMessage != ""}
+
+	startedAt := result.StartedAt
+	if startedAt.IsZero() {
+		startedAt = time.Now()
+	}
+
+	return []interface{}{
+		result.ID, result.OpportunityID, result.Symbol, result.BuyExchange, result.SellExchange,
+		formatDecimal(orderPrice(result.BuyOrder), amountScale),
+		formatDecimal(orderPrice(result.SellOrder), amountScale),
+		formatDecimal(result.TradingAmount, amountScale),
+		formatDecimal(result.EstProfit, amountScale),
+		actualProfit, result.Status, errorMessage, startedAt, completedAt,
+	}
+}
+
+// orderPrice 订单的成交均价（未成交时为下单价格）
+func orderPrice(order *execution.Order) decimal.Decimal {
+	if order == nil {
+		return decimal.Zero
+	}
+	if order.AveragePrice.IsPositive() {
+		return order.AveragePrice
+	}
+	return order.Price
+}
+
+// mysqlExecutionRepository 交易执行 MySQL 仓储
+type mysqlExecutionRepository struct {
+	conn   sqlx.SqlConn
+	orders *mysqlOrderRepository
+}
+
+// Save 保存执行结果及其买卖订单
+func (r *mysqlExecutionRepository) Save(ctx context.Context, result *execution.ExecutionResult) error {
+	if result == nil || result.ID == "" {
+		return fmt.Errorf("执行ID不能为空")
+	}
+
+	query := "INSERT INTO `trade_executions` (" + executionFields + ") VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?) " +
+		"ON DUPLICATE KEY UPDATE `buy_price`=VALUES(`buy_price`),`sell_price`=VALUES(`sell_price`),`actual_profit`=VALUES(`actual_profit`)," +
+		"`status`=VALUES(`status`),`error_message`=VALUES(`error_message`),`completed_at`=VALUES(`completed_at`)"
+
+	return r.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := session.ExecCtx(ctx, query, executionArgs(result)...); err != nil {
+			return fmt.Errorf("保存执行结果失败: %w", err)
+		}
+
+		for _, order := range []*execution.Order{result.BuyOrder, result.SellOrder} {
+			if order == nil {
+				continue
+			}
+			if err := r.orders.save(ctx, session, result.ID, order); err != nil && !isStaleOrder(err) {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Get 获取执行结果（包含买卖订单）
+func (r *mysqlExecutionRepository) Get(ctx context.Context, id string) (*execution.ExecutionResult, error) {
+	var row executionRow
+	query := "SELECT " + executionFields + " FROM `trade_executions` WHERE `id` = ? LIMIT 1"
+	if err := r.conn.QueryRowCtx(ctx, &row, query, id); err != nil {
+		return nil, notFound(err, "执行 %s", id)
+	}
+
+	result, err := row.toResult()
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := r.orders.ListByExecution(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	attachOrders(result, orders)
+	return result, nil
+}
+
+// ListByStatus 查询指定状态的执行结果
+func (r *mysqlExecutionRepository) ListByStatus(ctx context.Context, status string, limit int) ([]*execution.ExecutionResult, error) {
+	var rows []*executionRow
+	query := "SELECT " + executionFields + " FROM `trade_executions` WHERE `status` = ? ORDER BY `started_at` DESC LIMIT ?"
+	args := []interface{}{status, limit}
+	if status == "" {
+		query = "SELECT " + executionFields + " FROM `trade_executions` ORDER BY `started_at` DESC LIMIT ?"
+		args = args[1:]
+	}
+	if err := r.conn.QueryRowsCtx(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("查询执行结果失败: %w", err)
+	}
+
+	results := make([]*execution.ExecutionResult, 0, len(rows))
+	for _, row := range rows {
+		result, err := row.toResult()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// attachOrders 将订单按方向填入执行结果（同方向有多笔时取最早的一笔，之后的是对冲单）
+func attachOrders(result *execution.ExecutionResult, orders []*execution.Order) {
+	for _, order := range orders {
+		switch {
+		case order.Side == execution.OrderSideBuy && result.BuyOrder == nil:
+			result.BuyOrder = order
+		case order.Side == execution.OrderSideSell && result.SellOrder == nil:
+			result.SellOrder = order
+		}
+	}
+}
+
+// ================================================================================
+// orders
+// ================================================================================
+
+const orderFields = "`id`,`execution_id`,`exchange`,`symbol`,`side`,`type`,`price`,`amount`,`filled_amount`,`avg_price`,`fee`,`status`,`exchange_order_id`,`created_at`,`updated_at`"
+
+// orderRow orders 表的一行
+type orderRow struct {
+	ID              string         `db:"id"`
+	ExecutionID     string         `db:"execution_id"`
+	Exchange        string         `db:"exchange"`
+	Symbol          string         `db:"symbol"`
+	Side            string         `db:"side"`
+	Type            string         `db:"type"`
+	Price           string         `db:"price"`
+	Amount          string         `db:"amount"`
+	FilledAmount    string         `db:"filled_amount"`
+	AvgPrice        sql.NullString `db:"avg_price"`
+	Fee             string         `db:"fee"`
+	Status          string         `db:"status"`
+	ExchangeOrderID sql.NullString `db:"exchange_order_id"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at"`
+}
+
+// toOrder 转换为订单
+func (r *orderRow) toOrder() (*execution.Order, error) {
+	status, err := execution.FromDBStatus(r.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &execution.Order{
+		ID:              r.ID,
+		Exchange:        r.Exchange,
+		Symbol:          r.Symbol,
+		Side:            r.Side,
+		Type:            r.Type,
+		Status:          status,
+		ExchangeOrderID: r.ExchangeOrderID.String,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+
+	for _, field := range []struct {
+		dst *decimal.Decimal
+		src string
+	}{
+		{&order.Price, r.Price},
+		{&order.Amount, r.Amount},
+		{&order.FilledAmount, r.FilledAmount},
+		{&order.AveragePrice, r.AvgPrice.String},
+		{&order.Fee, r.Fee},
+	} {
+		if *field.dst, err = parseDecimal(field.src); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// newOrderRow 由订单构建表的一行
+func newOrderRow(executionID string, order *execution.Order) (*orderRow, error) {
+	id := orderID(order)
+	if id == "" {
+		return nil, fmt.Errorf("订单ID不能为空")
+	}
+
+	status, err := execution.ToDBStatus(order.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	createdAt := order.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	updatedAt := order.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = now
+	}
+
+	return &orderRow{
+		ID:              id,
+		ExecutionID:     executionID,
+		Exchange:        order.Exchange,
+		Symbol:          order.Symbol,
+		Side:            order.Side,
+		Type:            order.Type,
+		Price:           formatDecimal(order.Price, amountScale),
+		Amount:          formatDecimal(order.Amount, amountScale),
+		FilledAmount:    formatDecimal(order.FilledAmount, amountScale),
+		AvgPrice:        sql.NullString{String: formatDecimal(order.AveragePrice, amountScale), Valid: order.AveragePrice.IsPositive()},
+		Fee:             formatDecimal(order.Fee, amountScale),
+		Status:          status,
+		ExchangeOrderID: sql.NullString{String: order.ExchangeOrderID, Valid: order.ExchangeOrderID != ""},
+		CreatedAt:       createdAt,
+		UpdatedAt:       updatedAt,
+	}, nil
+}
+
+// orderID 订单主键（未到达交易所的失败订单没有订单ID，使用客户端订单ID）
+func orderID(order *execution.Order) string {
+	if order.ID != "" {
+		return order.ID
+	}
+	return order.ClientOrderID
+}
+
+// mysqlOrderRepository 订单 MySQL 仓储
+type mysqlOrderRepository struct {
+	conn sqlx.SqlConn
+}
+
+// Save 保存订单
+func (r *mysqlOrderRepository) Save(ctx context.Context, executionID string, order *execution.Order) error {
+	return r.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		return r.save(ctx, session, executionID, order)
+	})
+}
+
+// save 在事务中保存订单：锁定已保存的订单，经过状态机后写入
+func (r *mysqlOrderRepository) save(ctx context.Context, session sqlx.Session, executionID string, order *execution.Order) error {
+	if order == nil {
+		return fmt.Errorf("订单不能为空")
+	}
+
+	var current *execution.Order
+	var row orderRow
+	query := "SELECT " + orderFields + " FROM `orders` WHERE `id` = ? LIMIT 1 FOR UPDATE"
+	switch err := session.QueryRowCtx(ctx, &row, query, orderID(order)); {
+	case err == nil:
+		if current, err = row.toOrder(); err != nil {
+			return err
+		}
+	case errors.Is(err, sqlx.ErrNotFound):
+	default:
+		return fmt.Errorf("查询订单失败: %w", err)
+	}
+
+	update := order.Clone()
+	update.ID = orderID(order)
+	merged, err := advanceOrder(current, update)
+	if err != nil {
+		return err
+	}
+
+	next, err := newOrderRow(executionID, merged)
+	if err != nil {
+		return err
+	}
+
+	query = "INSERT INTO `orders` (" + orderFields + ") VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?) " +
+		"ON DUPLICATE KEY UPDATE `filled_amount`=VALUES(`filled_amount`),`avg_price`=VALUES(`avg_price`),`fee`=VALUES(`fee`)," +
+		"`status`=VALUES(`status`),`exchange_order_id`=VALUES(`exchange_order_id`),`updated_at`=VALUES(`updated_at`)"
+	_, err = session.ExecCtx(ctx, query,
+		next.ID, next.ExecutionID, next.Exchange, next.Symbol, next.Side, next.Type,
+		next.Price, next.Amount, next.FilledAmount, next.AvgPrice, next.Fee, next.Status,
+		next.ExchangeOrderID, next.CreatedAt, next.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("保存订单失败: %w", err)
+	}
+	return nil
+}
+
+// Get 获取订单
+func (r *mysqlOrderRepository) Get(ctx context.Context, id string) (*execution.Order, error) {
+	var row orderRow
+	query := "SELECT " + orderFields + " FROM `orders` WHERE `id` = ? LIMIT 1"
+	if err := r.conn.QueryRowCtx(ctx, &row, query, id); err != nil {
+		return nil, notFound(err, "订单 %s", id)
+	}
+	return row.toOrder()
+}
+
+// ListByExecution 查询执行的所有订单
+func (r *mysqlOrderRepository) ListByExecution(ctx context.Context, executionID string) ([]*execution.Order, error) {
+	var rows []*orderRow
+	query := "SELECT " + orderFields + " FROM `orders` WHERE `execution_id` = ? ORDER BY `created_at`, `id`"
+	if err := r.conn.QueryRowsCtx(ctx, &rows, query, executionID); err != nil {
+		return nil, fmt.Errorf("查询订单失败: %w", err)
+	}
+
+	orders := make([]*execution.Order, 0, len(rows))
+	for _, row := range rows {
+		order, err := row.toOrder()
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// ================================================================================
+// account_balances
+// ================================================================================
+
+// balanceRow account_balances 表的一行
+type balanceRow struct {
+	Exchange  string `db:"exchange"`
+	Currency  string `db:"currency"`
+	Locked    string `db:"locked"`
+	Available string `db:"available"`
+}
+
+// mysqlBalanceRepository 账户余额 MySQL 仓储
+type mysqlBalanceRepository struct {
+	conn sqlx.SqlConn
+}
+
+// Save 保存余额快照
+func (r *mysqlBalanceRepository) Save(ctx context.Context, balances []*execution.Balance) error {
+	query := "INSERT INTO `account_balances` (`exchange`,`currency`,`balance`,`locked`,`available`) VALUES (?,?,?,?,?) " +
+		"ON DUPLICATE KEY UPDATE `balance`=VALUES(`balance`),`locked`=VALUES(`locked`),`available`=VALUES(`available`)"
+
+	return r.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for _, balance := range balances {
+			_, err := session.ExecCtx(ctx, query,
+				balance.Exchange, balance.Asset,
+				formatDecimal(balance.Total(), amountScale),
+				formatDecimal(balance.Locked, amountScale),
+				formatDecimal(balance.Free, amountScale),
+			)
+			if err != nil {
+				return fmt.Errorf("保存余额失败 %s %s: %w", balance.Exchange, balance.Asset, err)
+			}
+		}
+		return nil
+	})
+}
+
+// List 查询余额
+func (r *mysqlBalanceRepository) List(ctx context.Context, exchange string) ([]*execution.Balance, error) {
+	var rows []*balanceRow
+	var err error
+	if exchange == "" {
+		err = r.conn.QueryRowsCtx(ctx, &rows, "SELECT `exchange`,`currency`,`locked`,`available` FROM `account_balances` ORDER BY `exchange`, `currency`")
+	} else {
+		err = r.conn.QueryRowsCtx(ctx, &rows, "SELECT `exchange`,`currency`,`locked`,`available` FROM `account_balances` WHERE `exchange` = ? ORDER BY `currency`", exchange)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询余额失败: %w", err)
+	}
+
+	balances := make([]*execution.Balance, 0, len(rows))
+	for _, row := range rows {
+		balance := &execution.Balance{Exchange: row.Exchange, Asset: row.Currency}
+		if balance.Free, err = parseDecimal(row.Available); err != nil {
+			return nil, err
+		}
+		if balance.Locked, err = parseDecimal(row.Locked); err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
+
+// ================================================================================
+// system_config
+// ================================================================================
+
+// configRow system_config 表的一行
+type configRow struct {
+	Key   string `db:"config_key"`
+	Value string `db:"config_value"`
+}
+
+// mysqlConfigRepository 系统配置 MySQL 仓储
+type mysqlConfigRepository struct {
+	conn sqlx.SqlConn
+}
+
+// Get 获取配置值
+func (r *mysqlConfigRepository) Get(ctx context.Context, key string) (string, error) {
+	var value string
+	query := "SELECT `config_value` FROM `system_config` WHERE `config_key` = ? LIMIT 1"
+	if err := r.conn.QueryRowCtx(ctx, &value, query, key); err != nil {
+		return "", notFound(err, "配置 %s", key)
+	}
+	return value, nil
+}
+
+// Set 设置配置值
+func (r *mysqlConfigRepository) Set(ctx context.Context, key, value, description string) error {
+	if key == "" {
+		return fmt.Errorf("配置键不能为空")
+	}
+
+	query := "INSERT INTO `system_config` (`config_key`,`config_value`,`description`) VALUES (?,?,?) " +
+		"ON DUPLICATE KEY UPDATE `config_value`=VALUES(`config_value`),`description`=COALESCE(VALUES(`description`),`description`)"
+	desc := sql.NullString{String: description, Valid: description != ""}
+	if _, err := r.conn.ExecCtx(ctx, query, key, value, desc); err != nil {
+		return fmt.Errorf("保存配置失败: %w", err)
+	}
+	return nil
+}
+
+// List 获取所有配置
+func (r *mysqlConfigRepository) List(ctx context.Context) (map[string]string, error) {
+	var rows []*configRow
+	if err := r.conn.QueryRowsCtx(ctx, &rows, "SELECT `config_key`,`config_value` FROM `system_config`"); err != nil {
+		return nil, fmt.Errorf("查询配置失败: %w", err)
+	}
+
+	configs := make(map[string]string, len(rows))
+	for _, row := range rows {
+		configs[row.Key] = row.Value
+	}
+	return configs, nil
+}
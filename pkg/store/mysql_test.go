@@ -0,0 +1,219 @@
+// Package store MySQL 仓储单元测试（使用 sqlmock，不需要数据库）
+package store
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/execution"
+)
+
+// newMockStore 创建基于 sqlmock 的 MySQL 仓储
+func newMockStore(t *testing.T) (*Store, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return NewMySQLStore(sqlx.NewSqlConnFromDB(db)), mock
+}
+
+// TestMySQLOpportunityRepository_Save 测试 DECIMAL 以字符串写入
+func TestMySQLOpportunityRepository_Save(t *testing.T) {
+	store, mock := newMockStore(t)
+	discoveredAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `arbitrage_opportunities`")).
+		WithArgs("opp-1", "BTC/USDT", "binance", "okx",
+			"0.10000000", "43100.00000000", "0.00000000", "0.232558", "0.000000", "12.50000000",
+			discoveredAt, discoveredAt, "0.232558", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := store.Opportunities.Save(context.Background(), &engine.ArbitrageOpportunity{
+		ID:            "opp-1",
+		Symbol:        "BTC/USDT",
+		BuyExchange:   "binance",
+		SellExchange:  "okx",
+		BuyPrice:      decimal.NewFromFloat(0.1),
+		SellPrice:     decimal.NewFromFloat(43100),
+		PriceDiffRate: 0.2325581395,
+		EstRevenue:    decimal.NewFromFloat(12.5),
+		DiscoveredAt:  discoveredAt,
+	})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestMySQLOpportunityRepository_Get 测试行扫描和记录不存在
+func TestMySQLOpportunityRepository_Get(t *testing.T) {
+	store, mock := newMockStore(t)
+	discoveredAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "symbol", "buy_exchange", "sell_exchange", "buy_price", "sell_price",
+		"price_diff", "price_diff_rate", "revenue_rate", "est_revenue", "discovered_at", "last_seen_at",
+		"peak_price_diff_rate", "sightings", "executed"}
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `arbitrage_opportunities` WHERE `id` = ?")).
+		WithArgs("opp-1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("opp-1", "BTC/USDT", "binance", "okx",
+			"43000.12345678", "43100.00000000", "99.87654322", "0.232271", "0.132271", "9.98765432",
+			discoveredAt, discoveredAt.Add(3*time.Second), "0.301000", 3, true))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `arbitrage_opportunities` WHERE `id` = ?")).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	record, err := store.Opportunities.Get(context.Background(), "opp-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	opp := record.Opportunity
+	if !opp.BuyPrice.Equal(decimal.NewFromFloat(43000.12345678)) || opp.PriceDiffRate != 0.232271 || !opp.DiscoveredAt.Equal(discoveredAt) {
+		t.Errorf("Get() = %+v", opp)
+	}
+	if record.Duration() != 3*time.Second || record.PeakPriceDiffRate != 0.301 || record.Sightings != 3 || !record.Executed {
+		t.Errorf("Get() record = %+v", record)
+	}
+
+	if _, err := store.Opportunities.Get(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestMySQLOrderRepository_Save 测试订单状态映射为表中的状态名，且状态不会倒退
+func TestMySQLOrderRepository_Save(t *testing.T) {
+	store, mock := newMockStore(t)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "execution_id", "exchange", "symbol", "side", "type", "price", "amount",
+		"filled_amount", "avg_price", "fee", "status", "exchange_order_id", "created_at", "updated_at"}
+
+	// 新订单：partially_filled 写入为 partial
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `orders` WHERE `id` = ? LIMIT 1 FOR UPDATE")).
+		WithArgs("binance:BTCUSDT:1").
+		WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `orders`")).
+		WithArgs("binance:BTCUSDT:1", "exec-1", "binance", "BTC/USDT", "buy", "limit",
+			"43000.00000000", "0.10000000", "0.05000000", sqlmock.AnyArg(), "0.00000000", "partial",
+			sqlmock.AnyArg(), createdAt, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// 已撤销的订单不能回到 open
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `orders` WHERE `id` = ? LIMIT 1 FOR UPDATE")).
+		WithArgs("binance:BTCUSDT:1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("binance:BTCUSDT:1", "exec-1", "binance", "BTC/USDT", "buy", "limit",
+			"43000.00000000", "0.10000000", "0.05000000", "42999.50000000", "0.00000000", "cancelled", "1", createdAt, createdAt))
+	mock.ExpectRollback()
+
+	order := &execution.Order{
+		ID: "binance:BTCUSDT:1", Exchange: "binance", Symbol: "BTC/USDT",
+		Side: execution.OrderSideBuy, Type: execution.OrderTypeLimit,
+		Price: decimal.NewFromFloat(43000), Amount: decimal.NewFromFloat(0.1), FilledAmount: decimal.NewFromFloat(0.05), AveragePrice: decimal.NewFromFloat(42999.5),
+		Status: execution.OrderStatusPartiallyFilled, ExchangeOrderID: "1", CreatedAt: createdAt,
+	}
+	if err := store.Orders.Save(context.Background(), "exec-1", order); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reopened := order.Clone()
+	reopened.Status = execution.OrderStatusOpen
+	if err := store.Orders.Save(context.Background(), "exec-1", reopened); !errors.Is(err, execution.ErrIllegalTransition) {
+		t.Errorf("Save(open after cancelled) error = %v, want ErrIllegalTransition", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestMySQLExecutionRepository_Get 测试执行结果读取，订单状态从表中的状态名转换回来
+func TestMySQLExecutionRepository_Get(t *testing.T) {
+	store, mock := newMockStore(t)
+	startedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `trade_executions` WHERE `id` = ?")).
+		WithArgs("exec-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "opportunity_id", "symbol", "buy_exchange", "sell_exchange",
+			"buy_price", "sell_price", "amount", "est_profit", "actual_profit", "status", "error_message", "started_at", "completed_at"}).
+			AddRow("exec-1", "opp-1", "BTC/USDT", "binance", "okx", "43000.00000000", "43100.00000000",
+				"0.10000000", "10.00000000", nil, "executing", nil, startedAt, nil))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `orders` WHERE `execution_id` = ?")).
+		WithArgs("exec-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "execution_id", "exchange", "symbol", "side", "type", "price", "amount",
+			"filled_amount", "avg_price", "fee", "status", "exchange_order_id", "created_at", "updated_at"}).
+			AddRow("binance:BTCUSDT:1", "exec-1", "binance", "BTC/USDT", "buy", "limit",
+				"43000.00000000", "0.10000000", "0.00000000", nil, "0.00000000", "submitted", "1", startedAt, startedAt))
+
+	result, err := store.Executions.Get(context.Background(), "exec-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !result.TradingAmount.Equal(decimal.NewFromFloat(0.1)) || !result.ActualProfit.IsZero() || !result.CompletedAt.IsZero() {
+		t.Errorf("Get() = %+v", result)
+	}
+	if result.BuyOrder == nil || result.BuyOrder.Status != execution.OrderStatusOpen {
+		t.Errorf("BuyOrder = %+v, want open", result.BuyOrder)
+	}
+	if result.SellOrder != nil {
+		t.Errorf("SellOrder = %+v, want nil", result.SellOrder)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestMySQLOpportunityRepository_Record 测试持续记录只推进最后出现时间和峰值价差
+func TestMySQLOpportunityRepository_Record(t *testing.T) {
+	store, mock := newMockStore(t)
+	discoveredAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastSeenAt := discoveredAt.Add(4 * time.Second)
+
+	mock.ExpectExec(regexp.QuoteMeta("ON DUPLICATE KEY UPDATE `last_seen_at`=GREATEST(`last_seen_at`,VALUES(`last_seen_at`))")).
+		WithArgs("opp-1", "BTC/USDT", "binance", "okx",
+			"43000.00000000", "43100.00000000", "100.00000000", "0.002326", "0.002326", "2.32558140",
+			discoveredAt, lastSeenAt, "0.003000", 3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := store.Opportunities.Record(context.Background(), &OpportunityRecord{
+		Opportunity: &engine.ArbitrageOpportunity{
+			ID:            "opp-1",
+			Symbol:        "BTC/USDT",
+			BuyExchange:   "binance",
+			SellExchange:  "okx",
+			BuyPrice:      decimal.NewFromFloat(43000),
+			SellPrice:     decimal.NewFromFloat(43100),
+			PriceDiff:     decimal.NewFromFloat(100),
+			PriceDiffRate: 0.0023255814,
+			RevenueRate:   0.0023255814,
+			EstRevenue:    decimal.NewFromFloat(2.3255814),
+			DiscoveredAt:  discoveredAt,
+		},
+		LastSeenAt:        lastSeenAt,
+		PeakPriceDiffRate: 0.003,
+		Sightings:         3,
+	})
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
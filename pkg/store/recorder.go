@@ -0,0 +1,122 @@
+// Package store 提供持久化功能
+package store
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"arbitragex/pkg/engine"
+)
+
+// OpportunityRecorder 套利机会记录器
+// 注册为套利引擎的生命周期事件回调，每个机会从出现到消失写入一条记录：
+// 出现时立即写入（交易服务可以马上标记执行），峰值价差变大时更新，消失时写入最终的最后出现时间和扫描次数
+type OpportunityRecorder struct {
+	repo OpportunityRepository
+
+	// active 进行中的记录（机会ID → 记录）
+	active map[string]*OpportunityRecord
+
+	mu sync.Mutex
+}
+
+// NewOpportunityRecorder 创建套利机会记录器
+// 参数:
+//   - repo: 套利机会仓储
+// 返回:
+//   - *OpportunityRecorder: 记录器（通过 engine.OnEvent(recorder.Record) 注册）
+func NewOpportunityRecorder(repo OpportunityRepository) *OpportunityRecorder {
+	return &OpportunityRecorder{
+		repo:   repo,
+		active: make(map[string]*OpportunityRecord),
+	}
+}
+
+// Record 处理套利机会生命周期事件（engine.OpportunityHook）
+// 写入失败只记录日志，不影响扫描
+func (r *OpportunityRecorder) Record(ctx context.Context, event *engine.OpportunityEvent) {
+	record := r.merge(event)
+	if record == nil {
+		return
+	}
+
+	if err := r.repo.Record(ctx, record); err != nil {
+		logx.WithContext(ctx).Errorf("记录套利机会失败 %s: %v", record.Opportunity.ID, err)
+	}
+}
+
+// merge 将事件合并到进行中的记录，返回需要写入的记录副本（不需要写入时返回 nil）
+func (r *OpportunityRecorder) merge(event *engine.OpportunityEvent) *OpportunityRecord {
+	opp := event.Opportunity
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.active[opp.ID]
+	if !ok {
+		// 第一次收到的事件不一定是 opened（如记录器晚于引擎注册）
+		record = newOpportunityRecord(opp)
+		if event.Type != engine.OpportunityClosed {
+			r.active[opp.ID] = record
+		}
+		return cloneRecord(record)
+	}
+
+	if opp.LastSeenAt.After(record.LastSeenAt) {
+		record.LastSeenAt = opp.LastSeenAt
+	}
+	if opp.Ticks > record.Sightings {
+		record.Sightings = opp.Ticks
+	}
+	peaked := opp.PriceDiffRate > record.PeakPriceDiffRate
+	if peaked {
+		record.PeakPriceDiffRate = opp.PriceDiffRate
+	}
+
+	if event.Type == engine.OpportunityClosed {
+		delete(r.active, opp.ID)
+		return cloneRecord(record)
+	}
+	if peaked {
+		return cloneRecord(record)
+	}
+	return nil
+}
+
+// MarkExecuted 标记套利机会已执行（单进程模式和交易服务受理机会的执行后调用）
+// 参数:
+//   - ctx: 上下文对象
+//   - opportunityID: 机会ID
+// 返回:
+//   - error: 记录不存在时返回 ErrNotFound
+func (r *OpportunityRecorder) MarkExecuted(ctx context.Context, opportunityID string) error {
+	return r.repo.MarkExecuted(ctx, opportunityID)
+}
+
+// Flush 写入所有进行中记录的最新状态（停止前调用，保存最后出现时间和扫描次数）
+func (r *OpportunityRecorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	records := make([]*OpportunityRecord, 0, len(r.active))
+	for _, record := range r.active {
+		records = append(records, cloneRecord(record))
+	}
+	r.mu.Unlock()
+
+	var firstErr error
+	for _, record := range records {
+		if err := r.repo.Record(ctx, record); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// cloneRecord 复制记录
+func cloneRecord(record *OpportunityRecord) *OpportunityRecord {
+	copied := *record
+	opp := *record.Opportunity
+	copied.Opportunity = &opp
+	return &copied
+}
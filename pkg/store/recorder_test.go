@@ -0,0 +1,115 @@
+// Package store 套利机会记录器单元测试
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+)
+
+// opportunityEvent 模拟引擎输出的生命周期事件
+func opportunityEvent(eventType string, rate float64, discoveredAt time.Time, ticks int) *engine.OpportunityEvent {
+	lastSeenAt := discoveredAt.Add(time.Duration(ticks-1) * 2 * time.Second)
+	return &engine.OpportunityEvent{
+		Type: eventType,
+		Opportunity: &engine.ArbitrageOpportunity{
+			ID:            "BTC/USDT_binance_okx_1",
+			Symbol:        "BTC/USDT",
+			BuyExchange:   "binance",
+			SellExchange:  "okx",
+			BuyPrice:      decimal.NewFromFloat(100),
+			SellPrice:     decimal.NewFromFloat(100 * (1 + rate)),
+			PriceDiff:     decimal.NewFromFloat(100 * rate),
+			PriceDiffRate: rate,
+			DiscoveredAt:  discoveredAt,
+			LastSeenAt:    lastSeenAt,
+			Ticks:         ticks,
+		},
+		Time: lastSeenAt,
+	}
+}
+
+// TestOpportunityRecorder_Lifetime 测试一个机会从出现到消失写入一条记录
+func TestOpportunityRecorder_Lifetime(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	recorder := NewOpportunityRecorder(store.Opportunities)
+	t0 := time.Now()
+
+	recorder.Record(ctx, opportunityEvent(engine.OpportunityOpened, 0.006, t0, 1))
+
+	// 出现后立即写入，用于交易服务标记执行
+	if err := recorder.MarkExecuted(ctx, "BTC/USDT_binance_okx_1"); err != nil {
+		t.Fatalf("MarkExecuted() error = %v", err)
+	}
+
+	recorder.Record(ctx, opportunityEvent(engine.OpportunityUpdated, 0.009, t0, 2))
+	recorder.Record(ctx, opportunityEvent(engine.OpportunityUpdated, 0.008, t0, 3))
+
+	// 进行中只在峰值变大时写入
+	record, err := store.Opportunities.Get(ctx, "BTC/USDT_binance_okx_1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if record.Sightings != 2 || record.PeakPriceDiffRate != 0.009 {
+		t.Errorf("record = sightings %d peak %v, want 2 0.009", record.Sightings, record.PeakPriceDiffRate)
+	}
+
+	recorder.Record(ctx, opportunityEvent(engine.OpportunityClosed, 0.008, t0, 3))
+
+	record, err = store.Opportunities.Get(ctx, "BTC/USDT_binance_okx_1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if record.Duration() != 4*time.Second || record.PeakPriceDiffRate != 0.009 || record.Sightings != 3 || !record.Executed {
+		t.Errorf("record = duration %v peak %v sightings %d executed %v, want 4s 0.009 3 true",
+			record.Duration(), record.PeakPriceDiffRate, record.Sightings, record.Executed)
+	}
+	if record.Opportunity.PriceDiffRate != 0.006 {
+		t.Errorf("PriceDiffRate = %v, want first seen 0.006", record.Opportunity.PriceDiffRate)
+	}
+	if len(recorder.active) != 0 {
+		t.Errorf("active = %d records after close, want 0", len(recorder.active))
+	}
+}
+
+// TestOpportunityRecorder_Engine 测试注册到引擎后按扫描写入，Flush 保存进行中的记录
+func TestOpportunityRecorder_Engine(t *testing.T) {
+	ctx := context.Background()
+	priceCache := cache.NewMemoryPriceCache(time.Minute)
+	arbitrageEngine := engine.NewArbitrageEngine(nil, priceCache)
+
+	store := NewMemoryStore()
+	recorder := NewOpportunityRecorder(store.Opportunities)
+	arbitrageEngine.OnEvent(recorder.Record)
+
+	now := time.Now()
+	priceCache.SetPrice(ctx, "binance", "BTC/USDT", &cache.PriceData{Exchange: "binance", Symbol: "BTC/USDT", BidPrice: decimal.NewFromFloat(43000), AskPrice: decimal.NewFromFloat(43010), Timestamp: now})
+	priceCache.SetPrice(ctx, "okx", "BTC/USDT", &cache.PriceData{Exchange: "okx", Symbol: "BTC/USDT", BidPrice: decimal.NewFromFloat(43800), AskPrice: decimal.NewFromFloat(43810), Timestamp: now})
+
+	var first []*engine.ArbitrageOpportunity
+	for i := 0; i < 3; i++ {
+		opportunities, err := arbitrageEngine.ScanOpportunities(ctx, []string{"BTC/USDT"}, []string{"binance", "okx"})
+		if err != nil || len(opportunities) != 1 {
+			t.Fatalf("ScanOpportunities() = %v, %v, want 1 opportunity", opportunities, err)
+		}
+		if first == nil {
+			first = opportunities
+		}
+	}
+
+	if err := recorder.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	record, err := store.Opportunities.Get(ctx, first[0].ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if record.Sightings != 3 {
+		t.Errorf("Sightings = %d, want 3", record.Sightings)
+	}
+}
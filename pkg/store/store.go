@@ -0,0 +1,210 @@
+// Package store 提供持久化功能
+// 职责：将套利机会、交易执行、订单、账户余额和系统配置写入 MySQL（scripts/mysql/01-init-database.sql）
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/execution"
+)
+
+// ErrNotFound 记录不存在
+var ErrNotFound = fmt.Errorf("record not found")
+
+// OpportunityRepository 套利机会仓储（arbitrage_opportunities 表）
+type OpportunityRepository interface {
+	// Save 保存套利机会（ID 已存在时更新价格和收益）
+	Save(ctx context.Context, opp *engine.ArbitrageOpportunity) error
+
+	// Record 保存套利机会的持续记录
+	// ID 已存在时更新最后出现时间和出现次数，峰值价差只增不减，价格和收益保持首次发现时的值
+	Record(ctx context.Context, record *OpportunityRecord) error
+
+	// Get 获取套利机会记录
+	Get(ctx context.Context, id string) (*OpportunityRecord, error)
+
+	// MarkExecuted 标记套利机会已执行
+	MarkExecuted(ctx context.Context, id string) error
+
+	// ListSince 按首次发现时间倒序查询套利机会记录
+	// 参数:
+	//   - ctx: 上下文对象
+	//   - symbol: 交易对（为空时查询所有交易对）
+	//   - since: 起始发现时间
+	//   - limit: 最多返回条数
+	// 返回:
+	//   - []*OpportunityRecord: 套利机会记录列表
+	//   - error: 错误信息
+	ListSince(ctx context.Context, symbol string, since time.Time, limit int) ([]*OpportunityRecord, error)
+}
+
+// OpportunityRecord 套利机会的持续记录（同一机会ID从出现到消失为一条记录）
+type OpportunityRecord struct {
+	// Opportunity 首次发现时的套利机会（DiscoveredAt 即首次发现时间）
+	Opportunity *engine.ArbitrageOpportunity `json:"opportunity"`
+
+	// LastSeenAt 最后一次被扫描到的时间
+	LastSeenAt time.Time `json:"last_seen_at"`
+
+	// PeakPriceDiffRate 持续期间的最大价差百分比
+	PeakPriceDiffRate float64 `json:"peak_price_diff_rate"`
+
+	// Sightings 被扫描到的次数
+	Sightings int `json:"sightings"`
+
+	// Executed 是否已执行
+	Executed bool `json:"executed"`
+}
+
+// Duration 机会持续时间（首次发现到最后一次出现）
+func (r *OpportunityRecord) Duration() time.Duration {
+	return r.LastSeenAt.Sub(r.Opportunity.DiscoveredAt)
+}
+
+// newOpportunityRecord 由扫描到的套利机会创建记录
+func newOpportunityRecord(opp *engine.ArbitrageOpportunity) *OpportunityRecord {
+	copied := *opp
+	if copied.DiscoveredAt.IsZero() {
+		copied.DiscoveredAt = time.Now()
+	}
+
+	record := &OpportunityRecord{
+		Opportunity:       &copied,
+		LastSeenAt:        copied.LastSeenAt,
+		PeakPriceDiffRate: copied.PriceDiffRate,
+		Sightings:         copied.Ticks,
+	}
+	if record.LastSeenAt.Before(copied.DiscoveredAt) {
+		record.LastSeenAt = copied.DiscoveredAt
+	}
+	if record.Sightings < 1 {
+		record.Sightings = 1
+	}
+	return record
+}
+
+// ExecutionRepository 交易执行仓储（trade_executions 表，连同买卖订单写入 orders 表）
+type ExecutionRepository interface {
+	// Save 保存执行结果及其买卖订单（同一事务）
+	Save(ctx context.Context, result *execution.ExecutionResult) error
+
+	// Get 获取执行结果（包含买卖订单）
+	Get(ctx context.Context, id string) (*execution.ExecutionResult, error)
+
+	// ListByStatus 按开始时间倒序查询指定状态的执行结果（不包含订单，status 为空时查询所有状态）
+	ListByStatus(ctx context.Context, status string, limit int) ([]*execution.ExecutionResult, error)
+}
+
+// OrderRepository 订单仓储（orders 表）
+// 写入前经过订单状态机，已保存的订单状态不会倒退
+type OrderRepository interface {
+	// Save 保存订单（所属执行记录必须已存在）
+	// 返回:
+	//   - error: 状态倒退时返回 execution.ErrIllegalTransition / execution.ErrFilledAmountDecreased
+	Save(ctx context.Context, executionID string, order *execution.Order) error
+
+	// Get 获取订单
+	Get(ctx context.Context, id string) (*execution.Order, error)
+
+	// ListByExecution 查询执行的所有订单（按创建时间排序）
+	ListByExecution(ctx context.Context, executionID string) ([]*execution.Order, error)
+}
+
+// BalanceRepository 账户余额仓储（account_balances 表）
+type BalanceRepository interface {
+	// Save 保存余额快照（按交易所 + 币种更新）
+	Save(ctx context.Context, balances []*execution.Balance) error
+
+	// List 查询交易所的所有币种余额（exchange 为空时查询所有交易所）
+	List(ctx context.Context, exchange string) ([]*execution.Balance, error)
+}
+
+// ConfigRepository 系统配置仓储（system_config 表）
+type ConfigRepository interface {
+	// Get 获取配置值
+	Get(ctx context.Context, key string) (string, error)
+
+	// Set 设置配置值（description 为空时保留原描述）
+	Set(ctx context.Context, key, value, description string) error
+
+	// List 获取所有配置
+	List(ctx context.Context) (map[string]string, error)
+}
+
+// Store 仓储集合
+type Store struct {
+	Opportunities OpportunityRepository
+	Executions    ExecutionRepository
+	Orders        OrderRepository
+	Balances      BalanceRepository
+	Configs       ConfigRepository
+}
+
+// 字段精度（与表结构 DECIMAL 定义一致）
+const (
+	amountScale = 8 // DECIMAL(20, 8)：价格、数量、金额
+	rateScale   = 6 // DECIMAL(10, 6)：价差百分比、收益率
+)
+
+// formatDecimal 按 DECIMAL 精度格式化价格、数量、金额
+func formatDecimal(v decimal.Decimal, scale int32) string {
+	return v.StringFixed(scale)
+}
+
+// parseDecimal 解析数据库返回的 DECIMAL 字符串（价格、数量、金额）
+func parseDecimal(s string) (decimal.Decimal, error) {
+	if s == "" {
+		return decimal.Zero, nil
+	}
+	v, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("解析 DECIMAL 失败: %s: %w", s, err)
+	}
+	return v, nil
+}
+
+// formatRate 按 DECIMAL 精度格式化比率
+// 以字符串传给数据库，避免 float64 的二进制误差（如 0.1 写成 0.1000000000000000055）
+func formatRate(v float64, scale int32) string {
+	return strconv.FormatFloat(v, 'f', int(scale), 64)
+}
+
+// parseRate 解析数据库返回的 DECIMAL 字符串（比率）
+func parseRate(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("解析 DECIMAL 失败: %s: %w", s, err)
+	}
+	return v, nil
+}
+
+// advanceOrder 通过订单状态机将更新应用到已保存的订单
+func advanceOrder(current, update *execution.Order) (*execution.Order, error) {
+	if current == nil {
+		return update.Clone(), nil
+	}
+
+	merged, _, err := orderStateMachine.Apply(current, update)
+	if err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// isStaleOrder 订单更新是否比已保存的状态旧
+// 保存执行结果时附带的订单可能晚于私有数据流写入的状态，此时保留已保存的状态
+func isStaleOrder(err error) bool {
+	return errors.Is(err, execution.ErrIllegalTransition) || errors.Is(err, execution.ErrFilledAmountDecreased)
+}
+
+// orderStateMachine 持久化使用的订单状态机（无回调）
+var orderStateMachine = execution.NewOrderStateMachine()
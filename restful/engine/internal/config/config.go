@@ -3,8 +3,71 @@
 
 package config
 
-import "github.com/zeromicro/go-zero/rest"
+import (
+	"fmt"
+	"time"
+
+	"arbitragex/pkg/bus"
+	"arbitragex/pkg/settings"
+
+	"github.com/zeromicro/go-zero/rest"
+)
 
 type Config struct {
 	rest.RestConf
+
+	// Auth 修改配置和控制扫描接口的鉴权
+	Auth AuthConf
+
+	// Exchanges 行情交易所（只连接和扫描 Enabled 的 CEX，手续费用于计算净收益）
+	Exchanges settings.Exchanges
+
+	// Symbols 扫描的交易对（标准格式，如 BTC/USDT）
+	Symbols []string `json:",default=[BTC/USDT,ETH/USDT]"`
+
+	// PriceTTL 价格缓存有效期（超过后不参与扫描）
+	PriceTTL time.Duration `json:",default=5s"`
+
+	// Scan 扫描循环配置
+	Scan ScanConf
+
+	// Engine 套利引擎阈值（运行时可通过 /api/engine/config 修改）
+	Engine settings.EngineConf
+
+	// Reload 运行中热更新引擎阈值和手续费率（参数文件或 system_config 表；参数变化时覆盖通过接口修改的配置）
+	Reload settings.ReloadConf
+
+	// MySQL system_config 表所在的数据库（Reload.SystemConfig 开启时需要）
+	MySQL settings.MySQLConf `json:",optional"`
+
+	// Bus 消息总线（配置后不直接连接交易所，从 Redis Streams 消费价格服务发布的价格，并发布套利机会事件）
+	Bus bus.Conf `json:",optional"`
+}
+
+// ScanConf 扫描循环配置
+type ScanConf struct {
+	Interval  time.Duration `json:",default=1s"`   // 扫描间隔
+	AutoStart bool          `json:",default=true"` // 服务启动后立即开始扫描（否则等待 /api/engine/start）
+}
+
+// AuthConf 鉴权配置
+type AuthConf struct {
+	Token string `json:",optional"` // 访问令牌（请求头 Authorization: Bearer <Token>；为空时拒绝所有修改配置和控制扫描的请求）
+}
+
+// Validate 校验配置（settings.Load 加载后调用）
+func (c Config) Validate() error {
+	if err := c.Exchanges.Validate(); err != nil {
+		return err
+	}
+	if len(c.Symbols) == 0 {
+		return fmt.Errorf("Symbols: 至少配置一个交易对")
+	}
+	if c.Scan.Interval <= 0 {
+		return fmt.Errorf("Scan.Interval 必须大于 0: %s", c.Scan.Interval)
+	}
+	if _, err := c.Engine.EngineConfig(c.Exchanges.TradingFees()); err != nil {
+		return err
+	}
+	return c.Reload.Validate(c.MySQL)
 }
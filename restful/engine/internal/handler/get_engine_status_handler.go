@@ -0,0 +1,26 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"net/http"
+
+	"arbitragex/restful/engine/internal/logic"
+	"arbitragex/restful/engine/internal/svc"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 查询扫描状态
+func getEngineStatusHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewGetEngineStatusLogic(r.Context(), svcCtx)
+		resp, err := l.GetEngineStatus()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"net/http"
+
+	"arbitragex/restful/engine/internal/logic"
+	"arbitragex/restful/engine/internal/svc"
+	"arbitragex/restful/engine/internal/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 查询套利机会详情
+func getOpportunityHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.OpportunityRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewGetOpportunityLogic(r.Context(), svcCtx)
+		resp, err := l.GetOpportunity(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
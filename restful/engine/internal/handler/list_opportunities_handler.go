@@ -0,0 +1,33 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"net/http"
+
+	"arbitragex/restful/engine/internal/logic"
+	"arbitragex/restful/engine/internal/svc"
+	"arbitragex/restful/engine/internal/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 查询当前套利机会（支持过滤和排序）
+func listOpportunitiesHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.OpportunityListRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewListOpportunitiesLogic(r.Context(), svcCtx)
+		resp, err := l.ListOpportunities(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
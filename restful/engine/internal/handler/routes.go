@@ -20,7 +20,58 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/health",
 				Handler: healthCheckHandler(serverCtx),
 			},
+			{
+				// 查询当前套利机会（支持过滤和排序）
+				Method:  http.MethodGet,
+				Path:    "/opportunities",
+				Handler: listOpportunitiesHandler(serverCtx),
+			},
+			{
+				// 查询套利机会详情
+				Method:  http.MethodGet,
+				Path:    "/opportunities/:id",
+				Handler: getOpportunityHandler(serverCtx),
+			},
+			{
+				// 查询引擎配置
+				Method:  http.MethodGet,
+				Path:    "/engine/config",
+				Handler: getEngineConfigHandler(serverCtx),
+			},
+			{
+				// 查询扫描状态
+				Method:  http.MethodGet,
+				Path:    "/engine/status",
+				Handler: getEngineStatusHandler(serverCtx),
+			},
 		},
 		rest.WithPrefix("/api"),
 	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 更新引擎配置（整体替换，校验失败时保持原配置）
+					Method:  http.MethodPut,
+					Path:    "/engine/config",
+					Handler: updateEngineConfigHandler(serverCtx),
+				},
+				{
+					// 开始扫描套利机会
+					Method:  http.MethodPost,
+					Path:    "/engine/start",
+					Handler: startEngineHandler(serverCtx),
+				},
+				{
+					// 暂停扫描套利机会
+					Method:  http.MethodPost,
+					Path:    "/engine/pause",
+					Handler: pauseEngineHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api"),
+	)
 }
@@ -0,0 +1,32 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"arbitragex/restful/engine/internal/svc"
+	"arbitragex/restful/engine/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetEngineConfigLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询引擎配置
+func NewGetEngineConfigLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetEngineConfigLogic {
+	return &GetEngineConfigLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetEngineConfigLogic) GetEngineConfig() (resp *types.EngineConfig, err error) {
+	return toEngineConfig(l.svcCtx.Engine.Config()), nil
+}
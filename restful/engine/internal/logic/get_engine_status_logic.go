@@ -0,0 +1,32 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"arbitragex/restful/engine/internal/svc"
+	"arbitragex/restful/engine/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetEngineStatusLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询扫描状态
+func NewGetEngineStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetEngineStatusLogic {
+	return &GetEngineStatusLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetEngineStatusLogic) GetEngineStatus() (resp *types.EngineStatus, err error) {
+	return toEngineStatus(l.svcCtx), nil
+}
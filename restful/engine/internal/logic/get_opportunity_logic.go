@@ -0,0 +1,39 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"arbitragex/restful/engine/internal/svc"
+	"arbitragex/restful/engine/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetOpportunityLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询套利机会详情
+func NewGetOpportunityLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetOpportunityLogic {
+	return &GetOpportunityLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetOpportunityLogic) GetOpportunity(req *types.OpportunityRequest) (resp *types.Opportunity, err error) {
+	opp, err := l.svcCtx.Engine.GetOpportunity(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = new(types.Opportunity)
+	*resp = toOpportunity(opp)
+	return resp, nil
+}
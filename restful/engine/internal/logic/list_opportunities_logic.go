@@ -0,0 +1,66 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"arbitragex/pkg/engine"
+	"arbitragex/restful/engine/internal/svc"
+	"arbitragex/restful/engine/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListOpportunitiesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询当前套利机会（支持过滤和排序）
+func NewListOpportunitiesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListOpportunitiesLogic {
+	return &ListOpportunitiesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListOpportunities 返回当前有效的套利机会，按交易对、交易所、收益率和风险评分过滤后排序
+func (l *ListOpportunitiesLogic) ListOpportunities(req *types.OpportunityListRequest) (resp *types.OpportunityListResponse, err error) {
+	symbol := normalizeSymbol(l.svcCtx, req.Symbol)
+	exchange := strings.ToLower(req.Exchange)
+
+	var matched []*engine.ArbitrageOpportunity
+	for _, opp := range l.svcCtx.Engine.GetAllOpportunities() {
+		if symbol != "" && opp.Symbol != symbol {
+			continue
+		}
+		if exchange != "" && opp.BuyExchange != exchange && opp.SellExchange != exchange {
+			continue
+		}
+		if opp.ProfitRate < req.MinProfitRate {
+			continue
+		}
+		if req.MaxRiskScore > 0 && opp.RiskScore > req.MaxRiskScore {
+			continue
+		}
+		matched = append(matched, opp)
+	}
+	sortOpportunities(matched, req.SortBy, req.Order != "asc")
+
+	resp = &types.OpportunityListResponse{
+		Opportunities: make([]types.Opportunity, 0, len(matched)),
+		Total:         len(matched),
+	}
+	if req.Limit > 0 && len(matched) > req.Limit {
+		matched = matched[:req.Limit]
+	}
+	for _, opp := range matched {
+		resp.Opportunities = append(resp.Opportunities, toOpportunity(opp))
+	}
+	return resp, nil
+}
@@ -0,0 +1,181 @@
+package logic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/engine"
+	"arbitragex/restful/engine/internal/svc"
+	"arbitragex/restful/engine/internal/types"
+)
+
+// toOpportunity 转换为接口返回的套利机会
+func toOpportunity(opp *engine.ArbitrageOpportunity) types.Opportunity {
+	return types.Opportunity{
+		Id:            opp.ID,
+		Symbol:        opp.Symbol,
+		BuyExchange:   opp.BuyExchange,
+		SellExchange:  opp.SellExchange,
+		BuyPrice:      opp.BuyPrice.String(),
+		SellPrice:     opp.SellPrice.String(),
+		PriceDiff:     opp.PriceDiff.String(),
+		PriceDiffRate: opp.PriceDiffRate,
+		EstRevenue:    opp.EstRevenue.String(),
+		EstCost:       opp.EstCost.String(),
+		NetProfit:     opp.NetProfit.String(),
+		ProfitRate:    opp.ProfitRate,
+		RiskScore:     opp.RiskScore,
+		Score:         opp.Score,
+		Ticks:         opp.Ticks,
+		DiscoveredAt:  opp.DiscoveredAt.UnixMilli(),
+		LastSeenAt:    opp.LastSeenAt.UnixMilli(),
+		ValidUntil:    opp.ValidUntil.UnixMilli(),
+	}
+}
+
+// sortOpportunities 按字段排序（相同时按 ID 排序，保证结果稳定）
+func sortOpportunities(opportunities []*engine.ArbitrageOpportunity, sortBy string, desc bool) {
+	compare := func(a, b *engine.ArbitrageOpportunity) int {
+		switch sortBy {
+		case "profit_rate":
+			return compareFloat(a.ProfitRate, b.ProfitRate)
+		case "net_profit":
+			return a.NetProfit.Cmp(b.NetProfit)
+		case "risk_score":
+			return compareFloat(a.RiskScore, b.RiskScore)
+		case "discovered_at":
+			return a.DiscoveredAt.Compare(b.DiscoveredAt)
+		default:
+			return compareFloat(a.Score, b.Score)
+		}
+	}
+
+	sort.Slice(opportunities, func(i, j int) bool {
+		c := compare(opportunities[i], opportunities[j])
+		if c == 0 {
+			return opportunities[i].ID < opportunities[j].ID
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// compareFloat 比较两个浮点数（-1、0、1）
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// normalizeSymbol 将请求中的交易对转换为标准格式
+// BTC/USDT、BTC-USDT、btc_usdt 直接替换分隔符；没有分隔符的 BTCUSDT 按扫描的交易对匹配
+func normalizeSymbol(svcCtx *svc.ServiceContext, symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return ""
+	}
+
+	normalized := strings.NewReplacer("-", "/", "_", "/").Replace(symbol)
+	if strings.Contains(normalized, "/") {
+		return normalized
+	}
+	for _, scanned := range svcCtx.Config.Symbols {
+		if strings.ReplaceAll(scanned, "/", "") == symbol {
+			return scanned
+		}
+	}
+	return symbol
+}
+
+// toEngineConfig 转换为接口返回的引擎配置
+func toEngineConfig(c *engine.EngineConfig) *types.EngineConfig {
+	fees := make([]types.TradingFee, 0, len(c.TradingFees))
+	for _, fee := range c.TradingFees {
+		fees = append(fees, types.TradingFee{
+			Exchange: fee.Exchange,
+			MakerFee: fee.MakerFee,
+			TakerFee: fee.TakerFee,
+		})
+	}
+
+	return &types.EngineConfig{
+		MinProfitRate:    c.MinProfitRate,
+		MinProfitAmount:  c.MinProfitAmount.String(),
+		MaxRiskScore:     c.MaxRiskScore,
+		OpportunityTtl:   c.OpportunityTTL.String(),
+		TradingFees:      fees,
+		SlippageRate:     c.SlippageRate,
+		GasFee:           c.GasFee.String(),
+		MinVolume:        c.MinVolume.String(),
+		MinConfirmations: c.MinConfirmations,
+	}
+}
+
+// fromEngineConfig 解析请求中的引擎配置（数值范围由 EngineConfig.Validate 校验）
+func fromEngineConfig(c *types.EngineConfig) (*engine.EngineConfig, error) {
+	minProfitAmount, err := decimal.NewFromString(c.MinProfitAmount)
+	if err != nil {
+		return nil, fmt.Errorf("min_profit_amount 无效: %w", err)
+	}
+	ttl, err := time.ParseDuration(c.OpportunityTtl)
+	if err != nil {
+		return nil, fmt.Errorf("opportunity_ttl 无效: %w", err)
+	}
+	gasFee, err := decimal.NewFromString(c.GasFee)
+	if err != nil {
+		return nil, fmt.Errorf("gas_fee 无效: %w", err)
+	}
+	minVolume, err := decimal.NewFromString(c.MinVolume)
+	if err != nil {
+		return nil, fmt.Errorf("min_volume 无效: %w", err)
+	}
+
+	fees := make([]engine.TradingFee, 0, len(c.TradingFees))
+	for _, fee := range c.TradingFees {
+		fees = append(fees, engine.TradingFee{
+			Exchange: strings.ToLower(fee.Exchange),
+			MakerFee: fee.MakerFee,
+			TakerFee: fee.TakerFee,
+		})
+	}
+
+	return &engine.EngineConfig{
+		MinProfitRate:    c.MinProfitRate,
+		MinProfitAmount:  minProfitAmount,
+		MaxRiskScore:     c.MaxRiskScore,
+		OpportunityTTL:   ttl,
+		TradingFees:      fees,
+		SlippageRate:     c.SlippageRate,
+		GasFee:           gasFee,
+		MinVolume:        minVolume,
+		MinConfirmations: c.MinConfirmations,
+	}, nil
+}
+
+// toEngineStatus 转换为接口返回的扫描状态
+func toEngineStatus(svcCtx *svc.ServiceContext) *types.EngineStatus {
+	status := svcCtx.Scanner.Status()
+	resp := &types.EngineStatus{
+		Running:       status.Running,
+		Symbols:       svcCtx.Config.Symbols,
+		Exchanges:     svcCtx.Config.Exchanges.Enabled().Names(),
+		Interval:      status.Interval.String(),
+		Scans:         status.Scans,
+		Opportunities: status.Opportunities,
+		Error:         status.Error,
+	}
+	if !status.LastScanAt.IsZero() {
+		resp.LastScanAt = status.LastScanAt.UnixMilli()
+	}
+	return resp
+}
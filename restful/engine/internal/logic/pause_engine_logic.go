@@ -0,0 +1,35 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"arbitragex/restful/engine/internal/svc"
+	"arbitragex/restful/engine/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PauseEngineLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 暂停扫描套利机会
+func NewPauseEngineLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PauseEngineLogic {
+	return &PauseEngineLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *PauseEngineLogic) PauseEngine() (resp *types.EngineStatus, err error) {
+	if err := l.svcCtx.Scanner.Pause(); err != nil {
+		return nil, err
+	}
+	return toEngineStatus(l.svcCtx), nil
+}
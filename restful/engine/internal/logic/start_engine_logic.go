@@ -0,0 +1,35 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"arbitragex/restful/engine/internal/svc"
+	"arbitragex/restful/engine/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type StartEngineLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 开始扫描套利机会
+func NewStartEngineLogic(ctx context.Context, svcCtx *svc.ServiceContext) *StartEngineLogic {
+	return &StartEngineLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *StartEngineLogic) StartEngine() (resp *types.EngineStatus, err error) {
+	if err := l.svcCtx.Scanner.Start(); err != nil {
+		return nil, err
+	}
+	return toEngineStatus(l.svcCtx), nil
+}
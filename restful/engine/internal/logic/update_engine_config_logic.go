@@ -0,0 +1,43 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"arbitragex/restful/engine/internal/svc"
+	"arbitragex/restful/engine/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateEngineConfigLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 更新引擎配置（整体替换，校验失败时保持原配置）
+func NewUpdateEngineConfigLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateEngineConfigLogic {
+	return &UpdateEngineConfigLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UpdateEngineConfig 校验并替换引擎配置，从下一次扫描开始生效
+func (l *UpdateEngineConfigLogic) UpdateEngineConfig(req *types.EngineConfig) (resp *types.EngineConfig, err error) {
+	config, err := fromEngineConfig(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.svcCtx.Engine.UpdateConfig(config); err != nil {
+		return nil, err
+	}
+
+	l.Infof("引擎配置已更新: 最小收益率 %v, 最小收益 %s, 最大风险评分 %v, 滑点率 %v",
+		config.MinProfitRate, config.MinProfitAmount, config.MaxRiskScore, config.SlippageRate)
+	return toEngineConfig(l.svcCtx.Engine.Config()), nil
+}
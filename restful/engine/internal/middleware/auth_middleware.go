@@ -0,0 +1,34 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+type AuthMiddleware struct {
+	token string
+}
+
+// NewAuthMiddleware 创建修改配置和控制扫描接口的鉴权中间件
+func NewAuthMiddleware(token string) *AuthMiddleware {
+	return &AuthMiddleware{
+		token: token,
+	}
+}
+
+// Handle 校验请求头 Authorization: Bearer <token>（未配置令牌时拒绝所有请求）
+func (m *AuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || m.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(m.token)) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -4,15 +4,134 @@
 package svc
 
 import (
+	"context"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/pkg/bus"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/feed"
+	"arbitragex/pkg/settings"
+	"arbitragex/pkg/store"
 	"arbitragex/restful/engine/internal/config"
+	"arbitragex/restful/engine/internal/middleware"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest"
 )
 
 type ServiceContext struct {
-	Config config.Config
+	Config     config.Config
+	Auth       rest.Middleware
+	PriceCache cache.PriceCache
+	Feed       *feed.Feed
+	Engine     *engine.ArbitrageEngine
+	Scanner    *engine.Scanner
+	Bus        bus.Bus // 消息总线（未配置时为 nil，直接连接交易所）
+
+	reloader *settings.Reloader // 参数热更新（未配置时为 nil）
+	logger   logx.Logger
 }
 
+// NewServiceContext 创建服务上下文
+// 未配置消息总线时按配置为每个交易所创建适配器，行情写入内存价格缓存；
+// 配置了消息总线时不连接交易所，从价格主题消费价格服务发布的行情，并将套利机会事件发布到机会主题。
+// 扫描循环从缓存中识别套利机会（调用 Start 后开始运行）
 func NewServiceContext(c config.Config) *ServiceContext {
-	return &ServiceContext{
-		Config: c,
+	priceCache := cache.NewMemoryPriceCache(c.PriceTTL)
+	priceFeed := feed.New(priceCache, c.Symbols)
+	var messageBus bus.Bus
+	if c.Bus.Enabled() {
+		messageBus = bus.MustNewRedisBus(c.Bus)
+	} else {
+		for _, ex := range c.Exchanges.Enabled() {
+			adapter, err := ex.NewAdapter(c.Symbols)
+			logx.Must(err)
+			priceFeed.AddAdapter(ex.Key(), adapter)
+		}
+	}
+	engineConfig, err := c.Engine.EngineConfig(c.Exchanges.TradingFees())
+	logx.Must(err)
+	arbitrageEngine := engine.NewArbitrageEngine(engineConfig, priceCache)
+
+	s := &ServiceContext{
+		Config:     c,
+		Auth:       middleware.NewAuthMiddleware(c.Auth.Token).Handle,
+		PriceCache: priceCache,
+		Feed:       priceFeed,
+		Engine:     arbitrageEngine,
+		Scanner:    engine.NewScanner(arbitrageEngine, c.Symbols, c.Exchanges.Enabled().Names(), c.Scan.Interval),
+		Bus:        messageBus,
+		logger:     logx.WithContext(context.Background()),
+	}
+	if c.Reload.Enabled() {
+		var configs store.ConfigRepository
+		if c.Reload.SystemConfig {
+			configs = c.MySQL.NewStore().Configs
+		}
+		s.reloader = settings.NewReloader(c.Reload.NewSource(configs), settings.Params{Engine: engineConfig}, arbitrageEngine, nil)
+	}
+
+	if c.Auth.Token == "" {
+		s.logger.Error("未配置 Auth.Token，修改配置和控制扫描的接口将拒绝所有请求")
+	}
+	return s
+}
+
+// Start 开始参数热更新和接收行情（连接交易所或订阅价格主题），配置了 AutoStart 时开始扫描
+func (s *ServiceContext) Start(ctx context.Context) error {
+	if s.reloader != nil {
+		// 首次读取失败或参数无效时已记录日志，使用启动配置继续运行
+		s.reloader.Start(s.Config.Reload.Interval)
+	}
+	if s.Bus != nil {
+		// 每个引擎实例使用独立的消费组，各自收到全部价格
+		consumer := s.Config.Bus.ConsumerName()
+		if err := s.Bus.Subscribe(ctx, bus.TopicPrices, "engine:"+consumer, consumer, s.consumePrice); err != nil {
+			return err
+		}
+		s.Engine.OnEvent(s.publishEvent)
+	} else if err := s.Feed.Start(ctx); err != nil {
+		return err
+	}
+	if s.Config.Scan.AutoStart {
+		return s.Scanner.Start()
+	}
+	return nil
+}
+
+// Stop 停止扫描和参数热更新，断开交易所连接或关闭消息总线
+func (s *ServiceContext) Stop() {
+	if s.Scanner.Running() {
+		s.Scanner.Pause()
+	}
+	if s.reloader != nil {
+		s.reloader.Stop()
+	}
+	if s.Bus != nil {
+		s.Bus.Close()
+		return
+	}
+	s.Feed.Stop()
+}
+
+// consumePrice 将价格主题中的价格写入价格缓存（跳过已超过有效期的价格，如重新投递的旧消息）
+func (s *ServiceContext) consumePrice(ctx context.Context, msg *bus.Message) error {
+	var price cache.PriceData
+	if err := msg.Decode(&price); err != nil {
+		// 格式错误的消息重试也无法处理，直接确认
+		s.logger.Errorf("解析价格消息 %s 失败: %v", msg.ID, err)
+		return nil
+	}
+	if time.Since(price.Timestamp) > s.Config.PriceTTL {
+		return nil
+	}
+	return s.PriceCache.SetPrice(ctx, price.Exchange, price.Symbol, &price)
+}
+
+// publishEvent 将套利机会生命周期事件发布到机会主题（OpportunityHook）
+func (s *ServiceContext) publishEvent(ctx context.Context, event *engine.OpportunityEvent) {
+	if _, err := bus.PublishJSON(ctx, s.Bus, bus.TopicOpportunities, event); err != nil {
+		s.logger.Errorf("发布套利机会事件 %s %s 失败: %v", event.Type, event.Opportunity.ID, err)
 	}
 }
@@ -3,8 +3,77 @@
 
 package types
 
+type EngineConfig struct {
+	MinProfitRate    float64      `json:"min_profit_rate"`
+	MinProfitAmount  string       `json:"min_profit_amount"`
+	MaxRiskScore     float64      `json:"max_risk_score"`
+	OpportunityTtl   string       `json:"opportunity_ttl"`
+	TradingFees      []TradingFee `json:"trading_fees"`
+	SlippageRate     float64      `json:"slippage_rate"`
+	GasFee           string       `json:"gas_fee"`
+	MinVolume        string       `json:"min_volume"`
+	MinConfirmations int          `json:"min_confirmations"`
+}
+
+type EngineStatus struct {
+	Running       bool     `json:"running"`
+	Symbols       []string `json:"symbols"`
+	Exchanges     []string `json:"exchanges"`
+	Interval      string   `json:"interval"`
+	Scans         int64    `json:"scans"`
+	LastScanAt    int64    `json:"last_scan_at"`  // 毫秒时间戳（0 表示尚未扫描）
+	Opportunities int      `json:"opportunities"` // 最近一次扫描发现的机会数
+	Error         string   `json:"error,omitempty"`
+}
+
 type HealthCheckResponse struct {
 	Status  string `json:"status"`
 	Uptime  int64  `json:"uptime"`
 	Version string `json:"version"`
 }
+
+type Opportunity struct {
+	Id            string  `json:"id"`
+	Symbol        string  `json:"symbol"`
+	BuyExchange   string  `json:"buy_exchange"`
+	SellExchange  string  `json:"sell_exchange"`
+	BuyPrice      string  `json:"buy_price"`
+	SellPrice     string  `json:"sell_price"`
+	PriceDiff     string  `json:"price_diff"`
+	PriceDiffRate float64 `json:"price_diff_rate"`
+	EstRevenue    string  `json:"est_revenue"`
+	EstCost       string  `json:"est_cost"`
+	NetProfit     string  `json:"net_profit"`
+	ProfitRate    float64 `json:"profit_rate"`
+	RiskScore     float64 `json:"risk_score"`
+	Score         float64 `json:"score"`
+	Ticks         int     `json:"ticks"`         // 连续被扫描到的次数
+	DiscoveredAt  int64   `json:"discovered_at"` // 毫秒时间戳
+	LastSeenAt    int64   `json:"last_seen_at"`  // 毫秒时间戳
+	ValidUntil    int64   `json:"valid_until"`   // 毫秒时间戳
+}
+
+type OpportunityListRequest struct {
+	Symbol        string  `form:"symbol,optional"`                                                                     // 交易对（如 BTC/USDT 或 BTC-USDT）
+	Exchange      string  `form:"exchange,optional"`                                                                   // 买入或卖出交易所
+	MinProfitRate float64 `form:"min_profit_rate,optional"`                                                            // 最小净收益率
+	MaxRiskScore  float64 `form:"max_risk_score,optional"`                                                             // 最大风险评分（0 表示不过滤）
+	SortBy        string  `form:"sort_by,default=score,options=score|profit_rate|net_profit|risk_score|discovered_at"` // 排序字段
+	Order         string  `form:"order,default=desc,options=asc|desc"`                                                 // 排序方向
+	Limit         int     `form:"limit,optional,range=[0:1000]"`                                                       // 返回数量（0 表示全部）
+}
+
+type OpportunityListResponse struct {
+	Opportunities []Opportunity `json:"opportunities"`
+	Total         int           `json:"total"` // 过滤后的机会总数（不受 limit 限制）
+}
+
+type OpportunityRequest struct {
+	Id string `path:"id"`
+}
+
+type TradingFee struct {
+	Exchange string  `json:"exchange"`
+	MakerFee float64 `json:"maker_fee"`
+	TakerFee float64 `json:"taker_fee"`
+}
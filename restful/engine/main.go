@@ -1,14 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 
+	"arbitragex/pkg/settings"
 	"arbitragex/restful/engine/internal/config"
 	"arbitragex/restful/engine/internal/handler"
 	"arbitragex/restful/engine/internal/svc"
 
-	"github.com/zeromicro/go-zero/core/conf"
+	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/rest"
 )
 
@@ -17,19 +19,26 @@ var configFile = flag.String("f", "etc/engine-api.yaml", "the config file")
 // main 套利引擎服务的主入口函数
 // 职责：
 //  1. 加载配置文件
-//  2. 初始化 REST 服务器
-//  3. 注册路由（使用 goctl 生成的 RegisterHandlers）
-//  4. 启动套利引擎服务
+//  2. 连接交易所，开始扫描套利机会
+//  3. 初始化 REST 服务器
+//  4. 注册路由（使用 goctl 生成的 RegisterHandlers）
+//  5. 启动套利引擎服务
 func main() {
 	flag.Parse()
 
-	// 加载配置文件
+	// 加载并校验配置文件
 	var c config.Config
-	conf.MustLoad(*configFile, &c)
+	settings.MustLoad(*configFile, &c)
 
 	// 创建服务上下文
 	ctx := svc.NewServiceContext(c)
 
+	// 连接交易所并开始扫描（失败时仍提供查询和配置接口）
+	if err := ctx.Start(context.Background()); err != nil {
+		logx.Errorf("启动套利引擎失败: %v", err)
+	}
+	defer ctx.Stop()
+
 	// 创建 REST 服务器
 	server := rest.MustNewServer(c.RestConf)
 	defer server.Stop()
@@ -1,10 +1,248 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/settings"
+	"arbitragex/restful/engine/internal/config"
+	"arbitragex/restful/engine/internal/handler"
+	"arbitragex/restful/engine/internal/svc"
+	"arbitragex/restful/engine/internal/types"
+
+	"github.com/zeromicro/go-zero/core/conf"
+	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/rest/pathvar"
 )
 
 // TestMainCompilation 测试主函数可以正常编译
 func TestMainCompilation(t *testing.T) {
 	t.Log("Main function compilation test passed")
 }
+
+const testToken = "test-token"
+
+// newTestServer 创建未连接交易所的服务，写入 BTC/USDT、ETH/USDT 存在价差的价格并扫描一次
+func newTestServer(t *testing.T) (*rest.Server, *svc.ServiceContext) {
+	t.Helper()
+
+	var c config.Config
+	c.Host, c.Port = "127.0.0.1", 0
+	c.Auth.Token = testToken
+	c.Exchanges = settings.Exchanges{
+		{Name: "binance", Type: "cex", Enabled: true, MakerFee: 0.001, TakerFee: 0.001},
+		{Name: "okx", Type: "cex", Enabled: true, MakerFee: 0.0008, TakerFee: 0.001},
+	}
+	c.Symbols = []string{"BTC/USDT", "ETH/USDT"}
+	c.PriceTTL = time.Minute
+	c.Scan = config.ScanConf{Interval: time.Hour}
+	if err := conf.FillDefault(&c.Engine); err != nil {
+		t.Fatalf("FillDefault() error = %v", err)
+	}
+	svcCtx := svc.NewServiceContext(c)
+
+	now := time.Now()
+	for _, p := range []*cache.PriceData{
+		{Exchange: "binance", Symbol: "BTC/USDT", BidPrice: decimal.RequireFromString("43000"), AskPrice: decimal.RequireFromString("43010"), Timestamp: now},
+		{Exchange: "okx", Symbol: "BTC/USDT", BidPrice: decimal.RequireFromString("44000"), AskPrice: decimal.RequireFromString("44010"), Timestamp: now},
+		{Exchange: "binance", Symbol: "ETH/USDT", BidPrice: decimal.RequireFromString("2300"), AskPrice: decimal.RequireFromString("2301"), Timestamp: now},
+		{Exchange: "okx", Symbol: "ETH/USDT", BidPrice: decimal.RequireFromString("2340"), AskPrice: decimal.RequireFromString("2341"), Timestamp: now},
+	} {
+		svcCtx.PriceCache.SetPrice(context.Background(), p.Exchange, p.Symbol, p)
+	}
+	if _, err := svcCtx.Engine.ScanOpportunities(context.Background(), c.Symbols, c.Exchanges.Names()); err != nil {
+		t.Fatalf("ScanOpportunities() error = %v", err)
+	}
+
+	server := rest.MustNewServer(c.RestConf)
+	handler.RegisterHandlers(server, svcCtx)
+	return server, svcCtx
+}
+
+// serve 调用路由对应的处理器（经过中间件）并解析 JSON 响应
+func serve(t *testing.T, server *rest.Server, method, path, target, token, body string, vars map[string]string, resp interface{}) int {
+	t.Helper()
+
+	for _, route := range server.Routes() {
+		if route.Method != method || route.Path != path {
+			continue
+		}
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			r.Header.Set("Content-Type", "application/json")
+		}
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		if vars != nil {
+			r = pathvar.WithVars(r, vars)
+		}
+		w := httptest.NewRecorder()
+		route.Handler(w, r)
+		if w.Code == http.StatusOK && resp != nil {
+			if err := json.Unmarshal(w.Body.Bytes(), resp); err != nil {
+				t.Fatalf("%s: invalid JSON %s: %v", target, w.Body.String(), err)
+			}
+		}
+		return w.Code
+	}
+	t.Fatalf("route %s %s not registered", method, path)
+	return 0
+}
+
+// TestOpportunityAPI 测试套利机会列表的过滤、排序、分页和详情接口
+func TestOpportunityAPI(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var list types.OpportunityListResponse
+	if code := serve(t, server, http.MethodGet, "/api/opportunities", "/api/opportunities?sort_by=net_profit&order=asc", "", "", nil, &list); code != http.StatusOK {
+		t.Fatalf("GET /api/opportunities status = %d", code)
+	}
+	if list.Total != 2 || list.Opportunities[0].Symbol != "ETH/USDT" || list.Opportunities[1].Symbol != "BTC/USDT" {
+		t.Fatalf("opportunities?sort_by=net_profit&order=asc = %+v, want ETH/USDT then BTC/USDT", list.Opportunities)
+	}
+
+	serve(t, server, http.MethodGet, "/api/opportunities", "/api/opportunities?sort_by=net_profit&limit=1", "", "", nil, &list)
+	if list.Total != 2 || len(list.Opportunities) != 1 || list.Opportunities[0].Symbol != "BTC/USDT" {
+		t.Errorf("opportunities?limit=1 = %+v (total %d), want BTC/USDT of 2", list.Opportunities, list.Total)
+	}
+
+	serve(t, server, http.MethodGet, "/api/opportunities", "/api/opportunities?symbol=ETHUSDT&exchange=OKX", "", "", nil, &list)
+	if list.Total != 1 || list.Opportunities[0].BuyExchange != "binance" || list.Opportunities[0].SellExchange != "okx" {
+		t.Errorf("opportunities?symbol=ETHUSDT = %+v, want binance -> okx ETH/USDT", list.Opportunities)
+	}
+
+	serve(t, server, http.MethodGet, "/api/opportunities", "/api/opportunities?min_profit_rate=0.015", "", "", nil, &list)
+	if list.Total != 1 || list.Opportunities[0].Symbol != "BTC/USDT" {
+		t.Errorf("opportunities?min_profit_rate=0.015 = %+v, want BTC/USDT", list.Opportunities)
+	}
+
+	if code := serve(t, server, http.MethodGet, "/api/opportunities", "/api/opportunities?sort_by=volume", "", "", nil, nil); code == http.StatusOK {
+		t.Error("GET /api/opportunities?sort_by=volume status = 200, want error")
+	}
+
+	id := list.Opportunities[0].Id
+	var opp types.Opportunity
+	if code := serve(t, server, http.MethodGet, "/api/opportunities/:id", "/api/opportunities/"+id, "", "", map[string]string{"id": id}, &opp); code != http.StatusOK {
+		t.Fatalf("GET /api/opportunities/%s status = %d", id, code)
+	}
+	if opp.Id != id || opp.BuyPrice != "43010" || opp.SellPrice != "44000" {
+		t.Errorf("opportunity = %+v, want buy 43010 sell 44000", opp)
+	}
+
+	if code := serve(t, server, http.MethodGet, "/api/opportunities/:id", "/api/opportunities/unknown", "", "", map[string]string{"id": "unknown"}, nil); code == http.StatusOK {
+		t.Error("GET /api/opportunities/unknown status = 200, want error")
+	}
+}
+
+// TestEngineConfigAPI 测试读取和更新引擎配置，无效配置被拒绝且不生效
+func TestEngineConfigAPI(t *testing.T) {
+	server, svcCtx := newTestServer(t)
+
+	var cfg types.EngineConfig
+	if code := serve(t, server, http.MethodGet, "/api/engine/config", "/api/engine/config", "", "", nil, &cfg); code != http.StatusOK {
+		t.Fatalf("GET /api/engine/config status = %d", code)
+	}
+	if cfg.MinProfitRate != 0.005 || cfg.MinVolume != "1000" || cfg.OpportunityTtl != "5s" || len(cfg.TradingFees) != 2 {
+		t.Errorf("config = %+v, want defaults", cfg)
+	}
+
+	cfg.MinProfitRate = 0.01
+	cfg.SlippageRate = 0.002
+	cfg.OpportunityTtl = "10s"
+	cfg.MinVolume = "2000.5"
+	body, _ := json.Marshal(cfg)
+	var updated types.EngineConfig
+	if code := serve(t, server, http.MethodPut, "/api/engine/config", "/api/engine/config", testToken, string(body), nil, &updated); code != http.StatusOK {
+		t.Fatalf("PUT /api/engine/config status = %d", code)
+	}
+	if updated.MinProfitRate != 0.01 || updated.MinVolume != "2000.5" || updated.OpportunityTtl != "10s" {
+		t.Errorf("updated config = %+v", updated)
+	}
+	if got := svcCtx.Engine.Config(); got.SlippageRate != 0.002 || got.OpportunityTTL != 10*time.Second {
+		t.Errorf("engine config = %+v, want slippage 0.002 and TTL 10s", got)
+	}
+
+	for _, invalid := range []func(c *types.EngineConfig){
+		func(c *types.EngineConfig) { c.SlippageRate = 1.5 },
+		func(c *types.EngineConfig) { c.MinVolume = "abc" },
+		func(c *types.EngineConfig) { c.OpportunityTtl = "0s" },
+		func(c *types.EngineConfig) { c.TradingFees = append(c.TradingFees, types.TradingFee{Exchange: "okx"}) },
+	} {
+		c := cfg
+		c.TradingFees = append([]types.TradingFee(nil), cfg.TradingFees...)
+		invalid(&c)
+		body, _ := json.Marshal(c)
+		if code := serve(t, server, http.MethodPut, "/api/engine/config", "/api/engine/config", testToken, string(body), nil, nil); code == http.StatusOK {
+			t.Errorf("PUT /api/engine/config %s status = 200, want error", body)
+		}
+	}
+	if got := svcCtx.Engine.Config(); got.MinProfitRate != 0.01 || got.SlippageRate != 0.002 {
+		t.Errorf("engine config after invalid updates = %+v, want previous config", got)
+	}
+}
+
+// TestEngineControlAPI 测试扫描循环的启动、暂停和状态接口
+func TestEngineControlAPI(t *testing.T) {
+	server, svcCtx := newTestServer(t)
+	defer svcCtx.Stop()
+
+	var status types.EngineStatus
+	serve(t, server, http.MethodGet, "/api/engine/status", "/api/engine/status", "", "", nil, &status)
+	if status.Running || status.Scans != 0 || status.Interval != "1h0m0s" || len(status.Symbols) != 2 {
+		t.Errorf("status = %+v, want not running", status)
+	}
+
+	if code := serve(t, server, http.MethodPost, "/api/engine/pause", "/api/engine/pause", testToken, "", nil, nil); code == http.StatusOK {
+		t.Error("POST /api/engine/pause before start status = 200, want error")
+	}
+	if code := serve(t, server, http.MethodPost, "/api/engine/start", "/api/engine/start", testToken, "", nil, &status); code != http.StatusOK || !status.Running {
+		t.Fatalf("POST /api/engine/start status = %d, %+v", code, status)
+	}
+	if code := serve(t, server, http.MethodPost, "/api/engine/start", "/api/engine/start", testToken, "", nil, nil); code == http.StatusOK {
+		t.Error("second POST /api/engine/start status = 200, want error")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for svcCtx.Scanner.Status().Scans == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if code := serve(t, server, http.MethodPost, "/api/engine/pause", "/api/engine/pause", testToken, "", nil, &status); code != http.StatusOK {
+		t.Fatalf("POST /api/engine/pause status = %d", code)
+	}
+	if status.Running || status.Scans != 1 || status.Opportunities != 2 || status.LastScanAt == 0 {
+		t.Errorf("status after pause = %+v, want 1 scan with 2 opportunities", status)
+	}
+}
+
+// TestAuth 测试修改配置和控制扫描的接口需要访问令牌，查询类接口不需要
+func TestAuth(t *testing.T) {
+	server, svcCtx := newTestServer(t)
+	defer svcCtx.Stop()
+
+	for _, token := range []string{"", "wrong-token"} {
+		if code := serve(t, server, http.MethodPut, "/api/engine/config", "/api/engine/config", token, `{"min_profit_rate":0}`, nil, nil); code != http.StatusUnauthorized {
+			t.Errorf("PUT /api/engine/config with token %q status = %d, want 401", token, code)
+		}
+		if code := serve(t, server, http.MethodPost, "/api/engine/start", "/api/engine/start", token, "", nil, nil); code != http.StatusUnauthorized {
+			t.Errorf("POST /api/engine/start with token %q status = %d, want 401", token, code)
+		}
+		if code := serve(t, server, http.MethodPost, "/api/engine/pause", "/api/engine/pause", token, "", nil, nil); code != http.StatusUnauthorized {
+			t.Errorf("POST /api/engine/pause with token %q status = %d, want 401", token, code)
+		}
+	}
+	if svcCtx.Scanner.Running() {
+		t.Error("scanner started without a token")
+	}
+
+	if code := serve(t, server, http.MethodGet, "/api/engine/status", "/api/engine/status", "", "", nil, nil); code != http.StatusOK {
+		t.Errorf("GET /api/engine/status status = %d, want 200", code)
+	}
+}
@@ -3,8 +3,63 @@
 
 package config
 
-import "github.com/zeromicro/go-zero/rest"
+import (
+	"fmt"
+	"time"
+
+	"arbitragex/pkg/bus"
+	"arbitragex/pkg/settings"
+
+	"github.com/zeromicro/go-zero/rest"
+)
 
 type Config struct {
 	rest.RestConf
+
+	// Exchanges 行情交易所（只连接 Enabled 的 CEX）
+	Exchanges settings.Exchanges
+
+	// Symbols 订阅的交易对（标准格式，如 BTC/USDT）
+	Symbols []string `json:",default=[BTC/USDT,ETH/USDT]"`
+
+	// PriceTTL 价格缓存有效期（超过后不再返回）
+	PriceTTL time.Duration `json:",default=5s"`
+
+	// Stream 价格推送配置
+	Stream StreamConf
+
+	// Bus 消息总线（配置后价格同时发布到 Redis Streams，供套利引擎服务消费）
+	Bus bus.Conf `json:",optional"`
+}
+
+// StreamConf 价格推送配置
+type StreamConf struct {
+	History   int           `json:",default=10000"` // 保留的历史更新数（断线重连可续传的范围）
+	Buffer    int           `json:",default=256"`   // 每个客户端的缓冲队列长度（队列满时断开该客户端）
+	Heartbeat time.Duration `json:",default=15s"`   // 心跳间隔
+}
+
+// Validate 校验价格推送配置（心跳间隔 ≤ 0 时创建定时器会 panic）
+func (c StreamConf) Validate() error {
+	if c.History <= 0 {
+		return fmt.Errorf("Stream.History 必须大于 0: %d", c.History)
+	}
+	if c.Buffer <= 0 {
+		return fmt.Errorf("Stream.Buffer 必须大于 0: %d", c.Buffer)
+	}
+	if c.Heartbeat <= 0 {
+		return fmt.Errorf("Stream.Heartbeat 必须大于 0: %s", c.Heartbeat)
+	}
+	return nil
+}
+
+// Validate 校验配置（settings.Load 加载后调用）
+func (c Config) Validate() error {
+	if err := c.Exchanges.Validate(); err != nil {
+		return err
+	}
+	if len(c.Symbols) == 0 {
+		return fmt.Errorf("Symbols: 至少配置一个交易对")
+	}
+	return c.Stream.Validate()
 }
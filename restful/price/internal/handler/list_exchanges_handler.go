@@ -0,0 +1,26 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"net/http"
+
+	"arbitragex/restful/price/internal/logic"
+	"arbitragex/restful/price/internal/svc"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 查询交易所连接状态
+func listExchangesHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewListExchangesLogic(r.Context(), svcCtx)
+		resp, err := l.ListExchanges()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
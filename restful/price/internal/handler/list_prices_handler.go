@@ -0,0 +1,33 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"net/http"
+
+	"arbitragex/restful/price/internal/logic"
+	"arbitragex/restful/price/internal/svc"
+	"arbitragex/restful/price/internal/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 查询缓存中的价格（按交易所、交易对过滤）
+func listPricesHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.PriceListRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewListPricesLogic(r.Context(), svcCtx)
+		resp, err := l.ListPrices(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -20,7 +20,38 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/health",
 				Handler: healthCheckHandler(serverCtx),
 			},
+			{
+				// 查询缓存中的价格（按交易所、交易对过滤）
+				Method:  http.MethodGet,
+				Path:    "/prices",
+				Handler: listPricesHandler(serverCtx),
+			},
+			{
+				// 查询交易对在各交易所的价格和价差
+				Method:  http.MethodGet,
+				Path:    "/prices/:symbol",
+				Handler: getSymbolPriceHandler(serverCtx),
+			},
+			{
+				// 查询交易所连接状态
+				Method:  http.MethodGet,
+				Path:    "/exchanges",
+				Handler: listExchangesHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 订阅价格推送（Server-Sent Events）
+				Method:  http.MethodGet,
+				Path:    "/stream/prices",
+				Handler: streamPricesHandler(serverCtx),
+			},
 		},
+		rest.WithSSE(),
 		rest.WithPrefix("/api"),
 	)
 }
@@ -0,0 +1,63 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"arbitragex/restful/price/internal/logic"
+	"arbitragex/restful/price/internal/svc"
+	"arbitragex/restful/price/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// retryMillis 建议客户端断线后的重连间隔（毫秒）
+const retryMillis = 3000
+
+// 订阅价格推送（Server-Sent Events）
+// 客户端需携带 Accept: text/event-stream 请求头，重连时通过 Last-Event-ID 续传
+func streamPricesHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.StreamPricesRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+		if req.LastEventId == 0 {
+			if id, err := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64); err == nil {
+				req.LastEventId = id
+			}
+		}
+
+		rc := http.NewResponseController(w)
+		fmt.Fprintf(w, "retry: %d\n\n", retryMillis)
+		if err := rc.Flush(); err != nil {
+			logx.WithContext(r.Context()).Errorf("价格推送不支持 Flush: %v", err)
+			return
+		}
+
+		l := logic.NewStreamPricesLogic(r.Context(), svcCtx)
+		err := l.StreamPrices(&req, func(event string, id uint64, data interface{}) error {
+			payload, err := json.Marshal(data)
+			if err != nil {
+				return err
+			}
+			if id > 0 {
+				fmt.Fprintf(w, "id: %d\n", id)
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+				return err
+			}
+			return rc.Flush()
+		})
+		if err != nil {
+			logx.WithContext(r.Context()).Infof("价格推送结束: %v", err)
+		}
+	}
+}
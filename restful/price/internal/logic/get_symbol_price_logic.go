@@ -0,0 +1,79 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"fmt"
+
+	"arbitragex/common/cache"
+	"arbitragex/restful/price/internal/svc"
+	"arbitragex/restful/price/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetSymbolPriceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询交易对在各交易所的价格和价差
+func NewGetSymbolPriceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSymbolPriceLogic {
+	return &GetSymbolPriceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetSymbolPrice 返回交易对在各交易所的价格，以及最高买一价与最低卖一价之间的价差
+func (l *GetSymbolPriceLogic) GetSymbolPrice(req *types.SymbolPriceRequest) (resp *types.SymbolPriceResponse, err error) {
+	symbol := normalizeSymbol(l.svcCtx, req.Symbol)
+	if symbol == "" {
+		return nil, fmt.Errorf("交易对不能为空")
+	}
+
+	resp = &types.SymbolPriceResponse{
+		Symbol: symbol,
+		Prices: make([]types.Price, 0),
+	}
+
+	var bestBid, bestAsk *cache.PriceData
+	for _, exchange := range l.svcCtx.Feed.Exchanges() {
+		price, err := l.svcCtx.PriceCache.GetPrice(l.ctx, exchange, symbol)
+		if err != nil {
+			continue
+		}
+		resp.Prices = append(resp.Prices, toPrice(price))
+
+		if price.BidPrice.IsPositive() && (bestBid == nil || price.BidPrice.GreaterThan(bestBid.BidPrice)) {
+			bestBid = price
+		}
+		if price.AskPrice.IsPositive() && (bestAsk == nil || price.AskPrice.LessThan(bestAsk.AskPrice)) {
+			bestAsk = price
+		}
+	}
+	if len(resp.Prices) == 0 {
+		return nil, fmt.Errorf("交易对 %s 暂无价格", symbol)
+	}
+	sortPrices(resp.Prices)
+
+	if bestBid != nil {
+		resp.BestBidExchange = bestBid.Exchange
+		resp.BestBid = bestBid.BidPrice.String()
+	}
+	if bestAsk != nil {
+		resp.BestAskExchange = bestAsk.Exchange
+		resp.BestAsk = bestAsk.AskPrice.String()
+	}
+	if bestBid != nil && bestAsk != nil {
+		spread := bestBid.BidPrice.Sub(bestAsk.AskPrice)
+		resp.Spread = spread.String()
+		resp.SpreadRate = spread.Div(bestAsk.AskPrice).Float64()
+	}
+
+	return resp, nil
+}
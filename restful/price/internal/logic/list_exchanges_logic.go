@@ -0,0 +1,52 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"arbitragex/restful/price/internal/svc"
+	"arbitragex/restful/price/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListExchangesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询交易所连接状态
+func NewListExchangesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListExchangesLogic {
+	return &ListExchangesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListExchangesLogic) ListExchanges() (resp *types.ExchangeListResponse, err error) {
+	statuses := l.svcCtx.Feed.Status()
+
+	resp = &types.ExchangeListResponse{
+		Exchanges: make([]types.ExchangeStatus, 0, len(statuses)),
+	}
+	for _, status := range statuses {
+		var lastUpdate int64
+		if !status.LastUpdate.IsZero() {
+			lastUpdate = status.LastUpdate.UnixMilli()
+		}
+		resp.Exchanges = append(resp.Exchanges, types.ExchangeStatus{
+			Name:       status.Name,
+			Connected:  status.Connected,
+			Symbols:    status.Symbols,
+			Updates:    status.Updates,
+			LastUpdate: lastUpdate,
+			Error:      status.Error,
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,57 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"arbitragex/restful/price/internal/svc"
+	"arbitragex/restful/price/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListPricesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询缓存中的价格（按交易所、交易对过滤）
+func NewListPricesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListPricesLogic {
+	return &ListPricesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListPricesLogic) ListPrices(req *types.PriceListRequest) (resp *types.PriceListResponse, err error) {
+	exchanges := l.svcCtx.Feed.Exchanges()
+	if req.Exchange != "" {
+		exchanges = []string{strings.ToLower(req.Exchange)}
+	}
+	symbol := normalizeSymbol(l.svcCtx, req.Symbol)
+
+	prices := make([]types.Price, 0)
+	for _, exchange := range exchanges {
+		cached, err := l.svcCtx.PriceCache.GetAllPrices(l.ctx, exchange)
+		if err != nil {
+			return nil, err
+		}
+		for _, price := range cached {
+			if symbol != "" && price.Symbol != symbol {
+				continue
+			}
+			prices = append(prices, toPrice(price))
+		}
+	}
+	sortPrices(prices)
+
+	return &types.PriceListResponse{
+		Prices: prices,
+		Total:  len(prices),
+	}, nil
+}
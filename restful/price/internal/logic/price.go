@@ -0,0 +1,53 @@
+package logic
+
+import (
+	"sort"
+	"strings"
+
+	"arbitragex/common/cache"
+	"arbitragex/restful/price/internal/svc"
+	"arbitragex/restful/price/internal/types"
+)
+
+// toPrice 转换为接口返回的价格
+func toPrice(p *cache.PriceData) types.Price {
+	return types.Price{
+		Exchange:  p.Exchange,
+		Symbol:    p.Symbol,
+		BidPrice:  p.BidPrice.String(),
+		AskPrice:  p.AskPrice.String(),
+		LastPrice: p.LastPrice.String(),
+		Volume24h: p.Volume24h.String(),
+		Timestamp: p.Timestamp.UnixMilli(),
+	}
+}
+
+// sortPrices 按交易对、交易所排序
+func sortPrices(prices []types.Price) {
+	sort.Slice(prices, func(i, j int) bool {
+		if prices[i].Symbol != prices[j].Symbol {
+			return prices[i].Symbol < prices[j].Symbol
+		}
+		return prices[i].Exchange < prices[j].Exchange
+	})
+}
+
+// normalizeSymbol 将请求中的交易对转换为标准格式
+// BTC/USDT、BTC-USDT、btc_usdt 直接替换分隔符；没有分隔符的 BTCUSDT 按订阅的交易对匹配
+func normalizeSymbol(svcCtx *svc.ServiceContext, symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return ""
+	}
+
+	normalized := strings.NewReplacer("-", "/", "_", "/").Replace(symbol)
+	if strings.Contains(normalized, "/") {
+		return normalized
+	}
+	for _, subscribed := range svcCtx.Feed.Symbols() {
+		if strings.ReplaceAll(subscribed, "/", "") == symbol {
+			return subscribed
+		}
+	}
+	return symbol
+}
@@ -0,0 +1,104 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"arbitragex/pkg/feed"
+	"arbitragex/restful/price/internal/svc"
+	"arbitragex/restful/price/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 推送事件类型
+const (
+	EventPrice     = "price"     // 价格更新
+	EventHeartbeat = "heartbeat" // 心跳
+	EventReset     = "reset"     // 续传的序号已过期，客户端需要重新获取价格快照
+	EventLagged    = "lagged"    // 消费过慢被断开，客户端需要重连续传
+)
+
+// SendFunc 发送推送事件（id 为 0 时不设置事件序号）
+type SendFunc func(event string, id uint64, data interface{}) error
+
+type StreamPricesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 订阅价格推送（Server-Sent Events）
+func NewStreamPricesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *StreamPricesLogic {
+	return &StreamPricesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// StreamPrices 推送价格更新，直到客户端断开或因消费过慢被断开
+func (l *StreamPricesLogic) StreamPrices(req *types.StreamPricesRequest, send SendFunc) error {
+	filter := feed.Filter{Exchanges: splitList(req.Exchange)}
+	for _, symbol := range splitList(req.Symbol) {
+		filter.Symbols = append(filter.Symbols, normalizeSymbol(l.svcCtx, symbol))
+	}
+
+	hub := l.svcCtx.Hub
+	sub := hub.Subscribe(filter, req.LastEventId)
+	defer sub.Close()
+
+	if sub.Gap {
+		if err := send(EventReset, 0, l.status()); err != nil {
+			return err
+		}
+	}
+
+	heartbeat := time.NewTicker(l.svcCtx.Config.Stream.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			return nil
+		case update, ok := <-sub.Updates:
+			if !ok {
+				if sub.Lagged() {
+					l.Infof("价格推送消费过慢，已断开: %+v", filter)
+					return send(EventLagged, 0, l.status())
+				}
+				return nil
+			}
+			if err := send(EventPrice, update.Seq, types.PriceEvent{Seq: update.Seq, Price: toPrice(update.Price)}); err != nil {
+				return err
+			}
+		case <-heartbeat.C:
+			if err := send(EventHeartbeat, 0, l.status()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// status 当前推送状态
+func (l *StreamPricesLogic) status() types.StreamStatus {
+	return types.StreamStatus{
+		Seq:  l.svcCtx.Hub.Seq(),
+		Time: time.Now().UnixMilli(),
+	}
+}
+
+// splitList 拆分逗号分隔的参数（去掉空白和空项）
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -4,15 +4,116 @@
 package svc
 
 import (
+	"context"
+	"sync"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/tracing"
+	"arbitragex/pkg/bus"
+	"arbitragex/pkg/feed"
 	"arbitragex/restful/price/internal/config"
+
+	"github.com/zeromicro/go-zero/core/logx"
 )
 
 type ServiceContext struct {
-	Config config.Config
+	Config     config.Config
+	PriceCache cache.PriceCache
+	Feed       *feed.Feed
+	Hub        *feed.Hub
+	Bus        bus.Bus // 消息总线（未配置时为 nil，价格只在本服务内使用）
+
+	prices chan *cache.PriceData // 待发布到消息总线的价格
+	done   chan struct{}
+	wg     sync.WaitGroup
+	logger logx.Logger
 }
 
+// publishQueueSize 价格发布队列长度
+const publishQueueSize = 4096
+
+// NewServiceContext 创建服务上下文
+// 按配置为每个交易所创建适配器，行情写入内存价格缓存并广播给推送订阅者，配置了消息总线时同时发布到价格主题（调用 Start 后开始接收）
 func NewServiceContext(c config.Config) *ServiceContext {
-	return &ServiceContext{
-		Config: c,
+	priceCache := cache.NewMemoryPriceCache(c.PriceTTL)
+	hub := feed.NewHub(c.Stream.History, c.Stream.Buffer)
+	priceFeed := feed.New(priceCache, c.Symbols)
+	priceFeed.OnPrice(hub.Publish)
+	for _, ex := range c.Exchanges.Enabled() {
+		adapter, err := ex.NewAdapter(c.Symbols)
+		logx.Must(err)
+		priceFeed.AddAdapter(ex.Key(), adapter)
+	}
+
+	s := &ServiceContext{
+		Config:     c,
+		PriceCache: priceCache,
+		Feed:       priceFeed,
+		Hub:        hub,
+		done:       make(chan struct{}),
+		logger:     logx.WithContext(context.Background()),
+	}
+	if c.Bus.Enabled() {
+		s.Bus = bus.MustNewRedisBus(c.Bus)
+	}
+	return s
+}
+
+// Start 连接交易所并开始接收行情（配置了消息总线时先启动价格发布）
+func (s *ServiceContext) Start(ctx context.Context) error {
+	if s.Bus != nil {
+		s.prices = make(chan *cache.PriceData, publishQueueSize)
+		s.Feed.OnPrice(s.enqueuePrice)
+		s.wg.Add(1)
+		go s.publishPrices()
+	}
+	return s.Feed.Start(ctx)
+}
+
+// Stop 断开交易所连接，发布完队列中的价格后关闭消息总线
+func (s *ServiceContext) Stop() {
+	s.Feed.Stop()
+	if s.Bus != nil {
+		close(s.done)
+		s.wg.Wait()
+		s.Bus.Close()
+	}
+}
+
+// enqueuePrice 将价格放入发布队列（PriceListener，不能阻塞行情回调；队列满时丢弃，后续行情会覆盖）
+func (s *ServiceContext) enqueuePrice(price *cache.PriceData) {
+	select {
+	case s.prices <- price:
+	default:
+		s.logger.Errorf("价格发布队列已满，丢弃 %s %s", price.Exchange, price.Symbol)
+	}
+}
+
+// publishPrices 将队列中的价格发布到消息总线
+func (s *ServiceContext) publishPrices() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case price := <-s.prices:
+			s.publishPrice(price)
+		case <-s.done:
+			for {
+				select {
+				case price := <-s.prices:
+					s.publishPrice(price)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// publishPrice 发布一条价格
+func (s *ServiceContext) publishPrice(price *cache.PriceData) {
+	ctx := tracing.ContextWithParent(context.Background(), price.SpanContext)
+	if _, err := bus.PublishJSON(ctx, s.Bus, bus.TopicPrices, price); err != nil {
+		s.logger.Errorf("发布价格 %s %s 失败: %v", price.Exchange, price.Symbol, err)
 	}
 }
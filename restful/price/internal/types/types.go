@@ -3,8 +3,72 @@
 
 package types
 
+type ExchangeListResponse struct {
+	Exchanges []ExchangeStatus `json:"exchanges"`
+}
+
+type ExchangeStatus struct {
+	Name       string   `json:"name"`
+	Connected  bool     `json:"connected"`
+	Symbols    []string `json:"symbols"`
+	Updates    int64    `json:"updates"`     // 收到的行情数
+	LastUpdate int64    `json:"last_update"` // 最近一次收到行情的毫秒时间戳（0 表示尚未收到）
+	Error      string   `json:"error,omitempty"`
+}
+
 type HealthCheckResponse struct {
 	Status  string `json:"status"`
 	Uptime  int64  `json:"uptime"`
 	Version string `json:"version"`
 }
+
+type Price struct {
+	Exchange  string `json:"exchange"`
+	Symbol    string `json:"symbol"`
+	BidPrice  string `json:"bid_price"`
+	AskPrice  string `json:"ask_price"`
+	LastPrice string `json:"last_price"`
+	Volume24h string `json:"volume_24h"`
+	Timestamp int64  `json:"timestamp"` // 毫秒时间戳
+}
+
+type PriceEvent struct {
+	Seq   uint64 `json:"seq"`
+	Price Price  `json:"price"`
+}
+
+type PriceListRequest struct {
+	Exchange string `form:"exchange,optional"` // 交易所（为空时返回所有交易所）
+	Symbol   string `form:"symbol,optional"`   // 交易对（如 BTC/USDT，为空时返回所有交易对）
+}
+
+type PriceListResponse struct {
+	Prices []Price `json:"prices"`
+	Total  int     `json:"total"`
+}
+
+type SymbolPriceRequest struct {
+	Symbol string `path:"symbol"` // 交易对（路径中使用 BTC-USDT 或 BTCUSDT）
+}
+
+type SymbolPriceResponse struct {
+	Symbol          string  `json:"symbol"`
+	Prices          []Price `json:"prices"`            // 各交易所价格
+	BestBidExchange string  `json:"best_bid_exchange"` // 买一价最高的交易所（卖出）
+	BestBid         string  `json:"best_bid"`
+	BestAskExchange string  `json:"best_ask_exchange"` // 卖一价最低的交易所（买入）
+	BestAsk         string  `json:"best_ask"`
+	Spread          string  `json:"spread"`      // 价差（最高买一价 - 最低卖一价，为正时存在套利空间）
+	SpreadRate      float64 `json:"spread_rate"` // 价差率（价差 / 最低卖一价）
+}
+
+type StreamPricesRequest struct {
+	Exchange    string `form:"exchange,optional"`      // 交易所（逗号分隔，为空时不过滤）
+	Symbol      string `form:"symbol,optional"`        // 交易对（逗号分隔，如 BTC/USDT,ETH-USDT，为空时不过滤）
+	LastEventId uint64 `form:"last_event_id,optional"` // 最后收到的序号，之后的更新会先补发（断线重连时浏览器通过 Last-Event-ID 请求头传入）
+}
+
+type StreamStatus struct {
+	Seq  uint64 `json:"seq"`  // 服务端最新序号
+	Time int64  `json:"time"` // 毫秒时间戳
+}
@@ -1,7 +1,25 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/settings"
+	"arbitragex/restful/price/internal/config"
+	"arbitragex/restful/price/internal/handler"
+	"arbitragex/restful/price/internal/svc"
+	"arbitragex/restful/price/internal/types"
+
+	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/rest/pathvar"
 )
 
 // TestMain 测试主函数可以正常编译
@@ -11,3 +29,206 @@ func TestMainCompilation(t *testing.T) {
 	// 实际的启动测试需要更复杂的设置
 	t.Log("Main function compilation test passed")
 }
+
+// newTestServer 创建未连接交易所的服务，并写入 binance / okx 的 BTC/USDT 价格
+func newTestServer(t *testing.T) (*rest.Server, *svc.ServiceContext) {
+	t.Helper()
+
+	var c config.Config
+	c.Host, c.Port = "127.0.0.1", 0
+	c.Exchanges = settings.Exchanges{
+		{Name: "binance", Type: "cex", Enabled: true, MakerFee: 0.001, TakerFee: 0.001},
+		{Name: "okx", Type: "cex", Enabled: true, MakerFee: 0.0008, TakerFee: 0.001},
+	}
+	c.Symbols = []string{"BTC/USDT", "ETH/USDT"}
+	c.PriceTTL = time.Minute
+	c.Stream = config.StreamConf{History: 100, Buffer: 16, Heartbeat: time.Minute}
+	svcCtx := svc.NewServiceContext(c)
+
+	now := time.Now()
+	for _, p := range []*cache.PriceData{
+		{Exchange: "binance", Symbol: "BTC/USDT", BidPrice: decimal.RequireFromString("43000.1"), AskPrice: decimal.RequireFromString("43000.2"), Timestamp: now},
+		{Exchange: "okx", Symbol: "BTC/USDT", BidPrice: decimal.RequireFromString("43100.5"), AskPrice: decimal.RequireFromString("43101"), Timestamp: now},
+		{Exchange: "okx", Symbol: "ETH/USDT", BidPrice: decimal.RequireFromString("2300"), AskPrice: decimal.RequireFromString("2300.1"), Timestamp: now},
+	} {
+		svcCtx.PriceCache.SetPrice(context.Background(), p.Exchange, p.Symbol, p)
+	}
+
+	server := rest.MustNewServer(c.RestConf)
+	handler.RegisterHandlers(server, svcCtx)
+	return server, svcCtx
+}
+
+// serve 调用路由对应的处理器并解析 JSON 响应
+func serve(t *testing.T, server *rest.Server, path, target string, vars map[string]string, resp interface{}) int {
+	t.Helper()
+
+	for _, route := range server.Routes() {
+		if route.Method != http.MethodGet || route.Path != path {
+			continue
+		}
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		if vars != nil {
+			r = pathvar.WithVars(r, vars)
+		}
+		w := httptest.NewRecorder()
+		route.Handler(w, r)
+		if w.Code == http.StatusOK && resp != nil {
+			if err := json.Unmarshal(w.Body.Bytes(), resp); err != nil {
+				t.Fatalf("%s: invalid JSON %s: %v", target, w.Body.String(), err)
+			}
+		}
+		return w.Code
+	}
+	t.Fatalf("route %s not registered", path)
+	return 0
+}
+
+// TestPriceAPI 测试价格列表、交易对价差和交易所状态接口
+func TestPriceAPI(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var list types.PriceListResponse
+	if code := serve(t, server, "/api/prices", "/api/prices?exchange=okx", nil, &list); code != http.StatusOK {
+		t.Fatalf("GET /api/prices status = %d", code)
+	}
+	if list.Total != 2 || list.Prices[0].Symbol != "BTC/USDT" || list.Prices[1].Symbol != "ETH/USDT" {
+		t.Errorf("prices?exchange=okx = %+v, want okx BTC/USDT and ETH/USDT", list.Prices)
+	}
+
+	if serve(t, server, "/api/prices", "/api/prices?symbol=BTC-USDT", nil, &list); list.Total != 2 {
+		t.Errorf("prices?symbol=BTC-USDT total = %d, want 2", list.Total)
+	}
+
+	var symbol types.SymbolPriceResponse
+	if code := serve(t, server, "/api/prices/:symbol", "/api/prices/BTCUSDT", map[string]string{"symbol": "BTCUSDT"}, &symbol); code != http.StatusOK {
+		t.Fatalf("GET /api/prices/BTCUSDT status = %d", code)
+	}
+	if symbol.Symbol != "BTC/USDT" || symbol.BestBidExchange != "okx" || symbol.BestAskExchange != "binance" {
+		t.Errorf("symbol price = %+v, want best bid okx, best ask binance", symbol)
+	}
+	if symbol.Spread != "100.3" || symbol.SpreadRate <= 0 {
+		t.Errorf("spread = %s (%v), want 100.3", symbol.Spread, symbol.SpreadRate)
+	}
+
+	if code := serve(t, server, "/api/prices/:symbol", "/api/prices/DOGE-USDT", map[string]string{"symbol": "DOGE-USDT"}, nil); code == http.StatusOK {
+		t.Error("GET /api/prices/DOGE-USDT status = 200, want error")
+	}
+
+	var exchanges types.ExchangeListResponse
+	serve(t, server, "/api/exchanges", "/api/exchanges", nil, &exchanges)
+	if len(exchanges.Exchanges) != 2 || exchanges.Exchanges[0].Name != "binance" || exchanges.Exchanges[0].Connected {
+		t.Errorf("exchanges = %+v, want binance and okx disconnected", exchanges.Exchanges)
+	}
+}
+
+// sseEvent 推送事件
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// readEvents 订阅价格推送并读取 n 个事件
+func readEvents(t *testing.T, server *rest.Server, query, lastEventID string, n int) []sseEvent {
+	t.Helper()
+
+	var handler http.HandlerFunc
+	for _, route := range server.Routes() {
+		if route.Path == "/api/stream/prices" {
+			handler = route.Handler
+		}
+	}
+	if handler == nil {
+		t.Fatal("route /api/stream/prices not registered")
+	}
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/stream/prices"+query, nil)
+	r.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		r.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatalf("GET /api/stream/prices error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for len(events) < n && scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if current.event != "" {
+				events = append(events, current)
+			}
+			current = sseEvent{}
+		case strings.HasPrefix(line, "id: "):
+			current.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			current.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			current.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	if len(events) < n {
+		t.Fatalf("received %d events %+v, want %d (%v)", len(events), events, n, scanner.Err())
+	}
+	return events
+}
+
+// TestPriceStream 测试价格推送按交易所过滤、按 Last-Event-ID 续传，序号过期时发送 reset
+func TestPriceStream(t *testing.T) {
+	server, svcCtx := newTestServer(t)
+
+	now := time.Now()
+	for _, p := range []*cache.PriceData{
+		{Exchange: "binance", Symbol: "BTC/USDT", BidPrice: decimal.RequireFromString("43000.1"), AskPrice: decimal.RequireFromString("43000.2"), Timestamp: now},
+		{Exchange: "okx", Symbol: "ETH/USDT", BidPrice: decimal.RequireFromString("2300"), AskPrice: decimal.RequireFromString("2300.1"), Timestamp: now},
+		{Exchange: "okx", Symbol: "BTC/USDT", BidPrice: decimal.RequireFromString("43100.5"), AskPrice: decimal.RequireFromString("43101"), Timestamp: now},
+	} {
+		svcCtx.Hub.Publish(p)
+	}
+
+	events := readEvents(t, server, "?exchange=okx&symbol=BTC-USDT", "1", 1)
+	var update types.PriceEvent
+	if err := json.Unmarshal([]byte(events[0].data), &update); err != nil {
+		t.Fatalf("invalid event data %s: %v", events[0].data, err)
+	}
+	if events[0].event != "price" || events[0].id != "3" || update.Price.Exchange != "okx" || update.Price.BidPrice != "43100.5" {
+		t.Errorf("event = %+v, want okx BTC/USDT price with id 3", events[0])
+	}
+
+	events = readEvents(t, server, "?last_event_id=100", "", 1)
+	if events[0].event != "reset" || !strings.Contains(events[0].data, `"seq":3`) {
+		t.Errorf("event = %+v, want reset with seq 3", events[0])
+	}
+}
+
+// TestConfigValidate 测试价格推送配置校验（心跳间隔为 0 时拒绝启动，而不是在推送时 panic）
+func TestConfigValidate(t *testing.T) {
+	var c config.Config
+	c.Exchanges = settings.Exchanges{{Name: "binance", Type: "cex", Enabled: true}}
+	c.Symbols = []string{"BTC/USDT"}
+	c.Stream = config.StreamConf{History: 100, Buffer: 16, Heartbeat: time.Minute}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	for _, stream := range []config.StreamConf{
+		{History: 100, Buffer: 16, Heartbeat: 0},
+		{History: 0, Buffer: 16, Heartbeat: time.Minute},
+		{History: 100, Buffer: -1, Heartbeat: time.Minute},
+	} {
+		c.Stream = stream
+		if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "Stream.") {
+			t.Errorf("Validate(%+v) error = %v, want Stream error", stream, err)
+		}
+	}
+}
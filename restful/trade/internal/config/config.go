@@ -3,8 +3,80 @@
 
 package config
 
-import "github.com/zeromicro/go-zero/rest"
+import (
+	"fmt"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/bus"
+	"arbitragex/pkg/settings"
+
+	"github.com/zeromicro/go-zero/rest"
+)
 
 type Config struct {
 	rest.RestConf
+
+	// Auth 交易类接口鉴权
+	Auth AuthConf
+
+	// Exchanges 交易所（为 Enabled 的 CEX 创建订单执行器，真实交易需要 API 密钥）
+	Exchanges settings.Exchanges
+
+	// Executor 套利执行器（模拟交易、并发数、队列长度、执行日志）
+	Executor settings.ExecutorConf
+
+	// Credentials API 密钥来源（环境变量或加密密钥库，真实交易需要）
+	Credentials settings.CredentialConf
+
+	// MySQL 持久化（为空时执行记录只保存在内存中）
+	MySQL settings.MySQLConf `json:",optional"`
+
+	// Bus 消息总线（配置后执行结果发布到 Redis Streams，并可自动执行套利引擎服务发布的机会）
+	Bus bus.Conf `json:",optional"`
+
+	// AutoExecute 自动执行消息总线上的套利机会
+	AutoExecute AutoExecuteConf
+
+	// Risk 自动执行前的风控（所有执行结果计入当日盈亏）
+	Risk settings.RiskConf
+}
+
+// AutoExecuteConf 自动执行配置
+type AutoExecuteConf struct {
+	Enabled bool          `json:",default=false"` // 是否自动执行新出现的套利机会（需要配置 Bus）
+	Amount  string        `json:",default=100"`   // 每次执行的金额（USDT）
+	MaxAge  time.Duration `json:",default=3s"`    // 机会出现超过该时间后不再执行（重新投递的旧消息会被跳过）
+}
+
+// AuthConf 鉴权配置
+type AuthConf struct {
+	Token string `json:",optional"` // 访问令牌（请求头 Authorization: Bearer <Token>；为空时拒绝所有交易类请求）
+}
+
+// Validate 校验配置（settings.Load 加载后调用）
+func (c Config) Validate() error {
+	if err := c.Exchanges.Validate(); err != nil {
+		return err
+	}
+	if err := c.Executor.Validate(); err != nil {
+		return err
+	}
+	if err := c.Credentials.Validate(); err != nil {
+		return err
+	}
+	if !c.Executor.Paper {
+		if err := c.Exchanges.ValidateCredentials(c.Credentials); err != nil {
+			return err
+		}
+	}
+	if c.AutoExecute.Enabled {
+		if amount, err := decimal.NewFromString(c.AutoExecute.Amount); err != nil || !amount.IsPositive() {
+			return fmt.Errorf("无效的 AutoExecute.Amount: %q", c.AutoExecute.Amount)
+		}
+	}
+	if _, err := c.Risk.Limits(); err != nil {
+		return err
+	}
+	return nil
 }
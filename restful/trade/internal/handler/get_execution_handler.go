@@ -0,0 +1,33 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"net/http"
+
+	"arbitragex/restful/trade/internal/logic"
+	"arbitragex/restful/trade/internal/svc"
+	"arbitragex/restful/trade/internal/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 查询套利执行结果详情（包含买卖订单）
+func getExecutionHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ExecutionRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewGetExecutionLogic(r.Context(), svcCtx)
+		resp, err := l.GetExecution(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"net/http"
+
+	"arbitragex/restful/trade/internal/logic"
+	"arbitragex/restful/trade/internal/svc"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 查询执行器状态
+func getStatusHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewGetStatusLogic(r.Context(), svcCtx)
+		resp, err := l.GetStatus()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -20,7 +20,64 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/health",
 				Handler: healthCheckHandler(serverCtx),
 			},
+			{
+				// 查询执行器状态
+				Method:  http.MethodGet,
+				Path:    "/status",
+				Handler: getStatusHandler(serverCtx),
+			},
+			{
+				// 查询套利执行结果
+				Method:  http.MethodGet,
+				Path:    "/executions",
+				Handler: listExecutionsHandler(serverCtx),
+			},
+			{
+				// 查询套利执行结果详情（包含买卖订单）
+				Method:  http.MethodGet,
+				Path:    "/executions/:id",
+				Handler: getExecutionHandler(serverCtx),
+			},
+			{
+				// 查询订单
+				Method:  http.MethodGet,
+				Path:    "/orders",
+				Handler: listOrdersHandler(serverCtx),
+			},
+			{
+				// 查询订单最新状态
+				Method:  http.MethodGet,
+				Path:    "/orders/:id",
+				Handler: getOrderHandler(serverCtx),
+			},
 		},
 		rest.WithPrefix("/api"),
 	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 提交套利机会执行（等待执行结束后返回结果）
+					Method:  http.MethodPost,
+					Path:    "/executions",
+					Handler: submitExecutionHandler(serverCtx),
+				},
+				{
+					// 手动下单
+					Method:  http.MethodPost,
+					Path:    "/orders",
+					Handler: placeOrderHandler(serverCtx),
+				},
+				{
+					// 撤单
+					Method:  http.MethodDelete,
+					Path:    "/orders/:id",
+					Handler: cancelOrderHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api"),
+	)
 }
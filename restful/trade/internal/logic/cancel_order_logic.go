@@ -0,0 +1,48 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"fmt"
+
+	"arbitragex/restful/trade/internal/svc"
+	"arbitragex/restful/trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CancelOrderLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 撤单
+func NewCancelOrderLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CancelOrderLogic {
+	return &CancelOrderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CancelOrder 撤单并返回撤单后的订单状态
+func (l *CancelOrderLogic) CancelOrder(req *types.OrderRequest) (resp *types.Order, err error) {
+	executor, exchange, err := orderExecutor(l.svcCtx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := executor.CancelOrder(l.ctx, exchange, req.Id); err != nil {
+		return nil, err
+	}
+	l.Infof("已撤单: %s %s", exchange, req.Id)
+
+	order, err := executor.QueryOrder(l.ctx, exchange, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("已撤单，但查询订单状态失败: %w", err)
+	}
+	return toOrder(l.svcCtx.TrackOrder(order)), nil
+}
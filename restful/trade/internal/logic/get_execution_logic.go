@@ -0,0 +1,36 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"arbitragex/restful/trade/internal/svc"
+	"arbitragex/restful/trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetExecutionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询套利执行结果详情（包含买卖订单）
+func NewGetExecutionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetExecutionLogic {
+	return &GetExecutionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetExecutionLogic) GetExecution(req *types.ExecutionRequest) (resp *types.ExecutionResult, err error) {
+	result, err := l.svcCtx.Store.Executions.Get(l.ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return toExecutionResult(result), nil
+}
@@ -0,0 +1,62 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"arbitragex/pkg/execution"
+	"arbitragex/restful/trade/internal/svc"
+	"arbitragex/restful/trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetOrderLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询订单最新状态
+func NewGetOrderLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetOrderLogic {
+	return &GetOrderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetOrder 从交易所查询订单的最新状态
+func (l *GetOrderLogic) GetOrder(req *types.OrderRequest) (resp *types.Order, err error) {
+	executor, exchange, err := orderExecutor(l.svcCtx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := executor.QueryOrder(l.ctx, exchange, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return toOrder(l.svcCtx.TrackOrder(order)), nil
+}
+
+// orderExecutor 查找订单所在交易所的执行器（本服务跟踪的订单使用记录的交易所，否则使用请求中的交易所）
+func orderExecutor(svcCtx *svc.ServiceContext, req *types.OrderRequest) (execution.OrderExecutor, string, error) {
+	exchange := req.Exchange
+	if order, ok := svcCtx.Orders.Get(req.Id); ok {
+		exchange = order.Exchange
+	}
+	if exchange == "" {
+		return nil, "", fmt.Errorf("订单 %s 不是本服务下的订单，需要指定交易所", req.Id)
+	}
+
+	executor, err := svcCtx.OrderExecutor(exchange)
+	if err != nil {
+		return nil, "", err
+	}
+	return executor, strings.ToLower(exchange), nil
+}
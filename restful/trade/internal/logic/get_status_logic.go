@@ -0,0 +1,53 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"sort"
+
+	"arbitragex/restful/trade/internal/svc"
+	"arbitragex/restful/trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetStatusLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询执行器状态
+func NewGetStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetStatusLogic {
+	return &GetStatusLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetStatusLogic) GetStatus() (resp *types.ExecutorStatus, err error) {
+	status := l.svcCtx.Executor.GetStatus()
+
+	exchanges := make([]string, 0, len(l.svcCtx.Executors))
+	for name := range l.svcCtx.Executors {
+		exchanges = append(exchanges, name)
+	}
+	sort.Strings(exchanges)
+
+	return &types.ExecutorStatus{
+		Running:          status.Running,
+		Exchanges:        exchanges,
+		Paper:            l.svcCtx.Config.Executor.Paper,
+		ActiveExecutions: status.ActiveExecutions,
+		MaxConcurrent:    status.MaxConcurrent,
+		QueuedTasks:      status.QueuedTasks,
+		TotalExecuted:    status.TotalExecuted,
+		TotalFailed:      status.TotalFailed,
+		TotalSuccess:     status.TotalSuccess,
+		TotalProfit:      status.TotalProfit.String(),
+		StartTime:        unixMilli(status.StartTime),
+	}, nil
+}
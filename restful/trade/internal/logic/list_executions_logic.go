@@ -0,0 +1,44 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"arbitragex/restful/trade/internal/svc"
+	"arbitragex/restful/trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListExecutionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询套利执行结果
+func NewListExecutionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListExecutionsLogic {
+	return &ListExecutionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListExecutionsLogic) ListExecutions(req *types.ExecutionListRequest) (resp *types.ExecutionListResponse, err error) {
+	results, err := l.svcCtx.Store.Executions.ListByStatus(l.ctx, req.Status, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &types.ExecutionListResponse{
+		Executions: make([]types.ExecutionResult, 0, len(results)),
+		Total:      len(results),
+	}
+	for _, result := range results {
+		resp.Executions = append(resp.Executions, *toExecutionResult(result))
+	}
+	return resp, nil
+}
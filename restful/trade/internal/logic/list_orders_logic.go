@@ -0,0 +1,55 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"arbitragex/restful/trade/internal/svc"
+	"arbitragex/restful/trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListOrdersLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询订单
+func NewListOrdersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListOrdersLogic {
+	return &ListOrdersLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListOrders 返回本服务下单和执行产生的订单（按创建时间倒序）
+func (l *ListOrdersLogic) ListOrders(req *types.OrderListRequest) (resp *types.OrderListResponse, err error) {
+	exchange := strings.ToLower(req.Exchange)
+	symbol := normalizeSymbol(req.Symbol)
+
+	resp = &types.OrderListResponse{
+		Orders: make([]types.Order, 0),
+	}
+	for _, order := range l.svcCtx.Orders.List() {
+		if exchange != "" && order.Exchange != exchange {
+			continue
+		}
+		if symbol != "" && order.Symbol != symbol {
+			continue
+		}
+		if req.Status != "" && order.Status != req.Status {
+			continue
+		}
+		resp.Total++
+		if len(resp.Orders) < req.Limit {
+			resp.Orders = append(resp.Orders, *toOrder(order))
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,69 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"arbitragex/pkg/execution"
+	"arbitragex/restful/trade/internal/svc"
+	"arbitragex/restful/trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PlaceOrderLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 手动下单
+func NewPlaceOrderLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PlaceOrderLogic {
+	return &PlaceOrderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// PlaceOrder 手动下单（参数由交易所执行器校验）
+func (l *PlaceOrderLogic) PlaceOrder(req *types.PlaceOrderRequest) (resp *types.Order, err error) {
+	price, err := parseDecimal("price", req.Price)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseDecimal("amount", req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	quoteAmount, err := parseDecimal("quote_amount", req.QuoteAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	executor, err := l.svcCtx.OrderExecutor(req.Exchange)
+	if err != nil {
+		return nil, err
+	}
+	order, err := executor.PlaceOrder(l.ctx, &execution.PlaceOrderRequest{
+		Exchange:      strings.ToLower(req.Exchange),
+		Symbol:        normalizeSymbol(req.Symbol),
+		Side:          req.Side,
+		Type:          req.Type,
+		Price:         price,
+		Amount:        amount,
+		QuoteAmount:   quoteAmount,
+		TimeInForce:   strings.ToLower(req.TimeInForce),
+		PostOnly:      req.PostOnly,
+		ClientOrderID: req.ClientOrderId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("手动下单: %s %s %s %s, 订单ID: %s, 状态: %s", order.Exchange, order.Symbol, order.Side, order.Type, order.ID, order.Status)
+	return toOrder(l.svcCtx.TrackOrder(order)), nil
+}
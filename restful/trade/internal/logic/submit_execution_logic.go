@@ -0,0 +1,70 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"arbitragex/pkg/execution"
+	"arbitragex/restful/trade/internal/svc"
+	"arbitragex/restful/trade/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SubmitExecutionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 提交套利机会执行（等待执行结束后返回结果）
+func NewSubmitExecutionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SubmitExecutionLogic {
+	return &SubmitExecutionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SubmitExecution 提交套利机会并等待执行结束（请求中断或超时后执行仍会继续，结果通过 /api/executions 查询）
+func (l *SubmitExecutionLogic) SubmitExecution(req *types.SubmitExecutionRequest) (resp *types.ExecutionResult, err error) {
+	buyPrice, err := parseDecimal("buy_price", req.BuyPrice)
+	if err != nil {
+		return nil, err
+	}
+	sellPrice, err := parseDecimal("sell_price", req.SellPrice)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseDecimal("amount", req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	netProfit, err := parseDecimal("net_profit", req.NetProfit)
+	if err != nil {
+		return nil, err
+	}
+
+	opp := &execution.ArbitrageOpportunity{
+		ID:           req.OpportunityId,
+		Symbol:       normalizeSymbol(req.Symbol),
+		BuyExchange:  strings.ToLower(req.BuyExchange),
+		SellExchange: strings.ToLower(req.SellExchange),
+		BuyPrice:     buyPrice,
+		SellPrice:    sellPrice,
+		PriceDiff:    sellPrice.Sub(buyPrice),
+		NetProfit:    netProfit,
+		DiscoveredAt: time.Now(),
+	}
+	l.Infof("提交套利执行: %s %s -> %s, 金额 %s USDT", opp.Symbol, opp.BuyExchange, opp.SellExchange, amount)
+
+	result, err := l.svcCtx.ExecuteOpportunity(l.ctx, opp, amount)
+	if err != nil {
+		return nil, err
+	}
+	return toExecutionResult(result), nil
+}
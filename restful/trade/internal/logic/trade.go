@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/execution"
+	"arbitragex/restful/trade/internal/types"
+)
+
+// toOrder 转换为接口返回的订单
+func toOrder(o *execution.Order) *types.Order {
+	if o == nil {
+		return nil
+	}
+
+	return &types.Order{
+		Id:              o.ID,
+		Exchange:        o.Exchange,
+		Symbol:          o.Symbol,
+		Side:            o.Side,
+		Type:            o.Type,
+		TimeInForce:     o.TimeInForce,
+		PostOnly:        o.PostOnly,
+		Price:           o.Price.String(),
+		Amount:          o.Amount.String(),
+		FilledAmount:    o.FilledAmount.String(),
+		AveragePrice:    o.AveragePrice.String(),
+		Fee:             o.Fee.String(),
+		FeeCurrency:     o.FeeCurrency,
+		Status:          o.Status,
+		ExchangeOrderId: o.ExchangeOrderID,
+		ClientOrderId:   o.ClientOrderID,
+		ErrorMessage:    o.ErrorMessage,
+		CreatedAt:       unixMilli(o.CreatedAt),
+		UpdatedAt:       unixMilli(o.UpdatedAt),
+	}
+}
+
+// toExecutionResult 转换为接口返回的执行结果
+func toExecutionResult(r *execution.ExecutionResult) *types.ExecutionResult {
+	return &types.ExecutionResult{
+		Id:            r.ID,
+		OpportunityId: r.OpportunityID,
+		Symbol:        r.Symbol,
+		BuyExchange:   r.BuyExchange,
+		SellExchange:  r.SellExchange,
+		TradingAmount: r.TradingAmount.String(),
+		BuyOrder:      toOrder(r.BuyOrder),
+		SellOrder:     toOrder(r.SellOrder),
+		EstProfit:     r.EstProfit.String(),
+		ActualProfit:  r.ActualProfit.String(),
+		Status:        r.Status,
+		ErrorMessage:  r.ErrorMessage,
+		StartedAt:     unixMilli(r.StartedAt),
+		CompletedAt:   unixMilli(r.CompletedAt),
+	}
+}
+
+// unixMilli 毫秒时间戳（零值时间为 0）
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// parseDecimal 解析请求中的十进制字符串（为空时为 0）
+func parseDecimal(field, value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	v, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%s 无效: %w", field, err)
+	}
+	return v, nil
+}
+
+// normalizeSymbol 将请求中的交易对转换为标准格式（BTC-USDT、btc_usdt -> BTC/USDT）
+func normalizeSymbol(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	return strings.NewReplacer("-", "/", "_", "/").Replace(symbol)
+}
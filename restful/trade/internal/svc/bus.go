@@ -0,0 +1,54 @@
+package svc
+
+import (
+	"context"
+	"time"
+
+	"arbitragex/pkg/bus"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/execution"
+)
+
+// executeOpportunity 执行机会主题中新出现的套利机会
+// 只处理 opened 事件；机会出现超过 AutoExecute.MaxAge 后不再执行，确认超时后重新投递的消息因此不会重复执行；
+// 未通过风控检查的机会不执行。执行器受理后即确认消息，不等待执行结束（并发度由执行器的 MaxConcurrent 控制），
+// 执行结果通过 publishResult 发布
+func (s *ServiceContext) executeOpportunity(ctx context.Context, msg *bus.Message) error {
+	var event engine.OpportunityEvent
+	if err := msg.Decode(&event); err != nil {
+		// 格式错误的消息重试也无法处理，直接确认
+		s.logger.Errorf("解析套利机会消息 %s 失败: %v", msg.ID, err)
+		return nil
+	}
+	if event.Type != engine.OpportunityOpened || event.Opportunity == nil {
+		return nil
+	}
+
+	opp := execution.FromEngineOpportunity(event.Opportunity)
+	if age := time.Since(event.Time); age > s.Config.AutoExecute.MaxAge {
+		s.logger.Infof("跳过过期的套利机会 %s（已出现 %s）", opp.ID, age)
+		return nil
+	}
+
+	if err := s.Risk.Check(opp, s.autoAmount); err != nil {
+		s.logger.Infof("套利机会 %s 未执行: %v", opp.ID, err)
+		return nil
+	}
+
+	s.logger.Infof("自动执行套利机会 %s: %s %s -> %s, 金额 %s USDT", opp.ID, opp.Symbol, opp.BuyExchange, opp.SellExchange, s.autoAmount)
+	// 执行不随消费的 ctx 取消
+	if _, err := s.SubmitOpportunity(context.WithoutCancel(ctx), opp, s.autoAmount); err != nil {
+		// 执行未被受理，释放风控预留的交易金额；机会很快会失效，不重新投递
+		s.Risk.Release(opp, s.autoAmount)
+		s.logger.Errorf("提交套利机会 %s 失败: %v", opp.ID, err)
+		return nil
+	}
+	return nil
+}
+
+// publishResult 将执行结果发布到执行结果主题（ResultHook）
+func (s *ServiceContext) publishResult(result *execution.ExecutionResult) {
+	if _, err := bus.PublishJSON(context.Background(), s.Bus, bus.TopicExecutions, result); err != nil {
+		s.logger.Errorf("发布执行结果 %s 失败: %v", result.ID, err)
+	}
+}
@@ -4,15 +4,204 @@
 package svc
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/bus"
+	"arbitragex/pkg/execution"
+	"arbitragex/pkg/risk"
+	"arbitragex/pkg/store"
 	"arbitragex/restful/trade/internal/config"
+	"arbitragex/restful/trade/internal/middleware"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest"
 )
 
 type ServiceContext struct {
-	Config config.Config
+	Config    config.Config
+	Auth      rest.Middleware
+	Executors map[string]execution.OrderExecutor
+	Executor  *execution.DefaultConcurrentExecutor
+	Orders    *execution.OrderTracker
+	Risk      *risk.Checker // 自动执行前的风控
+	Store     *store.Store
+	Bus       bus.Bus // 消息总线（未配置时为 nil）
+
+	autoAmount decimal.Decimal    // 自动执行的金额
+	cancel     context.CancelFunc // 停止消费套利机会
+	journal    *execution.FileJournal
+	logger     logx.Logger
 }
 
+// NewServiceContext 创建服务上下文
+// 按配置为每个交易所创建订单执行器（模拟交易或真实交易），调用 Start 后开始接收执行任务
 func NewServiceContext(c config.Config) *ServiceContext {
-	return &ServiceContext{
-		Config: c,
+	executors, err := c.Exchanges.NewExecutors(c.Executor, c.Credentials)
+	logx.Must(err)
+
+	return NewServiceContextWithExecutors(c, executors)
+}
+
+// NewServiceContextWithExecutors 使用指定的订单执行器创建服务上下文（exchange -> OrderExecutor）
+// 执行结果保存到仓储（配置了 MySQL 时为 MySQL，否则为内存），执行和下单产生的订单由订单跟踪器记录，
+// 私有数据流推送的订单状态也同步到订单跟踪器
+func NewServiceContextWithExecutors(c config.Config, executors map[string]execution.OrderExecutor) *ServiceContext {
+	executor, journal, err := c.Executor.NewConcurrentExecutor(executors)
+	logx.Must(err)
+	limits, err := c.Risk.Limits()
+	logx.Must(err)
+
+	s := &ServiceContext{
+		Config:    c,
+		Auth:      middleware.NewAuthMiddleware(c.Auth.Token).Handle,
+		Executors: executors,
+		Executor:  executor,
+		Orders:    execution.NewOrderTracker(),
+		Risk:      risk.NewChecker(limits),
+		Store:     c.MySQL.NewStore(),
+		journal:   journal,
+		logger:    logx.WithContext(context.Background()),
+	}
+	if c.Bus.Enabled() {
+		s.Bus = bus.MustNewRedisBus(c.Bus)
+	}
+	if c.AutoExecute.Enabled {
+		amount, err := decimal.NewFromString(c.AutoExecute.Amount)
+		logx.Must(err)
+		s.autoAmount = amount
+	}
+	s.Executor.OnResult(s.recordResult)
+	s.Executor.OnResult(s.Risk.Record)
+	for _, executor := range executors {
+		// 执行器跟踪的订单（下单响应、查询和私有数据流推送）同步到 Orders
+		if streaming, ok := executor.(execution.UserStreamExecutor); ok {
+			streaming.Tracker().OnTransition(func(order *execution.Order, _ execution.OrderTransition) {
+				s.TrackOrder(order)
+			})
+		}
+	}
+
+	if c.Auth.Token == "" {
+		s.logger.Error("未配置 Auth.Token，交易类接口将拒绝所有请求")
+	}
+	return s
+}
+
+// Start 启动执行器（配置了执行日志时先恢复上次未完成的执行）
+// 配置了消息总线时执行结果发布到执行结果主题，开启自动执行时订阅机会主题
+func (s *ServiceContext) Start(ctx context.Context) error {
+	if s.Bus != nil {
+		s.Executor.OnResult(s.publishResult)
+	}
+	if err := s.Executor.Start(ctx); err != nil {
+		return err
+	}
+
+	if s.Config.AutoExecute.Enabled {
+		if s.Bus == nil {
+			s.logger.Error("未配置 Bus，自动执行不会生效")
+			return nil
+		}
+		// 所有交易服务实例共用一个消费组，每个机会只由一个实例执行
+		ctx, s.cancel = context.WithCancel(ctx)
+		if err := s.Bus.Subscribe(ctx, bus.TopicOpportunities, "trade", s.Config.Bus.ConsumerName(), s.executeOpportunity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop 停止消费套利机会，停止执行器后关闭消息总线（执行中的结果仍会发布）和执行日志
+func (s *ServiceContext) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.Executor.Stop()
+	if s.Bus != nil {
+		s.Bus.Close()
+	}
+	if s.journal != nil {
+		s.journal.Close()
+	}
+}
+
+// ExecuteOpportunity 执行套利机会并等待执行结果，执行器受理后标记机会已执行
+// 参数:
+//   - ctx: 上下文对象（取消或超时后执行仍会继续）
+//   - opp: 套利机会
+//   - amount: 交易金额（USDT）
+// 返回:
+//   - *execution.ExecutionResult: 执行结果
+//   - error: 队列已满、等待取消或超时时返回
+func (s *ServiceContext) ExecuteOpportunity(ctx context.Context, opp *execution.ArbitrageOpportunity, amount decimal.Decimal) (*execution.ExecutionResult, error) {
+	results, err := s.SubmitOpportunity(ctx, opp, amount)
+	if err != nil {
+		return nil, err
+	}
+	return s.Executor.Await(ctx, results)
+}
+
+// SubmitOpportunity 提交套利机会，执行器受理后（未因队列已满被拒绝）标记机会已执行，不等待执行结束
+// 参数:
+//   - ctx: 上下文对象（取消后执行仍会继续）
+//   - opp: 套利机会
+//   - amount: 交易金额（USDT）
+// 返回:
+//   - <-chan *execution.ExecutionResult: 执行结束后收到执行结果
+//   - error: 队列已满时返回 *execution.QueueFullError
+func (s *ServiceContext) SubmitOpportunity(ctx context.Context, opp *execution.ArbitrageOpportunity, amount decimal.Decimal) (<-chan *execution.ExecutionResult, error) {
+	results, err := s.Executor.Submit(ctx, opp, amount)
+	if err != nil {
+		return nil, err
+	}
+	s.markExecuted(opp.ID)
+	return results, nil
+}
+
+// markExecuted 标记套利机会已执行（机会由套利引擎服务记录，不在仓储中时忽略）
+func (s *ServiceContext) markExecuted(opportunityID string) {
+	if opportunityID == "" {
+		return
+	}
+	err := s.Store.Opportunities.MarkExecuted(context.Background(), opportunityID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		s.logger.Errorf("标记套利机会 %s 已执行失败: %v", opportunityID, err)
+	}
+}
+
+// OrderExecutor 获取交易所的订单执行器
+func (s *ServiceContext) OrderExecutor(exchange string) (execution.OrderExecutor, error) {
+	executor, ok := s.Executors[strings.ToLower(exchange)]
+	if !ok {
+		return nil, fmt.Errorf("未配置交易所执行器: %s", exchange)
+	}
+	return executor, nil
+}
+
+// TrackOrder 记录订单的最新状态（经过订单状态机，乱序的旧状态会被忽略）
+// 返回:
+//   - *execution.Order: 记录后的最新状态
+func (s *ServiceContext) TrackOrder(order *execution.Order) *execution.Order {
+	if order == nil || order.ID == "" {
+		return order
+	}
+	merged, _ := s.Orders.Apply(order)
+	if merged == nil {
+		return order
+	}
+	return merged
+}
+
+// recordResult 保存执行结果并记录买卖订单
+func (s *ServiceContext) recordResult(result *execution.ExecutionResult) {
+	for _, order := range []*execution.Order{result.BuyOrder, result.SellOrder} {
+		s.TrackOrder(order)
+	}
+	if err := s.Store.Executions.Save(context.Background(), result); err != nil {
+		s.logger.Errorf("保存执行结果 %s 失败: %v", result.ID, err)
 	}
 }
@@ -3,8 +3,116 @@
 
 package types
 
+type ExecutionListRequest struct {
+	Status string `form:"status,optional"` // 执行状态（executing, completed, failed，为空时返回所有状态）
+	Limit  int    `form:"limit,default=100,range=[1:1000]"`
+}
+
+type ExecutionListResponse struct {
+	Executions []ExecutionResult `json:"executions"`
+	Total      int               `json:"total"`
+}
+
+type ExecutionRequest struct {
+	Id string `path:"id"`
+}
+
+type ExecutionResult struct {
+	Id            string `json:"id"`
+	OpportunityId string `json:"opportunity_id"`
+	Symbol        string `json:"symbol"`
+	BuyExchange   string `json:"buy_exchange"`
+	SellExchange  string `json:"sell_exchange"`
+	TradingAmount string `json:"trading_amount"`
+	BuyOrder      *Order `json:"buy_order,omitempty"`
+	SellOrder     *Order `json:"sell_order,omitempty"`
+	EstProfit     string `json:"est_profit"`
+	ActualProfit  string `json:"actual_profit"`
+	Status        string `json:"status"`
+	ErrorMessage  string `json:"error_message,omitempty"`
+	StartedAt     int64  `json:"started_at"`   // 毫秒时间戳
+	CompletedAt   int64  `json:"completed_at"` // 毫秒时间戳（0 表示未完成）
+}
+
+type ExecutorStatus struct {
+	Running          bool     `json:"running"`
+	Exchanges        []string `json:"exchanges"`
+	Paper            bool     `json:"paper"` // 是否为模拟交易
+	ActiveExecutions int      `json:"active_executions"`
+	MaxConcurrent    int      `json:"max_concurrent"`
+	QueuedTasks      int      `json:"queued_tasks"`
+	TotalExecuted    int64    `json:"total_executed"`
+	TotalFailed      int64    `json:"total_failed"`
+	TotalSuccess     int64    `json:"total_success"`
+	TotalProfit      string   `json:"total_profit"`
+	StartTime        int64    `json:"start_time"` // 毫秒时间戳
+}
+
 type HealthCheckResponse struct {
 	Status  string `json:"status"`
 	Uptime  int64  `json:"uptime"`
 	Version string `json:"version"`
 }
+
+type Order struct {
+	Id              string `json:"id"`
+	Exchange        string `json:"exchange"`
+	Symbol          string `json:"symbol"`
+	Side            string `json:"side"`
+	Type            string `json:"type"`
+	TimeInForce     string `json:"time_in_force,omitempty"`
+	PostOnly        bool   `json:"post_only,omitempty"`
+	Price           string `json:"price"`
+	Amount          string `json:"amount"`
+	FilledAmount    string `json:"filled_amount"`
+	AveragePrice    string `json:"average_price"`
+	Fee             string `json:"fee"`
+	FeeCurrency     string `json:"fee_currency"`
+	Status          string `json:"status"`
+	ExchangeOrderId string `json:"exchange_order_id"`
+	ClientOrderId   string `json:"client_order_id"`
+	ErrorMessage    string `json:"error_message,omitempty"`
+	CreatedAt       int64  `json:"created_at"` // 毫秒时间戳
+	UpdatedAt       int64  `json:"updated_at"` // 毫秒时间戳
+}
+
+type OrderListRequest struct {
+	Exchange string `form:"exchange,optional"`
+	Symbol   string `form:"symbol,optional"` // 交易对（如 BTC/USDT 或 BTC-USDT）
+	Status   string `form:"status,optional"` // 订单状态（pending, open, partially_filled, filled, canceled, failed）
+	Limit    int    `form:"limit,default=100,range=[1:1000]"`
+}
+
+type OrderListResponse struct {
+	Orders []Order `json:"orders"`
+	Total  int     `json:"total"`
+}
+
+type OrderRequest struct {
+	Id       string `path:"id"`                // 订单ID
+	Exchange string `form:"exchange,optional"` // 交易所（订单不是本服务下的时必填）
+}
+
+type PlaceOrderRequest struct {
+	Exchange      string `json:"exchange"`
+	Symbol        string `json:"symbol"` // 交易对（如 BTC/USDT）
+	Side          string `json:"side,options=buy|sell"`
+	Type          string `json:"type,options=limit|market"`
+	Price         string `json:"price,optional"`         // 价格（限价单必填）
+	Amount        string `json:"amount,optional"`        // 数量（基础货币）
+	QuoteAmount   string `json:"quote_amount,optional"`  // 按计价货币下单的金额（仅市价单，与 amount 二选一）
+	TimeInForce   string `json:"time_in_force,optional"` // 有效方式（gtc, ioc, fok）
+	PostOnly      bool   `json:"post_only,optional"`
+	ClientOrderId string `json:"client_order_id,optional"`
+}
+
+type SubmitExecutionRequest struct {
+	OpportunityId string `json:"opportunity_id,optional"` // 套利机会 ID（引擎服务返回的 ID）
+	Symbol        string `json:"symbol"`
+	BuyExchange   string `json:"buy_exchange"`
+	SellExchange  string `json:"sell_exchange"`
+	BuyPrice      string `json:"buy_price"`           // 买入限价
+	SellPrice     string `json:"sell_price"`          // 卖出限价
+	Amount        string `json:"amount"`              // 交易金额（USDT）
+	NetProfit     string `json:"net_profit,optional"` // 预期净收益（USDT）
+}
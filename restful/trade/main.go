@@ -1,14 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 
+	"arbitragex/pkg/settings"
 	"arbitragex/restful/trade/internal/config"
 	"arbitragex/restful/trade/internal/handler"
 	"arbitragex/restful/trade/internal/svc"
 
-	"github.com/zeromicro/go-zero/core/conf"
+	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/rest"
 )
 
@@ -16,20 +18,25 @@ var configFile = flag.String("f", "etc/trade-api.yaml", "the config file")
 
 // main 交易执行服务的主入口函数
 // 职责：
-//  1. 加载配置文件
-//  2. 初始化 REST 服务器
-//  3. 注册路由（使用 goctl 生成的 RegisterHandlers）
-//  4. 启动交易执行服务
+//  1. 加载配置文件（支持 ${ENV} 引用环境变量，如访问令牌和 API 密钥）
+//  2. 创建交易所执行器，恢复上次未完成的执行
+//  3. 初始化 REST 服务器
+//  4. 注册路由（使用 goctl 生成的 RegisterHandlers）
+//  5. 启动交易执行服务
 func main() {
 	flag.Parse()
 
-	// 加载配置文件
+	// 加载并校验配置文件
 	var c config.Config
-	conf.MustLoad(*configFile, &c)
+	settings.MustLoad(*configFile, &c)
 
 	// 创建服务上下文
 	ctx := svc.NewServiceContext(c)
 
+	// 启动执行器（恢复未完成的执行失败时退出，避免与未确认的订单冲突）
+	logx.Must(ctx.Start(context.Background()))
+	defer ctx.Stop()
+
 	// 创建 REST 服务器
 	server := rest.MustNewServer(c.RestConf)
 	defer server.Stop()
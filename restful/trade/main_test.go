@@ -1,10 +1,327 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"arbitragex/common/decimal"
+	"arbitragex/pkg/bus"
+	"arbitragex/pkg/engine"
+	"arbitragex/pkg/execution"
+	"arbitragex/pkg/settings"
+	"arbitragex/restful/trade/internal/config"
+	"arbitragex/restful/trade/internal/handler"
+	"arbitragex/restful/trade/internal/svc"
+	"arbitragex/restful/trade/internal/types"
+
+	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/rest/pathvar"
 )
 
 // TestMainCompilation 测试主函数可以正常编译
 func TestMainCompilation(t *testing.T) {
 	t.Log("Main function compilation test passed")
 }
+
+const testToken = "test-token"
+
+// testConfig 测试配置
+func testConfig() config.Config {
+	var c config.Config
+	c.Host, c.Port = "127.0.0.1", 0
+	c.Auth.Token = testToken
+	c.Exchanges = settings.Exchanges{
+		{Name: "binance", Type: "cex", Enabled: true, MakerFee: 0.001, TakerFee: 0.001},
+		{Name: "okx", Type: "cex", Enabled: true, MakerFee: 0.001, TakerFee: 0.001},
+	}
+	c.Executor = settings.ExecutorConf{Paper: true, MaxConcurrent: 2, QueueSize: 100}
+	return c
+}
+
+// testExecutors 创建模拟执行器（binance 卖一 50000，okx 买一 50100）
+func testExecutors() map[string]execution.OrderExecutor {
+	level := func(price, amount string) []execution.OrderBookLevel {
+		return []execution.OrderBookLevel{{Price: decimal.RequireFromString(price), Amount: decimal.RequireFromString(amount)}}
+	}
+	binance := execution.NewPaperExecutor("binance", 0.001, 0.001)
+	binance.UpdateOrderBook(&execution.OrderBook{Exchange: "binance", Symbol: "BTC/USDT", Bids: level("49990", "1"), Asks: level("50000", "1")})
+	okx := execution.NewPaperExecutor("okx", 0.001, 0.001)
+	okx.UpdateOrderBook(&execution.OrderBook{Exchange: "okx", Symbol: "BTC/USDT", Bids: level("50100", "1"), Asks: level("50110", "1")})
+	return map[string]execution.OrderExecutor{"binance": binance, "okx": okx}
+}
+
+// newTestServer 创建使用模拟执行器的服务
+func newTestServer(t *testing.T) (*rest.Server, *svc.ServiceContext) {
+	t.Helper()
+
+	c := testConfig()
+	svcCtx := svc.NewServiceContextWithExecutors(c, testExecutors())
+	if err := svcCtx.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(svcCtx.Stop)
+
+	server := rest.MustNewServer(c.RestConf)
+	handler.RegisterHandlers(server, svcCtx)
+	return server, svcCtx
+}
+
+// serve 调用路由对应的处理器（经过中间件）并解析 JSON 响应
+func serve(t *testing.T, server *rest.Server, method, path, target, token string, body interface{}, vars map[string]string, resp interface{}) int {
+	t.Helper()
+
+	for _, route := range server.Routes() {
+		if route.Method != method || route.Path != path {
+			continue
+		}
+		var data []byte
+		if body != nil {
+			data, _ = json.Marshal(body)
+		}
+		r := httptest.NewRequest(method, target, bytes.NewReader(data))
+		r.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		if vars != nil {
+			r = pathvar.WithVars(r, vars)
+		}
+		w := httptest.NewRecorder()
+		route.Handler(w, r)
+		if w.Code == http.StatusOK && resp != nil {
+			if err := json.Unmarshal(w.Body.Bytes(), resp); err != nil {
+				t.Fatalf("%s: invalid JSON %s: %v", target, w.Body.String(), err)
+			}
+		}
+		return w.Code
+	}
+	t.Fatalf("route %s %s not registered", method, path)
+	return 0
+}
+
+// TestAuth 测试交易类接口需要访问令牌，查询类接口不需要
+func TestAuth(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	order := types.PlaceOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", Type: "market", Amount: "0.1"}
+	for _, token := range []string{"", "wrong-token"} {
+		if code := serve(t, server, http.MethodPost, "/api/orders", "/api/orders", token, order, nil, nil); code != http.StatusUnauthorized {
+			t.Errorf("POST /api/orders with token %q status = %d, want 401", token, code)
+		}
+		if code := serve(t, server, http.MethodPost, "/api/executions", "/api/executions", token, nil, nil, nil); code != http.StatusUnauthorized {
+			t.Errorf("POST /api/executions with token %q status = %d, want 401", token, code)
+		}
+		vars := map[string]string{"id": "binance:BTCUSDT:1"}
+		if code := serve(t, server, http.MethodDelete, "/api/orders/:id", "/api/orders/binance:BTCUSDT:1", token, nil, vars, nil); code != http.StatusUnauthorized {
+			t.Errorf("DELETE /api/orders/:id with token %q status = %d, want 401", token, code)
+		}
+	}
+
+	var status types.ExecutorStatus
+	if code := serve(t, server, http.MethodGet, "/api/status", "/api/status", "", nil, nil, &status); code != http.StatusOK {
+		t.Fatalf("GET /api/status status = %d", code)
+	}
+	if !status.Running || !status.Paper || len(status.Exchanges) != 2 || status.MaxConcurrent != 2 {
+		t.Errorf("status = %+v, want running paper executor with binance and okx", status)
+	}
+}
+
+// TestOrderAPI 测试手动下单、查询订单列表和撤单
+func TestOrderAPI(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var market types.Order
+	req := types.PlaceOrderRequest{Exchange: "BINANCE", Symbol: "BTC-USDT", Side: "buy", Type: "market", Amount: "0.1"}
+	if code := serve(t, server, http.MethodPost, "/api/orders", "/api/orders", testToken, req, nil, &market); code != http.StatusOK {
+		t.Fatalf("POST /api/orders status = %d", code)
+	}
+	if market.Status != execution.OrderStatusFilled || market.Symbol != "BTC/USDT" || market.AveragePrice != "50000" {
+		t.Errorf("market order = %+v, want filled at 50000", market)
+	}
+
+	var limit types.Order
+	req = types.PlaceOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", Type: "limit", Price: "49000", Amount: "0.2", PostOnly: true}
+	serve(t, server, http.MethodPost, "/api/orders", "/api/orders", testToken, req, nil, &limit)
+	if limit.Status != execution.OrderStatusOpen {
+		t.Fatalf("limit order = %+v, want open", limit)
+	}
+
+	req = types.PlaceOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", Type: "limit", Amount: "0.2", TimeInForce: "day"}
+	if code := serve(t, server, http.MethodPost, "/api/orders", "/api/orders", testToken, req, nil, nil); code == http.StatusOK {
+		t.Error("POST /api/orders with invalid time_in_force status = 200, want error")
+	}
+
+	var list types.OrderListResponse
+	serve(t, server, http.MethodGet, "/api/orders", "/api/orders?status=open", "", nil, nil, &list)
+	if list.Total != 1 || list.Orders[0].Id != limit.Id {
+		t.Errorf("orders?status=open = %+v, want %s", list.Orders, limit.Id)
+	}
+
+	vars := map[string]string{"id": limit.Id}
+	var canceled types.Order
+	if code := serve(t, server, http.MethodDelete, "/api/orders/:id", "/api/orders/"+limit.Id, testToken, nil, vars, &canceled); code != http.StatusOK {
+		t.Fatalf("DELETE /api/orders/%s status = %d", limit.Id, code)
+	}
+	if canceled.Status != execution.OrderStatusCanceled {
+		t.Errorf("canceled order = %+v, want canceled", canceled)
+	}
+
+	var order types.Order
+	serve(t, server, http.MethodGet, "/api/orders/:id", "/api/orders/"+limit.Id, "", nil, vars, &order)
+	if order.Status != execution.OrderStatusCanceled {
+		t.Errorf("GET /api/orders/%s status = %s, want canceled", limit.Id, order.Status)
+	}
+
+	vars = map[string]string{"id": "unknown"}
+	if code := serve(t, server, http.MethodGet, "/api/orders/:id", "/api/orders/unknown", "", nil, vars, nil); code == http.StatusOK {
+		t.Error("GET /api/orders/unknown without exchange status = 200, want error")
+	}
+
+	serve(t, server, http.MethodGet, "/api/orders", "/api/orders?exchange=binance&symbol=BTC-USDT&limit=1", "", nil, nil, &list)
+	if list.Total != 2 || len(list.Orders) != 1 {
+		t.Errorf("orders?exchange=binance&limit=1 = %d of %d, want 1 of 2", len(list.Orders), list.Total)
+	}
+}
+
+// TestExecutionAPI 测试提交套利执行、查询执行结果和执行产生的订单
+func TestExecutionAPI(t *testing.T) {
+	server, svcCtx := newTestServer(t)
+
+	submit := types.SubmitExecutionRequest{
+		OpportunityId: "BTC/USDT_binance_okx_1",
+		Symbol:        "BTC/USDT",
+		BuyExchange:   "binance",
+		SellExchange:  "okx",
+		BuyPrice:      "50000",
+		SellPrice:     "50100",
+		Amount:        "5000",
+		NetProfit:     "0.01",
+	}
+	// 套利引擎服务记录的机会，受理执行后标记为已执行
+	recorded := &engine.ArbitrageOpportunity{ID: submit.OpportunityId, Symbol: submit.Symbol, BuyExchange: "binance", SellExchange: "okx", DiscoveredAt: time.Now()}
+	if err := svcCtx.Store.Opportunities.Save(context.Background(), recorded); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	var result types.ExecutionResult
+	if code := serve(t, server, http.MethodPost, "/api/executions", "/api/executions", testToken, submit, nil, &result); code != http.StatusOK {
+		t.Fatalf("POST /api/executions status = %d", code)
+	}
+	if result.Status != execution.ExecutionStatusCompleted || result.OpportunityId != submit.OpportunityId {
+		t.Fatalf("result = %+v, want completed", result)
+	}
+	if result.BuyOrder == nil || result.BuyOrder.FilledAmount != "0.1" || result.SellOrder == nil || result.SellOrder.AveragePrice != "50100" {
+		t.Errorf("orders = %+v / %+v, want 0.1 BTC bought at 50000 and sold at 50100", result.BuyOrder, result.SellOrder)
+	}
+	if record, err := svcCtx.Store.Opportunities.Get(context.Background(), submit.OpportunityId); err != nil || !record.Executed {
+		t.Errorf("opportunity record = %+v, %v, want executed", record, err)
+	}
+
+	submit.Amount = "abc"
+	if code := serve(t, server, http.MethodPost, "/api/executions", "/api/executions", testToken, submit, nil, nil); code == http.StatusOK {
+		t.Error("POST /api/executions with invalid amount status = 200, want error")
+	}
+
+	var list types.ExecutionListResponse
+	serve(t, server, http.MethodGet, "/api/executions", "/api/executions", "", nil, nil, &list)
+	if list.Total != 1 || list.Executions[0].Id != result.Id {
+		t.Errorf("executions = %+v, want %s", list.Executions, result.Id)
+	}
+	serve(t, server, http.MethodGet, "/api/executions", "/api/executions?status=failed", "", nil, nil, &list)
+	if list.Total != 0 {
+		t.Errorf("executions?status=failed total = %d, want 0", list.Total)
+	}
+
+	var got types.ExecutionResult
+	vars := map[string]string{"id": result.Id}
+	if code := serve(t, server, http.MethodGet, "/api/executions/:id", "/api/executions/"+result.Id, "", nil, vars, &got); code != http.StatusOK {
+		t.Fatalf("GET /api/executions/%s status = %d", result.Id, code)
+	}
+	if got.BuyOrder == nil || got.SellOrder == nil || got.ActualProfit != result.ActualProfit {
+		t.Errorf("execution = %+v, want buy and sell orders with profit %s", got, result.ActualProfit)
+	}
+
+	if orders := svcCtx.Orders.List(); len(orders) != 2 {
+		t.Errorf("tracked orders = %d, want buy and sell legs", len(orders))
+	}
+
+	var status types.ExecutorStatus
+	serve(t, server, http.MethodGet, "/api/status", "/api/status", "", nil, nil, &status)
+	if status.TotalExecuted != 1 || status.TotalSuccess != 1 || status.TotalProfit != result.ActualProfit {
+		t.Errorf("status = %+v, want 1 successful execution", status)
+	}
+}
+
+// TestAutoExecute 测试自动执行消息总线上新出现的套利机会并发布执行结果
+func TestAutoExecute(t *testing.T) {
+	c := testConfig()
+	c.AutoExecute = config.AutoExecuteConf{Enabled: true, Amount: "0.1", MaxAge: time.Minute}
+	c.Risk.MaxRiskScore = 50
+	svcCtx := svc.NewServiceContextWithExecutors(c, testExecutors())
+	messageBus := bus.NewMemoryBus(0, time.Minute)
+	svcCtx.Bus = messageBus
+
+	results := make(chan *execution.ExecutionResult, 10)
+	err := messageBus.Subscribe(context.Background(), bus.TopicExecutions, "test", "test-1", func(ctx context.Context, msg *bus.Message) error {
+		var result execution.ExecutionResult
+		if err := msg.Decode(&result); err != nil {
+			return err
+		}
+		results <- &result
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := svcCtx.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer svcCtx.Stop()
+
+	opportunity := func(id string) *engine.ArbitrageOpportunity {
+		return &engine.ArbitrageOpportunity{
+			ID: id, Symbol: "BTC/USDT", BuyExchange: "binance", SellExchange: "okx",
+			BuyPrice: decimal.NewFromInt(50000), SellPrice: decimal.NewFromInt(50100), DiscoveredAt: time.Now(),
+		}
+	}
+	risky := opportunity("opp-risky")
+	risky.RiskScore = 80
+	now := time.Now()
+	for _, event := range []*engine.OpportunityEvent{
+		{Type: engine.OpportunityUpdated, Opportunity: opportunity("opp-updated"), Time: now},
+		{Type: engine.OpportunityOpened, Opportunity: opportunity("opp-stale"), Time: now.Add(-time.Hour)},
+		{Type: engine.OpportunityOpened, Opportunity: risky, Time: now},
+		{Type: engine.OpportunityOpened, Opportunity: opportunity("opp-1"), Time: now},
+	} {
+		if _, err := bus.PublishJSON(context.Background(), messageBus, bus.TopicOpportunities, event); err != nil {
+			t.Fatalf("PublishJSON() error = %v", err)
+		}
+	}
+
+	select {
+	case result := <-results:
+		if result.OpportunityID != "opp-1" || result.Status != execution.ExecutionStatusCompleted || !result.TradingAmount.Equal(decimal.RequireFromString("0.1")) {
+			t.Errorf("result = %+v, want completed execution of opp-1 for 0.1", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("未收到执行结果")
+	}
+
+	// updated 事件、过期和未通过风控的机会不执行
+	select {
+	case result := <-results:
+		t.Errorf("unexpected result for %s", result.OpportunityID)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if list, _ := svcCtx.Store.Executions.ListByStatus(context.Background(), "", 10); len(list) != 1 {
+		t.Errorf("保存了 %d 条执行记录, want 1", len(list))
+	}
+	if stats := svcCtx.Risk.Stats(); stats.Approved != 1 || stats.Rejected != 1 {
+		t.Errorf("risk stats = %+v, want 1 approved and 1 rejected", stats)
+	}
+}
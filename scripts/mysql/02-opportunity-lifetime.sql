@@ -0,0 +1,27 @@
+-- ================================================================================
+-- ArbitrageX 数据库迁移：套利机会持续时间
+-- ================================================================================
+-- 版本: v1.1.0
+-- 描述: 记录每个套利机会从出现到消失的持续时间和峰值价差
+--
+-- 变更:
+--   - discovered_at 精确到毫秒（扫描间隔为秒级，秒精度无法区分持续时间）
+--   - 新增 last_seen_at / peak_price_diff_rate / sightings
+--   - 新增 (executed, discovered_at) 索引，用于统计错过的机会
+--
+-- 说明: 在 01-init-database.sql 之后执行（Docker 初始化时按文件名顺序执行）
+-- ================================================================================
+
+USE `arbitragex`;
+
+ALTER TABLE `arbitrage_opportunities`
+    MODIFY COLUMN `discovered_at` TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '首次发现时间',
+    ADD COLUMN `last_seen_at` TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '最后一次被扫描到的时间' AFTER `discovered_at`,
+    ADD COLUMN `peak_price_diff_rate` DECIMAL(10, 6) NOT NULL DEFAULT 0 COMMENT '持续期间的最大价差百分比' AFTER `last_seen_at`,
+    ADD COLUMN `sightings` INT UNSIGNED NOT NULL DEFAULT 1 COMMENT '被扫描到的次数' AFTER `peak_price_diff_rate`,
+    ADD INDEX `idx_executed_discovered` (`executed`, `discovered_at`) COMMENT '按执行状态和发现时间查询 (统计错过的机会)';
+
+-- 已有记录只被扫描到过一次
+UPDATE `arbitrage_opportunities`
+SET `last_seen_at` = `discovered_at`,
+    `peak_price_diff_rate` = `price_diff_rate`;
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
 	"arbitragex/pkg/engine"
 )
 
@@ -20,9 +21,9 @@ func TestDebugCacheFlow(t *testing.T) {
 	binancePrice := &cache.PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43100.00,
-		AskPrice:  43150.00,
-		LastPrice: 43125.00,
+		BidPrice:  decimal.NewFromFloat(43100.00),
+		AskPrice:  decimal.NewFromFloat(43150.00),
+		LastPrice: decimal.NewFromFloat(43125.00),
 		Timestamp: time.Now(),
 	}
 
@@ -35,9 +36,9 @@ func TestDebugCacheFlow(t *testing.T) {
 	okxPrice := &cache.PriceData{
 		Exchange:  "okx",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43700.00,
-		AskPrice:  43750.00,
-		LastPrice: 43725.00,
+		BidPrice:  decimal.NewFromFloat(43700.00),
+		AskPrice:  decimal.NewFromFloat(43750.00),
+		LastPrice: decimal.NewFromFloat(43725.00),
 		Timestamp: time.Now(),
 	}
 
@@ -65,8 +66,8 @@ func TestDebugCacheFlow(t *testing.T) {
 	t.Log("\n步骤3: 计算价差")
 	buyFromBinance := binanceRetrieved.AskPrice  // 43150 (Binance 卖价，我们要买入)
 	sellToOKX := okxRetrieved.BidPrice         // 43700 (OKX 买价，我们要卖出)
-	priceDiff := sellToOKX - buyFromBinance
-	priceDiffRate := priceDiff / buyFromBinance
+	priceDiff := sellToOKX.Sub(buyFromBinance)
+	priceDiffRate := priceDiff.Div(buyFromBinance).Float64()
 
 	t.Logf("买入价 (Binance Ask): %.2f", buyFromBinance)
 	t.Logf("卖出价 (OKX Bid): %.2f", sellToOKX)
@@ -105,31 +106,31 @@ func TestDebugCacheFlow(t *testing.T) {
 
 		// 手动计算收益
 		tradingAmount := config.MinVolume // 1000 USDT
-		estRevenue := priceDiff * (tradingAmount / buyFromBinance)
+		estRevenue := priceDiff.Mul(tradingAmount.Div(buyFromBinance))
 		t.Logf("\n手动计算:")
 		t.Logf("  交易金额: %.2f USDT", tradingAmount)
-		t.Logf("  交易数量: %.6f BTC", tradingAmount/buyFromBinance)
+		t.Logf("  交易数量: %.6f BTC", tradingAmount.Div(buyFromBinance))
 		t.Logf("  毛收益: %.2f USDT", estRevenue)
 
 		// 计算成本
 		buyFee := config.TradingFees[0].TakerFee // Binance 0.1%
 		sellFee := config.TradingFees[1].TakerFee // OKX 0.1%
-		totalFees := tradingAmount * (buyFee + sellFee)
-		slippageCost := tradingAmount * config.SlippageRate
-		estCost := totalFees + slippageCost + config.GasFee
+		totalFees := tradingAmount.Mul(decimal.NewFromFloat(buyFee + sellFee))
+		slippageCost := tradingAmount.Mul(decimal.NewFromFloat(config.SlippageRate))
+		estCost := totalFees.Add(slippageCost).Add(config.GasFee)
 
-		t.Logf("  买入手续费 (%.2f%%): %.2f USDT", buyFee*100, tradingAmount*buyFee)
-		t.Logf("  卖出手续费 (%.2f%%): %.2f USDT", sellFee*100, tradingAmount*sellFee)
+		t.Logf("  买入手续费 (%.2f%%): %.2f USDT", buyFee*100, tradingAmount.Mul(decimal.NewFromFloat(buyFee)))
+		t.Logf("  卖出手续费 (%.2f%%): %.2f USDT", sellFee*100, tradingAmount.Mul(decimal.NewFromFloat(sellFee)))
 		t.Logf("  滑点成本 (%.2f%%): %.2f USDT", config.SlippageRate*100, slippageCost)
 		t.Logf("  总成本: %.2f USDT", estCost)
 
-		netProfit := estRevenue - estCost
-		netProfitRate := netProfit / tradingAmount
+		netProfit := estRevenue.Sub(estCost)
+		netProfitRate := netProfit.Div(tradingAmount).Float64()
 
 		t.Logf("\n  净收益: %.2f USDT", netProfit)
 		t.Logf("  净收益率: %.2f%%", netProfitRate*100)
 		t.Logf("\n检查阈值:")
-		t.Logf("  净收益 >= MinProfitAmount (%.2f): %v", config.MinProfitAmount, netProfit >= config.MinProfitAmount)
+		t.Logf("  净收益 >= MinProfitAmount (%.2f): %v", config.MinProfitAmount, netProfit.GreaterThanOrEqual(config.MinProfitAmount))
 		t.Logf("  净收益率 >= MinProfitRate (%.2f%%): %v", config.MinProfitRate*100, netProfitRate >= config.MinProfitRate)
 	} else {
 		for i, opp := range opportunities {
@@ -151,7 +152,7 @@ func TestSimpleArbitrage(t *testing.T) {
 	config := engine.DefaultEngineConfig()
 
 	// 降低最小收益要求以便测试
-	config.MinProfitAmount = 1.0  // 1 USDT
+	config.MinProfitAmount = decimal.NewFromInt(1) // 1 USDT
 	config.MinProfitRate = 0.001  // 0.1%
 
 	arbitrageEngine := engine.NewArbitrageEngine(config, priceCache)
@@ -162,18 +163,18 @@ func TestSimpleArbitrage(t *testing.T) {
 	binancePrice := &cache.PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43000.00,
-		AskPrice:  43010.00,
-		LastPrice: 43005.00,
+		BidPrice:  decimal.NewFromFloat(43000.00),
+		AskPrice:  decimal.NewFromFloat(43010.00),
+		LastPrice: decimal.NewFromFloat(43005.00),
 		Timestamp: time.Now(),
 	}
 
 	okxPrice := &cache.PriceData{
 		Exchange:  "okx",
 		Symbol:    "BTC/USDT",
-		BidPrice:  44000.00, // 大价差
-		AskPrice:  44010.00,
-		LastPrice: 44005.00,
+		BidPrice:  decimal.NewFromFloat(44000.00), // 大价差
+		AskPrice:  decimal.NewFromFloat(44010.00),
+		LastPrice: decimal.NewFromFloat(44005.00),
 		Timestamp: time.Now(),
 	}
 
@@ -200,7 +201,7 @@ func TestSimpleArbitrage(t *testing.T) {
 	t.Logf("  卖出: %s @ %.2f", opp.SellExchange, opp.SellPrice)
 	t.Logf("  净收益: %.2f USDT (%.2f%%)", opp.NetProfit, opp.ProfitRate*100)
 
-	if opp.NetProfit <= 0 {
+	if !opp.NetProfit.IsPositive() {
 		t.Errorf("净收益应该 > 0, got %.2f", opp.NetProfit)
 	}
 }
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"arbitragex/common/cache"
+	"arbitragex/common/decimal"
 	"arbitragex/pkg/engine"
 )
 
@@ -28,9 +29,9 @@ func TestEndToEndFlow(t *testing.T) {
 	binancePrice := &cache.PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43100.00,
-		AskPrice:  43150.00,
-		LastPrice: 43125.00,
+		BidPrice:  decimal.NewFromFloat(43100.00),
+		AskPrice:  decimal.NewFromFloat(43150.00),
+		LastPrice: decimal.NewFromFloat(43125.00),
 		Timestamp: time.Now(),
 	}
 	err := priceCache.SetPrice(ctx, "binance", "BTC/USDT", binancePrice)
@@ -42,9 +43,9 @@ func TestEndToEndFlow(t *testing.T) {
 	okxPrice := &cache.PriceData{
 		Exchange:  "okx",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43850.00, // OKX 买价更高（价差 700）
-		AskPrice:  43900.00,
-		LastPrice: 43875.00,
+		BidPrice:  decimal.NewFromFloat(43850.00), // OKX 买价更高（价差 700）
+		AskPrice:  decimal.NewFromFloat(43900.00),
+		LastPrice: decimal.NewFromFloat(43875.00),
 		Timestamp: time.Now(),
 	}
 	err = priceCache.SetPrice(ctx, "okx", "BTC/USDT", okxPrice)
@@ -90,7 +91,7 @@ func TestEndToEndFlow(t *testing.T) {
 	if opp.SellExchange != "okx" {
 		t.Errorf("卖出交易所错误 = %s, want okx", opp.SellExchange)
 	}
-	if opp.NetProfit <= 0 {
+	if !opp.NetProfit.IsPositive() {
 		t.Errorf("净收益应该 > 0, got %.2f", opp.NetProfit)
 	}
 }
@@ -112,9 +113,9 @@ func TestMultipleExchanges(t *testing.T) {
 		price := &cache.PriceData{
 			Exchange:  exchange,
 			Symbol:    "BTC/USDT",
-			BidPrice:  prices[i],
-			AskPrice:  prices[i] + 50,
-			LastPrice: prices[i] + 25,
+			BidPrice:  decimal.NewFromFloat(prices[i]),
+			AskPrice:  decimal.NewFromFloat(prices[i] + 50),
+			LastPrice: decimal.NewFromFloat(prices[i] + 25),
 			Timestamp: time.Now(),
 		}
 		err := priceCache.SetPrice(ctx, exchange, "BTC/USDT", price)
@@ -166,9 +167,9 @@ func TestMultipleSymbols(t *testing.T) {
 		binancePrice := &cache.PriceData{
 			Exchange:  "binance",
 			Symbol:    symbol,
-			BidPrice:  1000.00,
-			AskPrice:  1005.00,
-			LastPrice: 1002.50,
+			BidPrice:  decimal.NewFromFloat(1000.00),
+			AskPrice:  decimal.NewFromFloat(1005.00),
+			LastPrice: decimal.NewFromFloat(1002.50),
 			Timestamp: time.Now(),
 		}
 
@@ -176,9 +177,9 @@ func TestMultipleSymbols(t *testing.T) {
 		okxPrice := &cache.PriceData{
 			Exchange:  "okx",
 			Symbol:    symbol,
-			BidPrice:  1040.00,
-			AskPrice:  1045.00,
-			LastPrice: 1042.50,
+			BidPrice:  decimal.NewFromFloat(1040.00),
+			AskPrice:  decimal.NewFromFloat(1045.00),
+			LastPrice: decimal.NewFromFloat(1042.50),
 			Timestamp: time.Now(),
 		}
 
@@ -243,9 +244,9 @@ func TestConcurrentPriceUpdates(t *testing.T) {
 			binancePrice := &cache.PriceData{
 				Exchange:  "binance",
 				Symbol:    "BTC/USDT",
-				BidPrice:  float64(43000 + index%100),
-				AskPrice:  float64(43050 + index%100),
-				LastPrice: float64(43025 + index%100),
+				BidPrice:  decimal.NewFromInt(int64(43000 + index%100)),
+				AskPrice:  decimal.NewFromInt(int64(43050 + index%100)),
+				LastPrice: decimal.NewFromInt(int64(43025 + index%100)),
 				Timestamp: time.Now(),
 			}
 
@@ -253,9 +254,9 @@ func TestConcurrentPriceUpdates(t *testing.T) {
 			okxPrice := &cache.PriceData{
 				Exchange:  "okx",
 				Symbol:    "BTC/USDT",
-				BidPrice:  float64(43400 + index%100),
-				AskPrice:  float64(43450 + index%100),
-				LastPrice: float64(43425 + index%100),
+				BidPrice:  decimal.NewFromInt(int64(43400 + index%100)),
+				AskPrice:  decimal.NewFromInt(int64(43450 + index%100)),
+				LastPrice: decimal.NewFromInt(int64(43425 + index%100)),
 				Timestamp: time.Now(),
 			}
 
@@ -307,9 +308,9 @@ func TestPerformancePriceUpdateLatency(t *testing.T) {
 		price := &cache.PriceData{
 			Exchange:  "binance",
 			Symbol:    "BTC/USDT",
-			BidPrice:  float64(43000 + i),
-			AskPrice:  float64(43050 + i),
-			LastPrice: float64(43025 + i),
+			BidPrice:  decimal.NewFromInt(int64(43000 + i)),
+			AskPrice:  decimal.NewFromInt(int64(43050 + i)),
+			LastPrice: decimal.NewFromInt(int64(43025 + i)),
 			Timestamp: time.Now(),
 		}
 
@@ -358,9 +359,9 @@ func TestPerformanceArbitrageScanLatency(t *testing.T) {
 			price := &cache.PriceData{
 				Exchange:  exchange,
 				Symbol:    symbol,
-				BidPrice:  1000.00,
-				AskPrice:  1005.00,
-				LastPrice: 1002.50,
+				BidPrice:  decimal.NewFromFloat(1000.00),
+				AskPrice:  decimal.NewFromFloat(1005.00),
+				LastPrice: decimal.NewFromFloat(1002.50),
 				Timestamp: time.Now(),
 			}
 			err := priceCache.SetPrice(ctx, exchange, symbol, price)
@@ -413,18 +414,18 @@ func TestPerformanceThroughput(t *testing.T) {
 		binancePrice := &cache.PriceData{
 			Exchange:  "binance",
 			Symbol:    symbol,
-			BidPrice:  1000.00,
-			AskPrice:  1005.00,
-			LastPrice: 1002.50,
+			BidPrice:  decimal.NewFromFloat(1000.00),
+			AskPrice:  decimal.NewFromFloat(1005.00),
+			LastPrice: decimal.NewFromFloat(1002.50),
 			Timestamp: time.Now(),
 		}
 
 		okxPrice := &cache.PriceData{
 			Exchange:  "okx",
 			Symbol:    symbol,
-			BidPrice:  1040.00,
-			AskPrice:  1045.00,
-			LastPrice: 1042.50,
+			BidPrice:  decimal.NewFromFloat(1040.00),
+			AskPrice:  decimal.NewFromFloat(1045.00),
+			LastPrice: decimal.NewFromFloat(1042.50),
 			Timestamp: time.Now(),
 		}
 
@@ -484,9 +485,9 @@ func TestCacheExpirations(t *testing.T) {
 	price := &cache.PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43000.00,
-		AskPrice:  43050.00,
-		LastPrice: 43025.00,
+		BidPrice:  decimal.NewFromFloat(43000.00),
+		AskPrice:  decimal.NewFromFloat(43050.00),
+		LastPrice: decimal.NewFromFloat(43025.00),
 		Timestamp: time.Now(),
 	}
 
@@ -523,18 +524,18 @@ func BenchmarkFullFlow(b *testing.B) {
 	binancePrice := &cache.PriceData{
 		Exchange:  "binance",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43100.00,
-		AskPrice:  43150.00,
-		LastPrice: 43125.00,
+		BidPrice:  decimal.NewFromFloat(43100.00),
+		AskPrice:  decimal.NewFromFloat(43150.00),
+		LastPrice: decimal.NewFromFloat(43125.00),
 		Timestamp: time.Now(),
 	}
 
 	okxPrice := &cache.PriceData{
 		Exchange:  "okx",
 		Symbol:    "BTC/USDT",
-		BidPrice:  43500.00,
-		AskPrice:  43550.00,
-		LastPrice: 43525.00,
+		BidPrice:  decimal.NewFromFloat(43500.00),
+		AskPrice:  decimal.NewFromFloat(43550.00),
+		LastPrice: decimal.NewFromFloat(43525.00),
 		Timestamp: time.Now(),
 	}
 
@@ -561,9 +562,9 @@ func calculateP95Latency(t *testing.T, priceCache cache.PriceCache, ctx context.
 		price := &cache.PriceData{
 			Exchange:  "binance",
 			Symbol:    "BTC/USDT",
-			BidPrice:  float64(43000 + i),
-			AskPrice:  float64(43050 + i),
-			LastPrice: float64(43025 + i),
+			BidPrice:  decimal.NewFromInt(int64(43000 + i)),
+			AskPrice:  decimal.NewFromInt(int64(43050 + i)),
+			LastPrice: decimal.NewFromInt(int64(43025 + i)),
 			Timestamp: time.Now(),
 		}
 
@@ -625,9 +626,9 @@ func TestIntegrationStressTest(t *testing.T) {
 				price := &cache.PriceData{
 					Exchange:  fmt.Sprintf("exchange%d", index%5),
 					Symbol:    fmt.Sprintf("SYMBOL%d/USDT", j%10),
-					BidPrice:  float64(1000 + j),
-					AskPrice:  float64(1005 + j),
-					LastPrice: float64(1002 + j),
+					BidPrice:  decimal.NewFromInt(int64(1000 + j)),
+					AskPrice:  decimal.NewFromInt(int64(1005 + j)),
+					LastPrice: decimal.NewFromInt(int64(1002 + j)),
 					Timestamp: time.Now(),
 				}
 